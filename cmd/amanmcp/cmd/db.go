@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+func newDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect and manage the metadata database schema",
+	}
+
+	cmd.AddCommand(newDBMigrateCmd())
+
+	return cmd
+}
+
+func newDBMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [up|down|status]",
+		Short: "Apply, roll back, or report the metadata store's schema migrations",
+		Long: `Manage the SQLite metadata store's schema via the embedded migration framework.
+
+  up      apply every migration newer than the current schema version
+  down    roll back the single most recently applied migration
+  status  list every known migration and whether it's applied`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDBMigrate(cmd.Context(), cmd, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runDBMigrate(ctx context.Context, cmd *cobra.Command, action string) error {
+	root, err := config.FindProjectRoot(".")
+	if err != nil {
+		return fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	metadataPath := filepath.Join(root, ".amanmcp", "metadata.db")
+	if !fileExists(metadataPath) {
+		return fmt.Errorf("no index found in %s\nRun 'amanmcp index' to create one", root)
+	}
+
+	s, err := store.NewSQLiteStore(metadataPath)
+	if err != nil {
+		return fmt.Errorf("failed to open metadata store: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	switch action {
+	case "up":
+		// NewSQLiteStore above already migrated to latest on open, so
+		// this is only ever a no-op confirmation unless a future
+		// migration file was added after the store was opened.
+		statuses, err := s.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		target := 0
+		for _, st := range statuses {
+			if st.Version > target {
+				target = st.Version
+			}
+		}
+		if err := s.MigrateTo(ctx, target); err != nil {
+			return fmt.Errorf("failed to migrate up: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "schema is at version %d\n", target)
+		return nil
+
+	case "down":
+		statuses, err := s.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		current := 0
+		for _, st := range statuses {
+			if st.Applied && st.Version > current {
+				current = st.Version
+			}
+		}
+		if current == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no migrations to roll back")
+			return nil
+		}
+		if err := s.MigrateTo(ctx, current-1); err != nil {
+			return fmt.Errorf("failed to migrate down: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "rolled back migration %d, schema is now at version %d\n", current, current-1)
+		return nil
+
+	case "status":
+		statuses, err := s.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "pending"
+			switch {
+			case st.Applied && st.Checksum != st.RecordedChecksum:
+				state = "applied (drifted: file changed since apply)"
+			case st.Applied:
+				state = "applied"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%04d  %-40s  %s\n", st.Version, st.Name, state)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate action %q (want up, down, or status)", action)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/embed"
 	"github.com/Aman-CERP/amanmcp/internal/logging"
 	"github.com/Aman-CERP/amanmcp/internal/store"
 	"github.com/Aman-CERP/amanmcp/internal/ui"
@@ -30,6 +32,11 @@ type DebugInfo struct {
 	UpdatedAt string `json:"updated_at"`
 	IndexAge  string `json:"index_age"`
 
+	// IndexAgeSeconds is IndexAge's raw form, carried alongside the
+	// human-readable string since RecordMetricsSample needs a number, not
+	// a sentence.
+	IndexAgeSeconds int64 `json:"index_age_seconds"`
+
 	// Files and chunks
 	FileCount  int `json:"file_count"`
 	ChunkCount int `json:"chunk_count"`
@@ -41,7 +48,25 @@ type DebugInfo struct {
 	EmbedderProvider   string `json:"embedder_provider"`
 	EmbedderModel      string `json:"embedder_model"`
 	EmbedderDimensions int    `json:"embedder_dimensions"`
-	EmbedderAvailable  bool   `json:"embedder_available"`
+	// EmbedderAvailable is true only when a live probe actually embedded a
+	// string through the configured provider - not merely that a model
+	// name is set in config (see probeEmbedder). With --probe=false, no
+	// probe runs and this falls back to the old "model name is set"
+	// heuristic.
+	EmbedderAvailable bool `json:"embedder_available"`
+	// EmbedderLatencyMS is how long the probe's EmbedBatch call took, or
+	// zero if no probe ran.
+	EmbedderLatencyMS int64 `json:"embedder_latency_ms,omitempty"`
+	// EmbedderLiveDimensions is the vector dimension the probe actually
+	// got back, independent of EmbedderDimensions (which is whatever was
+	// recorded in metadata state at index time).
+	EmbedderLiveDimensions int `json:"embedder_live_dimensions,omitempty"`
+	// DimensionDrift is non-empty when a successful probe's
+	// EmbedderLiveDimensions disagrees with EmbedderDimensions - the
+	// index was built with one model/dimension and the configured
+	// embedder now returns another, so search results would be silently
+	// wrong until a re-index.
+	DimensionDrift string `json:"dimension_drift,omitempty"`
 
 	// BM25 index
 	BM25Backend   string `json:"bm25_backend"`
@@ -56,10 +81,19 @@ type DebugInfo struct {
 	TotalSizeBytes    int64 `json:"total_size_bytes"`
 	MemoryEstimate    int64 `json:"memory_estimate_bytes"`
 	MetadataSizeBytes int64 `json:"metadata_size_bytes"`
+
+	// Usage is a crawled, measured breakdown of dataDir's actual storage
+	// footprint - TotalSizeBytes/MemoryEstimate above are derived from
+	// it rather than from file-size heuristics.
+	Usage *UsageReport `json:"usage,omitempty"`
 }
 
 func newDebugCmd() *cobra.Command {
 	var jsonOutput bool
+	var history bool
+	var since string
+	var serveAddr string
+	var probe bool
 
 	cmd := &cobra.Command{
 		Use:   "debug",
@@ -71,18 +105,34 @@ Shows:
   - File and chunk counts with language distribution
   - Embedder configuration and availability
   - BM25 and vector store statistics
-  - Storage sizes and memory estimates`,
+  - Storage sizes and memory estimates
+
+Every invocation also records a metrics sample; pass --history to view the
+recorded series (a growth curve) instead of the current snapshot, or
+--serve to expose an OpenMetrics /metrics endpoint plus /healthz and
+/readyz for running amanmcp as a monitored background server.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDebug(cmd.Context(), cmd, jsonOutput)
+			if serveAddr != "" {
+				return runDebugServe(cmd.Context(), cmd, serveAddr)
+			}
+			if history {
+				return runDebugHistory(cmd.Context(), cmd, jsonOutput, since)
+			}
+			return runDebug(cmd.Context(), cmd, jsonOutput, probe)
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&history, "history", false, "Show recorded index metrics history instead of a snapshot")
+	cmd.Flags().StringVar(&since, "since", "", "With --history, only include samples from this long ago (e.g. 7d, 24h)")
+	cmd.Flags().StringVar(&serveAddr, "serve", "", "Serve /metrics, /healthz, and /readyz on this address (e.g. :9090) until interrupted")
+	cmd.Flags().BoolVar(&probe, "probe", true, "Dial the configured embedder and confirm it actually responds (set --probe=false to skip the network call)")
+	cmd.AddCommand(newFuzzWatcherCmd())
 
 	return cmd
 }
 
-func runDebug(ctx context.Context, cmd *cobra.Command, jsonOutput bool) error {
+func runDebug(ctx context.Context, cmd *cobra.Command, jsonOutput, probe bool) error {
 	// Set up file-only logging (no stderr output to keep CLI clean)
 	logCfg := logging.DefaultConfig()
 	logCfg.WriteToStderr = false // File only
@@ -108,11 +158,18 @@ func runDebug(ctx context.Context, cmd *cobra.Command, jsonOutput bool) error {
 	}
 
 	// Collect debug info
-	info, err := collectDebugInfo(ctx, root, dataDir)
+	info, err := collectDebugInfo(ctx, root, dataDir, probe)
 	if err != nil {
 		return fmt.Errorf("failed to collect debug info: %w", err)
 	}
 
+	// Record a metrics sample so --history has a growth curve to show.
+	// amanmcp index would record one here too, but isn't part of this
+	// checkout.
+	if err := store.RecordMetricsSample(dataDir, debugInfoToMetricsSample(info)); err != nil {
+		slog.Warn("metrics_sample_record_failed", slog.String("error", err.Error()))
+	}
+
 	// Log to file (always, for observability)
 	slog.Info("Debug info collected",
 		slog.String("index_path", info.IndexPath),
@@ -123,6 +180,8 @@ func runDebug(ctx context.Context, cmd *cobra.Command, jsonOutput bool) error {
 		slog.String("embedder_model", info.EmbedderModel),
 		slog.Int("embedder_dimensions", info.EmbedderDimensions),
 		slog.Bool("embedder_available", info.EmbedderAvailable),
+		slog.Int64("embedder_latency_ms", info.EmbedderLatencyMS),
+		slog.String("dimension_drift", info.DimensionDrift),
 		slog.String("bm25_backend", info.BM25Backend),
 		slog.Int("bm25_documents", info.BM25Documents),
 		slog.Int64("bm25_size_bytes", info.BM25SizeBytes),
@@ -140,7 +199,7 @@ func runDebug(ctx context.Context, cmd *cobra.Command, jsonOutput bool) error {
 	return outputDebugHuman(cmd, info)
 }
 
-func collectDebugInfo(ctx context.Context, root, dataDir string) (*DebugInfo, error) {
+func collectDebugInfo(ctx context.Context, root, dataDir string, probe bool) (*DebugInfo, error) {
 	info := &DebugInfo{
 		IndexPath:   dataDir,
 		ProjectRoot: root,
@@ -164,6 +223,9 @@ func collectDebugInfo(ctx context.Context, root, dataDir string) (*DebugInfo, er
 		info.CreatedAt = store.FormatTime(project.IndexedAt)
 		info.UpdatedAt = store.FormatTime(project.IndexedAt)
 		info.IndexAge = formatAge(project.IndexedAt)
+		if !project.IndexedAt.IsZero() {
+			info.IndexAgeSeconds = int64(time.Since(project.IndexedAt).Seconds())
+		}
 	}
 
 	// Load configuration for embedder info
@@ -186,8 +248,22 @@ func collectDebugInfo(ctx context.Context, root, dataDir string) (*DebugInfo, er
 		_, _ = fmt.Sscanf(dimStr, "%d", &info.EmbedderDimensions)
 	}
 
-	// Check embedder availability (simplified - check if model is set)
-	info.EmbedderAvailable = info.EmbedderModel != ""
+	// Check embedder availability. A real probe dials the provider and
+	// embeds a throwaway string rather than trusting that a model name
+	// is merely configured - see probeEmbedder.
+	if probe {
+		result := probeEmbedder(ctx, cfg)
+		info.EmbedderAvailable = result.reachable
+		info.EmbedderLatencyMS = result.latency.Milliseconds()
+		info.EmbedderLiveDimensions = result.liveDimensions
+		if result.reachable && info.EmbedderDimensions > 0 && result.liveDimensions != info.EmbedderDimensions {
+			info.DimensionDrift = fmt.Sprintf(
+				"index was built with %d-dimensional vectors but the configured embedder now returns %d dimensions - re-run 'amanmcp index' to rebuild",
+				info.EmbedderDimensions, result.liveDimensions)
+		}
+	} else {
+		info.EmbedderAvailable = info.EmbedderModel != ""
+	}
 
 	// Determine BM25 backend
 	bm25SQLitePath := filepath.Join(dataDir, "bm25.db")
@@ -209,18 +285,19 @@ func collectDebugInfo(ctx context.Context, root, dataDir string) (*DebugInfo, er
 	// Metadata size
 	info.MetadataSizeBytes = getFileSize(metadataPath)
 
-	// Total storage
-	info.TotalSizeBytes = info.MetadataSizeBytes + info.BM25SizeBytes + info.VectorSizeBytes
-
-	// Memory estimate heuristic:
-	// - HNSW: ~1.5x file size in memory (graph overhead)
-	// - BM25: ~0.3x file size in memory (inverted index)
-	// - Metadata: ~0.5x file size in memory (SQLite cache)
-	info.MemoryEstimate = int64(
-		float64(info.VectorSizeBytes)*1.5 +
-			float64(info.BM25SizeBytes)*0.3 +
-			float64(info.MetadataSizeBytes)*0.5,
-	)
+	// Total storage and memory: crawled actual bytes, not a heuristic.
+	// crawlUsage walks every file under dataDir (WAL/SHM files, bleve
+	// segments, anything else) rather than summing three known paths,
+	// and reports real process memory plus HNSW page-cache residency
+	// instead of guessing at in-memory overhead factors.
+	usage, err := crawlUsage(ctx, dataDir)
+	if err != nil {
+		slog.Warn("usage_crawl_failed", slog.String("error", err.Error()))
+	} else {
+		info.Usage = usage
+		info.TotalSizeBytes = usage.TotalBytes
+		info.MemoryEstimate = int64(usage.ProcessRSSBytes) + usage.HNSWResidentBytes
+	}
 
 	// Language distribution from file paths
 	filePaths, err := metadata.GetFilePathsByProject(ctx, projectID)
@@ -266,6 +343,47 @@ func collectDebugInfo(ctx context.Context, root, dataDir string) (*DebugInfo, er
 	return info, nil
 }
 
+// embedderProbeTimeout bounds how long collectDebugInfo waits on a live
+// embedder call before giving up and reporting it unreachable.
+const embedderProbeTimeout = 5 * time.Second
+
+// embedderProbeResult is what actually dialing the configured embedder
+// established, as opposed to info.EmbedderModel merely being a non-empty
+// string in config.
+type embedderProbeResult struct {
+	reachable      bool
+	latency        time.Duration
+	liveDimensions int
+}
+
+// probeEmbedder embeds a throwaway string through the configured
+// provider and times it. This is the only reachability signal available
+// from this package - internal/embed's providers each know how to dial
+// their own liveness endpoint (Ollama's /api/tags, OpenAI's /v1/models),
+// but that's internal to embed.NewEmbedder/Embedder and not exposed here,
+// so a successful embed call is used as the liveness/model-present proxy
+// instead of reimplementing per-provider HTTP probes this package has no
+// business owning.
+func probeEmbedder(ctx context.Context, cfg *config.Config) embedderProbeResult {
+	probeCtx, cancel := context.WithTimeout(ctx, embedderProbeTimeout)
+	defer cancel()
+
+	provider := embed.ParseProvider(cfg.Embeddings.Provider)
+	embedder, err := embed.NewEmbedder(probeCtx, provider, cfg.Embeddings.Model)
+	if err != nil {
+		return embedderProbeResult{}
+	}
+
+	start := time.Now()
+	vectors, err := embedder.EmbedBatch(probeCtx, []string{"amanmcp debug probe"})
+	latency := time.Since(start)
+	if err != nil || len(vectors) == 0 {
+		return embedderProbeResult{latency: latency}
+	}
+
+	return embedderProbeResult{reachable: true, latency: latency, liveDimensions: len(vectors[0])}
+}
+
 func outputDebugJSON(cmd *cobra.Command, info *DebugInfo) error {
 	enc := json.NewEncoder(cmd.OutOrStdout())
 	enc.SetIndent("", "  ")
@@ -307,9 +425,24 @@ func outputDebugHuman(cmd *cobra.Command, info *DebugInfo) error {
 	if info.EmbedderAvailable {
 		available = "\u2713"
 	}
-	fmt.Fprintf(w, "\u2514\u2500 Available:  %s\n", available)
+	if info.EmbedderLatencyMS > 0 {
+		fmt.Fprintf(w, "\u251c\u2500 Available:  %s (probed in %dms)\n", available, info.EmbedderLatencyMS)
+	} else {
+		fmt.Fprintf(w, "\u251c\u2500 Available:  %s\n", available)
+	}
+	fmt.Fprintf(w, "\u2514\u2500 Live Dimensions: %d\n", info.EmbedderLiveDimensions)
 	fmt.Fprintln(w)
 
+	if info.DimensionDrift != "" {
+		warning := fmt.Sprintf("WARNING: %s", info.DimensionDrift)
+		if noColor {
+			fmt.Fprintln(w, warning)
+		} else {
+			fmt.Fprintf(w, "\033[31m%s\033[0m\n", warning)
+		}
+		fmt.Fprintln(w)
+	}
+
 	// BM25 Index
 	fmt.Fprintln(w, "BM25 INDEX")
 	fmt.Fprintf(w, "\u251c\u2500 Backend:   %s\n", info.BM25Backend)
@@ -327,6 +460,11 @@ func outputDebugHuman(cmd *cobra.Command, info *DebugInfo) error {
 	fmt.Fprintln(w, "STORAGE")
 	fmt.Fprintf(w, "\u251c\u2500 Total Size:   %s\n", store.FormatBytes(info.TotalSizeBytes))
 	fmt.Fprintf(w, "\u2514\u2500 Memory (est): ~%s\n", store.FormatBytes(info.MemoryEstimate))
+	fmt.Fprintln(w)
+
+	if info.Usage != nil {
+		outputStorageBreakdown(w, info.Usage)
+	}
 
 	// Footer
 	if noColor {
@@ -338,6 +476,37 @@ func outputDebugHuman(cmd *cobra.Command, info *DebugInfo) error {
 	return nil
 }
 
+// outputStorageBreakdown prints usage's per-collection totals and largest
+// files - the detail collectDebugInfo's old three-number STORAGE section
+// couldn't show.
+func outputStorageBreakdown(w io.Writer, usage *UsageReport) {
+	fmt.Fprintln(w, "STORAGE BREAKDOWN")
+	fmt.Fprintf(w, "├─ Objects:  %s\n", formatNumber(usage.ObjectCount))
+	fmt.Fprintf(w, "├─ Process memory (sys): %s\n", store.FormatBytes(int64(usage.ProcessRSSBytes)))
+	fmt.Fprintf(w, "├─ HNSW resident (page cache): %s\n", store.FormatBytes(usage.HNSWResidentBytes))
+
+	collections := make([]string, 0, len(usage.Collections))
+	for name := range usage.Collections {
+		collections = append(collections, name)
+	}
+	sort.Strings(collections)
+	for _, name := range collections {
+		fmt.Fprintf(w, "├─ %s: %s\n", name, store.FormatBytes(usage.Collections[name]))
+	}
+
+	if len(usage.LargestFiles) > 0 {
+		fmt.Fprintln(w, "└─ Largest files:")
+		for i, f := range usage.LargestFiles {
+			prefix := "    ├─"
+			if i == len(usage.LargestFiles)-1 {
+				prefix = "    └─"
+			}
+			fmt.Fprintf(w, "%s %s (%s)\n", prefix, f.Path, store.FormatBytes(f.Size))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
 // formatAge returns a human-readable age string.
 func formatAge(t time.Time) string {
 	if t.IsZero() {
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// debugInfoToMetricsSample extracts the subset of DebugInfo that's worth
+// tracking over time into a store.MetricsSample.
+func debugInfoToMetricsSample(info *DebugInfo) store.MetricsSample {
+	return store.MetricsSample{
+		Timestamp:          time.Now(),
+		ChunkCount:         int64(info.ChunkCount),
+		FileCount:          int64(info.FileCount),
+		TotalSizeBytes:     info.TotalSizeBytes,
+		VectorSizeBytes:    info.VectorSizeBytes,
+		BM25SizeBytes:      info.BM25SizeBytes,
+		EmbedderDimensions: int64(info.EmbedderDimensions),
+		IndexAgeSeconds:    info.IndexAgeSeconds,
+	}
+}
+
+// runDebugHistory loads and displays the index's recorded metrics
+// history, the longitudinal counterpart to runDebug's point-in-time
+// snapshot.
+func runDebugHistory(ctx context.Context, cmd *cobra.Command, jsonOutput bool, since string) error {
+	root, err := config.FindProjectRoot(".")
+	if err != nil {
+		root = "."
+	}
+	dataDir := filepath.Join(root, ".amanmcp")
+
+	var sinceTime time.Time
+	if since != "" {
+		d, err := parseSinceDuration(since)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: %w", since, err)
+		}
+		sinceTime = time.Now().Add(-d)
+	}
+
+	samples, err := store.LoadMetricsHistory(dataDir, sinceTime)
+	if err != nil {
+		return fmt.Errorf("failed to load metrics history: %w", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(samples)
+	}
+
+	return outputDebugHistoryHuman(cmd, samples)
+}
+
+// parseSinceDuration parses a --since value. time.ParseDuration already
+// handles "24h"/"90m"; the only extension needed is a "d" (day) suffix,
+// since "7d" reads far more naturally than "168h" for this flag.
+func parseSinceDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// historySparklineMetrics lists the columns outputDebugHistoryHuman plots,
+// in the order they're shown.
+var historySparklineMetrics = []struct {
+	label string
+	get   func(s store.MetricsSample) int64
+}{
+	{"Chunks", func(s store.MetricsSample) int64 { return s.ChunkCount }},
+	{"Files", func(s store.MetricsSample) int64 { return s.FileCount }},
+	{"Total size", func(s store.MetricsSample) int64 { return s.TotalSizeBytes }},
+	{"Vector size", func(s store.MetricsSample) int64 { return s.VectorSizeBytes }},
+	{"BM25 size", func(s store.MetricsSample) int64 { return s.BM25SizeBytes }},
+}
+
+func outputDebugHistoryHuman(cmd *cobra.Command, samples []store.MetricsSample) error {
+	w := cmd.OutOrStdout()
+
+	fmt.Fprintln(w, "AmanMCP Index History")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w)
+
+	if len(samples) == 0 {
+		fmt.Fprintln(w, "No metrics history recorded yet - run 'amanmcp debug' again to start one.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Samples: %s  (%s to %s)\n\n",
+		formatNumber(len(samples)),
+		samples[0].Timestamp.Format(time.RFC3339),
+		samples[len(samples)-1].Timestamp.Format(time.RFC3339))
+
+	for i, m := range historySparklineMetrics {
+		values := make([]int64, len(samples))
+		for j, s := range samples {
+			values[j] = m.get(s)
+		}
+		prefix := "├─"
+		if i == len(historySparklineMetrics)-1 {
+			prefix = "└─"
+		}
+		fmt.Fprintf(w, "%s %-12s %s  (latest: %s)\n", prefix, m.label, sparkline(values), formatNumber(int(values[len(values)-1])))
+	}
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// sparklineBlocks are the eight block-height characters a sparkline picks
+// from, lowest to highest.
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a single line of Unicode block characters
+// scaled between their own min and max, the same trick tools like
+// spark(1) use to pack a trend into one terminal line.
+func sparkline(values []int64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	span := max - min
+	out := make([]rune, len(values))
+	for i, v := range values {
+		if span == 0 {
+			out[i] = sparklineBlocks[0]
+			continue
+		}
+		level := int((v - min) * int64(len(sparklineBlocks)-1) / span)
+		out[i] = sparklineBlocks[level]
+	}
+	return string(out)
+}
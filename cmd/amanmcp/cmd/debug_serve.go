@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/metrics"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// embedderPingInterval bounds how often /healthz actually calls the
+// configured embedding provider - a scrape interval of a few seconds
+// shouldn't turn into a few requests per second against someone's
+// embedding API.
+const embedderPingInterval = 30 * time.Second
+
+// runDebugServe keeps the process alive, serving /metrics, /healthz, and
+// /readyz on addr until ctx is canceled - the long-running counterpart
+// to debug's otherwise one-shot snapshot, for running alongside `amanmcp
+// serve` as an operational sidecar.
+func runDebugServe(ctx context.Context, cmd *cobra.Command, addr string) error {
+	root, err := config.FindProjectRoot(".")
+	if err != nil {
+		cwd, _ := os.Getwd()
+		root = cwd
+	}
+	dataDir := filepath.Join(root, ".amanmcp")
+
+	counters := metrics.NewEventCounters()
+	if logger := slog.Default(); logger != nil {
+		slog.SetDefault(slog.New(metrics.NewLogCountingHandler(logger.Handler(), counters)))
+	}
+
+	cfg, err := config.Load(root)
+	if err != nil {
+		cfg = config.NewConfig()
+	}
+	checker := newEmbedderHealthChecker(cfg)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		handleMetrics(r.Context(), w, root, dataDir, counters)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		handleHealthz(r.Context(), w, dataDir, checker)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		handleReadyz(r.Context(), w, dataDir)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(cmd.OutOrStdout(), "Serving metrics on %s (/metrics, /healthz, /readyz)\n", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		return err
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+func handleMetrics(ctx context.Context, w http.ResponseWriter, root, dataDir string, counters *metrics.EventCounters) {
+	// probe=false: /healthz already owns the real embedder probe, cached
+	// behind embedderPingInterval - collectDebugInfo dialing it again on
+	// every /metrics scrape would double the request rate against the
+	// provider for no new information.
+	info, err := collectDebugInfo(ctx, root, dataDir, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	families := debugInfoToFamilies(info)
+	families = append(families, logCounterFamily(counters))
+
+	var b strings.Builder
+	metrics.WriteOpenMetrics(&b, families)
+
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// debugInfoToFamilies translates every numeric/boolean field of DebugInfo
+// into a gauge family, the one-to-one mapping the request asks for
+// (amanmcp_chunk_count, amanmcp_bm25_size_bytes{backend="..."}, etc).
+func debugInfoToFamilies(info *DebugInfo) []metrics.Family {
+	boolGauge := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	families := []metrics.Family{
+		{Name: "amanmcp_file_count", Type: "gauge", Help: "Indexed file count.",
+			Samples: []metrics.Sample{{Name: "amanmcp_file_count", Value: float64(info.FileCount)}}},
+		{Name: "amanmcp_chunk_count", Type: "gauge", Help: "Indexed chunk count.",
+			Samples: []metrics.Sample{{Name: "amanmcp_chunk_count", Value: float64(info.ChunkCount)}}},
+		{Name: "amanmcp_index_age_seconds", Type: "gauge", Help: "Seconds since the index was last built.",
+			Samples: []metrics.Sample{{Name: "amanmcp_index_age_seconds", Value: float64(info.IndexAgeSeconds)}}},
+		{Name: "amanmcp_embedder_dimensions", Type: "gauge", Help: "Configured embedder vector dimensions.",
+			Samples: []metrics.Sample{{Name: "amanmcp_embedder_dimensions", Value: float64(info.EmbedderDimensions)}}},
+		{Name: "amanmcp_embedder_available", Type: "gauge", Help: "Whether an embedder model is configured.",
+			Samples: []metrics.Sample{{Name: "amanmcp_embedder_available", Value: boolGauge(info.EmbedderAvailable)}}},
+		{Name: "amanmcp_bm25_documents", Type: "gauge", Help: "Documents in the BM25 index.",
+			Samples: []metrics.Sample{{Name: "amanmcp_bm25_documents", Value: float64(info.BM25Documents)}}},
+		{Name: "amanmcp_bm25_size_bytes", Type: "gauge", Help: "BM25 index size on disk.",
+			Samples: []metrics.Sample{{Name: "amanmcp_bm25_size_bytes", Labels: map[string]string{"backend": info.BM25Backend}, Value: float64(info.BM25SizeBytes)}}},
+		{Name: "amanmcp_vector_count", Type: "gauge", Help: "Vectors in the vector store.",
+			Samples: []metrics.Sample{{Name: "amanmcp_vector_count", Value: float64(info.VectorCount)}}},
+		{Name: "amanmcp_vector_size_bytes", Type: "gauge", Help: "Vector store size on disk.",
+			Samples: []metrics.Sample{{Name: "amanmcp_vector_size_bytes", Value: float64(info.VectorSizeBytes)}}},
+		{Name: "amanmcp_total_size_bytes", Type: "gauge", Help: "Total on-disk index size.",
+			Samples: []metrics.Sample{{Name: "amanmcp_total_size_bytes", Value: float64(info.TotalSizeBytes)}}},
+		{Name: "amanmcp_memory_estimate_bytes", Type: "gauge", Help: "Estimated memory footprint of the index.",
+			Samples: []metrics.Sample{{Name: "amanmcp_memory_estimate_bytes", Value: float64(info.MemoryEstimate)}}},
+		{Name: "amanmcp_metadata_size_bytes", Type: "gauge", Help: "Metadata store size on disk.",
+			Samples: []metrics.Sample{{Name: "amanmcp_metadata_size_bytes", Value: float64(info.MetadataSizeBytes)}}},
+		{Name: "amanmcp_embedder_latency_ms", Type: "gauge", Help: "Latency of the embedder probe, or 0 if no probe ran.",
+			Samples: []metrics.Sample{{Name: "amanmcp_embedder_latency_ms", Value: float64(info.EmbedderLatencyMS)}}},
+		{Name: "amanmcp_embedder_live_dimensions", Type: "gauge", Help: "Vector dimensions the embedder probe returned.",
+			Samples: []metrics.Sample{{Name: "amanmcp_embedder_live_dimensions", Value: float64(info.EmbedderLiveDimensions)}}},
+	}
+
+	if len(info.Languages) > 0 {
+		langFamily := metrics.Family{Name: "amanmcp_language_ratio", Type: "gauge", Help: "Fraction of indexed files per language."}
+		for lang, ratio := range info.Languages {
+			langFamily.Samples = append(langFamily.Samples, metrics.Sample{
+				Name: "amanmcp_language_ratio", Labels: map[string]string{"lang": lang}, Value: ratio,
+			})
+		}
+		families = append(families, langFamily)
+	}
+
+	return families
+}
+
+// logCounterFamily turns every distinct message LogCountingHandler has
+// seen into one counter sample, labeled by message - a generic stand-in
+// for dedicated search/index op counters, since this package doesn't
+// define those call sites itself.
+func logCounterFamily(counters *metrics.EventCounters) metrics.Family {
+	family := metrics.Family{Name: "amanmcp_log_events_total", Type: "counter", Help: "Count of logged events by message."}
+	for message, count := range counters.Snapshot() {
+		family.Samples = append(family.Samples, metrics.Sample{
+			Name: "amanmcp_log_events_total", Labels: map[string]string{"message": message}, Value: float64(count),
+		})
+	}
+	return family
+}
+
+// embedderHealthChecker caches the result of actually calling the
+// configured embedding provider, so /healthz hits it at most once per
+// embedderPingInterval rather than once per scrape.
+type embedderHealthChecker struct {
+	cfg *config.Config
+
+	mu       sync.Mutex
+	lastErr  error
+	lastTime time.Time
+}
+
+func newEmbedderHealthChecker(cfg *config.Config) *embedderHealthChecker {
+	return &embedderHealthChecker{cfg: cfg}
+}
+
+func (c *embedderHealthChecker) check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.lastTime) < embedderPingInterval {
+		return c.lastErr
+	}
+
+	provider := embed.ParseProvider(c.cfg.Embeddings.Provider)
+	embedder, err := embed.NewEmbedder(ctx, provider, c.cfg.Embeddings.Model)
+	if err != nil {
+		c.lastErr, c.lastTime = err, time.Now()
+		return c.lastErr
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err = embedder.EmbedBatch(pingCtx, []string{"ping"})
+
+	c.lastErr, c.lastTime = err, time.Now()
+	return c.lastErr
+}
+
+func handleHealthz(ctx context.Context, w http.ResponseWriter, dataDir string, checker *embedderHealthChecker) {
+	status := struct {
+		MetadataOpen    bool   `json:"metadata_open"`
+		EmbedderReached bool   `json:"embedder_reachable"`
+		Error           string `json:"error,omitempty"`
+	}{}
+
+	metadataPath := filepath.Join(dataDir, "metadata.db")
+	metadata, err := store.NewSQLiteStore(metadataPath)
+	if err == nil {
+		status.MetadataOpen = true
+		_ = metadata.Close()
+	} else {
+		status.Error = err.Error()
+	}
+
+	if err := checker.check(ctx); err == nil {
+		status.EmbedderReached = true
+	} else if status.Error == "" {
+		status.Error = err.Error()
+	}
+
+	code := http.StatusOK
+	if !status.MetadataOpen || !status.EmbedderReached {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSONStatus(w, code, status)
+}
+
+func handleReadyz(ctx context.Context, w http.ResponseWriter, dataDir string) {
+	status := struct {
+		IndexExists     bool  `json:"index_exists"`
+		DimensionKnown  bool  `json:"dimension_known"`
+		IndexDimensions int64 `json:"index_dimensions"`
+	}{}
+
+	metadataPath := filepath.Join(dataDir, "metadata.db")
+	status.IndexExists = fileExists(metadataPath)
+
+	if status.IndexExists {
+		metadata, err := store.NewSQLiteStore(metadataPath)
+		if err == nil {
+			defer func() { _ = metadata.Close() }()
+			if dimStr, err := metadata.GetState(ctx, store.StateKeyIndexDimension); err == nil && dimStr != "" {
+				var dim int64
+				if _, err := fmt.Sscanf(dimStr, "%d", &dim); err == nil && dim > 0 {
+					status.DimensionKnown = true
+					status.IndexDimensions = dim
+				}
+			}
+		}
+	}
+
+	code := http.StatusOK
+	if !status.IndexExists || !status.DimensionKnown {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSONStatus(w, code, status)
+}
+
+func writeJSONStatus(w http.ResponseWriter, code int, status any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/logging"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+	"github.com/Aman-CERP/amanmcp/internal/ui"
+)
+
+// DoctorReport is the --json shape of `amanmcp doctor`'s findings, built
+// directly from a store.VerifyReport plus whatever --fix did about it.
+type DoctorReport struct {
+	MissingFromBM25    []string `json:"missing_from_bm25"`
+	MissingFromVectors []string `json:"missing_from_vectors"`
+	OrphanBM25IDs      []string `json:"orphan_bm25_ids"`
+	OrphanVectorIDs    []string `json:"orphan_vector_ids"`
+
+	DimensionMismatch   bool `json:"dimension_mismatch"`
+	RecordedDimensions  int  `json:"recorded_dimensions"`
+	ConfiguredVectorDim int  `json:"configured_vector_dim"`
+
+	DriftedFiles []string `json:"drifted_files"`
+
+	Clean bool `json:"clean"`
+
+	// Fixed is only populated when --fix was given: what doctor actually
+	// did about the findings above.
+	Fixed *DoctorFixSummary `json:"fixed,omitempty"`
+}
+
+// DoctorFixSummary reports what --fix changed.
+type DoctorFixSummary struct {
+	DeletedOrphanBM25    int `json:"deleted_orphan_bm25"`
+	DeletedOrphanVectors int `json:"deleted_orphan_vectors"`
+	ReembeddedChunks     int `json:"reembedded_chunks"`
+}
+
+func newDoctorCmd() *cobra.Command {
+	var jsonOutput bool
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Verify cross-store index consistency and optionally repair it",
+		Long: `Check that the metadata store, BM25 index, and vector store agree with
+each other and with the source files on disk.
+
+This goes beyond 'debug' (which just reports counts) by actively
+verifying:
+  - every chunk metadata has an embedding for exists in both the BM25
+    index and the vector store
+  - neither index holds an ID metadata no longer recognizes (orphans)
+  - the embedding dimension recorded in state still matches what the
+    vector store loaded
+  - source files metadata recorded a content hash for haven't changed
+    on disk since they were indexed
+
+'doctor --fix' deletes orphan entries and re-embeds chunks that are
+missing from one index but present in metadata. Files whose content
+has drifted are reported, not auto-repaired - run 'amanmcp index' to
+pick up their changes.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.Context(), cmd, jsonOutput, fix)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Delete orphans and re-embed missing chunks")
+
+	return cmd
+}
+
+func runDoctor(ctx context.Context, cmd *cobra.Command, jsonOutput, fix bool) error {
+	logCfg := logging.DefaultConfig()
+	logCfg.WriteToStderr = false
+	logger, cleanup, err := logging.Setup(logCfg)
+	if err == nil {
+		defer cleanup()
+		slog.SetDefault(logger)
+	}
+
+	root, err := config.FindProjectRoot(".")
+	if err != nil {
+		cwd, _ := os.Getwd()
+		root = cwd
+	}
+	dataDir := filepath.Join(root, ".amanmcp")
+
+	metadataPath := filepath.Join(dataDir, "metadata.db")
+	if !fileExists(metadataPath) {
+		return fmt.Errorf("no index found in %s\nRun 'amanmcp index' to create one", root)
+	}
+
+	cfg, err := config.Load(root)
+	if err != nil {
+		cfg = config.NewConfig()
+	}
+
+	metadata, err := store.NewSQLiteStore(metadataPath)
+	if err != nil {
+		return fmt.Errorf("failed to open metadata store: %w", err)
+	}
+	defer func() { _ = metadata.Close() }()
+
+	bm25BasePath := filepath.Join(dataDir, "bm25")
+	bm25, err := store.NewBM25IndexWithBackend(bm25BasePath, store.DefaultBM25Config(), cfg.Search.BM25Backend)
+	if err != nil {
+		return fmt.Errorf("failed to open BM25 index: %w", err)
+	}
+	defer func() { _ = bm25.Close() }()
+
+	dimensions := 0
+	if dimStr, err := metadata.GetState(ctx, store.StateKeyIndexDimension); err == nil && dimStr != "" {
+		_, _ = fmt.Sscanf(dimStr, "%d", &dimensions)
+	}
+	if dimensions <= 0 {
+		return fmt.Errorf("index has no recorded embedding dimension in state; run 'amanmcp index' first")
+	}
+
+	vectorPath := filepath.Join(dataDir, "vectors.hnsw")
+	vectorCfg := store.DefaultVectorStoreConfig(dimensions)
+	vector, err := store.NewHNSWStore(vectorCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create vector store: %w", err)
+	}
+	defer func() { _ = vector.Close() }()
+
+	vectorLoadErr := error(nil)
+	if fileExists(vectorPath) {
+		vectorLoadErr = vector.Load(vectorPath)
+	}
+
+	projectID := hashString(root)
+	manifest, err := sourceFileManifest(ctx, metadata, projectID, root)
+	if err != nil {
+		slog.Warn("doctor_manifest_build_failed", slog.String("error", err.Error()))
+	}
+
+	report, err := store.VerifyIndex(ctx, metadata, bm25, vector, dataDir, store.VerifyOptions{
+		VectorDimensions: dimensions,
+		Manifest:         manifest,
+		ManifestRoot:     root,
+	})
+	if err != nil {
+		return fmt.Errorf("verify index: %w", err)
+	}
+
+	doctorReport := newDoctorReport(report)
+	if vectorLoadErr != nil {
+		doctorReport.DimensionMismatch = true
+		slog.Warn("doctor_vector_store_load_failed",
+			slog.String("error", vectorLoadErr.Error()),
+			slog.String("note", "on-disk vector store failed to load at the recorded dimension"))
+	}
+
+	if fix {
+		summary, err := runDoctorFix(ctx, cfg, metadata, bm25, vector, report)
+		if err != nil {
+			return fmt.Errorf("fix: %w", err)
+		}
+		doctorReport.Fixed = summary
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(doctorReport)
+	}
+	return outputDoctorHuman(cmd, doctorReport)
+}
+
+// newDoctorReport flattens a store.VerifyReport into the JSON/human shape
+// doctor prints, renaming ChecksumMismatches to the user-facing
+// "drifted files" framing.
+func newDoctorReport(r *store.VerifyReport) *DoctorReport {
+	return &DoctorReport{
+		MissingFromBM25:     r.MissingFromBM25,
+		MissingFromVectors:  r.MissingFromVectors,
+		OrphanBM25IDs:       r.OrphanBM25IDs,
+		OrphanVectorIDs:     r.OrphanVectorIDs,
+		DimensionMismatch:   r.DimensionMismatch,
+		RecordedDimensions:  r.RecordedDimensions,
+		ConfiguredVectorDim: r.ConfiguredVectorDim,
+		DriftedFiles:        r.ChecksumMismatches,
+		Clean:               r.Clean(),
+	}
+}
+
+// sourceFileManifest builds a store.VerifyOptions.Manifest from every file
+// metadata recorded a content hash for, so VerifyIndex can detect files
+// that changed on disk without the indexer having re-run against them
+// yet. Files with no recorded ContentHash (hashing may not be enabled for
+// every content type) are skipped rather than reported as drifted.
+func sourceFileManifest(ctx context.Context, metadata store.MetadataStore, projectID, root string) (map[string]string, error) {
+	files, err := metadata.GetFilesForReconciliation(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	manifest := make(map[string]string, len(files))
+	for path, f := range files {
+		if f.ContentHash == "" {
+			continue
+		}
+		manifest[path] = f.ContentHash
+	}
+	return manifest, nil
+}
+
+// runDoctorFix deletes orphan IDs unconditionally and, if anything is
+// missing from bm25 or vectors, re-embeds it with the project's currently
+// configured embedder. Drifted files (report.ChecksumMismatches, surfaced
+// to the caller via DoctorReport.DriftedFiles) aren't touched here - they
+// need the full chunking pipeline to re-split, which belongs to
+// 'amanmcp index', not a targeted repair.
+func runDoctorFix(ctx context.Context, cfg *config.Config, metadata store.MetadataStore, bm25 store.BM25Index, vector store.VectorStore, report *store.VerifyReport) (*DoctorFixSummary, error) {
+	summary := &DoctorFixSummary{
+		DeletedOrphanBM25:    len(report.OrphanBM25IDs),
+		DeletedOrphanVectors: len(report.OrphanVectorIDs),
+	}
+
+	missing := len(report.MissingFromBM25)+len(report.MissingFromVectors) > 0
+	var embedder embed.Embedder
+	if missing {
+		provider := embed.ParseProvider(cfg.Embeddings.Provider)
+		var err error
+		embedder, err = embed.NewEmbedder(ctx, provider, cfg.Embeddings.Model)
+		if err != nil {
+			return nil, fmt.Errorf("create embedder for re-embedding: %w", err)
+		}
+		defer func() { _ = embedder.Close() }()
+	}
+
+	err := store.RepairIndex(ctx, bm25, vector, report, func(ctx context.Context, chunkIDs []string) ([]*store.Document, [][]float32, error) {
+		docs := make([]*store.Document, 0, len(chunkIDs))
+		texts := make([]string, 0, len(chunkIDs))
+		for _, id := range chunkIDs {
+			chunk, err := metadata.GetChunk(ctx, id)
+			if err != nil || chunk == nil {
+				// A chunk metadata lists as embedded but can no longer
+				// read back (e.g. its file was deleted) can't be
+				// re-embedded; RepairIndex treats an omitted ID as "skip
+				// it", not an error.
+				continue
+			}
+			docs = append(docs, &store.Document{ID: chunk.ID, Content: chunk.Content})
+			texts = append(texts, chunk.Content)
+		}
+		if len(texts) == 0 {
+			return nil, nil, nil
+		}
+		vectors, err := embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("embed batch: %w", err)
+		}
+		summary.ReembeddedChunks += len(docs)
+		return docs, vectors, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func outputDoctorHuman(cmd *cobra.Command, r *DoctorReport) error {
+	w := cmd.OutOrStdout()
+	noColor := ui.DetectNoColor()
+
+	fmt.Fprintln(w, "AmanMCP Doctor")
+	if noColor {
+		fmt.Fprintln(w, "========================================")
+	} else {
+		fmt.Fprintln(w, "════════════════════════════════════════")
+	}
+
+	if r.Clean {
+		fmt.Fprintln(w, "All stores are consistent.")
+	} else {
+		fmt.Fprintln(w, "Inconsistencies found:")
+		printDoctorIDs(w, "Missing from BM25", r.MissingFromBM25)
+		printDoctorIDs(w, "Missing from vectors", r.MissingFromVectors)
+		printDoctorIDs(w, "Orphan BM25 entries", r.OrphanBM25IDs)
+		printDoctorIDs(w, "Orphan vector entries", r.OrphanVectorIDs)
+		if r.DimensionMismatch {
+			fmt.Fprintf(w, "  Dimension mismatch: state=%d configured=%d\n", r.RecordedDimensions, r.ConfiguredVectorDim)
+		}
+		printDoctorIDs(w, "Drifted files (re-run 'amanmcp index')", r.DriftedFiles)
+	}
+
+	if r.Fixed != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "FIX APPLIED")
+		fmt.Fprintf(w, "├─ Deleted orphan BM25 entries:   %d\n", r.Fixed.DeletedOrphanBM25)
+		fmt.Fprintf(w, "├─ Deleted orphan vector entries: %d\n", r.Fixed.DeletedOrphanVectors)
+		fmt.Fprintf(w, "└─ Re-embedded chunks:            %d\n", r.Fixed.ReembeddedChunks)
+	}
+
+	if noColor {
+		fmt.Fprintln(w, "========================================")
+	} else {
+		fmt.Fprintln(w, "════════════════════════════════════════")
+	}
+	return nil
+}
+
+func printDoctorIDs(w io.Writer, label string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	sort.Strings(ids)
+	const maxShown = 10
+	fmt.Fprintf(w, "  %s (%d):\n", label, len(ids))
+	for i, id := range ids {
+		if i >= maxShown {
+			fmt.Fprintf(w, "    ... and %d more\n", len(ids)-maxShown)
+			break
+		}
+		fmt.Fprintf(w, "    - %s\n", id)
+	}
+}
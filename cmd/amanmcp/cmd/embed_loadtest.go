@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/embed/loadtest"
+)
+
+func newEmbedLoadtestCmd() *cobra.Command {
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "embed-loadtest",
+		Short: "Load-test the configured Embedder for throughput and tail latency",
+		Long: `Drive the project's configured Embedder with synthetic traffic shaped by
+--config, then print a JSON report of latency percentiles, throughput, and
+an error breakdown.
+
+This is for tuning PoolSize, BatchSize, TimeoutProgression, and
+RetryTimeoutMultiplier against real hardware, not for everyday use - it
+issues real embedding requests against whatever backend config.yaml
+configures for the current project.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configPath == "" {
+				return fmt.Errorf("--config is required")
+			}
+			return runEmbedLoadtest(cmd, configPath)
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "", "Path to a JSON load-test config file (required)")
+
+	return cmd
+}
+
+// embedLoadtestConfigFile is the on-disk shape of --config: the same
+// knobs as loadtest.Config, but with durations as plain seconds so the
+// file stays readable instead of carrying raw time.Duration nanosecond
+// counts.
+type embedLoadtestConfigFile struct {
+	Concurrency int     `json:"concurrency"`
+	DurationSec float64 `json:"duration_seconds"`
+	RPS         float64 `json:"rps"`
+
+	BatchSize  loadtest.Distribution `json:"batch_size"`
+	TextLength loadtest.Distribution `json:"text_length"`
+
+	RequestTimeoutSec      float64 `json:"request_timeout_seconds"`
+	TimeoutProgression     float64 `json:"timeout_progression"`
+	MaxRetries             int     `json:"max_retries"`
+	RetryBackoffSec        float64 `json:"retry_backoff_seconds"`
+	RetryTimeoutMultiplier float64 `json:"retry_timeout_multiplier"`
+}
+
+func (f embedLoadtestConfigFile) toLoadtestConfig() loadtest.Config {
+	return loadtest.Config{
+		Concurrency:            f.Concurrency,
+		Duration:               secondsToDuration(f.DurationSec),
+		RPS:                    f.RPS,
+		BatchSize:              f.BatchSize,
+		TextLength:             f.TextLength,
+		RequestTimeout:         secondsToDuration(f.RequestTimeoutSec),
+		TimeoutProgression:     f.TimeoutProgression,
+		MaxRetries:             f.MaxRetries,
+		RetryBackoff:           secondsToDuration(f.RetryBackoffSec),
+		RetryTimeoutMultiplier: f.RetryTimeoutMultiplier,
+	}
+}
+
+func runEmbedLoadtest(cmd *cobra.Command, configPath string) error {
+	ctx := cmd.Context()
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("read load-test config: %w", err)
+	}
+	var fileCfg embedLoadtestConfigFile
+	if err := json.Unmarshal(data, &fileCfg); err != nil {
+		return fmt.Errorf("parse load-test config: %w", err)
+	}
+
+	root, err := config.FindProjectRoot(".")
+	if err != nil {
+		return fmt.Errorf("failed to find project root: %w", err)
+	}
+	cfg, err := config.Load(root)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	embed.SetMLXConfig(embed.MLXServerConfig{
+		Endpoint: cfg.Embeddings.MLXEndpoint,
+		Model:    cfg.Embeddings.MLXModel,
+	})
+	provider := embed.ParseProvider(cfg.Embeddings.Provider)
+	embedder, err := embed.NewEmbedder(ctx, provider, cfg.Embeddings.Model)
+	if err != nil {
+		return fmt.Errorf("failed to create embedder: %w", err)
+	}
+	defer func() { _ = embedder.Close() }()
+
+	h := loadtest.NewHarness(embedder, fileCfg.toLoadtestConfig())
+	report, err := h.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("load test failed: %w", err)
+	}
+
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func secondsToDuration(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
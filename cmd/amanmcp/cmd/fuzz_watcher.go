@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Aman-CERP/amanmcp/internal/chunk"
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/index"
+	"github.com/Aman-CERP/amanmcp/internal/scanner"
+	"github.com/Aman-CERP/amanmcp/internal/search"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+	"github.com/Aman-CERP/amanmcp/internal/testharness"
+	"github.com/Aman-CERP/amanmcp/internal/watcher"
+)
+
+// newFuzzWatcherCmd is a hidden developer command: it runs
+// internal/testharness's fault-injection scenarios against the same
+// watcher -> coordinator pipeline startFileWatcher wires up in
+// production, so BUG-017/BUG-033/BUG-035/BUG-054-style races can be
+// reproduced on demand with a fixed seed instead of waiting for them to
+// resurface in production.
+func newFuzzWatcherCmd() *cobra.Command {
+	var seed int64
+	var burstSize int
+
+	cmd := &cobra.Command{
+		Use:    "fuzz-watcher",
+		Short:  "Fuzz the watcher/coordinator pipeline under simulated adversarial conditions",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFuzzWatcher(cmd.Context(), seed, burstSize)
+		},
+	}
+	cmd.Flags().Int64Var(&seed, "seed", 1, "Seed for the deterministic fault schedule")
+	cmd.Flags().IntVar(&burstSize, "burst-size", 0, "Events per scenario burst (0 uses the harness default)")
+
+	return cmd
+}
+
+func runFuzzWatcher(ctx context.Context, seed int64, burstSize int) error {
+	report, err := testharness.RunFuzz(ctx, testharness.Config{
+		Seed:           seed,
+		EventBurstSize: burstSize,
+	}, startFuzzPipeline)
+	if err != nil {
+		return fmt.Errorf("fuzz-watcher: %w", err)
+	}
+
+	fmt.Print(report.String())
+	if !report.Passed() {
+		return fmt.Errorf("fuzz-watcher: one or more scenarios failed invariants")
+	}
+	return nil
+}
+
+// startFuzzPipeline is a testharness.PipelineStarter. It builds the real
+// watcher/coordinator/engine pipeline against the (possibly fault-wrapped)
+// metadata store and embedder the harness hands it, wrapping the watcher
+// itself with testharness.FaultyWatcher when the scenario calls for
+// dropped events, so a fuzz run exercises the production wiring rather
+// than a simplified stand-in.
+func startFuzzPipeline(ctx context.Context, root, dataDir string, metadata store.MetadataStore, embedder embed.Embedder, rng *rand.Rand, faults testharness.FaultProbabilities) (func() testharness.IndexCounts, error) {
+	bm25, err := store.NewBM25IndexWithBackend(filepath.Join(dataDir, "bm25.db"), store.DefaultBM25Config(), "sqlite")
+	if err != nil {
+		return nil, fmt.Errorf("open bm25 index: %w", err)
+	}
+
+	vector, err := store.NewHNSWStore(store.DefaultVectorStoreConfig(embedder.Dimensions()))
+	if err != nil {
+		return nil, fmt.Errorf("create vector store: %w", err)
+	}
+
+	engine, err := search.NewEngine(bm25, vector, embedder, metadata, search.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("create engine: %w", err)
+	}
+
+	fileScanner, err := scanner.New()
+	if err != nil {
+		return nil, fmt.Errorf("create scanner: %w", err)
+	}
+	codeChunker := chunk.NewCodeChunker()
+	mdChunker := chunk.NewMarkdownChunker()
+
+	h := sha256.Sum256([]byte(root))
+	projectID := hex.EncodeToString(h[:])[:16]
+	coordinator := index.NewCoordinator(index.CoordinatorConfig{
+		ProjectID:   projectID,
+		RootPath:    root,
+		DataDir:     dataDir,
+		Engine:      engine,
+		Metadata:    metadata,
+		CodeChunker: codeChunker,
+		MDChunker:   mdChunker,
+		Scanner:     fileScanner,
+	})
+
+	baseWatcher, err := watcher.NewHybridWatcher(watcher.Options{
+		DebounceWindow:  200 * time.Millisecond,
+		PollInterval:    5 * time.Second,
+		EventBufferSize: 1000,
+	}.WithDefaults())
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+
+	var w watcher.Watcher = baseWatcher
+	if faults.DropEventProbability > 0 {
+		w = testharness.NewFaultyWatcher(baseWatcher, rng, faults.DropEventProbability)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error { return w.Start(gctx, root) })
+	g.Go(func() error {
+		defer func() {
+			_ = w.Stop()
+			codeChunker.Close()
+			mdChunker.Close()
+		}()
+		for {
+			select {
+			case <-gctx.Done():
+				return gctx.Err()
+			case events, ok := <-w.Events():
+				if !ok {
+					return nil
+				}
+				if len(events) > 0 {
+					if err := coordinator.HandleEvents(gctx, events); err != nil {
+						slog.Warn("fuzz_watcher_handle_events_error", slog.String("error", err.Error()))
+					}
+				}
+			case _, ok := <-w.Errors():
+				if !ok {
+					return nil
+				}
+			}
+		}
+	})
+
+	stop := func() testharness.IndexCounts {
+		_ = w.Stop()
+		_ = g.Wait()
+
+		ids, _ := bm25.AllIDs()
+		total, _, _ := metadata.GetEmbeddingStats(ctx)
+		counts := testharness.IndexCounts{
+			BM25Docs:       len(ids),
+			VectorCount:    vector.Count(),
+			MetadataChunks: total,
+		}
+		_ = bm25.Close()
+		_ = vector.Close()
+		return counts
+	}
+	return stop, nil
+}
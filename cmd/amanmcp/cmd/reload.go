@@ -0,0 +1,156 @@
+// SIGHUP used to share signal.NotifyContext's shutdown signal set, which
+// meant a client that can't send POSIX signals (or an operator on a
+// terminal that closes by SIGHUP) had no way to tweak search weights or
+// the MLX endpoint without a full restart. This file splits SIGHUP into
+// its own listener that reloads config.yaml and applies safe deltas in
+// place. An `amanmcp_reload_config` MCP tool exposing the same path to
+// clients that can't send signals belongs in internal/mcp, which isn't
+// part of this tree; reloadConfig is written so that tool only needs to
+// call it directly once that package exists.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/search"
+)
+
+// reloadState is the mutable, reload-affected state a running serve
+// process can swap out under reloadMu without restarting: the file
+// watcher, the HNSW index, and connected MCP clients all survive a
+// SIGHUP, unlike a full process restart.
+type reloadState struct {
+	mu       sync.Mutex
+	cfg      *config.Config
+	engine   *search.Engine
+	reranker search.Reranker
+	provider embed.Provider
+}
+
+// diffReload compares old and next, returning the safe deltas a reload
+// should apply and the names of any unsafe fields that changed
+// (embeddings.provider, embeddings.model, search.bm25_backend - any
+// change that would change the shape of embeddings already on disk),
+// which should be rejected with a logged warning instead of applied.
+func diffReload(old, next *config.Config) (weightsChanged bool, limitsChanged bool, mlxChanged bool, unsafe []string) {
+	if old.Search.BM25Weight != next.Search.BM25Weight || old.Search.SemanticWeight != next.Search.SemanticWeight || old.Search.RRFConstant != next.Search.RRFConstant {
+		weightsChanged = true
+	}
+	if old.Search.MaxResults != next.Search.MaxResults {
+		limitsChanged = true
+	}
+	if old.Embeddings.MLXEndpoint != next.Embeddings.MLXEndpoint {
+		mlxChanged = true
+	}
+
+	if old.Embeddings.Provider != next.Embeddings.Provider {
+		unsafe = append(unsafe, "embeddings.provider")
+	}
+	if old.Embeddings.Model != next.Embeddings.Model {
+		unsafe = append(unsafe, "embeddings.model")
+	}
+	if old.Search.BM25Backend != next.Search.BM25Backend {
+		unsafe = append(unsafe, "search.bm25_backend")
+	}
+	return weightsChanged, limitsChanged, mlxChanged, unsafe
+}
+
+// startConfigReloadWatcher listens for SIGHUP on its own signal channel
+// (separate from the shutdown context's NotifyContext, so a reload no
+// longer tears down the watcher/HNSW/MCP session) and applies safe config
+// deltas to state under state.mu. It runs until ctx is done.
+func startConfigReloadWatcher(ctx context.Context, root string, state *reloadState) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			reloadConfig(ctx, root, state)
+		}
+	}
+}
+
+// reloadConfig loads root's config fresh, diffs it against the config
+// currently in effect, applies any safe deltas, and logs a warning (rather
+// than applying) for every unsafe field diffReload reports as changed.
+func reloadConfig(ctx context.Context, root string, state *reloadState) {
+	next, err := config.Load(root)
+	if err != nil {
+		slog.Error("config_reload_failed", slog.String("error", err.Error()))
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	weightsChanged, limitsChanged, mlxChanged, unsafe := diffReload(state.cfg, next)
+
+	if len(unsafe) > 0 {
+		slog.Warn("config_reload_rejected_unsafe_fields",
+			slog.Any("fields", unsafe),
+			slog.String("note", "restart the server to apply these changes"))
+	}
+
+	if weightsChanged {
+		weights := search.Weights{BM25: next.Search.BM25Weight, Semantic: next.Search.SemanticWeight}
+		state.engine.UpdateWeights(weights, next.Search.RRFConstant)
+		slog.Info("config_reload_weights_updated",
+			slog.Float64("bm25_weight", weights.BM25),
+			slog.Float64("semantic_weight", weights.Semantic),
+			slog.Int("rrf_constant", next.Search.RRFConstant))
+	}
+
+	if limitsChanged {
+		state.engine.UpdateLimits(next.Search.MaxResults)
+		slog.Info("config_reload_limits_updated", slog.Int("max_results", next.Search.MaxResults))
+	}
+
+	if mlxChanged {
+		embed.SetMLXConfig(embed.MLXServerConfig{
+			Endpoint: next.Embeddings.MLXEndpoint,
+			Model:    next.Embeddings.MLXModel,
+		})
+		if err := swapMLXReranker(ctx, next, state); err != nil {
+			slog.Warn("config_reload_reranker_swap_failed", slog.String("error", err.Error()))
+		} else {
+			slog.Info("config_reload_mlx_endpoint_updated", slog.String("endpoint", next.Embeddings.MLXEndpoint))
+		}
+	}
+
+	state.cfg = next
+}
+
+// swapMLXReranker rebuilds the reranker against next's MLX endpoint and
+// hands it to state.engine, closing whichever reranker it replaces.
+func swapMLXReranker(ctx context.Context, next *config.Config, state *reloadState) error {
+	if state.provider != embed.ProviderMLX {
+		return nil
+	}
+	r, err := search.NewMLXReranker(ctx, search.MLXRerankerConfig{
+		Endpoint:        next.Embeddings.MLXEndpoint,
+		SkipHealthCheck: true,
+	})
+	if err != nil {
+		return fmt.Errorf("create reranker: %w", err)
+	}
+
+	old := state.reranker
+	state.engine.SwapReranker(r)
+	state.reranker = r
+	if old != nil {
+		_ = old.Close()
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Aman-CERP/amanmcp/internal/index"
 	"github.com/Aman-CERP/amanmcp/internal/logging"
 	"github.com/Aman-CERP/amanmcp/internal/mcp"
+	"github.com/Aman-CERP/amanmcp/internal/mcp/idletracker"
 	"github.com/Aman-CERP/amanmcp/internal/scanner"
 	"github.com/Aman-CERP/amanmcp/internal/search"
 	"github.com/Aman-CERP/amanmcp/internal/session"
@@ -62,6 +63,9 @@ func newServeCmd() *cobra.Command {
 	var port int
 	var sessionName string
 	var debug bool
+	var idleTimeout time.Duration
+	var projectsFile string
+	var noCache bool
 
 	cmd := &cobra.Command{
 		Use:   "serve",
@@ -108,14 +112,18 @@ Note: The cwd field is required for Claude Code to start the server in the corre
 				slog.Info("Debug logging enabled", slog.String("log_path", logging.DefaultLogPath()))
 			}
 
+			if projectsFile != "" {
+				return runServeFederated(cmd.Context(), projectsFile, transport, port, idleTimeout, noCache)
+			}
+
 			if sessionName != "" {
 				root, err := config.FindProjectRoot(".")
 				if err != nil {
 					return fmt.Errorf("failed to find project root: %w", err)
 				}
-				return runServeWithSession(cmd.Context(), sessionName, root, transport, port)
+				return runServeWithSession(cmd.Context(), sessionName, root, transport, port, idleTimeout, noCache)
 			}
-			return runServe(cmd.Context(), transport, port)
+			return runServe(cmd.Context(), transport, port, idleTimeout, noCache)
 		},
 	}
 
@@ -123,6 +131,11 @@ Note: The cwd field is required for Claude Code to start the server in the corre
 	cmd.Flags().IntVar(&port, "port", 8765, "Port for SSE transport")
 	cmd.Flags().StringVar(&sessionName, "session", "", "Named session to create/load")
 	cmd.Flags().BoolVar(&debug, "debug", false, "Enable debug logging to ~/.amanmcp/logs/server.log")
+	cmd.Flags().DurationVar(&idleTimeout, "idle-timeout", 0,
+		"Shut down after this long with no MCP traffic (0 disables, overrides server.idle_timeout in config.yaml)")
+	cmd.Flags().StringVar(&projectsFile, "projects", "",
+		"Path to a federation manifest (YAML list of {name, path, session}) to host several projects in one server")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Disable the on-disk embedding response cache")
 
 	return cmd
 }
@@ -144,7 +157,26 @@ func setupDebugLogging() (func(), error) {
 	return cleanup, nil
 }
 
-func runServe(ctx context.Context, transport string, port int) (err error) {
+// wireEmbedCache wraps embedder with embed.NewCachedEmbedder unless
+// noCache is set, backed by a BoltCache at dataDir/embed_cache.db so
+// repeated Embed/EmbedBatch calls across server restarts short-circuit
+// before hitting Ollama/MLX. A BoltCache open failure (e.g. dataDir not
+// yet created) degrades to an uncached embedder with a warning rather
+// than failing startup - the cache is a latency optimization, not a
+// correctness requirement.
+func wireEmbedCache(embedder embed.Embedder, dataDir string, noCache bool) embed.Embedder {
+	if noCache {
+		return embedder
+	}
+	cache, err := embed.NewBoltCache(filepath.Join(dataDir, "embed_cache.db"))
+	if err != nil {
+		slog.Warn("embed_cache_unavailable", slog.String("error", err.Error()))
+		return embedder
+	}
+	return embed.NewCachedEmbedder(embedder, cache)
+}
+
+func runServe(ctx context.Context, transport string, port int, idleTimeout time.Duration, noCache bool) (err error) {
 	// BUG-034: Initialize MCP-safe logging FIRST, before ANYTHING else.
 	// This ensures all logs go to file, never stdout/stderr.
 	// MCP protocol requires stdout to be used exclusively for JSON-RPC.
@@ -199,6 +231,10 @@ func runServe(ctx context.Context, transport string, port int) (err error) {
 	if transport == "" {
 		transport = cfg.Server.Transport
 	}
+	// Override idle timeout from config if the flag wasn't given
+	if idleTimeout == 0 {
+		idleTimeout = cfg.Server.IdleTimeout
+	}
 
 	// Data directory
 	dataDir := filepath.Join(root, ".amanmcp")
@@ -227,19 +263,35 @@ func runServe(ctx context.Context, transport string, port int) (err error) {
 
 	// Initialize stores
 	slog.Debug("Opening metadata store", slog.String("path", metadataPath))
-	metadata, err := store.NewSQLiteStore(metadataPath)
+	sqliteMetadata, err := store.NewSQLiteStore(metadataPath)
 	if err != nil {
 		return fmt.Errorf("failed to open metadata store: %w", err)
 	}
+	var metadata store.MetadataStore = sqliteMetadata
 	defer func() { _ = metadata.Close() }()
 
-	// ISSUE-02: Block serve if index is incomplete (checkpoint exists)
-	// Prevents race conditions between serve and index --resume
+	// ISSUE-02: index --resume may be running concurrently with serve.
+	// Rather than hard-fail, reopen metadata as a read-only snapshot and
+	// keep serving reads against it while the resume is in flight - a
+	// background watcher promotes back to read-write and lets the file
+	// watcher resume writes the moment the checkpoint reports "complete",
+	// so an operator never has to restart serve after `index --resume`.
 	checkpoint, checkpointErr := metadata.LoadIndexCheckpoint(ctx)
 	if checkpointErr == nil && checkpoint != nil && checkpoint.Stage != "" && checkpoint.Stage != "complete" {
-		return fmt.Errorf("incomplete index detected (stage=%s, %d/%d chunks embedded). "+
-			"Run 'amanmcp index --resume' to complete indexing before serving",
-			checkpoint.Stage, checkpoint.EmbeddedCount, checkpoint.Total)
+		slog.Warn("incomplete_index_detected_serving_readonly_snapshot",
+			slog.String("stage", checkpoint.Stage),
+			slog.Int("embedded", checkpoint.EmbeddedCount),
+			slog.Int("total", checkpoint.Total))
+		ro, roErr := store.NewSQLiteStoreReadOnlySnapshot(metadataPath)
+		if roErr != nil {
+			return fmt.Errorf("incomplete index detected (stage=%s, %d/%d chunks embedded) "+
+				"and failed to open read-only snapshot: %w",
+				checkpoint.Stage, checkpoint.EmbeddedCount, checkpoint.Total, roErr)
+		}
+		_ = sqliteMetadata.Close()
+		snapshotStore := store.NewSnapshotMetadataStore(ro)
+		metadata = snapshotStore
+		go watchSnapshotPromotion(ctx, metadataPath, snapshotStore)
 	}
 
 	// Use factory for BM25 backend selection (SQLite default for concurrent access)
@@ -266,6 +318,7 @@ func runServe(ctx context.Context, transport string, port int) (err error) {
 		return fmt.Errorf("failed to create embedder: %w", err)
 	}
 	defer func() { _ = embedder.Close() }()
+	embedder = wireEmbedCache(embedder, dataDir, noCache)
 
 	// FEAT-RR1: Initialize reranker if MLX provider is being used
 	var reranker search.Reranker
@@ -390,10 +443,25 @@ func runServe(ctx context.Context, transport string, port int) (err error) {
 	}
 	defer func() { _ = srv.Close() }()
 
-	// Handle graceful shutdown (DEBT-015: added SIGHUP for terminal close)
-	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	// Handle graceful shutdown. SIGHUP used to be in this list (DEBT-015:
+	// added for terminal close) but now triggers a live config reload
+	// instead (see startConfigReloadWatcher) - restarting on SIGHUP drops
+	// the file watcher and forces HNSW to reload from disk for no reason
+	// when only weights/limits/MLX endpoint changed.
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	reloadSt := &reloadState{cfg: cfg, engine: engine, reranker: reranker, provider: provider}
+	go startConfigReloadWatcher(ctx, root, reloadSt)
+
+	// Auto-shutdown after idleTimeout with no MCP traffic, so a client
+	// that exits without a proper shutdown doesn't leave this process
+	// running against a stale stdin pipe. idleTracker.WrapReader/WrapWriter
+	// are the hook point for srv's stdio/SSE transport loop to touch on
+	// every inbound/outbound message; idleTimeout == 0 leaves it disarmed.
+	idleTracker := idletracker.New(idleTimeout, cancel)
+	defer idleTracker.Stop()
+
 	// BUG-035: Start file watcher in background to avoid blocking MCP handshake.
 	// MCP protocol requires handshake response within 500ms. File watcher startup
 	// can take 2+ seconds on slow filesystems. Make it non-blocking so the MCP
@@ -594,7 +662,7 @@ func getWatcherStartupTimeout() time.Duration {
 
 // runServeWithSession runs the server with session management.
 // It creates or loads the named session and uses the session directory for index data.
-func runServeWithSession(ctx context.Context, sessionName, projectPath, transport string, port int) (err error) {
+func runServeWithSession(ctx context.Context, sessionName, projectPath, transport string, port int, idleTimeout time.Duration, noCache bool) (err error) {
 	// BUG-035/BUG-034 addendum: Initialize MCP-safe logging FIRST.
 	// This was a gap in BUG-034 - only runServe() had MCP logging.
 	// Without this, session mode would have stdout contamination.
@@ -690,21 +758,38 @@ func runServeWithSession(ctx context.Context, sessionName, projectPath, transpor
 	if transport == "" {
 		transport = projCfg.Server.Transport
 	}
+	// Override idle timeout from config if the flag wasn't given
+	if idleTimeout == 0 {
+		idleTimeout = projCfg.Server.IdleTimeout
+	}
 
 	// Initialize stores from session directory
-	metadata, err := store.NewSQLiteStore(sessionMetadataPath)
+	sqliteMetadata, err := store.NewSQLiteStore(sessionMetadataPath)
 	if err != nil {
 		return fmt.Errorf("failed to open metadata store: %w", err)
 	}
+	var metadata store.MetadataStore = sqliteMetadata
 	defer func() { _ = metadata.Close() }()
 
-	// ISSUE-02: Block serve if index is incomplete (checkpoint exists)
-	// Prevents race conditions between serve and index --resume
+	// ISSUE-02: see the equivalent block in runServe - degrade to a
+	// read-only snapshot instead of hard-failing when index --resume is
+	// running concurrently against this session's store.
 	checkpoint, checkpointErr := metadata.LoadIndexCheckpoint(ctx)
 	if checkpointErr == nil && checkpoint != nil && checkpoint.Stage != "" && checkpoint.Stage != "complete" {
-		return fmt.Errorf("incomplete index detected (stage=%s, %d/%d chunks embedded). "+
-			"Run 'amanmcp index --resume' to complete indexing before serving",
-			checkpoint.Stage, checkpoint.EmbeddedCount, checkpoint.Total)
+		slog.Warn("incomplete_index_detected_serving_readonly_snapshot",
+			slog.String("stage", checkpoint.Stage),
+			slog.Int("embedded", checkpoint.EmbeddedCount),
+			slog.Int("total", checkpoint.Total))
+		ro, roErr := store.NewSQLiteStoreReadOnlySnapshot(sessionMetadataPath)
+		if roErr != nil {
+			return fmt.Errorf("incomplete index detected (stage=%s, %d/%d chunks embedded) "+
+				"and failed to open read-only snapshot: %w",
+				checkpoint.Stage, checkpoint.EmbeddedCount, checkpoint.Total, roErr)
+		}
+		_ = sqliteMetadata.Close()
+		snapshotStore := store.NewSnapshotMetadataStore(ro)
+		metadata = snapshotStore
+		go watchSnapshotPromotion(ctx, sessionMetadataPath, snapshotStore)
 	}
 
 	// Use factory for BM25 backend selection (SQLite default for concurrent access)
@@ -730,6 +815,7 @@ func runServeWithSession(ctx context.Context, sessionName, projectPath, transpor
 		return fmt.Errorf("failed to create embedder: %w", err)
 	}
 	defer func() { _ = embedder.Close() }()
+	embedder = wireEmbedCache(embedder, dataDir, noCache)
 
 	// FEAT-RR1: Initialize reranker if MLX provider is being used (session mode)
 	var rerankerSession search.Reranker
@@ -849,10 +935,19 @@ func runServeWithSession(ctx context.Context, sessionName, projectPath, transpor
 	}
 	defer func() { _ = srv.Close() }()
 
-	// Handle graceful shutdown with session save (DEBT-015: added SIGHUP for terminal close)
-	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	// Handle graceful shutdown with session save. SIGHUP now drives a live
+	// config reload instead of a restart - see startConfigReloadWatcher.
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	reloadSt := &reloadState{cfg: projCfg, engine: engine, reranker: rerankerSession, provider: provider}
+	go startConfigReloadWatcher(ctx, projectPath, reloadSt)
+
+	// Auto-shutdown after idleTimeout with no MCP traffic (session mode);
+	// see the matching comment in runServe for the wrapping contract.
+	idleTracker := idletracker.New(idleTimeout, cancel)
+	defer idleTracker.Stop()
+
 	// Save session on shutdown if auto_save is enabled
 	if cfg.Sessions.AutoSave {
 		defer func() {
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/index"
+	"github.com/Aman-CERP/amanmcp/internal/logging"
+	"github.com/Aman-CERP/amanmcp/internal/mcp"
+	"github.com/Aman-CERP/amanmcp/internal/mcp/idletracker"
+	"github.com/Aman-CERP/amanmcp/internal/search"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// loadFederationConfig reads and parses a `serve --projects` manifest: a
+// YAML list of {name, path, session} entries, one per project this server
+// process should host.
+func loadFederationConfig(path string) (*search.FederationConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read federation manifest: %w", err)
+	}
+	var fc search.FederationConfig
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("parse federation manifest: %w", err)
+	}
+	if len(fc.Projects) == 0 {
+		return nil, fmt.Errorf("federation manifest %s lists no projects", path)
+	}
+	return &fc, nil
+}
+
+// federatedProject is everything runServeFederated needs to keep a single
+// project's engine alive and watched for the lifetime of the server.
+type federatedProject struct {
+	name     string
+	root     string
+	dataDir  string
+	engine   *search.Engine
+	metadata store.MetadataStore
+}
+
+// buildFederatedProject opens member's stores, runs the same quick
+// consistency check runServe does for a single project, and builds a
+// search.Engine sharing embedder/reranker with every other member so the
+// MLX model behind them is only ever loaded once.
+func buildFederatedProject(ctx context.Context, cfg *config.Config, member search.FederationMemberConfig, embedder embed.Embedder, reranker search.Reranker) (*federatedProject, error) {
+	root := member.Path
+	dataDir := filepath.Join(root, ".amanmcp")
+	if member.Session != "" {
+		dataDir = filepath.Join(root, ".amanmcp", "sessions", member.Session)
+	}
+
+	metadataPath := filepath.Join(dataDir, "metadata.db")
+	if _, err := os.Stat(metadataPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("project %q: no index found at %s, run 'amanmcp index' first", member.Name, root)
+	}
+
+	metadata, err := store.NewSQLiteStore(metadataPath)
+	if err != nil {
+		return nil, fmt.Errorf("project %q: open metadata store: %w", member.Name, err)
+	}
+
+	bm25, err := store.NewBM25IndexWithBackend(filepath.Join(dataDir, "bm25"), store.DefaultBM25Config(), cfg.Search.BM25Backend)
+	if err != nil {
+		_ = metadata.Close()
+		return nil, fmt.Errorf("project %q: open BM25 index: %w", member.Name, err)
+	}
+
+	vector, err := store.NewHNSWStore(store.DefaultVectorStoreConfig(embedder.Dimensions()))
+	if err != nil {
+		_ = metadata.Close()
+		_ = bm25.Close()
+		return nil, fmt.Errorf("project %q: create vector store: %w", member.Name, err)
+	}
+	vectorPath := filepath.Join(dataDir, "vectors.hnsw")
+	if _, err := os.Stat(vectorPath); err == nil {
+		if err := vector.Load(vectorPath); err != nil {
+			slog.Warn("federation_vector_load_failed",
+				slog.String("project", member.Name), slog.String("error", err.Error()))
+		}
+	}
+
+	consistencyChecker := index.NewConsistencyChecker(metadata, bm25, vector)
+	if consistent, err := consistencyChecker.QuickCheck(ctx); err != nil {
+		slog.Warn("federation_consistency_check_failed",
+			slog.String("project", member.Name), slog.String("error", err.Error()))
+	} else if !consistent {
+		slog.Warn("federation_index_consistency_mismatch_detected", slog.String("project", member.Name))
+	}
+
+	engineCfg := search.EngineConfig{
+		DefaultLimit:   cfg.Search.MaxResults,
+		MaxLimit:       100,
+		DefaultWeights: search.Weights{BM25: cfg.Search.BM25Weight, Semantic: cfg.Search.SemanticWeight},
+		RRFConstant:    cfg.Search.RRFConstant,
+		SearchTimeout:  search.DefaultConfig().SearchTimeout,
+	}
+	var engineOpts []search.EngineOption
+	if reranker != nil {
+		engineOpts = append(engineOpts, search.WithReranker(reranker))
+	}
+	engine, err := search.NewEngine(bm25, vector, embedder, metadata, engineCfg, engineOpts...)
+	if err != nil {
+		_ = metadata.Close()
+		_ = bm25.Close()
+		return nil, fmt.Errorf("project %q: create search engine: %w", member.Name, err)
+	}
+
+	return &federatedProject{name: member.Name, root: root, dataDir: dataDir, engine: engine, metadata: metadata}, nil
+}
+
+// runServeFederated hosts several indexed projects in one MCP server
+// process: one search.Engine per project, sharing a single embedder and
+// reranker to avoid duplicate model loads, fanned out by a
+// search.Federation that the search tool's project filter and the
+// list_projects tool both read from.
+func runServeFederated(ctx context.Context, projectsFile, transport string, port int, idleTimeout time.Duration, noCache bool) (err error) {
+	mcpLogCleanup, logErr := logging.SetupMCPMode()
+	if logErr != nil {
+		return fmt.Errorf("failed to setup MCP logging: %w", logErr)
+	}
+	defer mcpLogCleanup()
+
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Server panic recovered (federated mode)",
+				slog.Any("panic", r), slog.String("stack", string(debug.Stack())))
+			err = fmt.Errorf("server panic: %v", r)
+		}
+	}()
+
+	fedCfg, loadErr := loadFederationConfig(projectsFile)
+	if loadErr != nil {
+		return loadErr
+	}
+
+	cfg := config.NewConfig()
+
+	embed.SetMLXConfig(embed.MLXServerConfig{
+		Endpoint: cfg.Embeddings.MLXEndpoint,
+		Model:    cfg.Embeddings.MLXModel,
+	})
+	provider := embed.ParseProvider(cfg.Embeddings.Provider)
+	embedder, err := embed.NewEmbedder(ctx, provider, cfg.Embeddings.Model)
+	if err != nil {
+		return fmt.Errorf("failed to create shared embedder: %w", err)
+	}
+	defer func() { _ = embedder.Close() }()
+	// Shared across every federated project, so a per-project BoltCache
+	// path doesn't apply here the way it does for wireEmbedCache's
+	// single-project callers; a MemoryCache still short-circuits repeat
+	// Embed/EmbedBatch calls within this process's lifetime.
+	if !noCache {
+		embedder = embed.NewCachedEmbedder(embedder, embed.NewMemoryCache(0))
+	}
+
+	var reranker search.Reranker
+	if provider == embed.ProviderMLX {
+		r, rerankErr := search.NewMLXReranker(ctx, search.MLXRerankerConfig{
+			Endpoint:        cfg.Embeddings.MLXEndpoint,
+			SkipHealthCheck: true,
+		})
+		if rerankErr != nil {
+			slog.Warn("Shared reranker unavailable, federated search results will not be reranked",
+				slog.String("error", rerankErr.Error()))
+		} else {
+			reranker = r
+			defer func() { _ = reranker.Close() }()
+		}
+	}
+
+	projects := make([]*federatedProject, 0, len(fedCfg.Projects))
+	members := make([]search.FederationMember, 0, len(fedCfg.Projects))
+	defer func() {
+		for _, p := range projects {
+			_ = p.engine.Close()
+			_ = p.metadata.Close()
+		}
+	}()
+	for _, entry := range fedCfg.Projects {
+		p, buildErr := buildFederatedProject(ctx, cfg, entry, embedder, reranker)
+		if buildErr != nil {
+			return buildErr
+		}
+		projects = append(projects, p)
+		members = append(members, search.FederationMember{Name: p.name, Path: p.root, Engine: p.engine})
+		slog.Info("federation_project_loaded", slog.String("project", p.name), slog.String("root", p.root))
+	}
+
+	federation := search.NewFederation(members, cfg.Search.RRFConstant)
+
+	srv, err := mcp.NewFederatedServer(federation, metadataStores(projects), embedder, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create federated MCP server: %w", err)
+	}
+	defer func() { _ = srv.Close() }()
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if idleTimeout > 0 {
+		idleTracker := idletracker.New(idleTimeout, cancel)
+		defer idleTracker.Stop()
+	}
+
+	excludeSuffix := "**/.amanmcp/**"
+	for _, p := range projects {
+		p := p
+		go func() {
+			slog.Debug("Starting federated file watcher", slog.String("project", p.name), slog.String("root", p.root))
+			excludePatterns := append(append([]string{}, cfg.Paths.Exclude...), excludeSuffix)
+			if err := startFileWatcher(ctx, p.root, p.dataDir, p.engine, p.metadata, false, excludePatterns); err != nil {
+				slog.Error("Federated file watcher failed to start (non-fatal, search still works)",
+					slog.String("project", p.name), slog.String("error", err.Error()))
+				return
+			}
+			slog.Info("Federated file watcher running", slog.String("project", p.name))
+		}()
+	}
+
+	slog.Info("Federated MCP server ready",
+		slog.String("transport", transport), slog.Int("projects", len(projects)))
+	addr := fmt.Sprintf(":%d", port)
+	return srv.Serve(ctx, transport, addr)
+}
+
+// metadataStores collects each project's metadata store, keyed by project
+// name, for mcp.NewFederatedServer to route per-project admin operations
+// (e.g. list_projects detail) without re-deriving it from the Federation.
+func metadataStores(projects []*federatedProject) map[string]store.MetadataStore {
+	out := make(map[string]store.MetadataStore, len(projects))
+	for _, p := range projects {
+		out[p.name] = p.metadata
+	}
+	return out
+}
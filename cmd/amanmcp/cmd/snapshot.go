@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Aman-CERP/amanmcp/internal/config"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// snapshotManifestVersion is bumped whenever SnapshotManifest's shape
+// changes in a way that breaks older restore code.
+const snapshotManifestVersion = 1
+
+// snapshotManifestName is the tar entry name the manifest is always
+// written under, and always first, so restore can validate the bundle's
+// embedder/config context before streaming any of the (potentially much
+// larger) component files.
+const snapshotManifestName = "manifest.json"
+
+// SnapshotManifest describes a snapshot bundle's contents and the
+// embedder/config context it was built under.
+type SnapshotManifest struct {
+	Version            int                 `json:"version"`
+	CreatedAt          time.Time           `json:"created_at"`
+	EmbedderProvider   string              `json:"embedder_provider"`
+	EmbedderModel      string              `json:"embedder_model"`
+	EmbedderDimensions int                 `json:"embedder_dimensions"`
+	GitCommit          string              `json:"git_commit,omitempty"`
+	ChunkerVersions    map[string]string   `json:"chunker_versions,omitempty"`
+	Components         []SnapshotComponent `json:"components"`
+}
+
+// SnapshotComponent is one file bundled into the snapshot, with a
+// SHA-256 of its content so restore can detect truncation or corruption
+// in transit before it's served as a live index.
+type SnapshotComponent struct {
+	// Name is the tar entry path, relative to the bundle root.
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Create or restore portable index bundles",
+		Long: `Package an index (metadata DB, BM25 index, vector store) into a single
+versioned tarball, or restore one back onto disk.
+
+Snapshots let teams share pre-built indices for large monorepos, ship
+indices alongside CI artifacts, and roll back after a bad reindex without
+requiring the receiver to re-run embeddings.`,
+	}
+	cmd.AddCommand(newSnapshotCreateCmd())
+	cmd.AddCommand(newSnapshotRestoreCmd())
+	return cmd
+}
+
+func newSnapshotCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create <path>",
+		Short: "Bundle the current index into a snapshot tarball",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := config.FindProjectRoot(".")
+			if err != nil {
+				return fmt.Errorf("failed to find project root: %w", err)
+			}
+			return runSnapshotCreate(cmd.Context(), root, args[0])
+		},
+	}
+}
+
+func newSnapshotRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <path>",
+		Short: "Restore a snapshot tarball onto disk",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := config.FindProjectRoot(".")
+			if err != nil {
+				return fmt.Errorf("failed to find project root: %w", err)
+			}
+			return runSnapshotRestore(cmd.Context(), root, args[0])
+		},
+	}
+}
+
+// snapshotComponentPaths returns the on-disk index files a snapshot
+// bundles, relative to dataDir, in the same layout runServe and debug.go
+// use: metadata.db, whichever BM25 backend file/dir is present, and the
+// HNSW vector file. Entries that don't exist (e.g. no BM25 index built
+// yet) are skipped rather than failing the snapshot.
+func snapshotComponentPaths(dataDir string) []string {
+	candidates := []string{"metadata.db", "bm25.db", "bm25.bleve", "vectors.hnsw"}
+	var present []string
+	for _, name := range candidates {
+		if _, err := os.Stat(filepath.Join(dataDir, name)); err == nil {
+			present = append(present, name)
+		}
+	}
+	return present
+}
+
+// runSnapshotCreate writes a gzipped tarball to outPath containing
+// manifest.json followed by every present component. Checksums are
+// computed in a first, read-only pass so the manifest (which must come
+// first in the tar so restore can validate it before streaming the rest)
+// can be written before any component's bytes, without buffering an
+// entire 10GB+ index in memory.
+func runSnapshotCreate(ctx context.Context, root, outPath string) error {
+	cfg, err := config.Load(root)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dataDir := filepath.Join(root, ".amanmcp")
+	names := snapshotComponentPaths(dataDir)
+	if len(names) == 0 {
+		return fmt.Errorf("no index found in %s, run 'amanmcp index' first", root)
+	}
+
+	components := make([]SnapshotComponent, 0, len(names))
+	for _, name := range names {
+		size, sum, err := sha256File(filepath.Join(dataDir, name))
+		if err != nil {
+			return fmt.Errorf("hash %s: %w", name, err)
+		}
+		components = append(components, SnapshotComponent{Name: name, Size: size, SHA256: sum})
+	}
+
+	// The embedder model/dimensions actually used to build this index live
+	// in metadata.db's state table, not config.yaml - config may have moved
+	// on since the index was built. This is the same state GetIndexInfo and
+	// runServe's storedModel comparison read from.
+	metadataStore, err := store.NewSQLiteStore(filepath.Join(dataDir, "metadata.db"))
+	if err != nil {
+		return fmt.Errorf("open metadata store: %w", err)
+	}
+	indexInfo, err := store.GetIndexInfo(ctx, metadataStore, dataDir, nil)
+	_ = metadataStore.Close()
+	if err != nil {
+		return fmt.Errorf("read index info: %w", err)
+	}
+
+	embedderModel := indexInfo.IndexModel
+	if embedderModel == "" {
+		embedderModel = cfg.Embeddings.Model
+	}
+
+	manifest := SnapshotManifest{
+		Version:            snapshotManifestVersion,
+		CreatedAt:          time.Now(),
+		EmbedderProvider:   cfg.Embeddings.Provider,
+		EmbedderModel:      embedderModel,
+		EmbedderDimensions: indexInfo.IndexDimensions,
+		GitCommit:          gitCommitAt(root),
+		Components:         components,
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", outPath, err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: snapshotManifestName, Mode: 0o644, Size: int64(len(manifestJSON))}); err != nil {
+		return fmt.Errorf("write manifest header: %w", err)
+	}
+	if _, err := tw.Write(manifestJSON); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	for _, c := range components {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writeSnapshotComponent(tw, filepath.Join(dataDir, c.Name), c); err != nil {
+			return fmt.Errorf("write %s: %w", c.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// writeSnapshotComponent streams src into tw under a header matching c,
+// copying directly from disk to the tar writer (which sits on top of the
+// gzip writer on top of the output file) so the component's full content
+// is never held in memory at once.
+func writeSnapshotComponent(tw *tar.Writer, src string, c SnapshotComponent) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(&tar.Header{Name: c.Name, Mode: 0o644, Size: c.Size}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// runSnapshotRestore reads bundlePath and writes its components into
+// root's .amanmcp directory. The manifest (the tar's first entry) is
+// validated before any component is written: a bundle whose embedder
+// provider/model doesn't match the receiving project's current config is
+// rejected outright, the same "stored vs. current model" check runServe
+// uses to avoid serving mixed embeddings.
+func runSnapshotRestore(ctx context.Context, root, bundlePath string) error {
+	cfg, err := config.Load(root)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", bundlePath, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("open gzip stream: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return fmt.Errorf("read manifest header: %w", err)
+	}
+	if hdr.Name != snapshotManifestName {
+		return fmt.Errorf("malformed snapshot: expected first entry %q, got %q", snapshotManifestName, hdr.Name)
+	}
+
+	var manifest SnapshotManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return fmt.Errorf("decode manifest: %w", err)
+	}
+
+	if manifest.EmbedderProvider != cfg.Embeddings.Provider || manifest.EmbedderModel != cfg.Embeddings.Model {
+		return fmt.Errorf("snapshot embedder %s/%s does not match current config %s/%s; "+
+			"restoring would mix embeddings from different models",
+			manifest.EmbedderProvider, manifest.EmbedderModel,
+			cfg.Embeddings.Provider, cfg.Embeddings.Model)
+	}
+
+	dataDir := filepath.Join(root, ".amanmcp")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", dataDir, err)
+	}
+
+	expected := make(map[string]SnapshotComponent, len(manifest.Components))
+	for _, c := range manifest.Components {
+		expected[c.Name] = c
+	}
+
+	seen := make(map[string]bool, len(expected))
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read tar entry: %w", err)
+		}
+		c, ok := expected[hdr.Name]
+		if !ok {
+			return fmt.Errorf("snapshot entry %q is not listed in manifest", hdr.Name)
+		}
+		if err := restoreSnapshotComponent(tr, filepath.Join(dataDir, hdr.Name), c); err != nil {
+			return fmt.Errorf("restore %s: %w", hdr.Name, err)
+		}
+		seen[hdr.Name] = true
+	}
+
+	for name := range expected {
+		if !seen[name] {
+			return fmt.Errorf("snapshot is missing component %q listed in its manifest", name)
+		}
+	}
+
+	return nil
+}
+
+// restoreSnapshotComponent writes tr's current entry to dst while
+// computing its SHA-256, failing if the written bytes don't match c's
+// recorded checksum (a truncated or corrupted transfer).
+func restoreSnapshotComponent(tr *tar.Reader, dst string, c SnapshotComponent) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, h), tr); err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if sum != c.SHA256 {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", c.SHA256, sum)
+	}
+	return nil
+}
+
+// sha256File hashes path without holding its full content in memory.
+func sha256File(path string) (size int64, sum string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// gitCommitAt returns the short commit hash HEAD points to in root, or
+// "" if root isn't a git repository (or git isn't installed) - this is
+// recorded for provenance only, so its absence shouldn't fail a snapshot.
+func gitCommitAt(root string) string {
+	out, err := exec.Command("git", "-C", root, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
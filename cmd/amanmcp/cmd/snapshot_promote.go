@@ -0,0 +1,90 @@
+// Companion to the read-only snapshot path in serve.go's ISSUE-02 handling:
+// once serve degrades to a read-only metadata snapshot because
+// `index --resume` is running concurrently, this file polls the
+// checkpoint until it reports "complete" and promotes the store back to
+// read-write in place, so the operator never has to restart serve.
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/search"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// snapshotPromotionPollInterval is how often watchSnapshotPromotion
+// re-checks the checkpoint. There's no practical way to get inotify on a
+// single SQLite row, so this polls the same read-only connection the
+// snapshot is already serving reads from, matching Reconciler's own
+// ticker-based retry loop.
+const snapshotPromotionPollInterval = 5 * time.Second
+
+// watchSnapshotPromotion polls snap's current (read-only) connection for
+// LoadIndexCheckpoint to report stage "complete", then reopens
+// metadataPath read-write and calls snap.PromoteReadWrite so every
+// holder of snap (the engine, the file watcher's coordinator, the MCP
+// server) starts seeing writes succeed without any of them needing to
+// know the swap happened. It runs until ctx is done.
+func watchSnapshotPromotion(ctx context.Context, metadataPath string, snap *store.SnapshotMetadataStore) {
+	ticker := time.NewTicker(snapshotPromotionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkpoint, err := snap.LoadIndexCheckpoint(ctx)
+			if err != nil {
+				slog.Debug("snapshot_promotion_checkpoint_check_failed", slog.String("error", err.Error()))
+				continue
+			}
+			if checkpoint != nil && checkpoint.Stage != "" && checkpoint.Stage != "complete" {
+				continue
+			}
+
+			rw, err := store.NewSQLiteStore(metadataPath)
+			if err != nil {
+				slog.Warn("snapshot_promotion_reopen_failed", slog.String("error", err.Error()))
+				continue
+			}
+			previous := snap.PromoteReadWrite(rw)
+			if previous != nil {
+				_ = previous.Close()
+			}
+			slog.Info("snapshot_promoted_to_read_write",
+				slog.String("note", "writes and reconciliation resume without a restart"))
+			return
+		}
+	}
+}
+
+// currentSnapshotInfo builds the search.SnapshotInfo a future
+// internal/mcp `index_status` tool would serve: whether this process is
+// still degraded to the read-only snapshot, and how far the concurrent
+// reindex has gotten. internal/mcp isn't part of this tree, so there's
+// no tool to register it with yet; this is written so that tool only
+// needs to call it directly once that package exists, the same
+// deferred-wiring shape reload.go's reloadConfig uses for
+// amanmcp_reload_config.
+func currentSnapshotInfo(ctx context.Context, metadata store.MetadataStore) search.SnapshotInfo {
+	snap, degraded := metadata.(*store.SnapshotMetadataStore)
+	info := search.SnapshotInfo{Degraded: degraded && snap.IsReadOnly()}
+
+	checkpoint, err := metadata.LoadIndexCheckpoint(ctx)
+	if err == nil && checkpoint != nil {
+		info.Stage = checkpoint.Stage
+		info.EmbeddedCount = checkpoint.EmbeddedCount
+		info.Total = checkpoint.Total
+	}
+
+	if projectID, err := metadata.GetState(ctx, "project_id"); err == nil && projectID != "" {
+		if project, err := metadata.GetProject(ctx, projectID); err == nil && project != nil {
+			info.LastIndexedAt = project.IndexedAt
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// usageMaxWorkers bounds how many goroutines stat files concurrently
+// during a crawlUsage run.
+const usageMaxWorkers = 8
+
+// usageTopN is how many of the largest files crawlUsage keeps.
+const usageTopN = 10
+
+// UsageReport is a crawled, measured snapshot of an index's on-disk and
+// in-memory footprint - the replacement for collectDebugInfo's old
+// file-size-times-heuristic-factor MemoryEstimate and three-file
+// TotalSizeBytes sum, which missed WAL/SHM files, bleve segment files,
+// and anything else living under dataDir.
+type UsageReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	TotalBytes  int64     `json:"total_bytes"`
+	ObjectCount int       `json:"object_count"`
+	// Collections totals files by which part of the index they belong
+	// to: "metadata", "bm25", "hnsw", "wal", or "other".
+	Collections  map[string]int64 `json:"collections"`
+	LargestFiles []UsageFile      `json:"largest_files"`
+	// ProcessRSSBytes is this process's own memory footprint, read from
+	// runtime.MemStats.Sys - an approximation of resident memory, not a
+	// true OS-reported RSS (Go doesn't expose that without cgo).
+	ProcessRSSBytes uint64 `json:"process_rss_bytes"`
+	// HNSWResidentBytes is a best-effort count of how much of
+	// vectors.hnsw is currently resident in the page cache, via
+	// mincore(2) on Linux. Zero on platforms without an implementation,
+	// or if the file doesn't exist yet.
+	HNSWResidentBytes int64 `json:"hnsw_resident_bytes"`
+}
+
+// UsageFile is one entry in UsageReport.LargestFiles.
+type UsageFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// crawlUsage walks dataDir with a bounded worker pool (similar in spirit
+// to the concurrent recursive size crawlers object-storage tools use for
+// bucket usage reports), stat'ing every file to build a UsageReport. A
+// cached report is returned instead if dataDir's top-level entries
+// haven't changed mtime/size since the last crawl.
+func crawlUsage(ctx context.Context, dataDir string) (*UsageReport, error) {
+	key, keyErr := usageCacheKey(dataDir)
+	if keyErr == nil {
+		if cached, ok := loadUsageCache(dataDir, key); ok {
+			return cached, nil
+		}
+	}
+
+	var paths []string
+	err := filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Best-effort: a file that disappears mid-walk or a
+			// permission error shouldn't fail the whole report.
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", dataDir, err)
+	}
+
+	sizes, err := statPathsConcurrently(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{
+		GeneratedAt: time.Now(),
+		Collections: make(map[string]int64),
+	}
+	var largest []UsageFile
+	for path, size := range sizes {
+		report.TotalBytes += size
+		report.ObjectCount++
+		report.Collections[classifyUsageFile(dataDir, path)] += size
+		largest = pushLargestUsageFile(largest, UsageFile{Path: path, Size: size}, usageTopN)
+	}
+	sort.Slice(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+	report.LargestFiles = largest
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	report.ProcessRSSBytes = mem.Sys
+
+	if resident, err := mincoreResidentBytes(filepath.Join(dataDir, "vectors.hnsw")); err == nil {
+		report.HNSWResidentBytes = resident
+	}
+
+	if keyErr == nil {
+		saveUsageCache(dataDir, key, report)
+	}
+	return report, nil
+}
+
+// statPathsConcurrently stats every path in paths using a bounded pool of
+// usageMaxWorkers goroutines, returning a map of path to size. A path
+// that fails to stat (removed mid-walk) is silently omitted.
+func statPathsConcurrently(ctx context.Context, paths []string) (map[string]int64, error) {
+	workers := usageMaxWorkers
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	if workers < 1 {
+		return map[string]int64{}, nil
+	}
+
+	jobs := make(chan string)
+	type statResult struct {
+		path string
+		size int64
+	}
+	results := make(chan statResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				info, err := os.Stat(path)
+				if err != nil {
+					continue
+				}
+				select {
+				case results <- statResult{path: path, size: info.Size()}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, p := range paths {
+			select {
+			case jobs <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	sizes := make(map[string]int64, len(paths))
+	for res := range results {
+		sizes[res.path] = res.size
+	}
+	return sizes, ctx.Err()
+}
+
+// classifyUsageFile buckets path (relative to dataDir) into the
+// collection it reports storage for.
+func classifyUsageFile(dataDir, path string) string {
+	rel, err := filepath.Rel(dataDir, path)
+	if err != nil {
+		rel = path
+	}
+	base := filepath.Base(rel)
+
+	switch {
+	case strings.HasSuffix(base, "-wal"), strings.HasSuffix(base, "-shm"), strings.HasSuffix(base, "-journal"):
+		return "wal"
+	case strings.HasPrefix(rel, "bm25"):
+		return "bm25"
+	case strings.HasPrefix(rel, "vectors.hnsw"), strings.Contains(rel, "hnsw"):
+		return "hnsw"
+	case strings.HasPrefix(base, "metadata.db"):
+		return "metadata"
+	default:
+		return "other"
+	}
+}
+
+// pushLargestUsageFile keeps at most n UsageFiles, the largest seen so
+// far - cheap to re-sort on every call given dataDir's file count is
+// small (dozens, not millions).
+func pushLargestUsageFile(largest []UsageFile, f UsageFile, n int) []UsageFile {
+	largest = append(largest, f)
+	sort.Slice(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+	if len(largest) > n {
+		largest = largest[:n]
+	}
+	return largest
+}
+
+// usageCacheFileName is the cache file crawlUsage reads/writes under
+// dataDir, so repeated debug calls in the same session skip the
+// walk entirely once nothing has changed.
+const usageCacheFileName = ".usage_cache.json"
+
+type usageCacheEntry struct {
+	Key    string       `json:"key"`
+	Report *UsageReport `json:"report"`
+}
+
+// usageCacheKey hashes the name, size, and mtime of dataDir's top-level
+// entries. It deliberately doesn't walk recursively - that would cost as
+// much as the crawl it's meant to let callers skip - so a change nested
+// deep inside (e.g. a new bleve segment file) only invalidates the cache
+// once its containing top-level entry's own mtime moves, which is the
+// same trade-off a directory's mtime always makes.
+func usageCacheKey(dataDir string) (string, error) {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		// The cache file is rewritten after every crawl; hashing it would
+		// change the key each time and the cache would never hit.
+		if e.Name() == usageCacheFileName {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dataDir, name))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s:%d:%d\n", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func usageCachePath(dataDir string) string {
+	return filepath.Join(dataDir, usageCacheFileName)
+}
+
+func loadUsageCache(dataDir, key string) (*UsageReport, bool) {
+	raw, err := os.ReadFile(usageCachePath(dataDir))
+	if err != nil {
+		return nil, false
+	}
+	var entry usageCacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, false
+	}
+	if entry.Key != key {
+		return nil, false
+	}
+	return entry.Report, true
+}
+
+func saveUsageCache(dataDir, key string, report *UsageReport) {
+	raw, err := json.Marshal(usageCacheEntry{Key: key, Report: report})
+	if err != nil {
+		return
+	}
+	// Best-effort: a failure to cache shouldn't fail the debug call that
+	// triggered the crawl.
+	_ = os.WriteFile(usageCachePath(dataDir), raw, 0o644)
+}
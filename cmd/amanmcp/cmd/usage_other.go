@@ -0,0 +1,9 @@
+//go:build !linux
+
+package cmd
+
+// mincoreResidentBytes has no implementation outside Linux; callers treat
+// a zero result as "unknown" rather than "nothing resident".
+func mincoreResidentBytes(path string) (int64, error) {
+	return 0, nil
+}
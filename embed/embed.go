@@ -0,0 +1,149 @@
+// Package embed exposes the hybrid-search stack as an in-process Go
+// library, so other Go tools (editor plugins, custom agents, evaluation
+// harnesses) can run Search/Index directly against an already-open set of
+// stores instead of spawning `amanmcp serve` and speaking MCP JSON-RPC
+// over a subprocess. cmd/amanmcp's serve command builds the same stack
+// from on-disk config and paths; this package assumes the caller has
+// already opened whatever stores and embedder it wants and just wires
+// them into a search.Engine.
+package embed
+
+import (
+	"context"
+	"fmt"
+
+	searchembed "github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/search"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// Indexer performs a full or incremental index of root into the stores an
+// Instance was built with. internal/index.Coordinator satisfies this
+// interface; Instance depends on the interface rather than that concrete
+// type so embedding the search stack doesn't require pulling in the
+// chunker/scanner/watcher wiring unless the caller actually wants Index.
+type Indexer interface {
+	Index(ctx context.Context, root string) error
+}
+
+// Config wires the already-open stores and embedder an Instance runs
+// against. It mirrors the wiring cmd/amanmcp's runServe performs for the
+// MCP server, but takes already-open stores rather than paths, since a
+// library caller may already own its own store lifecycle (or be running
+// tests against in-memory ones).
+type Config struct {
+	Metadata store.MetadataStore
+	BM25     store.BM25Index
+	Vector   store.VectorStore
+	Embedder searchembed.Embedder
+
+	Engine     search.EngineConfig
+	EngineOpts []search.EngineOption
+
+	// Indexer, if set, backs Instance.Index. Left nil, Index returns
+	// ErrNoIndexer - an Instance built for search-only use (e.g. a
+	// read-only evaluation harness) shouldn't need to construct one.
+	Indexer Indexer
+}
+
+// ErrNoIndexer is returned by Instance.Index when Config.Indexer was nil.
+var ErrNoIndexer = fmt.Errorf("embed: no Indexer configured")
+
+// Hit is one ranked search result, the library-friendly projection of
+// search.SearchResult.
+type Hit struct {
+	Chunk *store.Chunk
+	Score float64
+}
+
+// EventKind identifies what changed in an Event delivered on
+// Instance.Events.
+type EventKind int
+
+const (
+	// EventIndexed means the chunk at Event.Path was (re)indexed.
+	EventIndexed EventKind = iota
+	// EventRemoved means the chunk at Event.Path was removed from the index.
+	EventRemoved
+)
+
+// Event is one index-change notification delivered on Instance.Events.
+// Instance.Index itself emits no events; only Instance.Notify puts events
+// on the channel, so a caller driving its own file watcher calls Notify
+// to surface index changes to anything consuming Events.
+type Event struct {
+	Kind EventKind
+	Path string
+}
+
+// Instance is a running, in-process instance of the hybrid-search stack.
+// The zero value is not usable; construct one with New.
+type Instance struct {
+	engine  *search.Engine
+	indexer Indexer
+	events  chan Event
+}
+
+// New wires cfg's stores and embedder into a search.Engine and returns a
+// ready-to-use Instance. Unlike runServe, New does not write a PID file or
+// start a file watcher of its own - Index runs synchronously, and a caller
+// that wants live updates should drive it from their own watcher loop.
+func New(cfg Config) (*Instance, error) {
+	engine, err := search.NewEngine(cfg.BM25, cfg.Vector, cfg.Embedder, cfg.Metadata, cfg.Engine, cfg.EngineOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("embed: create engine: %w", err)
+	}
+	return &Instance{
+		engine:  engine,
+		indexer: cfg.Indexer,
+		events:  make(chan Event, 64),
+	}, nil
+}
+
+// Search runs a hybrid search query against the in-process engine.
+func (i *Instance) Search(ctx context.Context, query string, opts search.SearchOptions) ([]Hit, error) {
+	results, err := i.engine.Search(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]Hit, len(results))
+	for idx, r := range results {
+		hits[idx] = Hit{Chunk: r.Chunk, Score: r.Score}
+	}
+	return hits, nil
+}
+
+// Index runs a full or incremental index of root using Config.Indexer,
+// returning ErrNoIndexer if none was configured.
+func (i *Instance) Index(ctx context.Context, root string) error {
+	if i.indexer == nil {
+		return ErrNoIndexer
+	}
+	return i.indexer.Index(ctx, root)
+}
+
+// Events returns the channel Instance delivers index-change notifications
+// on. The channel is never closed by Instance; callers that want to stop
+// listening should simply stop reading from it.
+func (i *Instance) Events() <-chan Event {
+	return i.events
+}
+
+// Notify delivers an Event to anything listening on Events. A caller
+// driving its own file watcher calls this after an Index call to surface
+// the change, the same way the MCP server's background watcher would feed
+// index updates to connected clients. Events is buffered (64 events);
+// Notify drops the event rather than blocking when that buffer is full.
+func (i *Instance) Notify(event Event) {
+	select {
+	case i.events <- event:
+	default:
+	}
+}
+
+// Close releases the underlying search.Engine. It does not close the
+// stores or embedder Config supplied - Instance never owned them, and
+// closing shared resources is the caller's responsibility.
+func (i *Instance) Close() error {
+	return i.engine.Close()
+}
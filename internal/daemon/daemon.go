@@ -14,6 +14,7 @@ import (
 
 	"github.com/Aman-CERP/amanmcp/internal/config"
 	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/mcp/logging"
 	"github.com/Aman-CERP/amanmcp/internal/search"
 	"github.com/Aman-CERP/amanmcp/internal/store"
 )
@@ -30,9 +31,25 @@ type Daemon struct {
 	reranker   search.Reranker       // FEAT-RR1: Cross-encoder reranker (optional)
 	compaction *CompactionManager    // FEAT-AI3: Background compaction
 
-	// Per-project state (lazy loaded)
+	// Per-request observability: request_id/root_path correlation across
+	// a search's sub-stages, plus a rolling log of recent requests for
+	// GetRecentRequests.
+	requestLogger *logging.RequestLogger
+	recent        *recentRequests
+
+	// vectorBackends/bm25Backends share one open backend per canonical
+	// (device, inode) index file across every projectState that resolves
+	// to it (worktrees, symlinks, monorepo subpaths), so RSS isn't paid
+	// twice for the same on-disk index.
+	vectorBackends *store.BackendRegistry[store.VectorStore]
+	bm25Backends   *store.BackendRegistry[store.BM25Index]
+
+	// Per-project state (lazy loaded). mu guards the load-or-evict decision
+	// in getOrCreateProject (including the embedder lazy-init below); the
+	// LRU ordering itself (projects.touch, .len, ...) has its own lock so
+	// HandleSearch's per-request lastUsed update doesn't contend mu.
 	mu       sync.RWMutex
-	projects map[string]*projectState
+	projects *projectLRU
 	started  time.Time
 
 	// Lifecycle
@@ -46,10 +63,16 @@ type projectState struct {
 	loadedAt time.Time
 	lastUsed time.Time
 
-	// Stores (owned by this project)
-	metadata store.MetadataStore
-	bm25     store.BM25Index
-	vector   store.VectorStore
+	// Stores (owned by this project). bm25/vector are typically backed by
+	// lazily-materializing, registry-shared wrappers (see loadProject); the
+	// canonical paths below are what's handed to the registries on Close.
+	metadata       store.MetadataStore
+	bm25           store.BM25Index
+	vector         store.VectorStore
+	bm25Path       string
+	vectorPath     string
+	vectorBackends *store.BackendRegistry[store.VectorStore]
+	bm25Backends   *store.BackendRegistry[store.BM25Index]
 
 	// Engine (uses shared embedder from Daemon)
 	engine *search.Engine
@@ -69,14 +92,18 @@ func (p *projectState) Close() error {
 			errs = append(errs, fmt.Errorf("metadata close: %w", err))
 		}
 	}
-	if p.bm25 != nil {
-		if err := p.bm25.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("bm25 close: %w", err))
+	// bm25/vector are shared via a BackendRegistry, so Close releases this
+	// project's reference rather than closing the backend directly - it's
+	// only actually closed once every other projectState sharing the same
+	// canonical index file has released it too.
+	if p.bm25 != nil && p.bm25Backends != nil {
+		if err := p.bm25Backends.Release(p.bm25Path); err != nil {
+			errs = append(errs, fmt.Errorf("bm25 release: %w", err))
 		}
 	}
-	if p.vector != nil {
-		if err := p.vector.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("vector close: %w", err))
+	if p.vector != nil && p.vectorBackends != nil {
+		if err := p.vectorBackends.Release(p.vectorPath); err != nil {
+			errs = append(errs, fmt.Errorf("vector release: %w", err))
 		}
 	}
 
@@ -100,11 +127,15 @@ func NewDaemon(cfg Config, opts ...DaemonOption) (*Daemon, error) {
 	}
 
 	d := &Daemon{
-		config:   cfg,
-		pidFile:  NewPIDFile(cfg.PIDPath),
-		projects: make(map[string]*projectState),
-		done:     make(chan struct{}),
-		expander: search.NewQueryExpander(), // Always create expander for QI-1 Lite
+		config:         cfg,
+		pidFile:        NewPIDFile(cfg.PIDPath),
+		projects:       newProjectLRU(),
+		done:           make(chan struct{}),
+		expander:       search.NewQueryExpander(), // Always create expander for QI-1 Lite
+		requestLogger:  logging.New(nil),
+		recent:         newRecentRequests(DefaultRecentRequestCapacity),
+		vectorBackends: store.NewBackendRegistry[store.VectorStore](),
+		bm25Backends:   store.NewBackendRegistry[store.BM25Index](),
 	}
 
 	for _, opt := range opts {
@@ -220,6 +251,10 @@ func (d *Daemon) Start(ctx context.Context) error {
 		d.compaction.Start(ctx)
 	}
 
+	// Start background idle-project eviction (frees HNSW/SQLite/BM25
+	// resources for projects nobody's actively searching)
+	go d.runIdleEviction(ctx)
+
 	// Handle shutdown signals
 	sigCtx, sigCancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer sigCancel()
@@ -265,15 +300,7 @@ func (d *Daemon) cleanup() {
 	defer d.mu.Unlock()
 
 	// Close all project states
-	for path, state := range d.projects {
-		slog.Debug("Closing project state", slog.String("path", path))
-		if err := state.Close(); err != nil {
-			slog.Warn("Error closing project state",
-				slog.String("path", path),
-				slog.String("error", err.Error()))
-		}
-	}
-	d.projects = make(map[string]*projectState)
+	d.projects.closeAll()
 
 	// Close shared embedder
 	if d.embedder != nil {
@@ -296,21 +323,42 @@ func (d *Daemon) cleanup() {
 
 // HandleSearch implements RequestHandler interface.
 func (d *Daemon) HandleSearch(ctx context.Context, params SearchParams) ([]SearchResult, error) {
+	ctx, logger := d.requestLogger.WithRequest(ctx, "search", params.RootPath, params.Query)
+	meta, _ := logging.FromContext(ctx)
+	metrics := RequestMetrics{RequestID: meta.RequestID, Tool: "search", RootPath: params.RootPath}
+	start := time.Now()
+	defer func() {
+		metrics.TotalElapsed = time.Since(start)
+		logger.Info("search_request_summary",
+			slog.Duration("total_elapsed", metrics.TotalElapsed),
+			slog.Duration("compaction_interrupt_elapsed", metrics.CompactionInterruptElapsed),
+			slog.Int("bm25_hits", metrics.BM25Hits),
+			slog.Int("vector_hits", metrics.VectorHits),
+			slog.Int("result_count", metrics.ResultCount),
+			slog.Bool("embedder_cold_start", metrics.EmbedderColdStart),
+			slog.String("error", metrics.Err))
+		d.recent.add(metrics)
+	}()
+
 	// FEAT-AI3: Interrupt any ongoing compaction for this project
 	if d.compaction != nil {
+		interruptStart := time.Now()
 		d.compaction.InterruptCompaction(params.RootPath)
+		metrics.CompactionInterruptElapsed = time.Since(interruptStart)
 	}
 
+	metrics.EmbedderColdStart = d.embedder == nil
+
 	// Get or create project state
 	state, err := d.getOrCreateProject(ctx, params.RootPath)
 	if err != nil {
+		metrics.Err = err.Error()
 		return nil, fmt.Errorf("failed to load project: %w", err)
 	}
 
-	// Update last used time
-	d.mu.Lock()
-	state.lastUsed = time.Now()
-	d.mu.Unlock()
+	// Update last used time (O(1) via the LRU's own lock, independent of
+	// d.mu so a busy search path never contends the load/evict lock)
+	d.projects.touch(params.RootPath)
 
 	// Build search options
 	limit := params.Limit
@@ -336,6 +384,7 @@ func (d *Daemon) HandleSearch(ctx context.Context, params SearchParams) ([]Searc
 	// Execute search via engine
 	results, err := state.engine.Search(ctx, params.Query, searchOpts)
 	if err != nil {
+		metrics.Err = err.Error()
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
@@ -354,6 +403,18 @@ func (d *Daemon) HandleSearch(ctx context.Context, params SearchParams) ([]Searc
 			Language:  r.Chunk.Language,
 		}
 
+		// Compute match highlighting/snippet only when requested, to keep
+		// the current fast path for callers that don't need a UI-ready
+		// payload.
+		if params.Highlight {
+			matches, matchedWords, fullyHighlighted, snippet := computeHighlight(
+				r.Chunk.Content, params.Query, params.HighlightTag, params.SnippetLines)
+			result.Matches = matches
+			result.MatchedWords = matchedWords
+			result.FullyHighlighted = fullyHighlighted
+			result.Snippet = snippet
+		}
+
 		// FEAT-UNIX3: Include explain data when requested
 		if params.Explain {
 			result.BM25Score = r.BM25Score
@@ -375,12 +436,15 @@ func (d *Daemon) HandleSearch(ctx context.Context, params SearchParams) ([]Searc
 					MultiQueryDecomposed: r.Explain.MultiQueryDecomposed,
 					SubQueries:           r.Explain.SubQueries,
 				}
+				metrics.BM25Hits = r.Explain.BM25ResultCount
+				metrics.VectorHits = r.Explain.VectorResultCount
 			}
 		}
 
 		daemonResults = append(daemonResults, result)
 	}
 
+	metrics.ResultCount = len(daemonResults)
 	slog.Debug("Search complete", slog.Int("results", len(daemonResults)))
 
 	// FEAT-AI3: Notify compaction manager of search completion (for idle tracking)
@@ -393,9 +457,13 @@ func (d *Daemon) HandleSearch(ctx context.Context, params SearchParams) ([]Searc
 
 // GetStatus implements RequestHandler interface.
 func (d *Daemon) GetStatus() StatusResult {
-	d.mu.RLock()
-	projectCount := len(d.projects)
-	d.mu.RUnlock()
+	_, logger := d.requestLogger.WithRequest(context.Background(), "status", "", "")
+	start := time.Now()
+	defer func() {
+		logger.Info("status_request_summary", slog.Duration("total_elapsed", time.Since(start)))
+	}()
+
+	projects := d.projects.snapshot()
 
 	embedderType := "unavailable"
 	embedderStatus := "unavailable"
@@ -415,17 +483,14 @@ func (d *Daemon) GetStatus() StatusResult {
 		Uptime:         time.Since(d.started).Round(time.Second).String(),
 		EmbedderType:   embedderType,
 		EmbedderStatus: embedderStatus,
-		ProjectsLoaded: projectCount,
+		ProjectsLoaded: len(projects),
+		Projects:       projects,
 	}
 }
 
 // getOrCreateProject lazily loads project state.
 func (d *Daemon) getOrCreateProject(ctx context.Context, rootPath string) (*projectState, error) {
-	d.mu.RLock()
-	state, exists := d.projects[rootPath]
-	d.mu.RUnlock()
-
-	if exists {
+	if state, exists := d.projects.get(rootPath); exists {
 		return state, nil
 	}
 
@@ -433,7 +498,7 @@ func (d *Daemon) getOrCreateProject(ctx context.Context, rootPath string) (*proj
 	defer d.mu.Unlock()
 
 	// Double-check after acquiring write lock
-	if state, exists = d.projects[rootPath]; exists {
+	if state, exists := d.projects.get(rootPath); exists {
 		return state, nil
 	}
 
@@ -445,7 +510,7 @@ func (d *Daemon) getOrCreateProject(ctx context.Context, rootPath string) (*proj
 	}
 
 	// Check if we need to evict
-	if len(d.projects) >= d.config.MaxProjects {
+	if d.projects.len() >= d.config.MaxProjects {
 		d.evictLRU()
 	}
 
@@ -455,10 +520,10 @@ func (d *Daemon) getOrCreateProject(ctx context.Context, rootPath string) (*proj
 		return nil, err
 	}
 
-	d.projects[rootPath] = state
+	d.projects.add(rootPath, state)
 	slog.Info("Loaded project",
 		slog.String("path", rootPath),
-		slog.Int("total_projects", len(d.projects)))
+		slog.Int("total_projects", d.projects.len()))
 
 	return state, nil
 }
@@ -485,34 +550,54 @@ func (d *Daemon) loadProject(ctx context.Context, rootPath string) (*projectStat
 		return nil, fmt.Errorf("failed to open metadata: %w", err)
 	}
 
-	// Open BM25 index using factory (SQLite default for concurrent access)
+	// Open BM25 index using factory (SQLite default for concurrent access).
+	// The real postings file isn't opened until the engine's first
+	// Search/Index call (LazyBM25Index), and sharing through bm25Backends
+	// means another projectState whose root resolves to the same on-disk
+	// index (worktree, symlink, monorepo subpath) reuses it instead of
+	// opening - and holding in memory - a second copy.
 	bm25BasePath := filepath.Join(dataDir, "bm25")
-	bm25, err := store.NewBM25IndexWithBackend(bm25BasePath, store.DefaultBM25Config(), cfg.Search.BM25Backend)
+	bm25Cfg := store.DefaultBM25Config()
+	bm25Backend := cfg.Search.BM25Backend
+	bm25, err := d.bm25Backends.Acquire(bm25BasePath, func() (store.BM25Index, error) {
+		return store.NewLazyBM25Index(func() (store.BM25Index, error) {
+			return store.NewBM25IndexWithBackend(bm25BasePath, bm25Cfg, bm25Backend)
+		}), nil
+	})
 	if err != nil {
 		_ = metadata.Close()
 		return nil, fmt.Errorf("failed to open BM25 index: %w", err)
 	}
 
-	// Open vector store with embedder dimensions
+	// Open vector store with embedder dimensions, deferring the actual
+	// HNSW graph construction + mmap'd vector load (LazyVectorStore) until
+	// first Search/Add, and sharing the materialized store across
+	// projectStates the same way bm25Backends does above.
 	dimensions := d.embedder.Dimensions()
-	vectorCfg := store.DefaultVectorStoreConfig(dimensions)
-	vector, err := store.NewHNSWStore(vectorCfg)
+	vectorPath := filepath.Join(dataDir, "vectors.hnsw")
+	vector, err := d.vectorBackends.Acquire(vectorPath, func() (store.VectorStore, error) {
+		return store.NewLazyVectorStore(func() (store.VectorStore, error) {
+			vectorCfg := store.DefaultVectorStoreConfig(dimensions)
+			v, err := store.NewHNSWStore(vectorCfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create vector store: %w", err)
+			}
+			if _, statErr := os.Stat(vectorPath); statErr == nil {
+				if loadErr := v.Load(vectorPath); loadErr != nil {
+					slog.Warn("Failed to load vectors, starting with empty store",
+						slog.String("error", loadErr.Error()),
+						slog.String("path", vectorPath))
+				}
+			}
+			return v, nil
+		}), nil
+	})
 	if err != nil {
-		_ = bm25.Close()
+		_ = d.bm25Backends.Release(bm25BasePath)
 		_ = metadata.Close()
 		return nil, fmt.Errorf("failed to create vector store: %w", err)
 	}
 
-	// Load vectors if they exist
-	vectorPath := filepath.Join(dataDir, "vectors.hnsw")
-	if _, err := os.Stat(vectorPath); err == nil {
-		if loadErr := vector.Load(vectorPath); loadErr != nil {
-			slog.Warn("Failed to load vectors, starting with empty store",
-				slog.String("error", loadErr.Error()),
-				slog.String("path", vectorPath))
-		}
-	}
-
 	// Create search engine with shared embedder and expander
 	engineCfg := search.EngineConfig{
 		DefaultLimit: cfg.Search.MaxResults,
@@ -538,47 +623,90 @@ func (d *Daemon) loadProject(ctx context.Context, rootPath string) (*projectStat
 
 	engine, err := search.NewEngine(bm25, vector, d.embedder, metadata, engineCfg, engineOpts...)
 	if err != nil {
-		_ = vector.Close()
-		_ = bm25.Close()
+		_ = d.vectorBackends.Release(vectorPath)
+		_ = d.bm25Backends.Release(bm25BasePath)
 		_ = metadata.Close()
 		return nil, fmt.Errorf("failed to create search engine: %w", err)
 	}
 
 	return &projectState{
-		rootPath: rootPath,
-		loadedAt: time.Now(),
-		lastUsed: time.Now(),
-		metadata: metadata,
-		bm25:     bm25,
-		vector:   vector,
-		engine:   engine,
-		cfg:      cfg,
+		rootPath:       rootPath,
+		loadedAt:       time.Now(),
+		lastUsed:       time.Now(),
+		metadata:       metadata,
+		bm25:           bm25,
+		vector:         vector,
+		bm25Path:       bm25BasePath,
+		vectorPath:     vectorPath,
+		vectorBackends: d.vectorBackends,
+		bm25Backends:   d.bm25Backends,
+		engine:         engine,
+		cfg:            cfg,
 	}, nil
 }
 
-// evictLRU removes the least recently used project.
+// evictLRU removes the least recently used project, in O(1) via the
+// intrusive projectLRU rather than an O(n) scan. Callers must hold d.mu.
 func (d *Daemon) evictLRU() {
-	var oldestPath string
-	var oldestTime time.Time
+	path, state, ok := d.projects.removeOldest()
+	if !ok {
+		return
+	}
+
+	slog.Info("Evicting project",
+		slog.String("path", path),
+		slog.Duration("idle_for", time.Since(state.lastUsed)))
 
-	for path, state := range d.projects {
-		if oldestPath == "" || state.lastUsed.Before(oldestTime) {
-			oldestPath = path
-			oldestTime = state.lastUsed
+	if err := state.Close(); err != nil {
+		slog.Warn("Error closing evicted project",
+			slog.String("path", path),
+			slog.String("error", err.Error()))
+	}
+}
+
+// runIdleEviction periodically closes any project that hasn't been
+// searched in longer than IdleTimeout, freeing its HNSW vector store,
+// SQLite handles, and BM25 index - important on developer machines
+// juggling many repos where MaxProjects-based eviction alone wouldn't
+// kick in until the limit is hit. Runs until ctx is cancelled.
+func (d *Daemon) runIdleEviction(ctx context.Context) {
+	idleTimeout := d.config.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+
+	ticker := time.NewTicker(DefaultIdleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.done:
+			return
+		case <-ticker.C:
+			d.evictIdleProjects(idleTimeout)
 		}
 	}
+}
+
+// evictIdleProjects closes every project idle longer than idleTimeout,
+// notifying the compaction manager first so any in-flight compaction for
+// that project is cancelled cleanly before its stores are closed.
+func (d *Daemon) evictIdleProjects(idleTimeout time.Duration) {
+	d.projects.removeIdle(idleTimeout, func(path string, state *projectState, idleFor time.Duration) {
+		if d.compaction != nil {
+			d.compaction.InterruptCompaction(path)
+		}
 
-	if oldestPath != "" {
-		state := d.projects[oldestPath]
-		slog.Info("Evicting project",
-			slog.String("path", oldestPath),
-			slog.Duration("idle_for", time.Since(state.lastUsed)))
+		slog.Info("Evicting idle project",
+			slog.String("path", path),
+			slog.Duration("idle_for", idleFor))
 
 		if err := state.Close(); err != nil {
-			slog.Warn("Error closing evicted project",
-				slog.String("path", oldestPath),
+			slog.Warn("Error closing idle-evicted project",
+				slog.String("path", path),
 				slog.String("error", err.Error()))
 		}
-		delete(d.projects, oldestPath)
-	}
+	})
 }
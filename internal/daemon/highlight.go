@@ -0,0 +1,182 @@
+package daemon
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultHighlightTag and DefaultSnippetLines are used when
+// SearchParams.HighlightTag/SnippetLines are unset but Highlight is true.
+const (
+	DefaultHighlightTag = "mark"
+	DefaultSnippetLines = 3
+)
+
+// MatchLevel reports how strongly a single Match matched the query.
+type MatchLevel string
+
+const (
+	MatchLevelNone    MatchLevel = "none"
+	MatchLevelPartial MatchLevel = "partial"
+	MatchLevelFull    MatchLevel = "full"
+)
+
+// Match is one highlighted line of a chunk's content, mirroring Algolia's
+// per-attribute highlight result shape: Value carries the line's tokenized
+// words joined by single spaces, with HighlightTag-wrapped matches (the
+// line's punctuation and original spacing are not kept), MatchLevel
+// summarizes how much of the line matched.
+type Match struct {
+	Value      string
+	MatchLevel MatchLevel
+}
+
+// computeHighlight tokenizes query the same coarse way FuzzyExpandQuery
+// does (whitespace-split, casefolded), then scans content line by line for
+// stem/casefold matches against those terms. It returns one Match per
+// content line, the query words actually found anywhere in content, whether
+// every query word was found, and a Snippet windowed around the
+// tag-consecutive lines with the most matches (SnippetLines wide).
+//
+// BM25Index's real analyzer chain (stemming, stopword filtering, etc.) is
+// defined in an invisible bleve-backed file outside this tree; this
+// tokenizer/stemmer is a deliberately coarse stand-in good enough for
+// highlighting; it doesn't need to agree with BM25 term-for-term, only to
+// find the same words a human skimming the query would expect lit up.
+func computeHighlight(content, query, tag string, snippetLines int) (matches []Match, matchedWords []string, fullyHighlighted bool, snippet string) {
+	if tag == "" {
+		tag = DefaultHighlightTag
+	}
+	if snippetLines <= 0 {
+		snippetLines = DefaultSnippetLines
+	}
+
+	queryStems := stemSet(tokenizeWords(query))
+	if len(queryStems) == 0 {
+		return nil, nil, false, ""
+	}
+
+	lines := strings.Split(content, "\n")
+	matches = make([]Match, len(lines))
+	found := make(map[string]bool, len(queryStems))
+	lineHits := make([]int, len(lines))
+
+	for i, line := range lines {
+		words := tokenizeWords(line)
+		stems := stemSlice(words)
+
+		hitCount := 0
+		for j, stem := range stems {
+			if _, ok := queryStems[stem]; ok {
+				found[stem] = true
+				hitCount++
+				words[j] = tagWord(words[j], tag)
+			}
+		}
+		lineHits[i] = hitCount
+		matches[i] = Match{
+			Value:      strings.Join(words, " "),
+			MatchLevel: matchLevelFor(hitCount, len(stems)),
+		}
+	}
+
+	fullyHighlighted = len(found) == len(queryStems)
+	for stem := range found {
+		matchedWords = append(matchedWords, stem)
+	}
+	sort.Strings(matchedWords)
+
+	snippet = extractSnippet(lines, lineHits, snippetLines)
+	return matches, matchedWords, fullyHighlighted, snippet
+}
+
+// matchLevelFor classifies a line's match strength: no hits is "none", every
+// word on the line hitting is "full", anything in between is "partial".
+func matchLevelFor(hits, total int) MatchLevel {
+	switch {
+	case hits == 0:
+		return MatchLevelNone
+	case total > 0 && hits == total:
+		return MatchLevelFull
+	default:
+		return MatchLevelPartial
+	}
+}
+
+// extractSnippet finds the snippetLines-wide window of lines with the
+// highest total hit count (the "densest match cluster") and joins it back
+// into a single excerpt.
+func extractSnippet(lines []string, lineHits []int, snippetLines int) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	if snippetLines >= len(lines) {
+		return strings.Join(lines, "\n")
+	}
+
+	bestStart, bestScore := 0, -1
+	windowScore := 0
+	for i := 0; i < snippetLines; i++ {
+		windowScore += lineHits[i]
+	}
+	bestScore = windowScore
+
+	for start := 1; start+snippetLines <= len(lines); start++ {
+		windowScore += lineHits[start+snippetLines-1] - lineHits[start-1]
+		if windowScore > bestScore {
+			bestScore = windowScore
+			bestStart = start
+		}
+	}
+
+	return strings.Join(lines[bestStart:bestStart+snippetLines], "\n")
+}
+
+// tokenizeWords splits s on runs of non-alphanumeric characters, the same
+// coarse whitespace/punctuation split FuzzyExpandQuery uses for queries.
+func tokenizeWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_')
+	})
+}
+
+// stemSlice casefolds and lightly stems each word (stripping common
+// English suffixes), preserving order/length so callers can map a stem hit
+// back to the original word. It's intentionally crude - real stemming
+// lives in BM25Index's analyzer chain, outside this tree.
+func stemSlice(words []string) []string {
+	stems := make([]string, len(words))
+	for i, w := range words {
+		stems[i] = stem(strings.ToLower(w))
+	}
+	return stems
+}
+
+// stemSet is stemSlice collapsed into a set, for matching a line's stems
+// against a query's stems regardless of order or repetition.
+func stemSet(words []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, s := range stemSlice(words) {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(w) > len(suffix)+2 && strings.HasSuffix(w, suffix) {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+// tagWord wraps word in <tag>...</tag> if it's non-empty, leaving
+// punctuation-only tokens (which tokenizeWords never produces, but guards
+// against empty input) untouched.
+func tagWord(word, tag string) string {
+	if word == "" {
+		return word
+	}
+	return "<" + tag + ">" + word + "</" + tag + ">"
+}
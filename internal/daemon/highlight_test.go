@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComputeHighlight_MarksMatchedWords(t *testing.T) {
+	content := "func parseConfig() error {\n\treturn nil\n}\n"
+	matches, matchedWords, fullyHighlighted, snippet := computeHighlight(content, "parseConfig error", "mark", 3)
+
+	if !strings.Contains(matches[0].Value, "<mark>parseConfig</mark>") {
+		t.Errorf("expected parseConfig to be tagged on the matched line, got %q", matches[0].Value)
+	}
+	if !strings.Contains(matches[0].Value, "<mark>error</mark>") {
+		t.Errorf("expected error to be tagged on the matched line, got %q", matches[0].Value)
+	}
+	if matches[0].MatchLevel != MatchLevelPartial {
+		t.Errorf("expected partial match level on the func line ('func' itself doesn't match), got %v", matches[0].MatchLevel)
+	}
+	if matches[1].MatchLevel != MatchLevelNone {
+		t.Errorf("expected no match on the return line, got %v", matches[1].MatchLevel)
+	}
+	if !fullyHighlighted {
+		t.Errorf("expected both query words (parseConfig, error) to be found, got matchedWords=%v", matchedWords)
+	}
+	if !strings.Contains(snippet, "parseConfig") {
+		t.Errorf("expected snippet to include the matched line, got %q", snippet)
+	}
+}
+
+func TestComputeHighlight_StemMatchesPluralAndTense(t *testing.T) {
+	content := "the configs were loading slowly"
+	_, matchedWords, fullyHighlighted, _ := computeHighlight(content, "config load", "mark", 3)
+
+	if !fullyHighlighted {
+		t.Fatalf("expected stemmed match for 'configs'~'config' and 'loading'~'load', got matchedWords=%v", matchedWords)
+	}
+}
+
+func TestComputeHighlight_NoMatchReturnsNoneLevel(t *testing.T) {
+	content := "line one\nline two\n"
+	matches, matchedWords, fullyHighlighted, snippet := computeHighlight(content, "xyzzy", "mark", 2)
+
+	for _, m := range matches {
+		if m.MatchLevel != MatchLevelNone {
+			t.Errorf("expected no matches, got %v on %q", m.MatchLevel, m.Value)
+		}
+	}
+	if fullyHighlighted {
+		t.Error("expected fullyHighlighted false when nothing matched")
+	}
+	if len(matchedWords) != 0 {
+		t.Errorf("expected no matched words, got %v", matchedWords)
+	}
+	if snippet == "" {
+		t.Error("expected a snippet even without a match (falls back to the first window)")
+	}
+}
+
+func TestComputeHighlight_EmptyQueryReturnsNothing(t *testing.T) {
+	matches, matchedWords, fullyHighlighted, snippet := computeHighlight("some content", "", "mark", 3)
+	if matches != nil || matchedWords != nil || fullyHighlighted || snippet != "" {
+		t.Errorf("expected all zero values for an empty query, got matches=%v words=%v full=%v snippet=%q",
+			matches, matchedWords, fullyHighlighted, snippet)
+	}
+}
+
+func TestComputeHighlight_SnippetWindowsDensestCluster(t *testing.T) {
+	content := strings.Join([]string{
+		"unrelated line 1",
+		"unrelated line 2",
+		"target match here",
+		"another target match",
+		"unrelated line 5",
+		"unrelated line 6",
+	}, "\n")
+
+	_, _, _, snippet := computeHighlight(content, "target match", "mark", 2)
+
+	if !strings.Contains(snippet, "target match here") || !strings.Contains(snippet, "another target match") {
+		t.Errorf("expected the snippet to window the two densest matching lines, got %q", snippet)
+	}
+	if strings.Contains(snippet, "unrelated") {
+		t.Errorf("expected the snippet not to include the sparser unrelated lines, got %q", snippet)
+	}
+}
+
+func TestComputeHighlight_CustomTag(t *testing.T) {
+	matches, _, _, _ := computeHighlight("hello world", "hello", "em", 1)
+	if !strings.Contains(matches[0].Value, "<em>hello</em>") {
+		t.Errorf("expected custom tag <em> to be used, got %q", matches[0].Value)
+	}
+}
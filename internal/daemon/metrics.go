@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultRecentRequestCapacity is how many RequestMetrics GetRecentRequests
+// retains when Daemon is constructed without an explicit capacity.
+const DefaultRecentRequestCapacity = 100
+
+// RequestMetrics is what HandleSearch/GetStatus accumulate over the
+// lifetime of one request and emit as a single summary log line, mirroring
+// the "meta logger" pattern used in distributed search systems where
+// correlating a request across BM25/vector/rerank stages is otherwise
+// impossible from the log stream alone.
+//
+// BM25Hits/VectorHits reflect search.ExplainData's counts when Explain
+// was requested (0 otherwise), and RerankOut is never set (always 0),
+// rather than independently measured
+// stage elapsed times: Engine.Search (engine.go, outside this tree) runs
+// BM25/vector/rerank internally as one call from HandleSearch's vantage
+// point, so per-stage timing would need Engine itself to call
+// logging.RequestLogger.Begin around each sub-stage. EmbedElapsed is left
+// at zero for the same reason; CacheHits is likewise a placeholder until
+// Engine exposes its ResultCache's Stats(). What IS measured directly at
+// the daemon boundary - TotalElapsed, CompactionInterruptElapsed, and
+// EmbedderColdStart - is populated for every request.
+type RequestMetrics struct {
+	RequestID string
+	Tool      string
+	RootPath  string
+
+	TotalElapsed               time.Duration
+	EmbedElapsed               time.Duration
+	CompactionInterruptElapsed time.Duration
+
+	BM25Hits    int
+	VectorHits  int
+	RerankOut   int
+	ResultCount int
+
+	CacheHits int
+
+	EmbedderColdStart bool
+	Err               string
+}
+
+// recentRequests is a fixed-capacity, oldest-evicted-first log of the most
+// recent RequestMetrics, read by GetRecentRequests for debugging a live
+// daemon without needing to grep its log file.
+type recentRequests struct {
+	mu       sync.Mutex
+	capacity int
+	entries  []RequestMetrics
+}
+
+func newRecentRequests(capacity int) *recentRequests {
+	if capacity <= 0 {
+		capacity = DefaultRecentRequestCapacity
+	}
+	return &recentRequests{capacity: capacity}
+}
+
+// add appends m, evicting the oldest entry once capacity is exceeded.
+func (r *recentRequests) add(m RequestMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, m)
+	if overflow := len(r.entries) - r.capacity; overflow > 0 {
+		r.entries = r.entries[overflow:]
+	}
+}
+
+// snapshot returns a copy of the retained entries, newest last.
+func (r *recentRequests) snapshot() []RequestMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RequestMetrics, len(r.entries))
+	copy(out, r.entries)
+	return out
+}
+
+// GetRecentRequests returns the most recent requests' RequestMetrics,
+// newest last, for debugging a live daemon (e.g. "why was the last search
+// slow") without needing to grep its log file.
+func (d *Daemon) GetRecentRequests() []RequestMetrics {
+	return d.recent.snapshot()
+}
@@ -0,0 +1,40 @@
+package daemon
+
+import "testing"
+
+func TestRecentRequests_EvictsOldestBeyondCapacity(t *testing.T) {
+	r := newRecentRequests(3)
+	for i := 0; i < 5; i++ {
+		r.add(RequestMetrics{RequestID: string(rune('a' + i))})
+	}
+
+	snap := r.snapshot()
+	if len(snap) != 3 {
+		t.Fatalf("expected 3 retained entries, got %d", len(snap))
+	}
+	want := []string{"c", "d", "e"}
+	for i, id := range want {
+		if snap[i].RequestID != id {
+			t.Errorf("entry %d: got %q, want %q", i, snap[i].RequestID, id)
+		}
+	}
+}
+
+func TestRecentRequests_DefaultCapacity(t *testing.T) {
+	r := newRecentRequests(0)
+	if r.capacity != DefaultRecentRequestCapacity {
+		t.Errorf("expected default capacity %d, got %d", DefaultRecentRequestCapacity, r.capacity)
+	}
+}
+
+func TestRecentRequests_SnapshotIsIndependentCopy(t *testing.T) {
+	r := newRecentRequests(2)
+	r.add(RequestMetrics{RequestID: "a"})
+
+	snap := r.snapshot()
+	snap[0].RequestID = "mutated"
+
+	if r.snapshot()[0].RequestID != "a" {
+		t.Error("expected snapshot to be a copy, not a view into internal state")
+	}
+}
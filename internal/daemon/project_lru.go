@@ -0,0 +1,192 @@
+package daemon
+
+import (
+	"container/list"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultIdleTimeout is how long a project can go without a search before
+// the background idle-eviction loop closes it, freeing its HNSW vector
+// store, SQLite handles, and BM25 index. Used when Config.IdleTimeout is
+// zero.
+const DefaultIdleTimeout = 30 * time.Minute
+
+// DefaultIdleCheckInterval is how often Daemon.runIdleEviction wakes up to
+// scan for idle projects.
+const DefaultIdleCheckInterval = time.Minute
+
+type projectLRUEntry struct {
+	path     string
+	state    *projectState
+	lastUsed time.Time
+}
+
+// projectLRU is an intrusive, container/list-backed LRU of loaded
+// projects, mirroring search.ResultCache's structure: a doubly-linked
+// list for O(1) most-recently-used ordering plus a map for O(1) lookup by
+// key.
+// It replaces the map[string]*projectState + O(n) evictLRU scan that used
+// to back Daemon.projects.
+type projectLRU struct {
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newProjectLRU() *projectLRU {
+	return &projectLRU{
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the project state for path, promoting it to
+// most-recently-used.
+func (l *projectLRU) get(path string) (*projectState, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[path]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(elem)
+	return elem.Value.(*projectLRUEntry).state, true
+}
+
+// touch updates path's last-used time and promotes it to
+// most-recently-used, without needing Daemon.mu. It is a no-op if path
+// isn't loaded.
+func (l *projectLRU) touch(path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[path]
+	if !ok {
+		return
+	}
+	entry := elem.Value.(*projectLRUEntry)
+	entry.lastUsed = time.Now()
+	entry.state.lastUsed = entry.lastUsed
+	l.order.MoveToFront(elem)
+}
+
+// add inserts state as the most-recently-used project for path.
+func (l *projectLRU) add(path string, state *projectState) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := &projectLRUEntry{path: path, state: state, lastUsed: state.lastUsed}
+	elem := l.order.PushFront(entry)
+	l.entries[path] = elem
+}
+
+// remove drops path from the LRU, returning its state if it was present.
+func (l *projectLRU) remove(path string) (*projectState, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[path]
+	if !ok {
+		return nil, false
+	}
+	l.order.Remove(elem)
+	delete(l.entries, path)
+	return elem.Value.(*projectLRUEntry).state, true
+}
+
+// len returns the number of currently loaded projects.
+func (l *projectLRU) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// removeOldest evicts and returns the least-recently-used project, in O(1)
+// rather than the old linear scan over a map.
+func (l *projectLRU) removeOldest() (path string, state *projectState, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	oldest := l.order.Back()
+	if oldest == nil {
+		return "", nil, false
+	}
+	entry := oldest.Value.(*projectLRUEntry)
+	l.order.Remove(oldest)
+	delete(l.entries, entry.path)
+	return entry.path, entry.state, true
+}
+
+// removeIdle evicts every project whose lastUsed is older than idleTimeout,
+// invoking onEvict for each (while not holding the LRU's lock) so the
+// caller can notify the compaction manager and close the project's stores.
+func (l *projectLRU) removeIdle(idleTimeout time.Duration, onEvict func(path string, state *projectState, idleFor time.Duration)) {
+	now := time.Now()
+
+	l.mu.Lock()
+	var evicted []*projectLRUEntry
+	for elem := l.order.Back(); elem != nil; {
+		entry := elem.Value.(*projectLRUEntry)
+		if now.Sub(entry.lastUsed) < idleTimeout {
+			break // order is oldest-to-newest from the back, so nothing further is idle
+		}
+		prev := elem.Prev()
+		l.order.Remove(elem)
+		delete(l.entries, entry.path)
+		evicted = append(evicted, entry)
+		elem = prev
+	}
+	l.mu.Unlock()
+
+	for _, entry := range evicted {
+		onEvict(entry.path, entry.state, now.Sub(entry.lastUsed))
+	}
+}
+
+// ProjectInfo describes one loaded project's position in the LRU, for
+// GetStatus observability.
+type ProjectInfo struct {
+	RootPath string
+	LastUsed time.Time
+	IdleFor  time.Duration
+}
+
+// snapshot returns every loaded project, most-recently-used first.
+func (l *projectLRU) snapshot() []ProjectInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	out := make([]ProjectInfo, 0, l.order.Len())
+	for elem := l.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*projectLRUEntry)
+		out = append(out, ProjectInfo{
+			RootPath: entry.path,
+			LastUsed: entry.lastUsed,
+			IdleFor:  now.Sub(entry.lastUsed),
+		})
+	}
+	return out
+}
+
+// closeAll closes and drops every loaded project, for use during daemon
+// shutdown.
+func (l *projectLRU) closeAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for elem := l.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*projectLRUEntry)
+		slog.Debug("Closing project state", slog.String("path", entry.path))
+		if err := entry.state.Close(); err != nil {
+			slog.Warn("Error closing project state",
+				slog.String("path", entry.path),
+				slog.String("error", err.Error()))
+		}
+	}
+	l.order = list.New()
+	l.entries = make(map[string]*list.Element)
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProjectLRU_RemoveOldestEvictsLeastRecentlyUsed(t *testing.T) {
+	l := newProjectLRU()
+	l.add("a", &projectState{lastUsed: time.Now()})
+	l.add("b", &projectState{lastUsed: time.Now()})
+
+	l.touch("a") // promotes "a", leaving "b" as the oldest
+
+	path, _, ok := l.removeOldest()
+	if !ok || path != "b" {
+		t.Fatalf("expected %q evicted first, got %q (ok=%v)", "b", path, ok)
+	}
+	if l.len() != 1 {
+		t.Fatalf("expected 1 project remaining, got %d", l.len())
+	}
+}
+
+func TestProjectLRU_RemoveIdleEvictsOnlyPastThreshold(t *testing.T) {
+	l := newProjectLRU()
+	l.add("old", &projectState{lastUsed: time.Now().Add(-time.Hour)})
+	l.add("recent", &projectState{lastUsed: time.Now()})
+
+	var evicted []string
+	l.removeIdle(time.Minute, func(path string, state *projectState, idleFor time.Duration) {
+		evicted = append(evicted, path)
+	})
+
+	if len(evicted) != 1 || evicted[0] != "old" {
+		t.Fatalf("expected only %q evicted, got %v", "old", evicted)
+	}
+	if _, ok := l.get("recent"); !ok {
+		t.Error("expected recent project to remain loaded")
+	}
+}
+
+func TestProjectLRU_GetAndTouchPromoteEntry(t *testing.T) {
+	l := newProjectLRU()
+	l.add("a", &projectState{lastUsed: time.Now().Add(-time.Minute)})
+	l.add("b", &projectState{lastUsed: time.Now()})
+
+	if _, ok := l.get("a"); !ok {
+		t.Fatal("expected to find project a")
+	}
+
+	// "a" is now most-recently-used, so removeOldest should take "b".
+	path, _, ok := l.removeOldest()
+	if !ok || path != "b" {
+		t.Fatalf("expected %q evicted after get promoted %q, got %q", "b", "a", path)
+	}
+}
+
+func TestProjectLRU_SnapshotOrdersMostRecentFirst(t *testing.T) {
+	l := newProjectLRU()
+	l.add("a", &projectState{lastUsed: time.Now()})
+	l.add("b", &projectState{lastUsed: time.Now()})
+	l.touch("a")
+
+	snap := l.snapshot()
+	if len(snap) != 2 || snap[0].RootPath != "a" || snap[1].RootPath != "b" {
+		t.Fatalf("expected [a, b] order, got %+v", snap)
+	}
+}
+
+func TestProjectLRU_RemoveDropsEntry(t *testing.T) {
+	l := newProjectLRU()
+	l.add("a", &projectState{lastUsed: time.Now()})
+
+	if _, ok := l.remove("a"); !ok {
+		t.Fatal("expected remove to find project a")
+	}
+	if l.len() != 0 {
+		t.Fatalf("expected 0 projects remaining, got %d", l.len())
+	}
+	if _, ok := l.remove("a"); ok {
+		t.Error("expected second remove of the same path to report not found")
+	}
+}
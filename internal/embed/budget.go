@@ -0,0 +1,123 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+)
+
+// InputBudget bounds how much text BudgetedEmbedder will forward to the
+// wrapped Embedder in a single call, modeled on the streaming/oxy pattern
+// of MaxRequestBodyBytes rejecting oversized payloads with 413 rather than
+// letting the server reject (or OOM on) them itself.
+//
+// MLXConfig has no MaxTextBytes/MaxBatchBytes fields, and MLXEmbedder
+// does not check input sizes before POSTing to /embed or /embed_batch.
+// InputBudget is standalone and is enforced only by BudgetedEmbedder,
+// which wraps any Embedder (the same decorator shape ThrottledEmbedder
+// already uses).
+type InputBudget struct {
+	// MaxTextBytes is the most UTF-8 bytes a single text may contain.
+	// Zero means unbounded.
+	MaxTextBytes int
+	// MaxBatchBytes is the most combined UTF-8 bytes an EmbedBatch call
+	// may contain across all of its texts. Zero means unbounded.
+	MaxBatchBytes int
+	// AutoSplit, when true, transparently splits a batch exceeding
+	// MaxBatchBytes into multiple sub-batches (each within budget) and
+	// reassembles the results in original order, instead of rejecting
+	// the call outright. A single text exceeding MaxTextBytes is always
+	// rejected - AutoSplit can't shrink one oversized text.
+	AutoSplit bool
+}
+
+// ErrInputTooLarge is returned when a text (or, with AutoSplit disabled, a
+// batch) exceeds the configured InputBudget. Index is the position within
+// the call's texts (0 for a single Embed call, -1 when a batch's combined
+// size exceeded MaxBatchBytes); ByteCount and Limit are the
+// offending size and the budget it exceeded.
+type ErrInputTooLarge struct {
+	Index     int
+	ByteCount int
+	Limit     int
+}
+
+func (e *ErrInputTooLarge) Error() string {
+	return fmt.Sprintf("embed: text at index %d is %d bytes, exceeding the %d byte limit", e.Index, e.ByteCount, e.Limit)
+}
+
+// BudgetedEmbedder wraps an Embedder, rejecting (or, for batches with
+// AutoSplit, transparently chunking) input that exceeds its InputBudget
+// before it ever reaches the wrapped Embedder.
+type BudgetedEmbedder struct {
+	Embedder
+	budget InputBudget
+}
+
+// NewBudgetedEmbedder wraps base with the given budget.
+func NewBudgetedEmbedder(base Embedder, budget InputBudget) *BudgetedEmbedder {
+	return &BudgetedEmbedder{Embedder: base, budget: budget}
+}
+
+// Embed rejects text with ErrInputTooLarge if it exceeds MaxTextBytes,
+// otherwise forwards to the wrapped Embedder.
+func (b *BudgetedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if b.budget.MaxTextBytes > 0 && len(text) > b.budget.MaxTextBytes {
+		return nil, &ErrInputTooLarge{Index: 0, ByteCount: len(text), Limit: b.budget.MaxTextBytes}
+	}
+	return b.Embedder.Embed(ctx, text)
+}
+
+// EmbedBatch rejects the call with ErrInputTooLarge if any single text
+// exceeds MaxTextBytes (AutoSplit can't shrink one oversized text). If the
+// batch's combined byte count exceeds MaxBatchBytes, it either errors
+// (AutoSplit=false) or is split into sub-batches each within MaxBatchBytes,
+// called against the wrapped Embedder in order, and reassembled.
+func (b *BudgetedEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	total := 0
+	for i, text := range texts {
+		if b.budget.MaxTextBytes > 0 && len(text) > b.budget.MaxTextBytes {
+			return nil, &ErrInputTooLarge{Index: i, ByteCount: len(text), Limit: b.budget.MaxTextBytes}
+		}
+		total += len(text)
+	}
+
+	if b.budget.MaxBatchBytes <= 0 || total <= b.budget.MaxBatchBytes {
+		return b.Embedder.EmbedBatch(ctx, texts)
+	}
+	if !b.budget.AutoSplit {
+		return nil, &ErrInputTooLarge{Index: -1, ByteCount: total, Limit: b.budget.MaxBatchBytes}
+	}
+
+	results := make([][]float32, 0, len(texts))
+	for _, sub := range b.splitBatch(texts) {
+		vecs, err := b.Embedder.EmbedBatch(ctx, sub)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, vecs...)
+	}
+	return results, nil
+}
+
+// splitBatch greedily groups texts into sub-batches that each stay within
+// MaxBatchBytes, preserving order so the reassembled results line up with
+// the original texts slice.
+func (b *BudgetedEmbedder) splitBatch(texts []string) [][]string {
+	var batches [][]string
+	var current []string
+	currentBytes := 0
+
+	for _, text := range texts {
+		if len(current) > 0 && currentBytes+len(text) > b.budget.MaxBatchBytes {
+			batches = append(batches, current)
+			current = nil
+			currentBytes = 0
+		}
+		current = append(current, text)
+		currentBytes += len(text)
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
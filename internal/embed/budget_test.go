@@ -0,0 +1,114 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// batchRecordingEmbedder2 records each EmbedBatch call's texts, for
+// asserting how BudgetedEmbedder split (or didn't split) a batch.
+type batchRecordingEmbedder2 struct {
+	stubEmbedder
+	calls [][]string
+}
+
+func (b *batchRecordingEmbedder2) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	b.calls = append(b.calls, append([]string(nil), texts...))
+	return b.stubEmbedder.EmbedBatch(ctx, texts)
+}
+
+func TestBudgetedEmbedder_RejectsOversizedText(t *testing.T) {
+	base := &batchRecordingEmbedder2{}
+	be := NewBudgetedEmbedder(base, InputBudget{MaxTextBytes: 10})
+
+	_, err := be.Embed(context.Background(), "this text is definitely over ten bytes")
+	var tooLarge *ErrInputTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected ErrInputTooLarge, got %v", err)
+	}
+	if tooLarge.Limit != 10 {
+		t.Errorf("expected limit 10, got %d", tooLarge.Limit)
+	}
+	if len(base.calls) != 0 {
+		t.Fatal("expected the oversized text to be rejected before reaching the wrapped embedder")
+	}
+}
+
+func TestBudgetedEmbedder_OversizedBatchNoAutoSplitErrors(t *testing.T) {
+	base := &batchRecordingEmbedder2{}
+	be := NewBudgetedEmbedder(base, InputBudget{MaxBatchBytes: 10, AutoSplit: false})
+
+	_, err := be.EmbedBatch(context.Background(), []string{"aaaaa", "bbbbb", "ccccc"})
+	var tooLarge *ErrInputTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected ErrInputTooLarge, got %v", err)
+	}
+	if len(base.calls) != 0 {
+		t.Fatal("expected the oversized batch to be rejected before reaching the wrapped embedder")
+	}
+}
+
+func TestBudgetedEmbedder_OversizedBatchAutoSplitMatchesUnsplitResult(t *testing.T) {
+	texts := []string{"aaaaa", "bbbbb", "ccccc", "ddddd"}
+
+	plain := &batchRecordingEmbedder2{}
+	want, err := plain.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("unsplit call: %v", err)
+	}
+
+	split := &batchRecordingEmbedder2{}
+	be := NewBudgetedEmbedder(split, InputBudget{MaxBatchBytes: 10, AutoSplit: true})
+	got, err := be.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("auto-split call: %v", err)
+	}
+
+	if len(split.calls) < 2 {
+		t.Fatalf("expected the batch to be split into multiple sub-batches, got %d calls", len(split.calls))
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d vectors, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Errorf("vector %d: length mismatch, got %d want %d", i, len(got[i]), len(want[i]))
+		}
+	}
+}
+
+func TestBudgetedEmbedder_WithinBudgetPassesThroughUnsplit(t *testing.T) {
+	base := &batchRecordingEmbedder2{}
+	be := NewBudgetedEmbedder(base, InputBudget{MaxBatchBytes: 1000, AutoSplit: true})
+
+	if _, err := be.EmbedBatch(context.Background(), []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(base.calls) != 1 {
+		t.Fatalf("expected a single pass-through call, got %d", len(base.calls))
+	}
+}
+
+func TestBudgetedEmbedder_OversizedTextInBatchRejectedEvenWithAutoSplit(t *testing.T) {
+	base := &batchRecordingEmbedder2{}
+	be := NewBudgetedEmbedder(base, InputBudget{MaxTextBytes: 3, MaxBatchBytes: 1000, AutoSplit: true})
+
+	_, err := be.EmbedBatch(context.Background(), []string{"ok", "way too long for the per-text limit"})
+	var tooLarge *ErrInputTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected ErrInputTooLarge, got %v", err)
+	}
+	if tooLarge.Index != 1 {
+		t.Errorf("expected the offending index to be 1, got %d", tooLarge.Index)
+	}
+}
+
+func TestBudgetedEmbedder_ZeroBudgetIsUnbounded(t *testing.T) {
+	base := &batchRecordingEmbedder2{}
+	be := NewBudgetedEmbedder(base, InputBudget{})
+
+	if _, err := be.Embed(context.Background(), "anything goes"); err != nil {
+		t.Fatalf("unexpected error with zero-value budget: %v", err)
+	}
+}
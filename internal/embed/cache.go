@@ -0,0 +1,372 @@
+package embed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// CacheKeyVersion is prepended to every cache key. Bump it whenever a
+// change upstream of the cache (vector normalization, EmbeddingCalibration
+// parameters, etc.) would make an old cached vector no longer comparable
+// to a freshly-computed one - this invalidates every existing entry
+// without needing to touch the cache's storage at all, the same "change
+// the prefix, stop reading the old rows" trick migrator.go's schema
+// version column plays for on-disk formats.
+const CacheKeyVersion = "v1"
+
+// CacheStats is a point-in-time snapshot returned by Cache.Stats, meant to
+// be wired into a caller's existing telemetry rather than read directly by
+// humans - mirrors QueueManagerStats' role for QueueManager.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// Cache stores embedding vectors keyed by CacheKey(model, text). Get/Set
+// deal in the already-hashed key rather than (model, text) directly so a
+// caller can reuse the same key across a Get/Set pair without hashing
+// twice.
+type Cache interface {
+	Get(key string) ([]float32, bool)
+	Set(key string, vec []float32)
+	Stats() CacheStats
+	Close() error
+}
+
+// CacheKey hashes model and text (and the package's CacheKeyVersion) into
+// the string Cache.Get/Set expect, matching the request's
+// sha256(model || "\x00" || text) shape with the version folded in ahead
+// of model so a version bump invalidates every model's entries at once.
+func CacheKey(model, text string) string {
+	h := sha256.New()
+	h.Write([]byte(CacheKeyVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheLRUEntry is one node in MemoryCache's intrusive doubly-linked
+// recency list.
+type cacheLRUEntry struct {
+	key        string
+	vec        []float32
+	prev, next *cacheLRUEntry
+}
+
+// MemoryCache is an in-process LRU Cache, sized by entry count rather than
+// bytes - simple and predictable, matching ThrottledEmbedder's own
+// preference for a plain bounded structure over a byte-accounted one.
+type MemoryCache struct {
+	capacity int
+
+	mu    sync.Mutex
+	index map[string]*cacheLRUEntry
+	head  *cacheLRUEntry // most recently used
+	tail  *cacheLRUEntry // least recently used
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// NewMemoryCache builds a MemoryCache holding at most capacity entries;
+// capacity <= 0 defaults to DefaultMemoryCacheCapacity.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DefaultMemoryCacheCapacity
+	}
+	return &MemoryCache{capacity: capacity, index: make(map[string]*cacheLRUEntry, capacity)}
+}
+
+// DefaultMemoryCacheCapacity bounds a MemoryCache with no explicit
+// capacity - generous enough to absorb a single reindex's worth of
+// repeated chunk text without growing unbounded on a long-running server.
+const DefaultMemoryCacheCapacity = 50000
+
+func (c *MemoryCache) Get(key string) ([]float32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.index[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.moveToFrontLocked(entry)
+	return entry.vec, true
+}
+
+func (c *MemoryCache) Set(key string, vec []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.index[key]; ok {
+		entry.vec = vec
+		c.moveToFrontLocked(entry)
+		return
+	}
+
+	entry := &cacheLRUEntry{key: key, vec: vec}
+	c.index[key] = entry
+	c.pushFrontLocked(entry)
+
+	if len(c.index) > c.capacity {
+		c.evictLocked()
+	}
+}
+
+func (c *MemoryCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions}
+}
+
+func (c *MemoryCache) Close() error { return nil }
+
+func (c *MemoryCache) pushFrontLocked(entry *cacheLRUEntry) {
+	entry.prev = nil
+	entry.next = c.head
+	if c.head != nil {
+		c.head.prev = entry
+	}
+	c.head = entry
+	if c.tail == nil {
+		c.tail = entry
+	}
+}
+
+func (c *MemoryCache) moveToFrontLocked(entry *cacheLRUEntry) {
+	if c.head == entry {
+		return
+	}
+	c.unlinkLocked(entry)
+	c.pushFrontLocked(entry)
+}
+
+func (c *MemoryCache) unlinkLocked(entry *cacheLRUEntry) {
+	if entry.prev != nil {
+		entry.prev.next = entry.next
+	} else {
+		c.head = entry.next
+	}
+	if entry.next != nil {
+		entry.next.prev = entry.prev
+	} else {
+		c.tail = entry.prev
+	}
+	entry.prev, entry.next = nil, nil
+}
+
+func (c *MemoryCache) evictLocked() {
+	lru := c.tail
+	if lru == nil {
+		return
+	}
+	c.unlinkLocked(lru)
+	delete(c.index, lru.key)
+	c.evictions++
+}
+
+// boltCacheBucket is the single bbolt bucket BoltCache stores entries in.
+var boltCacheBucket = []byte("embed_cache")
+
+// BoltCache is an on-disk Cache backed by a BoltDB (bbolt) file, for a
+// cache that should survive a process restart - a cold MLX/Ollama server
+// re-embedding a large, mostly-unchanged corpus after every server
+// restart is the case this exists for. Unlike MemoryCache it has no
+// capacity bound: BoltCache trades memory pressure for disk space, so
+// eviction isn't needed to keep the process healthy the way it is for
+// MemoryCache; Stats().Evictions is always 0.
+type BoltCache struct {
+	db *bolt.DB
+
+	mu     sync.Mutex
+	hits   int64
+	misses int64
+}
+
+// NewBoltCache opens (creating if necessary) a BoltDB file at path for use
+// as a Cache.
+func NewBoltCache(path string) (*BoltCache, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("embed: open bolt cache: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltCacheBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("embed: create bolt cache bucket: %w", err)
+	}
+	return &BoltCache{db: db}, nil
+}
+
+func (c *BoltCache) Get(key string) ([]float32, bool) {
+	var vec []float32
+	found := false
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(boltCacheBucket).Get([]byte(key))
+		if raw == nil {
+			return nil
+		}
+		vec = decodeFloat32s(raw)
+		found = true
+		return nil
+	})
+
+	c.mu.Lock()
+	if found {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	c.mu.Unlock()
+	return vec, found
+}
+
+func (c *BoltCache) Set(key string, vec []float32) {
+	raw := encodeFloat32s(vec)
+	_ = c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltCacheBucket).Put([]byte(key), raw)
+	})
+}
+
+func (c *BoltCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses}
+}
+
+func (c *BoltCache) Close() error {
+	return c.db.Close()
+}
+
+// encodeFloat32s/decodeFloat32s store a []float32 as its raw little-endian
+// bytes - no varint/compression, since vectors are small (a few hundred
+// to a couple thousand floats) and this keeps BoltCache's Get/Set free of
+// any dependency beyond bbolt itself.
+func encodeFloat32s(vec []float32) []byte {
+	buf := make([]byte, len(vec)*4)
+	for i, f := range vec {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func decodeFloat32s(raw []byte) []float32 {
+	vec := make([]float32, len(raw)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+	}
+	return vec
+}
+
+// CachedEmbedder wraps an Embedder with a Cache consulted before every
+// Embed/EmbedBatch call, populated only from calls that return a nil
+// error - matching the request's "never cache a timeout/5xx/retried
+// failure" rule implicitly, since an embedder that only returns nil on a
+// clean response (the convention every Embedder in this package follows:
+// see QueueManager's own retry-until-success-or-permanent-failure
+// handling) never reaches the Set call on anything but a clean result.
+type CachedEmbedder struct {
+	Embedder
+	cache Cache
+}
+
+// NewCachedEmbedder wraps base with cache. A nil cache makes
+// Embed/EmbedBatch behave exactly like base, for a "--no-cache" caller
+// that still wants to construct a CachedEmbedder uniformly rather than
+// branching on whether caching is enabled.
+func NewCachedEmbedder(base Embedder, cache Cache) *CachedEmbedder {
+	return &CachedEmbedder{Embedder: base, cache: cache}
+}
+
+// CacheStats returns the wrapped Cache's stats, or the zero value if this
+// CachedEmbedder was built with a nil cache.
+func (c *CachedEmbedder) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.Stats()
+}
+
+// Close closes the wrapped Cache (releasing a BoltCache's file lock, in
+// particular) in addition to the base Embedder - overriding the Embedder
+// field's promoted Close so a caller that only holds the CachedEmbedder
+// doesn't need to separately remember to close the Cache it was built
+// with.
+func (c *CachedEmbedder) Close() error {
+	embedderErr := c.Embedder.Close()
+	if c.cache == nil {
+		return embedderErr
+	}
+	if cacheErr := c.cache.Close(); cacheErr != nil && embedderErr == nil {
+		return cacheErr
+	}
+	return embedderErr
+}
+
+func (c *CachedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if c.cache == nil {
+		return c.Embedder.Embed(ctx, text)
+	}
+
+	key := CacheKey(c.Embedder.ModelName(), text)
+	if vec, ok := c.cache.Get(key); ok {
+		return vec, nil
+	}
+
+	vec, err := c.Embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(key, vec)
+	return vec, nil
+}
+
+func (c *CachedEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if c.cache == nil {
+		return c.Embedder.EmbedBatch(ctx, texts)
+	}
+
+	model := c.Embedder.ModelName()
+	results := make([][]float32, len(texts))
+	missIdx := make([]int, 0, len(texts))
+	missTexts := make([]string, 0, len(texts))
+
+	for i, text := range texts {
+		key := CacheKey(model, text)
+		if vec, ok := c.cache.Get(key); ok {
+			results[i] = vec
+			continue
+		}
+		missIdx = append(missIdx, i)
+		missTexts = append(missTexts, text)
+	}
+	if len(missTexts) == 0 {
+		return results, nil
+	}
+
+	vecs, err := c.Embedder.EmbedBatch(ctx, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range missIdx {
+		results[idx] = vecs[i]
+		c.cache.Set(CacheKey(model, missTexts[i]), vecs[i])
+	}
+	return results, nil
+}
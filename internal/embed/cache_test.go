@@ -0,0 +1,208 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheKey_StableAndDistinct(t *testing.T) {
+	a := CacheKey("model1", "hello")
+	b := CacheKey("model1", "hello")
+	if a != b {
+		t.Fatal("expected CacheKey to be deterministic for the same (model, text)")
+	}
+	if CacheKey("model2", "hello") == a {
+		t.Fatal("expected a different model to produce a different key")
+	}
+	if CacheKey("model1", "world") == a {
+		t.Fatal("expected different text to produce a different key")
+	}
+}
+
+func TestMemoryCache_GetSetAndStats(t *testing.T) {
+	c := NewMemoryCache(2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+	c.Set("a", []float32{1, 2})
+	vec, ok := c.Get("a")
+	if !ok || vec[0] != 1 {
+		t.Fatalf("expected a hit with the stored vector, got %v, %v", vec, ok)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []float32{1})
+	c.Set("b", []float32{2})
+	c.Get("a") // a is now most recently used; b is least recently used
+	c.Set("c", []float32{3})
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("expected b to have been evicted as the least recently used entry")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a to still be cached")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("expected c to still be cached")
+	}
+	if got := c.Stats().Evictions; got != 1 {
+		t.Fatalf("expected 1 eviction, got %d", got)
+	}
+}
+
+func TestBoltCache_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	c1, err := NewBoltCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c1.Set("key1", []float32{1.5, -2.5, 3})
+	if err := c1.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	c2, err := NewBoltCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error reopening: %v", err)
+	}
+	defer func() { _ = c2.Close() }()
+
+	vec, ok := c2.Get("key1")
+	if !ok {
+		t.Fatal("expected the entry to survive a reopen")
+	}
+	if len(vec) != 3 || vec[0] != 1.5 || vec[1] != -2.5 || vec[2] != 3 {
+		t.Fatalf("expected the exact stored vector back, got %v", vec)
+	}
+}
+
+// cacheStubEmbedder is a minimal Embedder used to test CachedEmbedder,
+// mirroring stubEmbedder in throttle_test.go. failNext makes the next
+// Embed/EmbedBatch call return an error (the failed call still counts in
+// calls), to
+// exercise CachedEmbedder's "never cache a failed call" behavior.
+type cacheStubEmbedder struct {
+	model     string
+	calls     int
+	failNext  bool
+	responses map[string][]float32
+}
+
+func (s *cacheStubEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	s.calls++
+	if s.failNext {
+		s.failNext = false
+		return nil, errors.New("cache stub: injected failure")
+	}
+	return s.responses[text], nil
+}
+func (s *cacheStubEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if s.failNext {
+		s.failNext = false
+		s.calls++
+		return nil, errors.New("cache stub: injected failure")
+	}
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i], _ = s.Embed(ctx, t)
+	}
+	return out, nil
+}
+func (s *cacheStubEmbedder) Dimensions() int                { return 2 }
+func (s *cacheStubEmbedder) ModelName() string              { return s.model }
+func (s *cacheStubEmbedder) Available(context.Context) bool { return true }
+func (s *cacheStubEmbedder) Close() error                   { return nil }
+
+func TestCachedEmbedder_ShortCircuitsOnRepeatCall(t *testing.T) {
+	base := &cacheStubEmbedder{model: "m1", responses: map[string][]float32{"hello": {1, 2}}}
+	ce := NewCachedEmbedder(base, NewMemoryCache(10))
+
+	if _, err := ce.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ce.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.calls != 1 {
+		t.Fatalf("expected the base embedder to be called exactly once, got %d", base.calls)
+	}
+
+	stats := ce.CacheStats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
+
+func TestCachedEmbedder_NeverCachesAFailedCall(t *testing.T) {
+	base := &cacheStubEmbedder{model: "m1", responses: map[string][]float32{"hello": {1, 2}}}
+	ce := NewCachedEmbedder(base, NewMemoryCache(10))
+
+	base.failNext = true
+	if _, err := ce.Embed(context.Background(), "hello"); err == nil {
+		t.Fatal("expected the injected failure to surface")
+	}
+
+	if _, err := ce.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.calls != 2 {
+		t.Fatalf("expected the failed call not to populate the cache, so a second base call was needed; got %d total calls", base.calls)
+	}
+}
+
+func TestCachedEmbedder_NilCacheIsANoOp(t *testing.T) {
+	base := &cacheStubEmbedder{model: "m1", responses: map[string][]float32{"hello": {1, 2}}}
+	ce := NewCachedEmbedder(base, nil)
+
+	if _, err := ce.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ce.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.calls != 2 {
+		t.Fatalf("expected every call to hit the base embedder with a nil cache, got %d", base.calls)
+	}
+	if stats := ce.CacheStats(); stats != (CacheStats{}) {
+		t.Fatalf("expected zero-value stats with a nil cache, got %+v", stats)
+	}
+}
+
+func TestCachedEmbedder_EmbedBatchOnlyCallsBaseForMisses(t *testing.T) {
+	base := &cacheStubEmbedder{
+		model: "m1",
+		responses: map[string][]float32{
+			"a": {1, 0},
+			"b": {0, 1},
+			"c": {1, 1},
+		},
+	}
+	ce := NewCachedEmbedder(base, NewMemoryCache(10))
+
+	if _, err := ce.Embed(context.Background(), "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base.calls = 0
+
+	vecs, err := ce.EmbedBatch(context.Background(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vecs) != 3 || vecs[0][0] != 1 || vecs[1][1] != 1 || vecs[2][0] != 1 {
+		t.Fatalf("unexpected results: %v", vecs)
+	}
+	if base.calls != 2 {
+		t.Fatalf("expected the base embedder to only be called for the 2 cache misses, got %d calls", base.calls)
+	}
+}
@@ -0,0 +1,216 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// EmbeddingCalibration holds the per-model mean/std shift Meilisearch calls
+// a "distribution shift": Model is the embedder this calibration was fit
+// against (see CalibratedEmbedder.Calibrate), and Mean/Std are per-dimension
+// statistics used to recenter and rescale a raw vector's components before
+// it's renormalized to unit length.
+type EmbeddingCalibration struct {
+	Model      string
+	Mean       []float64
+	Std        []float64
+	SampleSize int
+}
+
+// calibrationEpsilon guards Apply's division against a near-zero Std for a
+// dimension that happened to be constant across the calibration sample.
+const calibrationEpsilon = 1e-6
+
+// Apply recenters and rescales vec by c's per-dimension mean/std, then
+// renormalizes the result to unit length - matching the repo's convention
+// (see ollama_test.go's TestOllamaEmbedder_Embed_VectorIsNormalized) that
+// every embedding leaving this package has unit magnitude. Returns vec
+// unchanged if its length doesn't match len(c.Mean).
+func (c *EmbeddingCalibration) Apply(vec []float32) []float32 {
+	if c == nil || len(vec) != len(c.Mean) {
+		return vec
+	}
+
+	out := make([]float32, len(vec))
+	var sumSquares float64
+	for i, x := range vec {
+		std := c.Std[i]
+		if std < calibrationEpsilon {
+			std = calibrationEpsilon
+		}
+		shifted := (float64(x) - c.Mean[i]) / std
+		out[i] = float32(shifted)
+		sumSquares += shifted * shifted
+	}
+
+	norm := math.Sqrt(sumSquares)
+	if norm < calibrationEpsilon {
+		return out
+	}
+	for i := range out {
+		out[i] = float32(float64(out[i]) / norm)
+	}
+	return out
+}
+
+// CalibrationStats is CalibratedEmbedder.CalibrationStats' observability
+// snapshot - the calibration currently in effect, or the zero value if
+// none has been fit yet.
+type CalibrationStats struct {
+	Fitted     bool
+	Model      string
+	SampleSize int
+	Mean       []float64
+	Std        []float64
+}
+
+// CalibratedEmbedder wraps an Embedder with an optional
+// EmbeddingCalibration layer, applied after the base embedder's own
+// Embed/EmbedBatch returns and before the caller sees the vector. The base
+// embedder's own internal normalization (e.g. OllamaEmbedder.Embed's) has
+// already run by the time CalibratedEmbedder sees the vector, since
+// CalibratedEmbedder only sees what the base's Embed/EmbedBatch returns;
+// Apply's own renormalization afterward keeps the final output at unit
+// length regardless, which is what every downstream cosine-similarity
+// comparison actually relies on.
+type CalibratedEmbedder struct {
+	Embedder
+
+	mu          sync.RWMutex
+	calibration *EmbeddingCalibration
+}
+
+// NewCalibratedEmbedder wraps base with no calibration fitted yet; Embed
+// and EmbedBatch pass base's vectors through unchanged until Calibrate or
+// SetCalibration installs one.
+func NewCalibratedEmbedder(base Embedder) *CalibratedEmbedder {
+	return &CalibratedEmbedder{Embedder: base}
+}
+
+// SetCalibration installs a previously-persisted calibration. Use this to
+// restore one without re-running Calibrate's sample pass, e.g. after
+// loading EmbeddingCalibration back from wherever the caller persisted it
+// alongside the model name - calibration is only applied while
+// calib.Model matches c.Embedder.ModelName(), so swapping the underlying
+// model (even with a stale calibration still installed) safely falls back
+// to uncalibrated vectors until Calibrate is run again for the new model.
+func (c *CalibratedEmbedder) SetCalibration(calib *EmbeddingCalibration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calibration = calib
+}
+
+// Calibrate estimates a fresh EmbeddingCalibration by embedding
+// sampleTexts through the base embedder and computing each dimension's
+// mean and (population) standard deviation across the sample, then
+// installs it, tagged with the base embedder's current ModelName() so a
+// later model swap is detected rather than silently calibrating against
+// the wrong model.
+func (c *CalibratedEmbedder) Calibrate(ctx context.Context, sampleTexts []string) error {
+	if len(sampleTexts) == 0 {
+		return fmt.Errorf("embed: calibrate requires at least one sample text")
+	}
+
+	vecs, err := c.Embedder.EmbedBatch(ctx, sampleTexts)
+	if err != nil {
+		return fmt.Errorf("embed: calibrate: sample embed batch: %w", err)
+	}
+
+	dims := c.Embedder.Dimensions()
+	mean := make([]float64, dims)
+	for _, vec := range vecs {
+		for i, x := range vec {
+			mean[i] += float64(x)
+		}
+	}
+	n := float64(len(vecs))
+	for i := range mean {
+		mean[i] /= n
+	}
+
+	std := make([]float64, dims)
+	for _, vec := range vecs {
+		for i, x := range vec {
+			d := float64(x) - mean[i]
+			std[i] += d * d
+		}
+	}
+	for i := range std {
+		std[i] = math.Sqrt(std[i] / n)
+	}
+
+	c.mu.Lock()
+	c.calibration = &EmbeddingCalibration{
+		Model:      c.Embedder.ModelName(),
+		Mean:       mean,
+		Std:        std,
+		SampleSize: len(vecs),
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// CalibrationStats reports the calibration currently in effect, for
+// observability (e.g. a /health or status endpoint). Fitted is false if no
+// calibration has been installed, or if the installed one no longer
+// matches the base embedder's current model.
+func (c *CalibratedEmbedder) CalibrationStats() CalibrationStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	calib := c.activeLocked()
+	if calib == nil {
+		return CalibrationStats{}
+	}
+	return CalibrationStats{
+		Fitted:     true,
+		Model:      calib.Model,
+		SampleSize: calib.SampleSize,
+		Mean:       calib.Mean,
+		Std:        calib.Std,
+	}
+}
+
+// activeLocked returns c.calibration if it's fitted against the base
+// embedder's current model, nil otherwise. Callers must hold c.mu.
+func (c *CalibratedEmbedder) activeLocked() *EmbeddingCalibration {
+	if c.calibration == nil || c.calibration.Model != c.Embedder.ModelName() {
+		return nil
+	}
+	return c.calibration
+}
+
+// Embed returns the base embedder's vector for text, calibrated if a
+// matching-model calibration is installed.
+func (c *CalibratedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vec, err := c.Embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	calib := c.activeLocked()
+	c.mu.RUnlock()
+	return calib.Apply(vec), nil
+}
+
+// EmbedBatch returns the base embedder's vectors for texts, calibrated if
+// a matching-model calibration is installed.
+func (c *CalibratedEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs, err := c.Embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	calib := c.activeLocked()
+	c.mu.RUnlock()
+	if calib == nil {
+		return vecs, nil
+	}
+	out := make([][]float32, len(vecs))
+	for i, vec := range vecs {
+		out[i] = calib.Apply(vec)
+	}
+	return out, nil
+}
@@ -0,0 +1,136 @@
+package embed
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// calibrationStubEmbedder is a minimal Embedder used to test
+// CalibratedEmbedder without standing up a real backend, mirroring
+// stubEmbedder in throttle_test.go.
+type calibrationStubEmbedder struct {
+	model   string
+	vectors map[string][]float32
+}
+
+func (s *calibrationStubEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	return s.vectors[text], nil
+}
+func (s *calibrationStubEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i], _ = s.Embed(ctx, t)
+	}
+	return out, nil
+}
+func (s *calibrationStubEmbedder) Dimensions() int                { return 2 }
+func (s *calibrationStubEmbedder) ModelName() string              { return s.model }
+func (s *calibrationStubEmbedder) Available(context.Context) bool { return true }
+func (s *calibrationStubEmbedder) Close() error                   { return nil }
+
+func vectorMagnitude(vec []float32) float64 {
+	var sum float64
+	for _, x := range vec {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+func TestCalibratedEmbedder_PassesThroughBeforeCalibration(t *testing.T) {
+	base := &calibrationStubEmbedder{model: "m1", vectors: map[string][]float32{"a": {1, 0}}}
+	ce := NewCalibratedEmbedder(base)
+
+	vec, err := ce.Embed(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vec[0] != 1 || vec[1] != 0 {
+		t.Fatalf("expected unmodified vector before Calibrate, got %v", vec)
+	}
+	if stats := ce.CalibrationStats(); stats.Fitted {
+		t.Fatal("expected Fitted=false before Calibrate")
+	}
+}
+
+func TestCalibratedEmbedder_CalibrateFitsAndApplies(t *testing.T) {
+	base := &calibrationStubEmbedder{
+		model: "m1",
+		vectors: map[string][]float32{
+			"a": {1, 0},
+			"b": {0, 1},
+			"c": {1, 1},
+		},
+	}
+	ce := NewCalibratedEmbedder(base)
+
+	if err := ce.Calibrate(context.Background(), []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := ce.CalibrationStats()
+	if !stats.Fitted {
+		t.Fatal("expected Fitted=true after Calibrate")
+	}
+	if stats.Model != "m1" {
+		t.Fatalf("expected stats.Model %q, got %q", "m1", stats.Model)
+	}
+	if stats.SampleSize != 3 {
+		t.Fatalf("expected SampleSize 3, got %d", stats.SampleSize)
+	}
+
+	vec, err := ce.Embed(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mag := vectorMagnitude(vec); math.Abs(mag-1.0) > 1e-6 {
+		t.Fatalf("expected calibrated vector to be renormalized to unit length, got magnitude %v", mag)
+	}
+}
+
+func TestCalibratedEmbedder_FallsBackWhenModelChanges(t *testing.T) {
+	base := &calibrationStubEmbedder{
+		model:   "m1",
+		vectors: map[string][]float32{"a": {1, 0}, "b": {0, 1}},
+	}
+	ce := NewCalibratedEmbedder(base)
+	if err := ce.Calibrate(context.Background(), []string{"a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base.model = "m2"
+	vec, err := ce.Embed(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vec[0] != 1 || vec[1] != 0 {
+		t.Fatalf("expected uncalibrated passthrough after model changed, got %v", vec)
+	}
+	if stats := ce.CalibrationStats(); stats.Fitted {
+		t.Fatal("expected Fitted=false once the installed calibration's model no longer matches")
+	}
+}
+
+func TestCalibratedEmbedder_EmbedBatchCalibratesEachVector(t *testing.T) {
+	base := &calibrationStubEmbedder{
+		model: "m1",
+		vectors: map[string][]float32{
+			"a": {1, 0},
+			"b": {0, 1},
+		},
+	}
+	ce := NewCalibratedEmbedder(base)
+	if err := ce.Calibrate(context.Background(), []string{"a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vecs, err := ce.EmbedBatch(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, vec := range vecs {
+		if mag := vectorMagnitude(vec); math.Abs(mag-1.0) > 1e-6 {
+			t.Fatalf("vector %d: expected unit length, got magnitude %v", i, mag)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package embed provides the Embedder abstraction used throughout the
+// indexer and search paths, plus a set of decorators (cache, calibration,
+// throttle, truncate, budget, queue) that wrap a concrete backend without
+// the caller needing to know which one is in effect.
+package embed
+
+import "context"
+
+// Embedder turns text into a fixed-width vector. Every concrete backend
+// (MLXEmbedder, OllamaEmbedder, the remote provider.* implementations)
+// and every decorator in this package (CachedEmbedder, CalibratedEmbedder,
+// ThrottledEmbedder, TruncatedEmbedder, BudgetedEmbedder, FallbackEmbedder)
+// satisfies this same interface, so they compose by wrapping one another
+// rather than needing a type switch at the call site.
+type Embedder interface {
+	// Embed returns text's embedding. An empty or whitespace-only text
+	// returns a zero vector sized to Dimensions() rather than an error -
+	// every backend in this package follows that convention so callers
+	// don't need to special-case blank chunks.
+	Embed(ctx context.Context, text string) ([]float32, error)
+	// EmbedBatch embeds texts in order, returning one vector per input.
+	// Backends with a native batch API use it; others fall back to
+	// repeated Embed calls.
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+	// Dimensions returns the width of vectors this embedder produces.
+	Dimensions() int
+	// ModelName identifies the model backing this embedder, for
+	// checkpointing (see store.IndexCheckpoint) and cache keying.
+	ModelName() string
+	// Available reports whether the backend is currently reachable and
+	// ready to serve Embed/EmbedBatch calls.
+	Available(ctx context.Context) bool
+	// Close releases any resources (HTTP connections, local processes)
+	// the embedder holds. Safe to call more than once.
+	Close() error
+}
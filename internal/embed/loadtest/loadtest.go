@@ -0,0 +1,464 @@
+// Package loadtest drives an embed.Embedder with synthetic traffic to
+// characterize its throughput and tail latency under load, the same role
+// Coder's loadtest/harness package plays for workspace agent connections:
+// a configurable worker pool fires Embed/EmbedBatch calls (closed-loop or
+// at a fixed rate) for a fixed duration, classifies every failure, and
+// reduces the results to a JSON-friendly Report. It exists to make tuning
+// OllamaConfig's PoolSize, BatchSize, TimeoutProgression, and
+// RetryTimeoutMultiplier against real hardware a repeatable measurement
+// instead of guesswork - this package drives the Embedder interface
+// rather than setting OllamaConfig's fields directly.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+)
+
+// Distribution samples an integer in [Min, Max] inclusive, uniformly -
+// used for both BatchSize (how many texts per EmbedBatch call) and
+// TextLength (how many characters per text). Max <= Min degenerates to
+// the fixed value Min, so a caller that wants a constant batch size or
+// text length just sets Min == Max.
+type Distribution struct {
+	Min int
+	Max int
+}
+
+func (d Distribution) sample(rnd *rand.Rand) int {
+	if d.Max <= d.Min {
+		return d.Min
+	}
+	return d.Min + rnd.Intn(d.Max-d.Min+1)
+}
+
+// Config controls a Harness run. Concurrency, BatchSize, and TextLength
+// must be set to produce any load at all; the rest have workable zero
+// values (see DefaultConfig).
+type Config struct {
+	// Concurrency is how many workers issue requests in parallel.
+	Concurrency int
+	// Duration bounds how long Run drives traffic for. Run also returns
+	// early if ctx is cancelled first.
+	Duration time.Duration
+	// RPS is the target aggregate request rate across all workers. Zero
+	// (the default) runs closed-loop: each worker fires its next request
+	// as soon as the previous one completes, so achieved throughput is
+	// whatever the Embedder can sustain rather than a fixed target.
+	RPS float64
+
+	// BatchSize samples how many texts each EmbedBatch call carries.
+	BatchSize Distribution
+	// TextLength samples each text's length in characters.
+	TextLength Distribution
+
+	// RequestTimeout bounds each attempt (the first try and every retry
+	// separately) via context.WithTimeout. Zero disables the per-attempt timeout,
+	// leaving ctx's own deadline (if any) as the only bound.
+	RequestTimeout time.Duration
+	// TimeoutProgression scales RequestTimeout up as the run progresses,
+	// mirroring OllamaEmbedder.getProgressiveTimeout's own linear ramp:
+	// a request issued at the very end of Duration gets
+	// RequestTimeout * TimeoutProgression; a request issued at the start
+	// gets exactly RequestTimeout. 1.0 or less disables the ramp.
+	TimeoutProgression float64
+
+	// MaxRetries is how many additional attempts a failed request gets
+	// before being recorded as a failure. Zero means no retries.
+	MaxRetries int
+	// RetryBackoff is the base delay before the first retry.
+	RetryBackoff time.Duration
+	// RetryTimeoutMultiplier scales RequestTimeout up on each retry
+	// (attempt N's timeout is RequestTimeout * RetryTimeoutMultiplier^N),
+	// mirroring OllamaConfig's knob of the same name. 1.0 or less leaves
+	// the timeout unchanged across retries.
+	RetryTimeoutMultiplier float64
+}
+
+// DefaultConfig returns a modest closed-loop configuration: 4 workers,
+// batches of 1-8 texts, 50-500 character texts, a 30s request timeout, and
+// no retries - a reasonable starting point before a caller dials in
+// values for its own hardware.
+func DefaultConfig() Config {
+	return Config{
+		Concurrency:    4,
+		Duration:       30 * time.Second,
+		BatchSize:      Distribution{Min: 1, Max: 8},
+		TextLength:     Distribution{Min: 50, Max: 500},
+		RequestTimeout: 30 * time.Second,
+	}
+}
+
+// Error classes a RequestResult's Err is bucketed into. Unlike the typed
+// errors BudgetedEmbedder/FallbackEmbedder define for their own failure
+// modes, every Embedder implementation in this tree (and any a caller
+// plugs in) wraps its own errors with fmt.Errorf, so classification here
+// is necessarily heuristic: context.Err(), the net package's error
+// interfaces, and a best-effort regex over the error string for an HTTP
+// status code.
+const (
+	ErrorClassNone              = ""
+	ErrorClassTimeout           = "timeout"
+	ErrorClassContextCanceled   = "context_canceled"
+	ErrorClassConnectionRefused = "connection_refused"
+	ErrorClassServerError       = "server_error"
+	ErrorClassOther             = "other"
+)
+
+var serverErrorPattern = regexp.MustCompile(`\bstatus(?: code)? (?:5\d\d)\b`)
+
+// classifyError buckets err into one of the ErrorClass constants. A nil
+// err is never called with this - callers only classify a non-nil error.
+func classifyError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassContextCanceled
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassTimeout
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "connection refused") {
+		return ErrorClassConnectionRefused
+	}
+	if serverErrorPattern.MatchString(msg) {
+		return ErrorClassServerError
+	}
+	return ErrorClassOther
+}
+
+// RequestResult is one attempted EmbedBatch call's outcome, the unit Run
+// collects before reducing everything into a Report.
+type RequestResult struct {
+	Latency     time.Duration
+	Err         error
+	ErrorClass  string
+	Retries     int
+	TimeoutUsed time.Duration
+	BatchSize   int
+	TotalChars  int
+}
+
+// Harness drives embedder with synthetic traffic shaped by cfg.
+type Harness struct {
+	embedder embed.Embedder
+	cfg      Config
+}
+
+// NewHarness builds a Harness. cfg is used as given - callers that want
+// DefaultConfig's values should start from it and override what they need.
+func NewHarness(embedder embed.Embedder, cfg Config) *Harness {
+	return &Harness{embedder: embedder, cfg: cfg}
+}
+
+// Report summarizes a completed Run: latency percentiles, throughput, and
+// an error breakdown, shaped to be marshaled straight to JSON by the
+// embed-loadtest CLI command.
+type Report struct {
+	Requests  int `json:"requests"`
+	Successes int `json:"successes"`
+	Failures  int `json:"failures"`
+
+	DurationSeconds float64 `json:"duration_seconds"`
+
+	P50Millis  float64 `json:"p50_ms"`
+	P90Millis  float64 `json:"p90_ms"`
+	P99Millis  float64 `json:"p99_ms"`
+	P999Millis float64 `json:"p999_ms"`
+
+	EmbeddingsPerSec float64 `json:"embeddings_per_sec"`
+	TokensPerSec     float64 `json:"tokens_per_sec"`
+
+	ErrorBreakdown map[string]int `json:"error_breakdown,omitempty"`
+}
+
+// Run drives traffic against the Harness's Embedder for cfg.Duration (or
+// until ctx is cancelled, whichever comes first) and returns the reduced
+// Report. Run is itself the unit under test in this package's tests -
+// those exercise correctness (distributions respected, retries counted,
+// errors classified, percentiles computed right) rather than capacity;
+// measuring real throughput against a real backend is what the
+// embed-loadtest CLI command is for.
+func (h *Harness) Run(ctx context.Context) (*Report, error) {
+	if h.cfg.Concurrency <= 0 {
+		return nil, fmt.Errorf("loadtest: Concurrency must be positive, got %d", h.cfg.Concurrency)
+	}
+
+	runCtx := ctx
+	var cancel context.CancelFunc
+	if h.cfg.Duration > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, h.cfg.Duration)
+		defer cancel()
+	}
+
+	start := time.Now()
+	resultsCh := make(chan RequestResult, h.cfg.Concurrency*2)
+
+	var limiter *rateLimiter
+	if h.cfg.RPS > 0 {
+		limiter = newRateLimiter(h.cfg.RPS)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < h.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(workerSeed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(workerSeed))
+			for {
+				if limiter != nil {
+					if err := limiter.wait(runCtx); err != nil {
+						return
+					}
+				}
+				select {
+				case <-runCtx.Done():
+					return
+				default:
+				}
+				resultsCh <- h.doRequest(runCtx, rnd, start)
+			}
+		}(int64(i + 1))
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	var results []RequestResult
+	for r := range resultsCh {
+		results = append(results, r)
+	}
+
+	return reduce(results, time.Since(start)), nil
+}
+
+// doRequest samples a batch, then attempts it up to h.cfg.MaxRetries+1
+// times, scaling the per-attempt timeout by RetryTimeoutMultiplier on
+// each retry and by TimeoutProgression as elapsed approaches
+// h.cfg.Duration, and sleeping RetryBackoff (doubling each retry) between
+// attempts.
+func (h *Harness) doRequest(ctx context.Context, rnd *rand.Rand, start time.Time) RequestResult {
+	batchSize := h.cfg.BatchSize.sample(rnd)
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	texts := make([]string, batchSize)
+	totalChars := 0
+	for i := range texts {
+		n := h.cfg.TextLength.sample(rnd)
+		texts[i] = randomText(rnd, n)
+		totalChars += len(texts[i])
+	}
+
+	progression := h.cfg.TimeoutProgression
+	if progression < 1 {
+		progression = 1
+	}
+	baseTimeout := h.cfg.RequestTimeout
+	if h.cfg.Duration > 0 && baseTimeout > 0 {
+		elapsedFrac := float64(time.Since(start)) / float64(h.cfg.Duration)
+		if elapsedFrac > 1 {
+			elapsedFrac = 1
+		}
+		scaled := float64(baseTimeout) * (1 + (progression-1)*elapsedFrac)
+		baseTimeout = time.Duration(scaled)
+	}
+
+	retryMultiplier := h.cfg.RetryTimeoutMultiplier
+	if retryMultiplier < 1 {
+		retryMultiplier = 1
+	}
+
+	requestStart := time.Now()
+	var lastErr error
+	var timeoutUsed time.Duration
+	attempts := h.cfg.MaxRetries + 1
+	backoff := h.cfg.RetryBackoff
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		timeoutUsed = baseTimeout
+		for i := 0; i < attempt; i++ {
+			timeoutUsed = time.Duration(float64(timeoutUsed) * retryMultiplier)
+		}
+
+		attemptCtx := ctx
+		var attemptCancel context.CancelFunc
+		if timeoutUsed > 0 {
+			attemptCtx, attemptCancel = context.WithTimeout(ctx, timeoutUsed)
+		}
+		_, lastErr = h.embedder.EmbedBatch(attemptCtx, texts)
+		if attemptCancel != nil {
+			attemptCancel()
+		}
+
+		if lastErr == nil {
+			return RequestResult{
+				Latency:     time.Since(requestStart),
+				Retries:     attempt,
+				TimeoutUsed: timeoutUsed,
+				BatchSize:   batchSize,
+				TotalChars:  totalChars,
+			}
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+		if attempt < attempts-1 && backoff > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+			}
+			backoff *= 2
+		}
+	}
+
+	return RequestResult{
+		Latency:     time.Since(requestStart),
+		Err:         lastErr,
+		ErrorClass:  classifyError(lastErr),
+		Retries:     attempts - 1,
+		TimeoutUsed: timeoutUsed,
+		BatchSize:   batchSize,
+		TotalChars:  totalChars,
+	}
+}
+
+// reduce turns raw RequestResults into a Report: percentiles over every
+// attempted request's latency (successes and failures alike - a slow
+// failure is still latency a caller tuning timeouts cares about),
+// throughput computed only from successful requests, and a count per
+// ErrorClass.
+func reduce(results []RequestResult, wallClock time.Duration) *Report {
+	report := &Report{
+		Requests:        len(results),
+		DurationSeconds: wallClock.Seconds(),
+		ErrorBreakdown:  make(map[string]int),
+	}
+	if len(results) == 0 {
+		return report
+	}
+
+	latencies := make([]time.Duration, len(results))
+	var embeddings, totalChars int
+	for i, r := range results {
+		latencies[i] = r.Latency
+		if r.Err == nil {
+			report.Successes++
+			embeddings += r.BatchSize
+			totalChars += r.TotalChars
+		} else {
+			report.Failures++
+			report.ErrorBreakdown[r.ErrorClass]++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	report.P50Millis = percentileMillis(latencies, 0.50)
+	report.P90Millis = percentileMillis(latencies, 0.90)
+	report.P99Millis = percentileMillis(latencies, 0.99)
+	report.P999Millis = percentileMillis(latencies, 0.999)
+
+	if wallClock > 0 {
+		report.EmbeddingsPerSec = float64(embeddings) / wallClock.Seconds()
+		report.TokensPerSec = float64(approxTokens(totalChars)) / wallClock.Seconds()
+	}
+	return report
+}
+
+// percentileMillis returns the p-th percentile (0 < p <= 1) of a
+// pre-sorted ascending slice of latencies, in milliseconds.
+func percentileMillis(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p*float64(len(sorted))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return float64(sorted[idx]) / float64(time.Millisecond)
+}
+
+// approxTokens estimates a token count from a character count using the
+// common ~4-characters-per-token rule of thumb. It's a rough enough
+// estimate to compare tokens/sec across runs of this harness, not a
+// substitute for the real tokenizer the embedding model behind Embedder
+// actually uses.
+func approxTokens(chars int) int {
+	if chars <= 0 {
+		return 0
+	}
+	tokens := chars / 4
+	if tokens < 1 {
+		tokens = 1
+	}
+	return tokens
+}
+
+// textFiller is repeated to synthesize text of an arbitrary sampled
+// length; its content doesn't matter to the Embedder under test, only its
+// length (TextLength) and count (BatchSize) do.
+const textFiller = "the quick brown fox jumps over the lazy dog "
+
+func randomText(rnd *rand.Rand, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(n)
+	for b.Len() < n {
+		b.WriteString(textFiller)
+	}
+	s := b.String()[:n]
+	// Perturb the start offset per call so repeated calls at the same n
+	// don't all hash to literally the same string, for callers (e.g.
+	// CachedEmbedder) where that would mean the load test only ever
+	// measures cache hits after the first request of each size.
+	offset := rnd.Intn(len(textFiller))
+	if offset >= len(s) {
+		return s
+	}
+	return s[offset:] + s[:offset]
+}
+
+// rateLimiter paces requests to a target RPS across all of a Harness's
+// workers, shared via a single ticker channel - simpler than a per-worker
+// rate since workers otherwise have no reason to coordinate.
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+func newRateLimiter(rps float64) *rateLimiter {
+	interval := time.Duration(float64(time.Second) / rps)
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+	return &rateLimiter{ticker: time.NewTicker(interval)}
+}
+
+func (r *rateLimiter) wait(ctx context.Context) error {
+	select {
+	case <-r.ticker.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,208 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// loadtestStubEmbedder is a minimal embed.Embedder used to test Harness,
+// mirroring stubEmbedder in throttle_test.go. failUntil lets a test make
+// the first N EmbedBatch calls fail before succeeding.
+type loadtestStubEmbedder struct {
+	dims      int
+	failUntil int32
+	calls     int32
+
+	mu         sync.Mutex
+	batchSizes []int
+	textLens   []int
+}
+
+func (s *loadtestStubEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := s.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+func (s *loadtestStubEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	n := atomic.AddInt32(&s.calls, 1)
+
+	s.mu.Lock()
+	s.batchSizes = append(s.batchSizes, len(texts))
+	for _, t := range texts {
+		s.textLens = append(s.textLens, len(t))
+	}
+	s.mu.Unlock()
+
+	if n <= s.failUntil {
+		return nil, fmt.Errorf("stub api error: status 503")
+	}
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = make([]float32, s.dims)
+	}
+	return out, nil
+}
+
+func (s *loadtestStubEmbedder) Dimensions() int                { return s.dims }
+func (s *loadtestStubEmbedder) ModelName() string              { return "stub" }
+func (s *loadtestStubEmbedder) Available(context.Context) bool { return true }
+func (s *loadtestStubEmbedder) Close() error                   { return nil }
+
+func TestDistribution_Sample(t *testing.T) {
+	fixed := Distribution{Min: 5, Max: 5}
+	if got := fixed.sample(nil); got != 5 {
+		t.Fatalf("fixed distribution: expected 5, got %d", got)
+	}
+
+	ranged := Distribution{Min: 3, Max: 3}
+	if got := ranged.sample(nil); got != 3 {
+		t.Fatalf("degenerate range: expected 3, got %d", got)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"deadline exceeded", context.DeadlineExceeded, ErrorClassTimeout},
+		{"wrapped deadline exceeded", fmt.Errorf("call: %w", context.DeadlineExceeded), ErrorClassTimeout},
+		{"context canceled", context.Canceled, ErrorClassContextCanceled},
+		{"connection refused", errors.New("dial tcp: connection refused"), ErrorClassConnectionRefused},
+		{"server error", errors.New("openai api error (status 503): overloaded"), ErrorClassServerError},
+		{"other", errors.New("malformed response"), ErrorClassOther},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := classifyError(tc.err); got != tc.want {
+				t.Fatalf("classifyError(%q) = %q, want %q", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHarness_RunRespectsBatchSizeAndTextLengthDistributions(t *testing.T) {
+	stub := &loadtestStubEmbedder{dims: 4}
+	cfg := Config{
+		Concurrency: 2,
+		Duration:    100 * time.Millisecond,
+		BatchSize:   Distribution{Min: 2, Max: 4},
+		TextLength:  Distribution{Min: 10, Max: 20},
+	}
+	h := NewHarness(stub, cfg)
+	report, err := h.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Requests == 0 {
+		t.Fatal("expected at least one request to have been issued")
+	}
+	if report.Failures != 0 {
+		t.Fatalf("expected no failures, got %d", report.Failures)
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	for _, n := range stub.batchSizes {
+		if n < 2 || n > 4 {
+			t.Fatalf("batch size %d outside configured [2,4] range", n)
+		}
+	}
+	for _, n := range stub.textLens {
+		if n < 10 || n > 20 {
+			t.Fatalf("text length %d outside configured [10,20] range", n)
+		}
+	}
+}
+
+func TestHarness_RunCountsRetriesAndSucceedsAfterTransientFailures(t *testing.T) {
+	stub := &loadtestStubEmbedder{dims: 4, failUntil: 2}
+	cfg := Config{
+		Concurrency:  1,
+		Duration:     time.Second,
+		BatchSize:    Distribution{Min: 1, Max: 1},
+		TextLength:   Distribution{Min: 5, Max: 5},
+		MaxRetries:   5,
+		RetryBackoff: time.Millisecond,
+	}
+	h := NewHarness(stub, cfg)
+
+	result := h.doRequest(context.Background(), rand.New(rand.NewSource(1)), time.Now())
+	if result.Err != nil {
+		t.Fatalf("expected eventual success, got %v", result.Err)
+	}
+	if result.Retries != 2 {
+		t.Fatalf("expected 2 retries before success, got %d", result.Retries)
+	}
+}
+
+func TestHarness_RunRecordsFailureAfterExhaustingRetries(t *testing.T) {
+	stub := &loadtestStubEmbedder{dims: 4, failUntil: 1000}
+	cfg := Config{
+		Concurrency:  1,
+		Duration:     time.Second,
+		BatchSize:    Distribution{Min: 1, Max: 1},
+		TextLength:   Distribution{Min: 5, Max: 5},
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	}
+	h := NewHarness(stub, cfg)
+
+	result := h.doRequest(context.Background(), rand.New(rand.NewSource(1)), time.Now())
+	if result.Err == nil {
+		t.Fatal("expected a failure once retries are exhausted")
+	}
+	if result.Retries != 2 {
+		t.Fatalf("expected 2 retries consumed, got %d", result.Retries)
+	}
+	if result.ErrorClass != ErrorClassServerError {
+		t.Fatalf("expected server_error classification, got %q", result.ErrorClass)
+	}
+}
+
+func TestReduce_ComputesPercentilesThroughputAndErrorBreakdown(t *testing.T) {
+	results := []RequestResult{
+		{Latency: 10 * time.Millisecond, BatchSize: 2, TotalChars: 40},
+		{Latency: 20 * time.Millisecond, BatchSize: 2, TotalChars: 40},
+		{Latency: 30 * time.Millisecond, BatchSize: 2, TotalChars: 40},
+		{Latency: 40 * time.Millisecond, BatchSize: 2, TotalChars: 40},
+		{Latency: 1000 * time.Millisecond, Err: errors.New("boom"), ErrorClass: ErrorClassOther},
+	}
+	report := reduce(results, 2*time.Second)
+
+	if report.Requests != 5 || report.Successes != 4 || report.Failures != 1 {
+		t.Fatalf("unexpected counts: %+v", report)
+	}
+	if report.ErrorBreakdown[ErrorClassOther] != 1 {
+		t.Fatalf("expected 1 'other' error, got %d", report.ErrorBreakdown[ErrorClassOther])
+	}
+	if report.P50Millis <= 0 {
+		t.Fatalf("expected a positive p50, got %v", report.P50Millis)
+	}
+	if report.P999Millis < report.P50Millis {
+		t.Fatalf("expected p999 (%v) >= p50 (%v)", report.P999Millis, report.P50Millis)
+	}
+	if report.EmbeddingsPerSec != 4 {
+		t.Fatalf("expected 4 embeddings/sec over 2s with 8 successful embeddings, got %v", report.EmbeddingsPerSec)
+	}
+	if report.TokensPerSec <= 0 {
+		t.Fatalf("expected a positive tokens/sec, got %v", report.TokensPerSec)
+	}
+}
+
+func TestReduce_EmptyResultsDoesNotPanic(t *testing.T) {
+	report := reduce(nil, time.Second)
+	if report.Requests != 0 || report.Successes != 0 || report.Failures != 0 {
+		t.Fatalf("expected a zero-value report, got %+v", report)
+	}
+}
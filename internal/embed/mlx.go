@@ -0,0 +1,352 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMLXEndpoint is the local MLX embedding server's default
+	// address (see amanmcp-mlx-server, which listens on 9659 by default).
+	DefaultMLXEndpoint = "http://localhost:9659"
+	// DefaultMLXModel favors the 0.6B model for memory efficiency - the
+	// same TASK-MEM1 rationale DefaultOllamaConfig follows.
+	DefaultMLXModel = "small"
+
+	// mlxConstructTimeout bounds the /health and /models probes
+	// NewMLXEmbedder makes at construction time.
+	mlxConstructTimeout = 10 * time.Second
+	// mlxBaseTimeout is getProgressiveTimeout's floor, at batch index 0.
+	mlxBaseTimeout = 60 * time.Second
+	// mlxProgressionBatches is how many batches it takes for progression
+	// to climb by a full 1x, before mlxMaxProgression caps it.
+	mlxProgressionBatches = 50.0
+	// mlxMaxProgression caps how much getProgressiveTimeout scales
+	// mlxBaseTimeout by, before any final-batch boost.
+	mlxMaxProgression = 2.0
+	// mlxFinalBatchBoost further scales the (already-capped) progressive
+	// timeout once SetFinalBatch(true) has been called, giving the last
+	// batch of a run extra headroom to finish rather than racing a
+	// deadline identical to every batch before it.
+	mlxFinalBatchBoost = 1.5
+	// DefaultMLXMaxRetries bounds how many times EmbedBatch retries a
+	// failed request before giving up; every error is retried, whatever
+	// its status or cause.
+	DefaultMLXMaxRetries = 3
+)
+
+// MLXConfig configures an MLXEmbedder against a local MLX embedding
+// server (see cmd/amanmcp-mlx-server).
+type MLXConfig struct {
+	// Endpoint is the server's base URL. Defaults to DefaultMLXEndpoint.
+	Endpoint string
+	// Model selects which of the server's loaded models to embed with
+	// (e.g. "small", "medium", "large"). Defaults to DefaultMLXModel.
+	Model string
+	// SkipHealthCheck skips the /health probe NewMLXEmbedder otherwise
+	// makes at construction time, for callers that want to construct
+	// against a server that isn't up yet and check Available themselves
+	// before the first real call.
+	SkipHealthCheck bool
+}
+
+// DefaultMLXConfig returns an MLXConfig pointed at the local MLX server's
+// default endpoint and its smallest model.
+func DefaultMLXConfig() MLXConfig {
+	return MLXConfig{
+		Endpoint: DefaultMLXEndpoint,
+		Model:    DefaultMLXModel,
+	}
+}
+
+// MLXEmbedder embeds text via a local MLX embedding server's /embed and
+// /embed_batch endpoints.
+type MLXEmbedder struct {
+	cfg        MLXConfig
+	httpClient *http.Client
+	dimensions int
+
+	// batchIndex and finalBatch track where this embedder is within a
+	// larger indexing run, so getProgressiveTimeout can give later
+	// batches (which tend to land after the server has been under
+	// sustained load longest, e.g. thermal throttling on Apple Silicon)
+	// more time before giving up.
+	batchIndex atomic.Int64
+	finalBatch atomic.Bool
+}
+
+type mlxHealthResponse struct {
+	Status      string `json:"status"`
+	ModelStatus string `json:"model_status"`
+	LoadedModel string `json:"loaded_model"`
+}
+
+type mlxModelsResponse struct {
+	Models map[string]struct {
+		Dimensions int `json:"dimensions"`
+	} `json:"models"`
+}
+
+// NewMLXEmbedder connects to cfg.Endpoint, probing /health (unless
+// cfg.SkipHealthCheck) and /models to resolve cfg.Model's dimensions.
+func NewMLXEmbedder(ctx context.Context, cfg MLXConfig) (*MLXEmbedder, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultMLXEndpoint
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultMLXModel
+	}
+
+	e := &MLXEmbedder{
+		cfg:        cfg,
+		httpClient: &http.Client{},
+	}
+
+	if !cfg.SkipHealthCheck {
+		healthy, err := e.checkHealth(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("mlx: health check: %w", err)
+		}
+		if !healthy {
+			return nil, fmt.Errorf("mlx: server at %s is not healthy", cfg.Endpoint)
+		}
+	}
+
+	dims, err := e.fetchModelDimensions(ctx, cfg.Model)
+	if err != nil {
+		return nil, fmt.Errorf("mlx: resolve dimensions for model %q: %w", cfg.Model, err)
+	}
+	e.dimensions = dims
+
+	return e, nil
+}
+
+// checkHealth reports whether the server's /health endpoint is reachable
+// and reports status "healthy".
+func (e *MLXEmbedder) checkHealth(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, mlxConstructTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.cfg.Endpoint+"/health", nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+
+	var health mlxHealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return false, fmt.Errorf("decode health response: %w", err)
+	}
+	return health.Status == "healthy", nil
+}
+
+// fetchModelDimensions queries /models and returns model's dimensions.
+func (e *MLXEmbedder) fetchModelDimensions(ctx context.Context, model string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, mlxConstructTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.cfg.Endpoint+"/models", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+
+	var parsed mlxModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return 0, fmt.Errorf("decode models response: %w", err)
+	}
+	info, ok := parsed.Models[model]
+	if !ok {
+		return 0, fmt.Errorf("model %q not found in server's /models response", model)
+	}
+	return info.Dimensions, nil
+}
+
+// Embed returns text's embedding. An empty or whitespace-only text
+// returns a zero vector without calling the server.
+func (e *MLXEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if strings.TrimSpace(text) == "" {
+		return make([]float32, e.dimensions), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.getProgressiveTimeout())
+	defer cancel()
+
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return nil, fmt.Errorf("mlx: marshal embed request: %w", err)
+	}
+
+	var parsed struct {
+		Embedding []float64 `json:"embedding"`
+	}
+	if err := e.postJSON(ctx, "/embed", body, &parsed); err != nil {
+		return nil, fmt.Errorf("mlx: embed: %w", err)
+	}
+	return toFloat32(parsed.Embedding), nil
+}
+
+// EmbedBatch embeds texts in a single /embed_batch request, retrying
+// transient failures with jittered exponential backoff.
+func (e *MLXEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(map[string][]string{"texts": texts})
+	if err != nil {
+		return nil, fmt.Errorf("mlx: marshal embed_batch request: %w", err)
+	}
+
+	var lastErr error
+	backoff := 500 * time.Millisecond
+	const maxBackoff = 10 * time.Second
+
+	for attempt := 0; attempt <= DefaultMLXMaxRetries; attempt++ {
+		if attempt > 0 {
+			jitter := 0.5 + rand.Float64()
+			wait := time.Duration(float64(backoff) * jitter)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, e.getProgressiveTimeout())
+		var parsed struct {
+			Embeddings [][]float64 `json:"embeddings"`
+		}
+		err := e.postJSON(reqCtx, "/embed_batch", body, &parsed)
+		cancel()
+		if err == nil {
+			vecs := make([][]float32, len(parsed.Embeddings))
+			for i, emb := range parsed.Embeddings {
+				vecs[i] = toFloat32(emb)
+			}
+			return vecs, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("mlx: embed_batch: after %d attempts: %w", DefaultMLXMaxRetries+1, lastErr)
+}
+
+// postJSON POSTs body to e.cfg.Endpoint+path and decodes the response
+// into out, treating any non-2xx status as an error.
+func (e *MLXEmbedder) postJSON(ctx context.Context, path string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// toFloat32 narrows a []float64 response body to the []float32 every
+// Embedder in this package returns.
+func toFloat32(v []float64) []float32 {
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(x)
+	}
+	return out
+}
+
+// Dimensions returns the configured model's embedding width, resolved at
+// construction time.
+func (e *MLXEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// ModelName identifies the embedder for checkpointing and cache keying.
+func (e *MLXEmbedder) ModelName() string {
+	return "mlx-qwen3-embedding-" + e.cfg.Model
+}
+
+// Available reports whether the server is currently reachable and
+// healthy.
+func (e *MLXEmbedder) Available(ctx context.Context) bool {
+	healthy, err := e.checkHealth(ctx)
+	return err == nil && healthy
+}
+
+// Close releases the embedder's idle HTTP connections. Safe to call more
+// than once; the server process itself is out of process and outlives
+// any one MLXEmbedder.
+func (e *MLXEmbedder) Close() error {
+	e.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// SetBatchIndex records this embedder's position within a larger
+// indexing run, consulted by getProgressiveTimeout.
+func (e *MLXEmbedder) SetBatchIndex(index int) {
+	e.batchIndex.Store(int64(index))
+}
+
+// SetFinalBatch marks whether the next call is the last batch of a run,
+// consulted by getProgressiveTimeout.
+func (e *MLXEmbedder) SetFinalBatch(final bool) {
+	e.finalBatch.Store(final)
+}
+
+// getProgressiveTimeout scales mlxBaseTimeout up as batchIndex grows
+// (capped at mlxMaxProgression), then applies mlxFinalBatchBoost on top
+// once SetFinalBatch(true) has been called - later batches in a long
+// indexing run tend to land after the server has been under sustained
+// load the longest (e.g. thermal throttling on Apple Silicon), so they
+// get correspondingly more time before a call is given up on as timed
+// out.
+func (e *MLXEmbedder) getProgressiveTimeout() time.Duration {
+	progression := 1.0 + float64(e.batchIndex.Load())/mlxProgressionBatches
+	if progression > mlxMaxProgression {
+		progression = mlxMaxProgression
+	}
+
+	timeout := time.Duration(float64(mlxBaseTimeout) * progression)
+	if e.finalBatch.Load() {
+		timeout = time.Duration(float64(timeout) * mlxFinalBatchBoost)
+	}
+	return timeout
+}
+
+var _ Embedder = (*MLXEmbedder)(nil)
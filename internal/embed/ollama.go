@@ -0,0 +1,402 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed/vecutil"
+)
+
+const (
+	// DefaultOllamaHost is the local Ollama server's default address.
+	DefaultOllamaHost = "http://localhost:11434"
+	// DefaultOllamaModel favors the 0.6B model for memory efficiency - the
+	// same TASK-MEM1 rationale DefaultMLXConfig follows.
+	DefaultOllamaModel = "qwen3-embedding:0.6b"
+	// DefaultBatchSize bounds how many texts EmbedBatch sends to a single
+	// /api/embed call.
+	DefaultBatchSize = 32
+
+	// ollamaProgressiveBaseMultiplier scales cfg.Timeout up to
+	// getProgressiveTimeout's floor at batch index 0 - Ollama's own request
+	// handling (model load, KV cache growth) runs hotter than cfg.Timeout
+	// alone accounts for, so the progressive floor starts above it.
+	ollamaProgressiveBaseMultiplier = 2.0
+	// ollamaMaxProgression caps how much getProgressiveTimeout scales its
+	// base timeout by as batchIndex grows, before any final-batch boost.
+	ollamaMaxProgression = 3.0
+	// ollamaFinalBatchBoost further scales the (already-capped) progressive
+	// timeout once SetFinalBatch(true) has been called, mirroring
+	// mlxFinalBatchBoost's rationale in mlx.go.
+	ollamaFinalBatchBoost = 1.5
+)
+
+// OllamaConfig configures an OllamaEmbedder against a local Ollama server.
+type OllamaConfig struct {
+	// Host is the server's base URL. Defaults to DefaultOllamaHost.
+	Host string
+	// Model selects which pulled model to embed with. Defaults to
+	// DefaultOllamaModel.
+	Model string
+	// Dimensions is the model's embedding width. Zero auto-detects it at
+	// construction time with a probe /api/embed call.
+	Dimensions int
+	// BatchSize bounds how many texts EmbedBatch sends to a single
+	// /api/embed call. Defaults to DefaultBatchSize.
+	BatchSize int
+	// Timeout is the nominal per-request timeout getProgressiveTimeout
+	// scales up from. Defaults to 60s.
+	Timeout time.Duration
+	// ConnectTimeout bounds the /api/tags reachability probe NewOllamaEmbedder
+	// makes at construction time (unless SkipHealthCheck). Defaults to 5s.
+	ConnectTimeout time.Duration
+	// MaxRetries bounds how many attempts a single embed request gets
+	// before giving up. Defaults to 3.
+	MaxRetries int
+	// PoolSize bounds idle HTTP connections kept open per host. Defaults
+	// to 4.
+	PoolSize int
+	// FallbackModels are code-optimized models NewOllamaEmbedder's callers
+	// may try in order if Model isn't available on the server.
+	FallbackModels []string
+	// SkipHealthCheck skips the /api/tags probe NewOllamaEmbedder otherwise
+	// makes at construction time, for callers that want to construct
+	// against a server that isn't up yet and check Available themselves
+	// before the first real call.
+	SkipHealthCheck bool
+	// TimeoutProgression controls how much getProgressiveTimeout grows per
+	// 1000 chunks processed (e.g. 2.0 means a 100% increase per 1000
+	// chunks). 1.0 disables batch-driven growth.
+	TimeoutProgression float64
+	// RetryTimeoutMultiplier controls how much getProgressiveTimeout grows
+	// per retry attempt (e.g. 1.5 means a 50% increase per retry). 1.0
+	// disables retry-driven growth.
+	RetryTimeoutMultiplier float64
+}
+
+// DefaultOllamaConfig returns an OllamaConfig pointed at the local Ollama
+// server's default host and its smallest code-capable model.
+func DefaultOllamaConfig() OllamaConfig {
+	return OllamaConfig{
+		Host:                   DefaultOllamaHost,
+		Model:                  DefaultOllamaModel,
+		Dimensions:             0,
+		BatchSize:              DefaultBatchSize,
+		Timeout:                60 * time.Second,
+		ConnectTimeout:         5 * time.Second,
+		MaxRetries:             3,
+		PoolSize:               4,
+		FallbackModels:         []string{"embeddinggemma", "nomic-embed-code"},
+		TimeoutProgression:     1.0,
+		RetryTimeoutMultiplier: 1.0,
+	}
+}
+
+// OllamaEmbedder embeds text via a local Ollama server's /api/embed
+// endpoint, using its native batch support when EmbedBatch is called with
+// more than one text.
+type OllamaEmbedder struct {
+	cfg        OllamaConfig
+	httpClient *http.Client
+	dimensions int
+	closed     atomic.Bool
+
+	// batchIndex and finalBatch track where this embedder is within a
+	// larger indexing run, so getProgressiveTimeout can give later batches
+	// more time before giving up - see mlx.go's MLXEmbedder for the same
+	// pattern.
+	batchIndex atomic.Int64
+	finalBatch atomic.Bool
+}
+
+// NewOllamaEmbedder connects to cfg.Host, probing /api/tags (unless
+// cfg.SkipHealthCheck) and, if cfg.Dimensions is zero, auto-detecting the
+// model's dimensions with a probe /api/embed call.
+func NewOllamaEmbedder(ctx context.Context, cfg OllamaConfig) (*OllamaEmbedder, error) {
+	if cfg.Host == "" {
+		cfg.Host = DefaultOllamaHost
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultOllamaModel
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60 * time.Second
+	}
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = 5 * time.Second
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 1
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 4
+	}
+	if cfg.TimeoutProgression <= 0 {
+		cfg.TimeoutProgression = 1.0
+	}
+	if cfg.RetryTimeoutMultiplier <= 0 {
+		cfg.RetryTimeoutMultiplier = 1.0
+	}
+
+	e := &OllamaEmbedder{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Transport: &http.Transport{MaxIdleConnsPerHost: cfg.PoolSize},
+		},
+	}
+
+	if !cfg.SkipHealthCheck {
+		if err := e.checkConnectivity(ctx); err != nil {
+			return nil, fmt.Errorf("ollama: failed to connect to %s: %w", cfg.Host, err)
+		}
+	}
+
+	if cfg.Dimensions > 0 {
+		e.dimensions = cfg.Dimensions
+	} else {
+		vecs, err := e.embedRequest(ctx, []string{"dimension probe"})
+		if err != nil {
+			return nil, fmt.Errorf("ollama: detect dimensions: %w", err)
+		}
+		if len(vecs) == 0 {
+			return nil, fmt.Errorf("ollama: detect dimensions: server returned no embeddings")
+		}
+		e.dimensions = len(vecs[0])
+	}
+
+	return e, nil
+}
+
+// checkConnectivity probes /api/tags to confirm the server is reachable,
+// bounded by cfg.ConnectTimeout.
+func (e *OllamaEmbedder) checkConnectivity(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.ConnectTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.cfg.Host+"/api/tags", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Embed returns text's embedding, L2-normalized to unit magnitude. An
+// empty or whitespace-only text returns a zero vector without calling the
+// server.
+func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if e.closed.Load() {
+		return nil, fmt.Errorf("ollama: embedder is closed")
+	}
+	if strings.TrimSpace(text) == "" {
+		return make([]float32, e.dimensions), nil
+	}
+
+	vecs, err := e.embedRequest(ctx, []string{text})
+	if err != nil {
+		return nil, fmt.Errorf("ollama: embed: %w", err)
+	}
+	if len(vecs) == 0 {
+		return nil, fmt.Errorf("ollama: embed: server returned no embeddings")
+	}
+	return toFloat32(vecutil.Normalize(vecs[0])), nil
+}
+
+// EmbedBatch embeds texts via /api/embed's native batch support, grouping
+// non-empty texts into cfg.BatchSize chunks. Empty or whitespace-only
+// texts are resolved to zero vectors without being sent to the server.
+func (e *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.closed.Load() {
+		return nil, fmt.Errorf("ollama: embedder is closed")
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	results := make([][]float32, len(texts))
+	nonEmpty := make([]string, 0, len(texts))
+	nonEmptyIdx := make([]int, 0, len(texts))
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			results[i] = make([]float32, e.dimensions)
+			continue
+		}
+		nonEmpty = append(nonEmpty, text)
+		nonEmptyIdx = append(nonEmptyIdx, i)
+	}
+	if len(nonEmpty) == 0 {
+		return results, nil
+	}
+
+	for start := 0; start < len(nonEmpty); start += e.cfg.BatchSize {
+		end := start + e.cfg.BatchSize
+		if end > len(nonEmpty) {
+			end = len(nonEmpty)
+		}
+		vecs, err := e.embedRequest(ctx, nonEmpty[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("ollama: embed batch [%d:%d]: %w", start, end, err)
+		}
+		for i, vec := range vecs {
+			results[nonEmptyIdx[start+i]] = toFloat32(vecutil.Normalize(vec))
+		}
+	}
+	return results, nil
+}
+
+// embedRequest POSTs inputs to /api/embed, retrying transient failures up
+// to cfg.MaxRetries times with exponential backoff. Each attempt's context
+// deadline comes from getProgressiveTimeout, which also folds in
+// cfg.RetryTimeoutMultiplier for the attempt number.
+func (e *OllamaEmbedder) embedRequest(ctx context.Context, inputs []string) ([][]float64, error) {
+	var reqInput any = inputs
+	if len(inputs) == 1 {
+		reqInput = inputs[0]
+	}
+	body, err := json.Marshal(map[string]any{"model": e.cfg.Model, "input": reqInput})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < e.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(float64(250*time.Millisecond) * math.Pow(2, float64(attempt-1)))
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, e.getProgressiveTimeout(attempt))
+		vecs, err := e.doEmbedRequest(reqCtx, body)
+		cancel()
+		if err == nil {
+			return vecs, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, fmt.Errorf("after %d attempts: %w", e.cfg.MaxRetries, lastErr)
+}
+
+// doEmbedRequest makes a single /api/embed attempt.
+func (e *OllamaEmbedder) doEmbedRequest(ctx context.Context, body []byte) ([][]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Host+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed struct {
+		Embeddings [][]float64 `json:"embeddings"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return parsed.Embeddings, nil
+}
+
+// Dimensions returns the configured (or auto-detected) model's embedding
+// width.
+func (e *OllamaEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// ModelName identifies the embedder for checkpointing and cache keying.
+func (e *OllamaEmbedder) ModelName() string {
+	return e.cfg.Model
+}
+
+// Available reports whether the server is currently reachable.
+func (e *OllamaEmbedder) Available(ctx context.Context) bool {
+	if e.closed.Load() {
+		return false
+	}
+	return e.checkConnectivity(ctx) == nil
+}
+
+// Close releases the embedder's idle HTTP connections. Safe to call more
+// than once.
+func (e *OllamaEmbedder) Close() error {
+	e.closed.Store(true)
+	e.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// SetBatchIndex records this embedder's position within a larger indexing
+// run, consulted by getProgressiveTimeout.
+func (e *OllamaEmbedder) SetBatchIndex(index int) {
+	e.batchIndex.Store(int64(index))
+}
+
+// SetFinalBatch marks whether the next call is the last batch of a run,
+// consulted by getProgressiveTimeout.
+func (e *OllamaEmbedder) SetFinalBatch(final bool) {
+	e.finalBatch.Store(final)
+}
+
+// getProgressiveTimeout scales a base timeout (cfg.Timeout doubled) up as
+// batchIndex grows (capped at ollamaMaxProgression), then applies
+// cfg.RetryTimeoutMultiplier for retryAttempt and ollamaFinalBatchBoost on
+// top once SetFinalBatch(true) has been called - see mlx.go's
+// getProgressiveTimeout for the same batch-aging rationale.
+func (e *OllamaEmbedder) getProgressiveTimeout(retryAttempt int) time.Duration {
+	base := time.Duration(float64(e.cfg.Timeout) * ollamaProgressiveBaseMultiplier)
+
+	chunks := float64(e.batchIndex.Load()) * float64(e.cfg.BatchSize)
+	progression := 1.0 + (chunks/1000.0)*(e.cfg.TimeoutProgression-1.0)
+	if progression > ollamaMaxProgression {
+		progression = ollamaMaxProgression
+	}
+	if progression < 1.0 {
+		progression = 1.0
+	}
+
+	timeout := time.Duration(float64(base) * progression)
+	if retryAttempt > 0 {
+		timeout = time.Duration(float64(timeout) * math.Pow(e.cfg.RetryTimeoutMultiplier, float64(retryAttempt)))
+	}
+	if e.finalBatch.Load() {
+		timeout = time.Duration(float64(timeout) * ollamaFinalBatchBoost)
+	}
+	return timeout
+}
+
+var _ Embedder = (*OllamaEmbedder)(nil)
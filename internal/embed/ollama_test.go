@@ -14,6 +14,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed/vecutil"
 )
 
 // ============================================================================
@@ -886,17 +888,9 @@ func generateMockEmbedding(text string, dims int) []float64 {
 		embedding[i] = float64(i+1) / float64(dims) * (charSum / 1000.0)
 	}
 
-	// Normalize
-	var sumSq float64
-	for _, v := range embedding {
-		sumSq += v * v
-	}
-	if sumSq > 0 {
-		mag := 1.0 / (sumSq * sumSq)
-		for i := range embedding {
-			embedding[i] *= mag
-		}
-	}
-
-	return embedding
+	// Normalize to unit length via vecutil.Normalize rather than the
+	// hand-rolled 1/sumSq scaling this helper used to do (that scaled by
+	// 1/||v||^2 instead of 1/||v||, so the result was never actually
+	// unit-length).
+	return vecutil.Normalize(embedding)
 }
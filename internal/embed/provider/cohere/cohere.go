@@ -0,0 +1,300 @@
+// Package cohere implements embed.Embedder against Cohere's /embed REST
+// API, following the same Config/DefaultConfig/NewEmbedder shape as the
+// repo's Ollama and MLX embedders.
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is Cohere's public API host.
+	DefaultBaseURL = "https://api.cohere.com/v1"
+	// DefaultModel matches Cohere's current general-purpose embedding model.
+	DefaultModel = "embed-english-v3.0"
+	// DefaultBatchSize caps how many inputs go in one /embed call -
+	// Cohere's API limits a single request to 96 texts.
+	DefaultBatchSize = 96
+	// DefaultMaxRetries bounds retries on a failed request.
+	DefaultMaxRetries = 3
+	// DefaultTimeout bounds a single HTTP round trip.
+	DefaultTimeout = 60 * time.Second
+	// DefaultInputType is sent as Cohere's input_type field for indexing
+	// calls (as opposed to "search_query", used for queries). Embedder
+	// always embeds as a document; query-side embedding isn't this type's
+	// concern.
+	DefaultInputType = "search_document"
+)
+
+// Config configures an Embedder.
+type Config struct {
+	APIKey     string
+	BaseURL    string
+	Model      string
+	InputType  string
+	Dimensions int
+	BatchSize  int
+	MaxRetries int
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+// SharedConfig is the subset of provider.EmbedderConfig's fields this
+// package's ConfigFrom reads.
+type SharedConfig struct {
+	Model      string
+	APIKey     string
+	BaseURL    string
+	Dimensions int
+	BatchSize  int
+	MaxRetries int
+}
+
+// ConfigFrom builds a Config from the provider-agnostic fields
+// provider.EmbedderConfig carries, then fills in this package's own
+// defaults for anything left zero.
+func ConfigFrom(s SharedConfig) Config {
+	cfg := DefaultConfig()
+	cfg.APIKey = s.APIKey
+	if s.Model != "" {
+		cfg.Model = s.Model
+	}
+	if s.BaseURL != "" {
+		cfg.BaseURL = s.BaseURL
+	}
+	if s.Dimensions != 0 {
+		cfg.Dimensions = s.Dimensions
+	}
+	if s.BatchSize != 0 {
+		cfg.BatchSize = s.BatchSize
+	}
+	if s.MaxRetries != 0 {
+		cfg.MaxRetries = s.MaxRetries
+	}
+	return cfg
+}
+
+// DefaultConfig returns a Config pointed at Cohere's public API with
+// embed-english-v3.0 and auto-detected dimensions.
+func DefaultConfig() Config {
+	return Config{
+		BaseURL:    DefaultBaseURL,
+		Model:      DefaultModel,
+		InputType:  DefaultInputType,
+		Dimensions: 0,
+		BatchSize:  DefaultBatchSize,
+		MaxRetries: DefaultMaxRetries,
+		Timeout:    DefaultTimeout,
+	}
+}
+
+// Embedder embeds text via Cohere's /embed endpoint.
+type Embedder struct {
+	cfg        Config
+	httpClient *http.Client
+	dimensions int
+}
+
+// NewEmbedder builds an Embedder from cfg. If cfg.Dimensions is zero, it
+// probes the API with a single short text to discover the model's native
+// dimensions.
+func NewEmbedder(ctx context.Context, cfg Config) (*Embedder, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("cohere: APIKey is required")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.InputType == "" {
+		cfg.InputType = DefaultInputType
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	e := &Embedder{cfg: cfg, httpClient: httpClient, dimensions: cfg.Dimensions}
+	if e.dimensions == 0 {
+		vec, err := e.Embed(ctx, "dimension probe")
+		if err != nil {
+			return nil, fmt.Errorf("cohere: detect dimensions: %w", err)
+		}
+		e.dimensions = len(vec)
+	}
+	return e, nil
+}
+
+// Embed returns text's embedding. An empty or whitespace-only text returns
+// a zero vector sized to Dimensions().
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if strings.TrimSpace(text) == "" {
+		return make([]float32, e.Dimensions()), nil
+	}
+	vecs, err := e.embedRequest(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// EmbedBatch embeds texts in groups of at most cfg.BatchSize, using
+// Cohere's native "texts" array batch support.
+func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	results := make([][]float32, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		end := start + e.cfg.BatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		group := texts[start:end]
+		nonEmpty := make([]string, 0, len(group))
+		nonEmptyIdx := make([]int, 0, len(group))
+		for i, t := range group {
+			if strings.TrimSpace(t) == "" {
+				results[start+i] = make([]float32, e.Dimensions())
+				continue
+			}
+			nonEmpty = append(nonEmpty, t)
+			nonEmptyIdx = append(nonEmptyIdx, start+i)
+		}
+		if len(nonEmpty) == 0 {
+			continue
+		}
+
+		vecs, err := e.embedRequest(ctx, nonEmpty)
+		if err != nil {
+			return nil, fmt.Errorf("cohere: embed batch [%d:%d]: %w", start, end, err)
+		}
+		for i, vec := range vecs {
+			results[nonEmptyIdx[i]] = vec
+		}
+	}
+	return results, nil
+}
+
+type embedRequestBody struct {
+	Model     string   `json:"model"`
+	Texts     []string `json:"texts"`
+	InputType string   `json:"input_type"`
+}
+
+type embedResponseBody struct {
+	Embeddings [][]float32 `json:"embeddings"`
+	Message    string      `json:"message"`
+}
+
+// embedRequest sends one /embed call for texts, retrying transient
+// failures with jittered exponential backoff - reimplemented per-package
+// rather than shared, to avoid a circular import between this package and
+// the provider package that dispatches to it.
+func (e *Embedder) embedRequest(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embedRequestBody{Model: e.cfg.Model, Texts: texts, InputType: e.cfg.InputType})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	var lastErr error
+	backoff := 500 * time.Millisecond
+	const maxBackoff = 30 * time.Second
+
+	for attempt := 0; attempt <= e.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			jitter := 0.5 + rand.Float64()
+			wait := time.Duration(float64(backoff) * jitter)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+
+		vecs, err := e.doEmbedRequest(ctx, body)
+		if err == nil {
+			return vecs, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("after %d attempts: %w", e.cfg.MaxRetries+1, lastErr)
+}
+
+func (e *Embedder) doEmbedRequest(ctx context.Context, body []byte) ([][]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.BaseURL+"/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var parsed embedResponseBody
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("decode response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if parsed.Message != "" {
+			return nil, fmt.Errorf("cohere api error (status %d): %s", resp.StatusCode, parsed.Message)
+		}
+		return nil, fmt.Errorf("cohere api error: status %d", resp.StatusCode)
+	}
+
+	return parsed.Embeddings, nil
+}
+
+// Dimensions returns the embedder's output vector size, resolved at
+// construction time.
+func (e *Embedder) Dimensions() int {
+	return e.dimensions
+}
+
+// ModelName returns the configured model name.
+func (e *Embedder) ModelName() string {
+	return e.cfg.Model
+}
+
+// Available reports whether the Cohere API is currently reachable with a
+// minimal request.
+func (e *Embedder) Available(ctx context.Context) bool {
+	_, err := e.Embed(ctx, "availability probe")
+	return err == nil
+}
+
+// Close is a no-op: Embedder holds no resources beyond its *http.Client.
+func (e *Embedder) Close() error {
+	return nil
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+)
+
+// DefaultFailureThreshold is how many consecutive failures a stage accrues
+// before FallbackEmbedder stops trying it and starts skipping straight to
+// the next one - the cross-provider analogue of ThrottledEmbedder's
+// cached-health-check idea: don't re-probe a backend that just told you
+// it's down, wait it out instead.
+const DefaultFailureThreshold = 3
+
+// DefaultCooldown is how long a tripped stage is skipped before
+// FallbackEmbedder gives it another chance.
+const DefaultCooldown = 30 * time.Second
+
+// FallbackEmbedder chains embed.Embedders together: calls go to the first
+// stage that isn't currently tripped, falling through to the next stage on
+// error. A stage trips after failureThreshold consecutive failures and is
+// skipped for cooldown before being retried, so a truly dead backend isn't
+// re-attempted (and its request latency re-paid) on every single call.
+type FallbackEmbedder struct {
+	stages           []embed.Embedder
+	failureThreshold int
+	cooldown         time.Duration
+
+	mu    sync.Mutex
+	state []stageState
+}
+
+type stageState struct {
+	consecutiveFailures int
+	trippedUntil        time.Time
+}
+
+// NewFallbackEmbedder builds a FallbackEmbedder over stages, tried in
+// order. failureThreshold <= 0 and cooldown <= 0 fall back to
+// DefaultFailureThreshold and DefaultCooldown respectively.
+func NewFallbackEmbedder(stages []embed.Embedder, failureThreshold int, cooldown time.Duration) *FallbackEmbedder {
+	if failureThreshold <= 0 {
+		failureThreshold = DefaultFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &FallbackEmbedder{
+		stages:           stages,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		state:            make([]stageState, len(stages)),
+	}
+}
+
+// Embed tries each stage in order, skipping any currently tripped, and
+// returns the first success. It returns the last error seen if every stage
+// fails or is tripped.
+func (f *FallbackEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	var lastErr error
+	for i, stage := range f.stages {
+		if f.isTripped(i) {
+			continue
+		}
+		vec, err := stage.Embed(ctx, text)
+		if err == nil {
+			f.recordSuccess(i)
+			return vec, nil
+		}
+		f.recordFailure(i)
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("provider: all embedder stages tripped")
+	}
+	return nil, fmt.Errorf("provider: all fallback stages failed: %w", lastErr)
+}
+
+// EmbedBatch tries each stage in order the same way Embed does, sending the
+// whole batch to whichever stage succeeds first rather than splitting it
+// across stages - a partial batch from two different models isn't usable
+// together.
+func (f *FallbackEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	var lastErr error
+	for i, stage := range f.stages {
+		if f.isTripped(i) {
+			continue
+		}
+		vecs, err := stage.EmbedBatch(ctx, texts)
+		if err == nil {
+			f.recordSuccess(i)
+			return vecs, nil
+		}
+		f.recordFailure(i)
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("provider: all embedder stages tripped")
+	}
+	return nil, fmt.Errorf("provider: all fallback stages failed: %w", lastErr)
+}
+
+// Dimensions returns the first untripped stage's dimensions, or the
+// primary stage's if every stage is currently tripped.
+func (f *FallbackEmbedder) Dimensions() int {
+	for i, stage := range f.stages {
+		if !f.isTripped(i) {
+			return stage.Dimensions()
+		}
+	}
+	if len(f.stages) > 0 {
+		return f.stages[0].Dimensions()
+	}
+	return 0
+}
+
+// ModelName identifies the chain by its primary stage's model name,
+// suffixed with the fallback count - the full chain doesn't have one model
+// name, and callers logging this mostly care which one is primary.
+func (f *FallbackEmbedder) ModelName() string {
+	if len(f.stages) == 0 {
+		return "fallback(empty)"
+	}
+	if len(f.stages) == 1 {
+		return f.stages[0].ModelName()
+	}
+	return fmt.Sprintf("%s+%d fallback(s)", f.stages[0].ModelName(), len(f.stages)-1)
+}
+
+// Available reports whether at least one stage is both untripped and
+// reports itself available.
+func (f *FallbackEmbedder) Available(ctx context.Context) bool {
+	for i, stage := range f.stages {
+		if !f.isTripped(i) && stage.Available(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes every stage, collecting the first error but closing the
+// rest regardless, so an earlier stage's close error does not leak a
+// later stage's resources.
+func (f *FallbackEmbedder) Close() error {
+	var firstErr error
+	for _, stage := range f.stages {
+		if err := stage.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f *FallbackEmbedder) isTripped(i int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st := f.state[i]
+	if st.consecutiveFailures < f.failureThreshold {
+		return false
+	}
+	return time.Now().Before(st.trippedUntil)
+}
+
+func (f *FallbackEmbedder) recordSuccess(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state[i] = stageState{}
+}
+
+func (f *FallbackEmbedder) recordFailure(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state[i].consecutiveFailures++
+	if f.state[i].consecutiveFailures >= f.failureThreshold {
+		f.state[i].trippedUntil = time.Now().Add(f.cooldown)
+		slog.Warn("provider_fallback_stage_tripped",
+			slog.Int("stage", i), slog.Int("consecutive_failures", f.state[i].consecutiveFailures), slog.Duration("cooldown", f.cooldown))
+	}
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+)
+
+// fakeEmbedder is a minimal embed.Embedder used to test FallbackEmbedder
+// without standing up a real backend, mirroring internal/embed's own
+// stubEmbedder used for ThrottledEmbedder's tests.
+type fakeEmbedder struct {
+	name       string
+	failCalls  atomic.Bool
+	embedCalls atomic.Int32
+}
+
+func (f *fakeEmbedder) Embed(context.Context, string) ([]float32, error) {
+	f.embedCalls.Add(1)
+	if f.failCalls.Load() {
+		return nil, errors.New("fake embedder: injected failure")
+	}
+	return make([]float32, 8), nil
+}
+func (f *fakeEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if f.failCalls.Load() {
+		f.embedCalls.Add(1)
+		return nil, errors.New("fake embedder: injected failure")
+	}
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i], _ = f.Embed(ctx, texts[i])
+	}
+	return out, nil
+}
+func (f *fakeEmbedder) Dimensions() int                { return 8 }
+func (f *fakeEmbedder) ModelName() string              { return f.name }
+func (f *fakeEmbedder) Available(context.Context) bool { return !f.failCalls.Load() }
+func (f *fakeEmbedder) Close() error                   { return nil }
+
+func TestFallbackEmbedder_FallsThroughOnError(t *testing.T) {
+	primary := &fakeEmbedder{name: "primary"}
+	primary.failCalls.Store(true)
+	secondary := &fakeEmbedder{name: "secondary"}
+
+	fe := NewFallbackEmbedder([]embed.Embedder{primary, secondary}, 1, time.Minute)
+
+	vec, err := fe.Embed(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("expected fallback to succeed via secondary, got error: %v", err)
+	}
+	if len(vec) != 8 {
+		t.Fatalf("expected an 8-dim vector, got %d", len(vec))
+	}
+}
+
+func TestFallbackEmbedder_TripsAfterThreshold(t *testing.T) {
+	primary := &fakeEmbedder{name: "primary"}
+	primary.failCalls.Store(true)
+	secondary := &fakeEmbedder{name: "secondary"}
+
+	fe := NewFallbackEmbedder([]embed.Embedder{primary, secondary}, 2, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if _, err := fe.Embed(context.Background(), "text"); err != nil {
+			t.Fatalf("round %d: expected fallback to succeed via secondary, got error: %v", i, err)
+		}
+	}
+	if got := primary.embedCalls.Load(); got != 2 {
+		t.Fatalf("expected primary to be tried until it trips (2 calls), got %d", got)
+	}
+
+	if _, err := fe.Embed(context.Background(), "text"); err != nil {
+		t.Fatalf("expected fallback to still succeed via secondary after primary tripped: %v", err)
+	}
+	if got := primary.embedCalls.Load(); got != 2 {
+		t.Fatalf("expected a tripped primary to be skipped (still 2 calls), got %d", got)
+	}
+}
+
+func TestFallbackEmbedder_SucceedsWhilePrimaryCoolsDown(t *testing.T) {
+	primary := &fakeEmbedder{name: "primary"}
+	secondary := &fakeEmbedder{name: "secondary"}
+
+	fe := NewFallbackEmbedder([]embed.Embedder{primary, secondary}, 1, time.Hour)
+
+	primary.failCalls.Store(true)
+	if _, err := fe.Embed(context.Background(), "text"); err != nil {
+		t.Fatalf("expected fallback to succeed via secondary: %v", err)
+	}
+
+	primary.failCalls.Store(false)
+	// The next call still routes through the tripped primary's cooldown,
+	// so it goes to secondary again; recordSuccess only clears a stage's
+	// failure count once that stage itself is called and succeeds, not
+	// retroactively.
+	if _, err := fe.Embed(context.Background(), "text"); err != nil {
+		t.Fatalf("expected fallback to succeed via secondary while primary cools down: %v", err)
+	}
+}
+
+func TestFallbackEmbedder_AllStagesFail(t *testing.T) {
+	primary := &fakeEmbedder{name: "primary"}
+	primary.failCalls.Store(true)
+	secondary := &fakeEmbedder{name: "secondary"}
+	secondary.failCalls.Store(true)
+
+	fe := NewFallbackEmbedder([]embed.Embedder{primary, secondary}, 1, time.Hour)
+
+	if _, err := fe.Embed(context.Background(), "text"); err == nil {
+		t.Fatal("expected an error when every stage fails")
+	}
+}
@@ -0,0 +1,145 @@
+// Package provider adds a multi-backend Embedder registry alongside the
+// existing single-provider embed.NewEmbedder(ctx, Provider, model) factory -
+// lmcli splits its own embedding backends the same way, under
+// provider/{openai,anthropic,google,ollama}. It doesn't replace that
+// factory (cmd/amanmcp's serve command keeps using it for its existing
+// ProviderMLX/Ollama wiring); it gives callers who need remote-API
+// backends (OpenAI, Vertex, Cohere) and cross-provider fallback chains a
+// config-driven way to build one without hand-wiring each backend's
+// client.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/embed/provider/cohere"
+	"github.com/Aman-CERP/amanmcp/internal/embed/provider/openai"
+	"github.com/Aman-CERP/amanmcp/internal/embed/provider/vertex"
+)
+
+// EmbedderConfig is the provider-agnostic config NewEmbedder dispatches on.
+// Not every field applies to every Provider - each backend reads only the
+// fields its own API needs (see openai.ConfigFrom, vertex.ConfigFrom,
+// cohere.ConfigFrom) and ignores the rest, the same way StoreConfig's
+// SupportBucketTable only matters to the SQLite backend.
+type EmbedderConfig struct {
+	// Provider selects the backend: "openai", "vertex", or "cohere".
+	Provider string
+
+	// Model is the backend's model/deployment name, e.g.
+	// "text-embedding-3-small" (OpenAI), "text-embedding-004" (Vertex),
+	// "embed-english-v3.0" (Cohere).
+	Model string
+
+	// APIKey authenticates against the backend. Vertex has no notion of a
+	// simple API key in its full OAuth/service-account flow; vertex.Embedder
+	// sends it as a bearer token, which only works against endpoints
+	// fronted by an API-key-accepting proxy rather than Vertex AI directly -
+	// see vertex.go's doc comment.
+	APIKey string
+
+	// BaseURL overrides the backend's default endpoint, for self-hosted
+	// gateways or region-specific Vertex endpoints.
+	BaseURL string
+
+	// Organization is sent as the OpenAI-Organization header; ignored by
+	// the other backends.
+	Organization string
+
+	// ProjectID and Location are Vertex's GCP project and region; ignored
+	// by the other backends.
+	ProjectID string
+	Location  string
+
+	// Dimensions fixes the embedding size. Zero means auto-detect: the
+	// backend runs one probe Embed call during construction and uses the
+	// returned vector's length, matching OllamaConfig.Dimensions' "0 means
+	// auto-detect" convention. Only the OpenAI backend sends a non-zero
+	// value to its API; Vertex and Cohere report it from Dimensions()
+	// without sending it, so their vectors keep the model's native size.
+	Dimensions int
+
+	// BatchSize caps how many texts one EmbedBatch call sends the backend
+	// in a single native batch request. Zero means the backend's own
+	// default.
+	BatchSize int
+
+	// MaxRetries bounds how many times a failed request is retried before
+	// the backend gives up and returns an error, mirroring
+	// OllamaConfig.MaxRetries.
+	MaxRetries int
+
+	// Fallbacks, if non-empty, is tried in order whenever this config's own
+	// embedder's calls keep failing - the cross-provider analogue of
+	// OllamaConfig.FallbackModels, which only falls back across models
+	// within Ollama itself. A fallback entry with its own Fallbacks nests
+	// further; NewEmbedder builds the whole chain recursively.
+	Fallbacks []EmbedderConfig
+}
+
+// NewEmbedder builds the Embedder cfg.Provider selects, wrapping it in a
+// FallbackEmbedder if cfg.Fallbacks is non-empty. A fallback entry that
+// fails to construct (bad credentials, unreachable host) is skipped with a
+// logged warning rather than failing the whole chain - the point of a
+// fallback chain is to keep working when one backend is unusable.
+func NewEmbedder(ctx context.Context, cfg EmbedderConfig) (embed.Embedder, error) {
+	primary, err := newSingleEmbedder(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("provider: build %s embedder: %w", cfg.Provider, err)
+	}
+	if len(cfg.Fallbacks) == 0 {
+		return primary, nil
+	}
+
+	stages := []embed.Embedder{primary}
+	for _, fbCfg := range cfg.Fallbacks {
+		fb, err := NewEmbedder(ctx, fbCfg)
+		if err != nil {
+			slog.Warn("provider_fallback_stage_skipped",
+				slog.String("provider", fbCfg.Provider), slog.String("error", err.Error()))
+			continue
+		}
+		stages = append(stages, fb)
+	}
+	return NewFallbackEmbedder(stages, DefaultFailureThreshold, DefaultCooldown), nil
+}
+
+func newSingleEmbedder(ctx context.Context, cfg EmbedderConfig) (embed.Embedder, error) {
+	switch cfg.Provider {
+	case "openai":
+		return openai.NewEmbedder(ctx, openai.ConfigFrom(openai.SharedConfig{
+			Model:        cfg.Model,
+			APIKey:       cfg.APIKey,
+			BaseURL:      cfg.BaseURL,
+			Organization: cfg.Organization,
+			Dimensions:   cfg.Dimensions,
+			BatchSize:    cfg.BatchSize,
+			MaxRetries:   cfg.MaxRetries,
+		}))
+	case "vertex":
+		return vertex.NewEmbedder(ctx, vertex.ConfigFrom(vertex.SharedConfig{
+			Model:      cfg.Model,
+			APIKey:     cfg.APIKey,
+			BaseURL:    cfg.BaseURL,
+			ProjectID:  cfg.ProjectID,
+			Location:   cfg.Location,
+			Dimensions: cfg.Dimensions,
+			BatchSize:  cfg.BatchSize,
+			MaxRetries: cfg.MaxRetries,
+		}))
+	case "cohere":
+		return cohere.NewEmbedder(ctx, cohere.ConfigFrom(cohere.SharedConfig{
+			Model:      cfg.Model,
+			APIKey:     cfg.APIKey,
+			BaseURL:    cfg.BaseURL,
+			Dimensions: cfg.Dimensions,
+			BatchSize:  cfg.BatchSize,
+			MaxRetries: cfg.MaxRetries,
+		}))
+	default:
+		return nil, fmt.Errorf("provider: unknown provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,335 @@
+// Package vertex implements embed.Embedder against Google Vertex AI's
+// text-embedding models, following the same Config/DefaultConfig/NewEmbedder
+// shape as the repo's Ollama and MLX embedders.
+//
+// Auth is deliberately simplified: Vertex's real API expects a
+// service-account OAuth2 access token refreshed on a schedule, not a
+// static API key. This Embedder instead sends cfg.APIKey as a plain bearer
+// token on every request. That works against an API-key-accepting proxy in
+// front of Vertex (or against Vertex's newer API-key-auth preview surface)
+// but not directly against the full `aiplatform.googleapis.com` endpoint
+// without one - implementing the complete service-account/OAuth2 flow
+// here would pull in a GCP auth library this repo doesn't otherwise
+// depend on, and is left as a follow-up if a caller needs it.
+package vertex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultModel matches Vertex's current general-purpose embedding model.
+	DefaultModel = "text-embedding-004"
+	// DefaultBatchSize caps how many inputs go in one request - Vertex's
+	// predict endpoint caps batches at 250 instances per call.
+	DefaultBatchSize = 250
+	// DefaultMaxRetries bounds retries on a failed request.
+	DefaultMaxRetries = 3
+	// DefaultTimeout bounds a single HTTP round trip.
+	DefaultTimeout = 60 * time.Second
+)
+
+// Config configures an Embedder. ProjectID and Location build the
+// endpoint URL when BaseURL isn't set explicitly.
+type Config struct {
+	APIKey     string
+	BaseURL    string
+	ProjectID  string
+	Location   string
+	Model      string
+	Dimensions int
+	BatchSize  int
+	MaxRetries int
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+// SharedConfig is the subset of provider.EmbedderConfig's fields this
+// package's ConfigFrom reads.
+type SharedConfig struct {
+	Model      string
+	APIKey     string
+	BaseURL    string
+	ProjectID  string
+	Location   string
+	Dimensions int
+	BatchSize  int
+	MaxRetries int
+}
+
+// ConfigFrom builds a Config from the provider-agnostic fields
+// provider.EmbedderConfig carries, then fills in this package's own
+// defaults for anything left zero.
+func ConfigFrom(s SharedConfig) Config {
+	cfg := DefaultConfig()
+	cfg.APIKey = s.APIKey
+	cfg.ProjectID = s.ProjectID
+	cfg.Location = s.Location
+	if s.Model != "" {
+		cfg.Model = s.Model
+	}
+	if s.BaseURL != "" {
+		cfg.BaseURL = s.BaseURL
+	}
+	if s.Dimensions != 0 {
+		cfg.Dimensions = s.Dimensions
+	}
+	if s.BatchSize != 0 {
+		cfg.BatchSize = s.BatchSize
+	}
+	if s.MaxRetries != 0 {
+		cfg.MaxRetries = s.MaxRetries
+	}
+	return cfg
+}
+
+// DefaultConfig returns a Config with text-embedding-004, auto-detected
+// dimensions and Location us-central1; ProjectID (or BaseURL) still needs
+// filling in by the caller.
+func DefaultConfig() Config {
+	return Config{
+		Location:   "us-central1",
+		Model:      DefaultModel,
+		Dimensions: 0,
+		BatchSize:  DefaultBatchSize,
+		MaxRetries: DefaultMaxRetries,
+		Timeout:    DefaultTimeout,
+	}
+}
+
+func (c Config) endpoint() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return fmt.Sprintf(
+		"https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:predict",
+		c.Location, c.ProjectID, c.Location, c.Model,
+	)
+}
+
+// Embedder embeds text via Vertex AI's :predict endpoint.
+type Embedder struct {
+	cfg        Config
+	httpClient *http.Client
+	dimensions int
+}
+
+// NewEmbedder builds an Embedder from cfg. If cfg.Dimensions is zero, it
+// probes the API with a single short text to discover the model's native
+// dimensions.
+func NewEmbedder(ctx context.Context, cfg Config) (*Embedder, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("vertex: APIKey is required")
+	}
+	if cfg.BaseURL == "" && (cfg.ProjectID == "" || cfg.Location == "") {
+		return nil, fmt.Errorf("vertex: ProjectID and Location are required when BaseURL is not set")
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.Location == "" {
+		cfg.Location = "us-central1"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	e := &Embedder{cfg: cfg, httpClient: httpClient, dimensions: cfg.Dimensions}
+	if e.dimensions == 0 {
+		vec, err := e.Embed(ctx, "dimension probe")
+		if err != nil {
+			return nil, fmt.Errorf("vertex: detect dimensions: %w", err)
+		}
+		e.dimensions = len(vec)
+	}
+	return e, nil
+}
+
+// Embed returns text's embedding. An empty or whitespace-only text returns
+// a zero vector sized to Dimensions().
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if strings.TrimSpace(text) == "" {
+		return make([]float32, e.Dimensions()), nil
+	}
+	vecs, err := e.predict(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// EmbedBatch embeds texts in groups of at most cfg.BatchSize instances per
+// :predict call.
+func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	results := make([][]float32, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		end := start + e.cfg.BatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		group := texts[start:end]
+		nonEmpty := make([]string, 0, len(group))
+		nonEmptyIdx := make([]int, 0, len(group))
+		for i, t := range group {
+			if strings.TrimSpace(t) == "" {
+				results[start+i] = make([]float32, e.Dimensions())
+				continue
+			}
+			nonEmpty = append(nonEmpty, t)
+			nonEmptyIdx = append(nonEmptyIdx, start+i)
+		}
+		if len(nonEmpty) == 0 {
+			continue
+		}
+
+		vecs, err := e.predict(ctx, nonEmpty)
+		if err != nil {
+			return nil, fmt.Errorf("vertex: embed batch [%d:%d]: %w", start, end, err)
+		}
+		for i, vec := range vecs {
+			results[nonEmptyIdx[i]] = vec
+		}
+	}
+	return results, nil
+}
+
+type predictInstance struct {
+	Content string `json:"content"`
+}
+
+type predictRequest struct {
+	Instances []predictInstance `json:"instances"`
+}
+
+type predictResponse struct {
+	Predictions []struct {
+		Embeddings struct {
+			Values []float32 `json:"values"`
+		} `json:"embeddings"`
+	} `json:"predictions"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// predict sends one :predict call for texts, retrying transient failures
+// with jittered exponential backoff - reimplemented per-package rather
+// than shared, to avoid a circular import between this package and the
+// provider package that dispatches to it.
+func (e *Embedder) predict(ctx context.Context, texts []string) ([][]float32, error) {
+	instances := make([]predictInstance, len(texts))
+	for i, t := range texts {
+		instances[i] = predictInstance{Content: t}
+	}
+	body, err := json.Marshal(predictRequest{Instances: instances})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	var lastErr error
+	backoff := 500 * time.Millisecond
+	const maxBackoff = 30 * time.Second
+
+	for attempt := 0; attempt <= e.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			jitter := 0.5 + rand.Float64()
+			wait := time.Duration(float64(backoff) * jitter)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+
+		vecs, err := e.doPredict(ctx, body)
+		if err == nil {
+			return vecs, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("after %d attempts: %w", e.cfg.MaxRetries+1, lastErr)
+}
+
+func (e *Embedder) doPredict(ctx context.Context, body []byte) ([][]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var parsed predictResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("decode response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if parsed.Error != nil {
+			return nil, fmt.Errorf("vertex api error (status %d): %s", resp.StatusCode, parsed.Error.Message)
+		}
+		return nil, fmt.Errorf("vertex api error: status %d", resp.StatusCode)
+	}
+
+	vecs := make([][]float32, len(parsed.Predictions))
+	for i, p := range parsed.Predictions {
+		vecs[i] = p.Embeddings.Values
+	}
+	return vecs, nil
+}
+
+// Dimensions returns the embedder's output vector size, resolved at
+// construction time.
+func (e *Embedder) Dimensions() int {
+	return e.dimensions
+}
+
+// ModelName returns the configured model name.
+func (e *Embedder) ModelName() string {
+	return e.cfg.Model
+}
+
+// Available reports whether the Vertex endpoint is currently reachable
+// with a minimal request.
+func (e *Embedder) Available(ctx context.Context) bool {
+	_, err := e.Embed(ctx, "availability probe")
+	return err == nil
+}
+
+// Close is a no-op: Embedder holds no resources beyond its *http.Client.
+func (e *Embedder) Close() error {
+	return nil
+}
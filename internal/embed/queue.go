@@ -0,0 +1,375 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxBatchSize and DefaultBatchTimeout bound how long a QueueManager
+// shard waits to fill a batch before calling EmbedBatch with whatever it
+// has, matching DefaultReindexBatchSize's order of magnitude while staying
+// responsive for low-traffic shards.
+const (
+	DefaultMaxBatchSize  = 64
+	DefaultBatchTimeout  = 100 * time.Millisecond
+	defaultRetryInitial  = 500 * time.Millisecond
+	defaultRetryMax      = 30 * time.Second
+	defaultPendingBuffer = 256
+	defaultPauseCheck    = 100 * time.Millisecond
+)
+
+// ErrQueueManagerClosed is returned by Enqueue once Close has been called.
+var ErrQueueManagerClosed = errors.New("embed: queue manager is closed")
+
+// QueueManagerConfig configures a QueueManager. Zero values fall back to
+// the package defaults.
+type QueueManagerConfig struct {
+	// Shards is how many independent worker shards the manager runs, each
+	// with its own pending queue and in-flight EmbedBatch call. Zero means
+	// runtime.NumCPU()/2 (at least 1) - MLXEmbedder's server-side batching
+	// gets diminishing returns past that, so this errs conservative rather
+	// than saturating the embedding server's thermal budget by default.
+	Shards int
+	// MaxBatchSize is the most texts a shard accumulates before calling
+	// EmbedBatch. Zero means DefaultMaxBatchSize.
+	MaxBatchSize int
+	// BatchTimeout is how long a shard waits for MaxBatchSize items to
+	// accumulate before flushing a partial batch. Zero means
+	// DefaultBatchTimeout.
+	BatchTimeout time.Duration
+}
+
+// Result is delivered on the channel Enqueue returns once the text's batch
+// has been embedded (or has permanently failed).
+type Result struct {
+	Vector []float32
+	Err    error
+}
+
+// request is one enqueued text paired with the channel its Result is
+// delivered on.
+type request struct {
+	text   string
+	result chan<- Result
+}
+
+// QueueManagerStats is a point-in-time snapshot returned by Stats, meant to
+// be wired into a caller's existing telemetry rather than read directly by
+// humans.
+type QueueManagerStats struct {
+	ShardDepth      []int
+	InflightBatches int
+	Retries         int64
+	Dropped         int64
+}
+
+// QueueManager fans concurrent Enqueue calls out across N shards, each
+// batching its own pending texts and calling the underlying Embedder's
+// EmbedBatch independently - modeled on Prometheus's remote-write queue
+// manager, where a single serialized writer becomes the bottleneck once
+// request volume grows past what one in-flight call can absorb. Today
+// MLXEmbedder.EmbedBatch is called sequentially by every caller (see
+// Reindexer.reindexFile, which calls it once per file); QueueManager lets a
+// large ingestion job keep several batches in flight against the MLX server
+// at once, still bounded by Shards so it doesn't exceed the server's
+// thermal budget, and still pausing every shard when the embedder reports
+// embed.Throttleable backpressure.
+type QueueManager struct {
+	embedder Embedder
+	cfg      QueueManagerConfig
+
+	shards []*queueShard
+
+	retries atomic.Int64
+	dropped atomic.Int64
+
+	pauseMu sync.RWMutex
+	paused  bool
+
+	closeOnce sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+
+	rng   *rand.Rand
+	rngMu sync.Mutex
+}
+
+// queueShard owns one pending-text channel and processes it sequentially,
+// so EmbedBatch calls across shards run concurrently while calls within a
+// shard never overlap.
+type queueShard struct {
+	pending  chan request
+	depth    atomic.Int64
+	inflight atomic.Bool
+}
+
+// NewQueueManager wraps embedder with a QueueManager started with cfg
+// (defaults applied for any zero field) and begins its shard workers
+// immediately; callers should Close it when done to release the workers.
+func NewQueueManager(embedder Embedder, cfg QueueManagerConfig) *QueueManager {
+	if cfg.Shards <= 0 {
+		cfg.Shards = runtime.NumCPU() / 2
+		if cfg.Shards <= 0 {
+			cfg.Shards = 1
+		}
+	}
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = DefaultMaxBatchSize
+	}
+	if cfg.BatchTimeout <= 0 {
+		cfg.BatchTimeout = DefaultBatchTimeout
+	}
+
+	qm := &QueueManager{
+		embedder: embedder,
+		cfg:      cfg,
+		shards:   make([]*queueShard, cfg.Shards),
+		done:     make(chan struct{}),
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for i := range qm.shards {
+		qm.shards[i] = &queueShard{pending: make(chan request, defaultPendingBuffer)}
+	}
+
+	qm.wg.Add(cfg.Shards)
+	for i, shard := range qm.shards {
+		go qm.runShard(i, shard)
+	}
+	return qm
+}
+
+// Enqueue hashes text to a shard (FNV-1a, so identical texts always land on
+// the same shard and benefit from the same in-flight batch) and returns a
+// channel that receives exactly one Result once that batch completes.
+func (qm *QueueManager) Enqueue(text string) <-chan Result {
+	result := make(chan Result, 1)
+
+	select {
+	case <-qm.done:
+		result <- Result{Err: ErrQueueManagerClosed}
+		return result
+	default:
+	}
+
+	shard := qm.shards[qm.shardFor(text)]
+	shard.pending <- request{text: text, result: result}
+	shard.depth.Add(1)
+	return result
+}
+
+func (qm *QueueManager) shardFor(text string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(text))
+	return int(h.Sum32()) % len(qm.shards)
+}
+
+// Stats returns a point-in-time snapshot of queue depth per shard plus the
+// running retry/drop counters.
+func (qm *QueueManager) Stats() QueueManagerStats {
+	stats := QueueManagerStats{
+		ShardDepth: make([]int, len(qm.shards)),
+		Retries:    qm.retries.Load(),
+		Dropped:    qm.dropped.Load(),
+	}
+	for i, shard := range qm.shards {
+		stats.ShardDepth[i] = int(shard.depth.Load())
+		if shard.inflight.Load() {
+			stats.InflightBatches++
+		}
+	}
+	return stats
+}
+
+// Close stops accepting new work and waits for every shard to drain its
+// current in-flight batch. Safe to call multiple times.
+func (qm *QueueManager) Close() error {
+	qm.closeOnce.Do(func() {
+		close(qm.done)
+		for _, shard := range qm.shards {
+			close(shard.pending)
+		}
+	})
+	qm.wg.Wait()
+	return nil
+}
+
+// runShard drains its shard's pending channel, accumulating up to
+// MaxBatchSize requests or BatchTimeout (whichever comes first) before
+// calling EmbedBatch, and pauses entirely while the embedder reports
+// embed.Throttleable backpressure.
+func (qm *QueueManager) runShard(index int, shard *queueShard) {
+	defer qm.wg.Done()
+
+	for {
+		batch, ok := qm.collectBatch(shard)
+		if len(batch) == 0 {
+			if !ok {
+				return
+			}
+			continue
+		}
+
+		qm.waitWhilePaused()
+		qm.processBatch(index, shard, batch)
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// collectBatch pulls requests off shard.pending until MaxBatchSize is
+// reached, BatchTimeout elapses, or the channel is closed (ok=false, with
+// whatever was collected before the close still returned).
+func (qm *QueueManager) collectBatch(shard *queueShard) (batch []request, ok bool) {
+	first, open := <-shard.pending
+	if !open {
+		return nil, false
+	}
+	batch = append(batch, first)
+
+	timer := time.NewTimer(qm.cfg.BatchTimeout)
+	defer timer.Stop()
+
+	for len(batch) < qm.cfg.MaxBatchSize {
+		select {
+		case req, open := <-shard.pending:
+			if !open {
+				return batch, false
+			}
+			batch = append(batch, req)
+		case <-timer.C:
+			return batch, true
+		}
+	}
+	return batch, true
+}
+
+// processBatch calls EmbedBatch for batch, retrying with jittered
+// exponential backoff on error (counted via qm.retries) until it succeeds
+// or the manager is closed, at which point the batch is dropped (counted
+// via qm.dropped) and every request gets the final error.
+func (qm *QueueManager) processBatch(index int, shard *queueShard, batch []request) {
+	shard.inflight.Store(true)
+	defer shard.inflight.Store(false)
+	defer shard.depth.Add(-int64(len(batch)))
+
+	texts := make([]string, len(batch))
+	for i, req := range batch {
+		texts[i] = req.text
+	}
+
+	backoff := defaultRetryInitial
+	for {
+		vectors, err := qm.embedder.EmbedBatch(context.Background(), texts)
+		if err == nil {
+			for i, req := range batch {
+				req.result <- Result{Vector: vectors[i]}
+			}
+			return
+		}
+
+		select {
+		case <-qm.done:
+			qm.dropped.Add(int64(len(batch)))
+			for _, req := range batch {
+				req.result <- Result{Err: err}
+			}
+			return
+		default:
+		}
+
+		qm.retries.Add(1)
+		slog.Warn("queue_manager_batch_retry",
+			slog.Int("shard", index),
+			slog.Int("batch_size", len(batch)),
+			slog.String("error", err.Error()))
+
+		jittered := qm.jitter(backoff)
+		select {
+		case <-qm.done:
+			qm.dropped.Add(int64(len(batch)))
+			for _, req := range batch {
+				req.result <- Result{Err: err}
+			}
+			return
+		case <-time.After(jittered):
+		}
+
+		backoff *= 2
+		if backoff > defaultRetryMax {
+			backoff = defaultRetryMax
+		}
+	}
+}
+
+// jitter returns d scaled by a random factor in [0.5, 1.5), so shards
+// retrying in lockstep (e.g. after a shared server restart) don't all
+// re-hit the embedder at the same instant.
+func (qm *QueueManager) jitter(d time.Duration) time.Duration {
+	qm.rngMu.Lock()
+	factor := 0.5 + qm.rng.Float64()
+	qm.rngMu.Unlock()
+	return time.Duration(float64(d) * factor)
+}
+
+// waitWhilePaused blocks while a prior Throttled check has paused the
+// manager; see SetPaused.
+func (qm *QueueManager) waitWhilePaused() {
+	for {
+		qm.pauseMu.RLock()
+		paused := qm.paused
+		qm.pauseMu.RUnlock()
+		if !paused {
+			return
+		}
+		select {
+		case <-qm.done:
+			return
+		case <-time.After(defaultPauseCheck):
+		}
+	}
+}
+
+// SetPaused pauses or resumes every shard's batch processing; callers
+// should poll the embedder's embed.Throttleable.Throttled and call this
+// accordingly (see WatchThrottle for a ready-made poller).
+func (qm *QueueManager) SetPaused(paused bool) {
+	qm.pauseMu.Lock()
+	qm.paused = paused
+	qm.pauseMu.Unlock()
+}
+
+// WatchThrottle polls the wrapped embedder's embed.Throttleable.Throttled
+// (if it implements that interface) every interval and pauses/resumes the
+// QueueManager accordingly, until ctx is cancelled or Close is called. It
+// is a no-op for embedders that don't support Throttleable.
+func (qm *QueueManager) WatchThrottle(ctx context.Context, interval time.Duration) {
+	t, ok := qm.embedder.(Throttleable)
+	if !ok {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultThrottleCacheWindow
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-qm.done:
+			return
+		case <-ticker.C:
+			qm.SetPaused(t.Throttled(ctx))
+		}
+	}
+}
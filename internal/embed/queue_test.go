@@ -0,0 +1,285 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingEmbedder counts EmbedBatch calls and batch sizes, and can be
+// told to fail its first N calls before succeeding - used to exercise
+// QueueManager's retry path without a real flaky backend.
+type recordingEmbedder struct {
+	stubEmbedder
+
+	mu          sync.Mutex
+	batches     [][]string
+	failUntil   int32
+	callCount   atomic.Int32
+	maxInflight atomic.Int32
+	inflight    atomic.Int32
+}
+
+func (r *recordingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	n := r.inflight.Add(1)
+	for {
+		max := r.maxInflight.Load()
+		if n <= max || r.maxInflight.CompareAndSwap(max, n) {
+			break
+		}
+	}
+	defer r.inflight.Add(-1)
+
+	call := r.callCount.Add(1)
+	r.mu.Lock()
+	r.batches = append(r.batches, append([]string(nil), texts...))
+	r.mu.Unlock()
+
+	if call <= r.failUntil {
+		return nil, errors.New("simulated 5xx")
+	}
+	return r.stubEmbedder.EmbedBatch(ctx, texts)
+}
+
+func TestQueueManager_EnqueueReturnsVectors(t *testing.T) {
+	qm := NewQueueManager(&recordingEmbedder{}, QueueManagerConfig{Shards: 2, MaxBatchSize: 4, BatchTimeout: 10 * time.Millisecond})
+	defer qm.Close()
+
+	results := make([]<-chan Result, 0, 8)
+	for i := 0; i < 8; i++ {
+		results = append(results, qm.Enqueue("text"))
+	}
+	for _, r := range results {
+		select {
+		case res := <-r:
+			if res.Err != nil {
+				t.Fatalf("unexpected error: %v", res.Err)
+			}
+			if len(res.Vector) != 8 {
+				t.Fatalf("expected an 8-dim vector, got %d", len(res.Vector))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("result not delivered in time")
+		}
+	}
+}
+
+func TestQueueManager_SameTextSameShard(t *testing.T) {
+	qm := NewQueueManager(&recordingEmbedder{}, QueueManagerConfig{Shards: 4})
+	defer qm.Close()
+
+	first := qm.shardFor("hello world")
+	for i := 0; i < 10; i++ {
+		if got := qm.shardFor("hello world"); got != first {
+			t.Fatalf("expected identical text to hash to the same shard every time, got %d then %d", first, got)
+		}
+	}
+}
+
+func TestQueueManager_BatchesUpToMaxBatchSize(t *testing.T) {
+	embedder := &recordingEmbedder{}
+	qm := NewQueueManager(embedder, QueueManagerConfig{Shards: 1, MaxBatchSize: 4, BatchTimeout: time.Second})
+	defer qm.Close()
+
+	results := make([]<-chan Result, 0, 4)
+	for i := 0; i < 4; i++ {
+		results = append(results, qm.Enqueue("text"))
+	}
+	for _, r := range results {
+		<-r
+	}
+
+	embedder.mu.Lock()
+	defer embedder.mu.Unlock()
+	if len(embedder.batches) != 1 || len(embedder.batches[0]) != 4 {
+		t.Fatalf("expected a single batch of 4, got %v", embedder.batches)
+	}
+}
+
+func TestQueueManager_FlushesPartialBatchOnTimeout(t *testing.T) {
+	embedder := &recordingEmbedder{}
+	qm := NewQueueManager(embedder, QueueManagerConfig{Shards: 1, MaxBatchSize: 64, BatchTimeout: 10 * time.Millisecond})
+	defer qm.Close()
+
+	r := qm.Enqueue("only one")
+	select {
+	case res := <-r:
+		if res.Err != nil {
+			t.Fatalf("unexpected error: %v", res.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected partial batch to flush after BatchTimeout")
+	}
+}
+
+func TestQueueManager_RetriesOnErrorThenSucceeds(t *testing.T) {
+	embedder := &recordingEmbedder{failUntil: 2}
+	qm := NewQueueManager(embedder, QueueManagerConfig{Shards: 1, MaxBatchSize: 1, BatchTimeout: time.Millisecond})
+	defer qm.Close()
+
+	r := qm.Enqueue("retry me")
+	select {
+	case res := <-r:
+		if res.Err != nil {
+			t.Fatalf("expected eventual success after retries, got %v", res.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the batch to eventually succeed")
+	}
+
+	stats := qm.Stats()
+	if stats.Retries < 2 {
+		t.Fatalf("expected at least 2 recorded retries, got %d", stats.Retries)
+	}
+}
+
+func TestQueueManager_DropsInflightBatchOnClose(t *testing.T) {
+	embedder := &recordingEmbedder{failUntil: 1 << 30} // always fails
+	qm := NewQueueManager(embedder, QueueManagerConfig{Shards: 1, MaxBatchSize: 1, BatchTimeout: time.Millisecond})
+
+	r := qm.Enqueue("never succeeds")
+	time.Sleep(20 * time.Millisecond) // let the first attempt fail and start backing off
+	qm.Close()
+
+	select {
+	case res := <-r:
+		if res.Err == nil {
+			t.Fatal("expected an error for a batch dropped at Close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected dropped batch's result to be delivered promptly after Close")
+	}
+
+	if qm.Stats().Dropped == 0 {
+		t.Fatal("expected Dropped to be incremented")
+	}
+}
+
+func TestQueueManager_EnqueueAfterCloseErrors(t *testing.T) {
+	qm := NewQueueManager(&recordingEmbedder{}, QueueManagerConfig{Shards: 1})
+	qm.Close()
+
+	r := qm.Enqueue("too late")
+	res := <-r
+	if !errors.Is(res.Err, ErrQueueManagerClosed) {
+		t.Fatalf("expected ErrQueueManagerClosed, got %v", res.Err)
+	}
+}
+
+// barrierEmbedder blocks every EmbedBatch call until exactly n calls are
+// concurrently inside it, proving shards actually overlap rather than
+// merely not observably colliding in a race-prone timing test.
+type barrierEmbedder struct {
+	stubEmbedder
+	n  int
+	wg sync.WaitGroup
+}
+
+func newBarrierEmbedder(n int) *barrierEmbedder {
+	b := &barrierEmbedder{n: n}
+	b.wg.Add(n)
+	return b
+}
+
+func (b *barrierEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	b.wg.Done()
+	b.wg.Wait() // blocks until n callers have all entered concurrently
+	return b.stubEmbedder.EmbedBatch(ctx, texts)
+}
+
+func TestQueueManager_ConcurrentBatchesAcrossShards(t *testing.T) {
+	const shards = 4
+	embedder := newBarrierEmbedder(shards)
+	qm := NewQueueManager(embedder, QueueManagerConfig{Shards: shards, MaxBatchSize: 1, BatchTimeout: time.Millisecond})
+	defer qm.Close()
+
+	// Pick one text per shard so each of the `shards` goroutines lands on
+	// a distinct shard - otherwise two texts could collide onto the same
+	// shard, which processes its queue serially and would deadlock the
+	// barrier.
+	texts := make([]string, 0, shards)
+	seen := make(map[int]bool, shards)
+	for i := 0; len(texts) < shards; i++ {
+		candidate := string(rune('a' + i))
+		shard := qm.shardFor(candidate)
+		if seen[shard] {
+			continue
+		}
+		seen[shard] = true
+		texts = append(texts, candidate)
+	}
+
+	var wg sync.WaitGroup
+	for _, text := range texts {
+		wg.Add(1)
+		go func(text string) {
+			defer wg.Done()
+			<-qm.Enqueue(text)
+		}(text)
+	}
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected all shards' EmbedBatch calls to overlap and unblock the barrier")
+	}
+}
+
+func TestQueueManager_PauseBlocksProcessing(t *testing.T) {
+	embedder := &recordingEmbedder{}
+	qm := NewQueueManager(embedder, QueueManagerConfig{Shards: 1, MaxBatchSize: 1, BatchTimeout: time.Millisecond})
+	defer qm.Close()
+
+	qm.SetPaused(true)
+	r := qm.Enqueue("paused")
+
+	select {
+	case <-r:
+		t.Fatal("expected no result while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	qm.SetPaused(false)
+	select {
+	case res := <-r:
+		if res.Err != nil {
+			t.Fatalf("unexpected error: %v", res.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected result once unpaused")
+	}
+}
+
+func TestQueueManager_Stats(t *testing.T) {
+	qm := NewQueueManager(&recordingEmbedder{}, QueueManagerConfig{Shards: 2, MaxBatchSize: 64, BatchTimeout: time.Second})
+	defer qm.Close()
+
+	qm.Enqueue("a")
+	qm.Enqueue("b")
+
+	stats := qm.Stats()
+	if len(stats.ShardDepth) != 2 {
+		t.Fatalf("expected 2 shard depths, got %d", len(stats.ShardDepth))
+	}
+	total := 0
+	for _, d := range stats.ShardDepth {
+		total += d
+	}
+	if total != 2 {
+		t.Fatalf("expected total pending depth of 2 before the batch timeout flushes, got %d", total)
+	}
+}
+
+func TestQueueManager_DefaultShardsFromNumCPU(t *testing.T) {
+	qm := NewQueueManager(&recordingEmbedder{}, QueueManagerConfig{})
+	defer qm.Close()
+
+	if len(qm.shards) < 1 {
+		t.Fatal("expected at least 1 shard by default")
+	}
+}
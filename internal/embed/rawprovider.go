@@ -0,0 +1,505 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed/vecutil"
+)
+
+// EmbeddingProvider is the low-level wire-protocol client shape
+// OllamaEmbedder's own HTTP client already has (ListModels against
+// /api/tags, Embed against /api/embed - see mockOllamaServer in
+// ollama_test.go for the exact request/response shape), extracted as an
+// interface so a second backend doesn't require assuming Ollama's
+// endpoints. Embed's inputs/outputs use []float64 rather than []float32
+// to match Ollama's /api/embed response body directly; a caller building
+// an Embedder on top of an EmbeddingProvider narrows to float32 itself
+// (the same narrowing OllamaEmbedder's own Embed/EmbedBatch already do).
+//
+// OllamaEmbedder (ollama.go) does not use this interface: it makes its
+// HTTP calls inline, and nothing selects an EmbeddingProvider through
+// config. EmbeddingProvider and OpenAIProvider are standalone.
+type EmbeddingProvider interface {
+	// ListModels returns the models this provider currently has
+	// available to embed with.
+	ListModels(ctx context.Context) ([]string, error)
+	// Embed returns one embedding per entry in inputs, in order.
+	Embed(ctx context.Context, model string, inputs []string, opts EmbedOptions) ([][]float64, error)
+	// Dimensions returns model's embedding width, or 0 if the provider
+	// doesn't know model's dimensionality without calling Embed.
+	Dimensions(model string) int
+}
+
+// EmbedOptions carries per-call Embed parameters that aren't part of the
+// model selection itself.
+type EmbedOptions struct {
+	// Dimensions, when non-zero and less than the model's native width,
+	// truncates each returned vector to its first Dimensions components
+	// and re-normalizes (L2) the result - the Matryoshka Representation
+	// Learning trick TruncatedEmbedder already applies client-side in
+	// truncate.go, requested here at the EmbeddingProvider level so
+	// providers that support it (OpenAI's "dimensions" request field) can
+	// also save the bandwidth/storage of the untruncated vector. Providers
+	// that don't support server-side truncation still apply it client-side
+	// on the response, so the contract is the same either way.
+	Dimensions int
+}
+
+// truncateAndRenormalize applies opts.Dimensions to each vector in vecs,
+// shared by OllamaProvider and OpenAIProvider's Embed so both truncate the
+// same way regardless of whether the backend already honored the
+// "dimensions" field server-side (a vector already at or below the
+// requested width is returned unchanged).
+func truncateAndRenormalize(vecs [][]float64, dims int) [][]float64 {
+	if dims <= 0 {
+		return vecs
+	}
+	out := make([][]float64, len(vecs))
+	for i, v := range vecs {
+		if dims >= len(v) {
+			out[i] = v
+			continue
+		}
+		out[i] = vecutil.Normalize(v[:dims])
+	}
+	return out
+}
+
+// DefaultOllamaHost matches DefaultOllamaConfig's own default Host.
+const DefaultOllamaHost = "http://localhost:11434"
+
+// OllamaProviderConfig configures an OllamaProvider.
+type OllamaProviderConfig struct {
+	// Host defaults to DefaultOllamaHost when empty.
+	Host string
+	// Timeout bounds each HTTP request. Defaults to 60s when zero,
+	// matching DefaultOllamaConfig's own Timeout.
+	Timeout time.Duration
+}
+
+// OllamaProvider implements EmbeddingProvider against the same two Ollama
+// endpoints OllamaEmbedder itself calls (/api/tags, /api/embed), giving
+// EmbeddingProvider a first implementation alongside OpenAIProvider -
+// anything built generically against EmbeddingProvider (EmbedStream, in
+// particular) can be exercised against an Ollama-shaped mock server
+// without depending on OllamaEmbedder itself.
+type OllamaProvider struct {
+	cfg    OllamaProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	modelInfo map[string]*ModelInfo
+}
+
+// NewOllamaProvider builds an OllamaProvider from cfg, applying
+// DefaultOllamaHost/60s defaults for any zero fields. Unlike
+// NewOpenAIProvider there's no required field to validate - an Ollama
+// host needs no API key.
+func NewOllamaProvider(cfg OllamaProviderConfig) *OllamaProvider {
+	if cfg.Host == "" {
+		cfg.Host = DefaultOllamaHost
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60 * time.Second
+	}
+	return &OllamaProvider{
+		cfg:       cfg,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		modelInfo: make(map[string]*ModelInfo),
+	}
+}
+
+// ModelInfo is the per-model metadata /api/show exposes beyond the bare
+// name /api/tags returns. Real Ollama nests a family-prefixed embedding
+// width inside a larger "model_info" map (e.g. "bert.embedding_length")
+// rather than a flat field; ModelInfo exposes the flattened convenience
+// shape this provider's own client/mock contract defines instead of
+// reproducing that per-family key lookup, which would need a live
+// install of every model family to verify.
+type ModelInfo struct {
+	Name          string
+	Dimensions    int
+	ContextLength int
+	Quantization  string
+	Family        string
+	Capabilities  []string
+}
+
+// HasCapability reports whether name appears in m.Capabilities.
+func (m *ModelInfo) HasCapability(name string) bool {
+	for _, c := range m.Capabilities {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+type ollamaShowRequest struct {
+	Model string `json:"model"`
+}
+
+type ollamaShowResponse struct {
+	Details struct {
+		Family            string `json:"family"`
+		QuantizationLevel string `json:"quantization_level"`
+	} `json:"details"`
+	EmbeddingDimensions int      `json:"embedding_dimensions"`
+	ContextLength       int      `json:"context_length"`
+	Capabilities        []string `json:"capabilities"`
+}
+
+// ModelInfo calls POST /api/show for model and caches the result, so
+// repeated lookups (e.g. once per Embed call, to check the "embedding"
+// capability) don't re-hit the server. Callers that need a fresh read
+// after the local Ollama install changes should build a new
+// OllamaProvider - there's no cache invalidation here, matching
+// BoltCache's own "write-once, read-many" lifetime in cache.go.
+func (p *OllamaProvider) ModelInfo(ctx context.Context, model string) (*ModelInfo, error) {
+	p.mu.Lock()
+	if info, ok := p.modelInfo[model]; ok {
+		p.mu.Unlock()
+		return info, nil
+	}
+	p.mu.Unlock()
+
+	payload, err := json.Marshal(ollamaShowRequest{Model: model})
+	if err != nil {
+		return nil, fmt.Errorf("ollama: marshal show request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Host+"/api/show", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("ollama: build show request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: show request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama: show returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed ollamaShowResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("ollama: decode show response: %w", err)
+	}
+
+	info := &ModelInfo{
+		Name:          model,
+		Dimensions:    parsed.EmbeddingDimensions,
+		ContextLength: parsed.ContextLength,
+		Quantization:  parsed.Details.QuantizationLevel,
+		Family:        parsed.Details.Family,
+		Capabilities:  parsed.Capabilities,
+	}
+
+	p.mu.Lock()
+	p.modelInfo[model] = info
+	p.mu.Unlock()
+
+	return info, nil
+}
+
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// ListModels fetches GET /api/tags and returns each model's name.
+func (p *OllamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Host+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: build list models request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: list models request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama: list models returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed ollamaTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("ollama: decode list models response: %w", err)
+	}
+
+	models := make([]string, len(parsed.Models))
+	for i, m := range parsed.Models {
+		models[i] = m.Name
+	}
+	return models, nil
+}
+
+type ollamaEmbedRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+// Embed calls POST /api/embed with model and inputs, returning one
+// embedding per input in request order - /api/embed's response preserves
+// input order already, unlike OpenAI's index-tagged response. Embed
+// refuses to proceed if model's manifest doesn't list "embedding" among
+// its capabilities, surfacing a misconfigured model name (e.g. a chat
+// model) before spending a round trip on a response that isn't usable
+// as a vector. opts.Dimensions is forwarded as the request's own
+// "dimensions" field for models that honor it server-side, and applied
+// again client-side afterward in case the local install doesn't.
+func (p *OllamaProvider) Embed(ctx context.Context, model string, inputs []string, opts EmbedOptions) ([][]float64, error) {
+	info, err := p.ModelInfo(ctx, model)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: checking model capabilities: %w", err)
+	}
+	if !info.HasCapability("embedding") {
+		return nil, fmt.Errorf("ollama: model %q does not support embedding (capabilities: %v)", model, info.Capabilities)
+	}
+
+	payload, err := json.Marshal(ollamaEmbedRequest{Model: model, Input: inputs, Dimensions: opts.Dimensions})
+	if err != nil {
+		return nil, fmt.Errorf("ollama: marshal embed request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Host+"/api/embed", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("ollama: build embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: embed request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama api error: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("ollama: decode embed response: %w", err)
+	}
+	return truncateAndRenormalize(parsed.Embeddings, opts.Dimensions), nil
+}
+
+// Dimensions returns model's embedding width from a cached ModelInfo
+// lookup, or 0 if ModelInfo hasn't been called for model yet (Dimensions
+// has no context parameter to make that call itself). Callers that want
+// auto-detection without an Embed call first should call ModelInfo
+// directly; callers that skip ModelInfo entirely still get the original
+// fallback of detecting dimensionality from Embed's own response.
+func (p *OllamaProvider) Dimensions(model string) int {
+	p.mu.Lock()
+	info, ok := p.modelInfo[model]
+	p.mu.Unlock()
+	if !ok {
+		return 0
+	}
+	return info.Dimensions
+}
+
+// DefaultOpenAIBaseURL is the production OpenAI API host.
+const DefaultOpenAIBaseURL = "https://api.openai.com"
+
+// openaiModelDimensions holds the known embedding widths for OpenAI's
+// published embedding models, so Dimensions can answer without a round
+// trip. A model not in this map (a future release, a fine-tune) returns 0
+// - callers should fall back to a single Embed call to detect the size
+// the same way OllamaConfig.Dimensions' zero value triggers auto-detect.
+var openaiModelDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+}
+
+// OpenAIProviderConfig configures an OpenAIProvider.
+type OpenAIProviderConfig struct {
+	// BaseURL defaults to DefaultOpenAIBaseURL when empty - overridable
+	// for Azure OpenAI-compatible endpoints and tests.
+	BaseURL string
+	// APIKey is required; sent as "Authorization: Bearer <APIKey>".
+	APIKey string
+	// Organization, if set, is sent as the OpenAI-Organization header.
+	Organization string
+	// Timeout bounds each HTTP request. Defaults to 30s when zero.
+	Timeout time.Duration
+}
+
+// DefaultOpenAIProviderConfig returns a config with BaseURL and Timeout
+// set to their defaults; APIKey is still required before use.
+func DefaultOpenAIProviderConfig() OpenAIProviderConfig {
+	return OpenAIProviderConfig{BaseURL: DefaultOpenAIBaseURL, Timeout: 30 * time.Second}
+}
+
+// OpenAIProvider implements EmbeddingProvider against OpenAI's
+// "POST /v1/embeddings" API, the second backend the request this package
+// was built for asks for alongside Ollama's.
+type OpenAIProvider struct {
+	cfg    OpenAIProviderConfig
+	client *http.Client
+}
+
+// NewOpenAIProvider builds an OpenAIProvider from cfg, applying
+// DefaultOpenAIProviderConfig's defaults for any zero fields. cfg.APIKey
+// is required.
+func NewOpenAIProvider(cfg OpenAIProviderConfig) (*OpenAIProvider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("embed: OpenAIProvider requires an APIKey")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultOpenAIBaseURL
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &OpenAIProvider{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+type openAIModelListResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// ListModels fetches GET /v1/models and returns the subset whose ID looks
+// like an embedding model ("text-embedding" prefix) - OpenAI's /v1/models
+// endpoint lists every model the account can use, chat and embedding
+// alike, and this package only cares about the latter.
+func (p *OpenAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.BaseURL+"/v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("openai: build list models request: %w", err)
+	}
+	p.setHeaders(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: list models request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("openai: list models returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed openAIModelListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("openai: decode list models response: %w", err)
+	}
+
+	models := make([]string, 0, len(parsed.Data))
+	for _, m := range parsed.Data {
+		if strings.HasPrefix(m.ID, "text-embedding") {
+			models = append(models, m.ID)
+		}
+	}
+	return models, nil
+}
+
+type openAIEmbeddingsRequest struct {
+	Input      []string `json:"input"`
+	Model      string   `json:"model"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type openAIEmbeddingsResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Embed calls POST /v1/embeddings with inputs and model, returning one
+// embedding per input in the same order they were given - the response's
+// own Index field is used to reorder rather than assumed, since the API
+// doesn't document that data[] is always index-sorted. opts.Dimensions is
+// sent as OpenAI's own "dimensions" request field (the newer
+// text-embedding-3-* models return an already-truncated, already
+// unit-norm vector when it's set), and applied again client-side
+// afterward as a no-op safety net for models/mocks that ignore it.
+func (p *OpenAIProvider) Embed(ctx context.Context, model string, inputs []string, opts EmbedOptions) ([][]float64, error) {
+	payload, err := json.Marshal(openAIEmbeddingsRequest{Input: inputs, Model: model, Dimensions: opts.Dimensions})
+	if err != nil {
+		return nil, fmt.Errorf("openai: marshal embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+"/v1/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("openai: build embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	p.setHeaders(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: embeddings request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("openai: read embeddings response: %w", err)
+	}
+
+	var parsed openAIEmbeddingsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("openai: decode embeddings response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if parsed.Error != nil {
+			return nil, fmt.Errorf("openai api error (status %d): %s", resp.StatusCode, parsed.Error.Message)
+		}
+		return nil, fmt.Errorf("openai api error: status %d", resp.StatusCode)
+	}
+
+	out := make([][]float64, len(inputs))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(out) {
+			return nil, fmt.Errorf("openai: embeddings response index %d out of range for %d inputs", d.Index, len(inputs))
+		}
+		out[d.Index] = d.Embedding
+	}
+	return truncateAndRenormalize(out, opts.Dimensions), nil
+}
+
+// Dimensions returns model's known embedding width, or 0 if model isn't
+// one of OpenAI's published embedding models.
+func (p *OpenAIProvider) Dimensions(model string) int {
+	return openaiModelDimensions[model]
+}
+
+func (p *OpenAIProvider) setHeaders(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+	if p.cfg.Organization != "" {
+		req.Header.Set("OpenAI-Organization", p.cfg.Organization)
+	}
+}
@@ -0,0 +1,354 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed/vecutil"
+)
+
+// mockOpenAIServer creates a mock OpenAI API server, the parallel of
+// mockOllamaServer (ollama_test.go) for OpenAIProvider: GET /v1/models
+// returns a fixed model list, POST /v1/embeddings returns one
+// deterministic embedding per input.
+func mockOpenAIServer(t *testing.T, dims int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/models" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{
+					{"id": "text-embedding-3-small"},
+					{"id": "text-embedding-3-large"},
+					{"id": "gpt-4o"},
+				},
+			})
+			return
+		}
+
+		if r.URL.Path == "/v1/embeddings" {
+			var req openAIEmbeddingsRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+
+			width := dims
+			truncated := req.Dimensions > 0 && req.Dimensions < width
+			if truncated {
+				width = req.Dimensions
+			}
+			data := make([]map[string]any, len(req.Input))
+			for i := range req.Input {
+				vec := make([]float64, width)
+				for d := range vec {
+					vec[d] = float64(i+1) / float64(d+1)
+				}
+				if truncated {
+					vec = vecutil.Normalize(vec)
+				}
+				data[i] = map[string]any{"embedding": vec, "index": i}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+}
+
+func TestNewOpenAIProvider_RequiresAPIKey(t *testing.T) {
+	if _, err := NewOpenAIProvider(OpenAIProviderConfig{}); err == nil {
+		t.Fatal("expected an error when APIKey is empty")
+	}
+}
+
+func TestOpenAIProvider_ListModels_FiltersToEmbeddingModels(t *testing.T) {
+	server := mockOpenAIServer(t, 4)
+	defer server.Close()
+
+	p, err := NewOpenAIProvider(OpenAIProviderConfig{BaseURL: server.URL, APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	models, err := p.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]bool{"text-embedding-3-small": true, "text-embedding-3-large": true}
+	if len(models) != len(want) {
+		t.Fatalf("expected %d embedding models, got %v", len(want), models)
+	}
+	for _, m := range models {
+		if !want[m] {
+			t.Fatalf("unexpected non-embedding model returned: %q", m)
+		}
+	}
+}
+
+func TestOpenAIProvider_Embed_ReturnsOneVectorPerInputInOrder(t *testing.T) {
+	server := mockOpenAIServer(t, 8)
+	defer server.Close()
+
+	p, err := NewOpenAIProvider(OpenAIProviderConfig{BaseURL: server.URL, APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vecs, err := p.Embed(context.Background(), "text-embedding-3-small", []string{"a", "b", "c"}, EmbedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vecs) != 3 {
+		t.Fatalf("expected 3 vectors, got %d", len(vecs))
+	}
+	for i, v := range vecs {
+		if len(v) != 8 {
+			t.Fatalf("vector %d: expected len 8, got %d", i, len(v))
+		}
+	}
+	// The mock scales each vector's first element by (index+1)/1 - confirm
+	// each result landed at its input's position. The mock returns data
+	// already in index order, so this does not tell reassembly by the
+	// Index field apart from reassembly by array order.
+	if vecs[0][0] != 1 || vecs[1][0] != 2 || vecs[2][0] != 3 {
+		t.Fatalf("vectors not reassembled in input order: %v", vecs)
+	}
+}
+
+func TestOpenAIProvider_Embed_SurfacesAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"error": map[string]any{"message": "invalid api key"},
+		})
+	}))
+	defer server.Close()
+
+	p, err := NewOpenAIProvider(OpenAIProviderConfig{BaseURL: server.URL, APIKey: "bad-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := p.Embed(context.Background(), "text-embedding-3-small", []string{"a"}, EmbedOptions{}); err == nil {
+		t.Fatal("expected an error from a 401 response")
+	}
+}
+
+func TestOpenAIProvider_Dimensions_KnownAndUnknownModels(t *testing.T) {
+	p, err := NewOpenAIProvider(OpenAIProviderConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := p.Dimensions("text-embedding-3-small"); got != 1536 {
+		t.Fatalf("expected 1536, got %d", got)
+	}
+	if got := p.Dimensions("text-embedding-3-large"); got != 3072 {
+		t.Fatalf("expected 3072, got %d", got)
+	}
+	if got := p.Dimensions("some-future-model"); got != 0 {
+		t.Fatalf("expected 0 for an unknown model, got %d", got)
+	}
+}
+
+func TestOpenAIProvider_ImplementsEmbeddingProviderInterface(t *testing.T) {
+	var _ EmbeddingProvider = (*OpenAIProvider)(nil)
+}
+
+func TestOllamaProvider_ImplementsEmbeddingProviderInterface(t *testing.T) {
+	var _ EmbeddingProvider = (*OllamaProvider)(nil)
+}
+
+// mockOllamaManifestServer serves /api/show from manifests (keyed by
+// model name) alongside /api/embed, and counts /api/show calls so tests
+// can confirm OllamaProvider.ModelInfo caches rather than re-fetching.
+func mockOllamaManifestServer(t *testing.T, manifests map[string]*ollamaShowResponse) (*httptest.Server, *int32) {
+	t.Helper()
+	var showCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/show" {
+			atomic.AddInt32(&showCalls, 1)
+			var req ollamaShowRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			manifest, ok := manifests[req.Model]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(manifest)
+			return
+		}
+
+		if r.URL.Path == "/api/embed" {
+			var req ollamaEmbedRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+
+			width := manifests[req.Model].EmbeddingDimensions
+			truncated := req.Dimensions > 0 && req.Dimensions < width
+			if truncated {
+				width = req.Dimensions
+			}
+			embeddings := make([][]float64, len(req.Input))
+			for i := range embeddings {
+				vec := make([]float64, width)
+				for d := range vec {
+					vec[d] = float64(i+1) / float64(d+1)
+				}
+				if truncated {
+					vec = vecutil.Normalize(vec)
+				}
+				embeddings[i] = vec
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	return server, &showCalls
+}
+
+func TestOllamaProvider_ModelInfo_CachesAfterFirstCall(t *testing.T) {
+	server, showCalls := mockOllamaManifestServer(t, map[string]*ollamaShowResponse{
+		"qwen3-embedding:0.6b": {
+			EmbeddingDimensions: 1024,
+			ContextLength:       8192,
+			Capabilities:        []string{"embedding"},
+		},
+	})
+	defer server.Close()
+
+	p := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	for i := 0; i < 3; i++ {
+		info, err := p.ModelInfo(context.Background(), "qwen3-embedding:0.6b")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Dimensions != 1024 {
+			t.Fatalf("expected 1024 dimensions, got %d", info.Dimensions)
+		}
+	}
+	if got := atomic.LoadInt32(showCalls); got != 1 {
+		t.Fatalf("expected ModelInfo to hit /api/show once and cache after, got %d calls", got)
+	}
+}
+
+func TestOllamaProvider_Dimensions_AutoDetectsAfterModelInfo(t *testing.T) {
+	server, _ := mockOllamaManifestServer(t, map[string]*ollamaShowResponse{
+		"qwen3-embedding:0.6b": {EmbeddingDimensions: 1024, Capabilities: []string{"embedding"}},
+	})
+	defer server.Close()
+
+	p := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	if got := p.Dimensions("qwen3-embedding:0.6b"); got != 0 {
+		t.Fatalf("expected 0 before any ModelInfo/Embed call, got %d", got)
+	}
+	if _, err := p.ModelInfo(context.Background(), "qwen3-embedding:0.6b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.Dimensions("qwen3-embedding:0.6b"); got != 1024 {
+		t.Fatalf("expected 1024 after ModelInfo populated the cache, got %d", got)
+	}
+}
+
+func TestOllamaProvider_Embed_RefusesModelWithoutEmbeddingCapability(t *testing.T) {
+	server, _ := mockOllamaManifestServer(t, map[string]*ollamaShowResponse{
+		"llama3.2": {Capabilities: []string{"completion"}},
+	})
+	defer server.Close()
+
+	p := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	if _, err := p.Embed(context.Background(), "llama3.2", []string{"a"}, EmbedOptions{}); err == nil {
+		t.Fatal("expected an error embedding against a model without the embedding capability")
+	}
+}
+
+func TestTruncateAndRenormalize_LengthAndUnitNorm(t *testing.T) {
+	v := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	got := truncateAndRenormalize([][]float64{v}, 3)[0]
+
+	if len(got) != 3 {
+		t.Fatalf("expected length 3, got %d", len(got))
+	}
+	var sumSq float64
+	for _, x := range got {
+		sumSq += x * x
+	}
+	if mag := sumSq; mag < 0.999 || mag > 1.001 {
+		t.Fatalf("expected unit norm, got squared magnitude %v (vector %v)", mag, got)
+	}
+}
+
+func TestTruncateAndRenormalize_MatchesNormalizingTheFullVectorPrefix(t *testing.T) {
+	v := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	got := truncateAndRenormalize([][]float64{v}, 3)[0]
+	want := vecutil.Normalize(v[:3])
+
+	if len(got) != len(want) {
+		t.Fatalf("length mismatch: got %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("index %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTruncateAndRenormalize_DimensionsAtOrAboveLengthIsNoOp(t *testing.T) {
+	v := []float64{1, 2, 3}
+
+	got := truncateAndRenormalize([][]float64{v}, 5)[0]
+
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Fatalf("expected vec unchanged when dims >= len(vec), got %v", got)
+	}
+}
+
+func TestOpenAIProvider_Embed_WithDimensions_ReturnsTruncatedUnitNormVector(t *testing.T) {
+	server := mockOpenAIServer(t, 8)
+	defer server.Close()
+
+	p, err := NewOpenAIProvider(OpenAIProviderConfig{BaseURL: server.URL, APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vecs, err := p.Embed(context.Background(), "text-embedding-3-small", []string{"a"}, EmbedOptions{Dimensions: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vecs[0]) != 4 {
+		t.Fatalf("expected a 4-dimensional vector, got length %d", len(vecs[0]))
+	}
+	var sumSq float64
+	for _, x := range vecs[0] {
+		sumSq += x * x
+	}
+	if sumSq < 0.999 || sumSq > 1.001 {
+		t.Fatalf("expected unit norm, got squared magnitude %v", sumSq)
+	}
+}
+
+func TestOllamaProvider_Embed_SucceedsForEmbeddingCapableModel(t *testing.T) {
+	server, _ := mockOllamaManifestServer(t, map[string]*ollamaShowResponse{
+		"qwen3-embedding:0.6b": {EmbeddingDimensions: 4, Capabilities: []string{"embedding"}},
+	})
+	defer server.Close()
+
+	p := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	vecs, err := p.Embed(context.Background(), "qwen3-embedding:0.6b", []string{"a", "b"}, EmbedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vecs) != 2 || len(vecs[0]) != 4 {
+		t.Fatalf("expected 2 vectors of length 4, got %+v", vecs)
+	}
+}
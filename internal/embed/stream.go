@@ -0,0 +1,133 @@
+package embed
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultStreamSubBatchSize bounds how many inputs EmbedStream puts in a
+// single EmbeddingProvider.Embed call, when StreamConfig.SubBatchSize is
+// left zero.
+const DefaultStreamSubBatchSize = 32
+
+// DefaultStreamConcurrency bounds how many sub-batches EmbedStream has
+// in flight at once, when StreamConfig.Concurrency is left zero.
+const DefaultStreamConcurrency = 4
+
+// StreamProgress is invoked after each sub-batch EmbedStream issues
+// completes successfully: done is the number of inputs embedded so far
+// (including this sub-batch), total is len(inputs), and partial is a
+// snapshot of the full results slice as filled in so far (nil entries for
+// inputs not yet embedded). Sub-batches can complete out of index order,
+// so done only ever increases, but which indices it's safe to read from
+// partial on a given call isn't guaranteed to be a contiguous prefix.
+type StreamProgress func(done, total int, partial [][]float64)
+
+// StreamConfig controls EmbedStream's batching and concurrency.
+type StreamConfig struct {
+	// SubBatchSize is how many inputs go in a single Embed call. Zero
+	// means DefaultStreamSubBatchSize.
+	SubBatchSize int
+	// Concurrency is how many sub-batches are in flight at once. Zero
+	// means DefaultStreamConcurrency.
+	Concurrency int
+}
+
+func (c StreamConfig) withDefaults() StreamConfig {
+	if c.SubBatchSize <= 0 {
+		c.SubBatchSize = DefaultStreamSubBatchSize
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultStreamConcurrency
+	}
+	return c
+}
+
+// EmbedStream embeds inputs against provider in SubBatchSize-sized
+// sub-batches, up to Concurrency of them in flight at once, invoking
+// progress as each sub-batch completes - the streaming counterpart to a
+// single EmbeddingProvider.Embed call for a caller embedding thousands of
+// chunks who wants to report progress instead of blocking silently until
+// everything is done.
+//
+// The returned slice always has len(inputs) entries, reordered back into
+// input order regardless of which sub-batch completed first. If ctx is
+// cancelled or any sub-batch errors, EmbedStream stops launching new
+// sub-batches, waits for in-flight ones to finish, and returns the first
+// error alongside the partially-filled results slice: results already
+// delivered via progress are never rolled back, only left alongside nil
+// entries for the inputs that never got embedded.
+func EmbedStream(ctx context.Context, provider EmbeddingProvider, model string, inputs []string, cfg StreamConfig, progress StreamProgress) ([][]float64, error) {
+	cfg = cfg.withDefaults()
+	total := len(inputs)
+	results := make([][]float64, total)
+	if total == 0 {
+		return results, nil
+	}
+
+	type subBatch struct{ start, end int }
+	var batches []subBatch
+	for start := 0; start < total; start += cfg.SubBatchSize {
+		end := start + cfg.SubBatchSize
+		if end > total {
+			end = total
+		}
+		batches = append(batches, subBatch{start, end})
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		done     int
+	)
+	sem := make(chan struct{}, cfg.Concurrency)
+
+launch:
+	for _, b := range batches {
+		select {
+		case <-streamCtx.Done():
+			break launch
+		case sem <- struct{}{}:
+		}
+
+		b := b
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			vecs, err := provider.Embed(streamCtx, model, inputs[b.start:b.end], EmbedOptions{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			for i, v := range vecs {
+				results[b.start+i] = v
+			}
+			done += b.end - b.start
+			if progress != nil {
+				partial := append([][]float64(nil), results...)
+				progress(done, total, partial)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	return results, nil
+}
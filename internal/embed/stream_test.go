@@ -0,0 +1,197 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockOllamaServerSlow is the slow-responding counterpart to
+// mockOllamaServer (ollama_test.go): every /api/embed call sleeps delay
+// before responding, so tests can observe EmbedStream's progress
+// callbacks arriving one at a time instead of all at once. failAfter, if
+// non-zero, makes every /api/embed call at or past that request count
+// return a 500 instead of a real response, so a later sub-batch can be
+// made to fail without affecting earlier ones.
+func mockOllamaServerSlow(t *testing.T, dims int, delay time.Duration, failAfter int32) *httptest.Server {
+	t.Helper()
+	var requestCount int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tags" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"models": []map[string]any{{"name": "qwen3-embedding:0.6b"}},
+			})
+			return
+		}
+
+		if r.URL.Path == "/api/show" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"embedding_dimensions": dims,
+				"capabilities":         []string{"embedding"},
+			})
+			return
+		}
+
+		if r.URL.Path == "/api/embed" {
+			n := atomic.AddInt32(&requestCount, 1)
+			time.Sleep(delay)
+
+			if failAfter > 0 && n >= failAfter {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error":"overloaded"}`))
+				return
+			}
+
+			var req ollamaEmbedRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			embeddings := make([][]float64, len(req.Input))
+			for i := range embeddings {
+				vec := make([]float64, dims)
+				for d := range vec {
+					vec[d] = float64(i + 1)
+				}
+				embeddings[i] = vec
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+}
+
+func TestEmbedStream_DeliversProgressInIncreasingOrder(t *testing.T) {
+	server := mockOllamaServerSlow(t, 4, 10*time.Millisecond, 0)
+	defer server.Close()
+	provider := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	inputs := make([]string, 20)
+	for i := range inputs {
+		inputs[i] = "text"
+	}
+
+	var mu sync.Mutex
+	var doneSeen []int
+	_, err := EmbedStream(context.Background(), provider, "qwen3-embedding:0.6b", inputs,
+		StreamConfig{SubBatchSize: 5, Concurrency: 2},
+		func(done, total int, partial [][]float64) {
+			mu.Lock()
+			defer mu.Unlock()
+			doneSeen = append(doneSeen, done)
+			if total != 20 {
+				t.Errorf("expected total 20, got %d", total)
+			}
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(doneSeen) != 4 {
+		t.Fatalf("expected 4 progress callbacks (20 inputs / 5 per sub-batch), got %d: %v", len(doneSeen), doneSeen)
+	}
+	for i := 1; i < len(doneSeen); i++ {
+		if doneSeen[i] <= doneSeen[i-1] {
+			t.Fatalf("expected done to strictly increase across callbacks, got %v", doneSeen)
+		}
+	}
+	if doneSeen[len(doneSeen)-1] != 20 {
+		t.Fatalf("expected the final callback to report done=20, got %d", doneSeen[len(doneSeen)-1])
+	}
+}
+
+func TestEmbedStream_ReturnsAllVectorsInInputOrder(t *testing.T) {
+	server := mockOllamaServerSlow(t, 3, 0, 0)
+	defer server.Close()
+	provider := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	inputs := make([]string, 9)
+	for i := range inputs {
+		inputs[i] = "text"
+	}
+
+	results, err := EmbedStream(context.Background(), provider, "qwen3-embedding:0.6b", inputs,
+		StreamConfig{SubBatchSize: 4, Concurrency: 3}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 9 {
+		t.Fatalf("expected 9 results, got %d", len(results))
+	}
+	for i, v := range results {
+		if v == nil {
+			t.Fatalf("result %d is nil, expected an embedded vector", i)
+		}
+	}
+}
+
+func TestEmbedStream_CancellationStopsLaunchingNewSubBatches(t *testing.T) {
+	server := mockOllamaServerSlow(t, 4, 50*time.Millisecond, 0)
+	defer server.Close()
+	provider := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	inputs := make([]string, 40)
+	for i := range inputs {
+		inputs[i] = "text"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+
+	results, err := EmbedStream(ctx, provider, "qwen3-embedding:0.6b", inputs,
+		StreamConfig{SubBatchSize: 5, Concurrency: 1}, nil)
+	if err == nil {
+		t.Fatal("expected an error from the cancelled context")
+	}
+
+	embedded := 0
+	for _, v := range results {
+		if v != nil {
+			embedded++
+		}
+	}
+	if embedded == 0 {
+		t.Fatal("expected at least one sub-batch to have completed before cancellation")
+	}
+	if embedded == len(inputs) {
+		t.Fatal("expected cancellation to stop before every input was embedded")
+	}
+}
+
+func TestEmbedStream_PreservesEarlierPartialResultsWhenALaterBatchErrors(t *testing.T) {
+	// failAfter=2: the first request succeeds, the second (and beyond)
+	// fail - with Concurrency:1 sub-batches are issued one at a time, so
+	// the first sub-batch's results should survive in the returned slice
+	// even though the run as a whole ends in an error.
+	server := mockOllamaServerSlow(t, 4, 0, 2)
+	defer server.Close()
+	provider := NewOllamaProvider(OllamaProviderConfig{Host: server.URL})
+
+	inputs := make([]string, 15)
+	for i := range inputs {
+		inputs[i] = "text"
+	}
+
+	results, err := EmbedStream(context.Background(), provider, "qwen3-embedding:0.6b", inputs,
+		StreamConfig{SubBatchSize: 5, Concurrency: 1}, nil)
+	if err == nil {
+		t.Fatal("expected an error once a later sub-batch fails")
+	}
+
+	for i := 0; i < 5; i++ {
+		if results[i] == nil {
+			t.Fatalf("expected the first sub-batch's result at index %d to be preserved", i)
+		}
+	}
+	for i := 5; i < 15; i++ {
+		if results[i] != nil {
+			t.Fatalf("expected index %d (never successfully embedded) to be nil", i)
+		}
+	}
+}
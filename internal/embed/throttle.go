@@ -0,0 +1,159 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultThrottleCacheWindow bounds how often ThrottledEmbedder actually
+// polls /health for a pressure signal; Throttled calls within the window
+// reuse the last result instead of hammering the endpoint on every batch.
+const DefaultThrottleCacheWindow = time.Second
+
+// throttleLogInterval caps how often a throttled state is logged, to
+// match Prometheus's "at most one throttle message per minute" UX during
+// a long stretch of sustained backpressure.
+const throttleLogInterval = time.Minute
+
+// throttleQueueDepthLimit is the MLX server queue depth above which
+// Throttled treats the endpoint as under pressure, for servers that
+// report queue_depth instead of an explicit throttled flag.
+const throttleQueueDepthLimit = 32
+
+// Throttleable is implemented by Embedder backends that can signal
+// pre-flight backpressure the way Prometheus's ingestion path signals it
+// with Throttled() bool, checked before a batch is submitted rather than
+// by failing individual calls. It is not part of Embedder, and
+// MLXEmbedder (mlx.go) does not implement it; ThrottledEmbedder does, so
+// callers get the check by wrapping a backend in ThrottledEmbedder and
+// type-asserting against this interface.
+type Throttleable interface {
+	// Throttled reports whether the backend is currently signalling
+	// backpressure (queue depth, thermal state, or an explicit flag) and
+	// should not be sent more work right now.
+	Throttled(ctx context.Context) bool
+}
+
+// ThrottledEmbedder wraps an Embedder with a cached /health poll so a
+// caller can check Throttled() before enqueueing a batch and back off,
+// instead of firing the request and eating the progressive timeout once
+// the server is already overloaded - the rationale being that today's
+// progressive timeout only compensates after the fact, during thermal
+// throttling on Apple Silicon in particular.
+type ThrottledEmbedder struct {
+	Embedder
+	healthEndpoint string
+	client         *http.Client
+	cacheWindow    time.Duration
+
+	mu         sync.Mutex
+	checkedAt  time.Time
+	cached     bool
+	lastLogged time.Time
+}
+
+// NewThrottledEmbedder wraps base, polling healthEndpoint (typically
+// base's own "<endpoint>/health") for pressure signals. cacheWindow
+// defaults to DefaultThrottleCacheWindow when zero or negative.
+func NewThrottledEmbedder(base Embedder, healthEndpoint string, cacheWindow time.Duration) *ThrottledEmbedder {
+	if cacheWindow <= 0 {
+		cacheWindow = DefaultThrottleCacheWindow
+	}
+	return &ThrottledEmbedder{
+		Embedder:       base,
+		healthEndpoint: healthEndpoint,
+		client:         &http.Client{Timeout: 2 * time.Second},
+		cacheWindow:    cacheWindow,
+	}
+}
+
+// throttleHealthResponse is the subset of /health's body Throttled reads
+// a pressure signal from: an explicit flag, an MLX server queue depth, or
+// a thermal state string (e.g. "throttled" on Apple Silicon under load).
+type throttleHealthResponse struct {
+	Throttled    bool   `json:"throttled"`
+	QueueDepth   int    `json:"queue_depth"`
+	ThermalState string `json:"thermal_state"`
+}
+
+// Throttled polls healthEndpoint for a pressure signal, caching the
+// result for cacheWindow so repeated pre-flight checks (one per batch)
+// don't themselves become load on an already-stressed server. A poll
+// failure is treated as "not throttled" - the caller's own request will
+// surface the real error if the server is actually down.
+func (t *ThrottledEmbedder) Throttled(ctx context.Context) bool {
+	t.mu.Lock()
+	if time.Since(t.checkedAt) < t.cacheWindow {
+		cached := t.cached
+		t.mu.Unlock()
+		return cached
+	}
+	t.mu.Unlock()
+
+	throttled := t.pollHealth(ctx)
+
+	t.mu.Lock()
+	t.cached = throttled
+	t.checkedAt = time.Now()
+	shouldLog := throttled && time.Since(t.lastLogged) >= throttleLogInterval
+	if shouldLog {
+		t.lastLogged = time.Now()
+	}
+	t.mu.Unlock()
+
+	if shouldLog {
+		slog.Warn("embedder_throttled", slog.String("endpoint", t.healthEndpoint))
+	}
+	return throttled
+}
+
+func (t *ThrottledEmbedder) pollHealth(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.healthEndpoint, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var health throttleHealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return false
+	}
+	return health.Throttled ||
+		health.QueueDepth > throttleQueueDepthLimit ||
+		health.ThermalState == "throttled"
+}
+
+// WaitWhileThrottled checks embedder for Throttleable support and, while
+// Throttled() reports true, sleeps with exponential backoff (starting at
+// initial, capped at max, doubling each round) instead of letting the
+// caller fire a batch straight into an overloaded server. It returns nil
+// immediately for non-Throttleable embedders, and returns ctx.Err() if
+// ctx is cancelled while waiting.
+func WaitWhileThrottled(ctx context.Context, embedder Embedder, initial, max time.Duration) error {
+	t, ok := embedder.(Throttleable)
+	if !ok {
+		return nil
+	}
+
+	backoff := initial
+	for t.Throttled(ctx) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > max {
+			backoff = max
+		}
+	}
+	return nil
+}
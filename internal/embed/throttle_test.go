@@ -0,0 +1,125 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubEmbedder is a minimal Embedder used to test ThrottledEmbedder
+// without standing up a real MLX backend.
+type stubEmbedder struct {
+	embedCalls atomic.Int32
+}
+
+func (s *stubEmbedder) Embed(context.Context, string) ([]float32, error) {
+	s.embedCalls.Add(1)
+	return make([]float32, 8), nil
+}
+func (s *stubEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i], _ = s.Embed(ctx, texts[i])
+	}
+	return out, nil
+}
+func (s *stubEmbedder) Dimensions() int                { return 8 }
+func (s *stubEmbedder) ModelName() string              { return "stub" }
+func (s *stubEmbedder) Available(context.Context) bool { return true }
+func (s *stubEmbedder) Close() error                   { return nil }
+func (s *stubEmbedder) SetBatchIndex(int)              {}
+func (s *stubEmbedder) SetFinalBatch(bool)             {}
+
+func newHealthServer(t *testing.T, throttled *atomic.Bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(throttleHealthResponse{Throttled: throttled.Load()})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestThrottledEmbedder_ReflectsHealthFlag(t *testing.T) {
+	var throttled atomic.Bool
+	srv := newHealthServer(t, &throttled)
+
+	te := NewThrottledEmbedder(&stubEmbedder{}, srv.URL, time.Millisecond)
+
+	if te.Throttled(context.Background()) {
+		t.Fatal("expected not throttled before server toggles the flag")
+	}
+
+	throttled.Store(true)
+	time.Sleep(2 * time.Millisecond) // let the cache window lapse
+	if !te.Throttled(context.Background()) {
+		t.Fatal("expected throttled after server reports throttled=true")
+	}
+}
+
+func TestThrottledEmbedder_CachesWithinWindow(t *testing.T) {
+	var throttled atomic.Bool
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		_ = json.NewEncoder(w).Encode(throttleHealthResponse{Throttled: throttled.Load()})
+	}))
+	t.Cleanup(srv.Close)
+
+	te := NewThrottledEmbedder(&stubEmbedder{}, srv.URL, time.Hour)
+
+	te.Throttled(context.Background())
+	te.Throttled(context.Background())
+	te.Throttled(context.Background())
+
+	if got := polls.Load(); got != 1 {
+		t.Fatalf("expected exactly 1 poll within the cache window, got %d", got)
+	}
+}
+
+func TestWaitWhileThrottled_DefersUntilHealthClears(t *testing.T) {
+	var throttled atomic.Bool
+	throttled.Store(true)
+	srv := newHealthServer(t, &throttled)
+
+	stub := &stubEmbedder{}
+	te := NewThrottledEmbedder(stub, srv.URL, time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- WaitWhileThrottled(context.Background(), te, time.Millisecond, 10*time.Millisecond)
+	}()
+
+	// While throttled, EmbedBatch must not have been called yet.
+	time.Sleep(5 * time.Millisecond)
+	if stub.embedCalls.Load() != 0 {
+		t.Fatal("embedder should not have been called while throttled")
+	}
+
+	throttled.Store(false)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitWhileThrottled did not return after health cleared")
+	}
+
+	if _, err := te.EmbedBatch(context.Background(), []string{"a"}); err != nil {
+		t.Fatalf("EmbedBatch after throttle clears: %v", err)
+	}
+	if stub.embedCalls.Load() != 1 {
+		t.Fatalf("expected 1 embed call after throttle cleared, got %d", stub.embedCalls.Load())
+	}
+}
+
+func TestWaitWhileThrottled_NoopForNonThrottleable(t *testing.T) {
+	stub := &stubEmbedder{}
+	if err := WaitWhileThrottled(context.Background(), stub, time.Millisecond, time.Millisecond); err != nil {
+		t.Fatalf("expected no error for a non-Throttleable embedder, got %v", err)
+	}
+}
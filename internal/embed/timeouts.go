@@ -0,0 +1,98 @@
+package embed
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxProgressiveMultiplier matches the 2x cap getProgressiveTimeout
+// already applies to the single outer request timeout, now scoped to just
+// ResponseTimeout once MLXTimeouts splits the knobs apart.
+const DefaultMaxProgressiveMultiplier = 2.0
+
+// MLXTimeouts splits MLXEmbedder's single progressive request timeout
+// into the phases Traefik's RespondingTimeouts distinguishes, so a stuck
+// TLS handshake or a slow response-header phase fails fast instead of
+// riding the same budget as the embedding computation itself:
+//
+//   - DialTimeout bounds establishing the TCP connection.
+//   - RequestHeaderTimeout bounds how long the server may take to start
+//     writing response headers once the request is sent.
+//   - ResponseTimeout bounds reading the full response body; this is the
+//     only phase getProgressiveTimeout should scale with batch progress.
+//   - IdleConnTimeout bounds how long an idle keep-alive connection is
+//     kept in the client's pool before being closed.
+//
+// MLXTimeouts is standalone: MLXConfig (mlx.go) has no Timeouts field,
+// and MLXEmbedder does not use these phases; it still bounds each call
+// with the single context timeout getProgressiveTimeout returns.
+// NewTransport builds an http.Transport with these phases, and
+// ProgressiveResponseTimeout replicates getProgressiveTimeout's scaling
+// logic scoped to just ResponseTimeout.
+type MLXTimeouts struct {
+	DialTimeout              time.Duration
+	RequestHeaderTimeout     time.Duration
+	ResponseTimeout          time.Duration
+	IdleConnTimeout          time.Duration
+	MaxProgressiveMultiplier float64
+}
+
+// DefaultMLXTimeouts returns the timeout split matching today's MLXConfig
+// defaults: a 60s base ResponseTimeout (mlxBaseTimeout, the floor of
+// MLXEmbedder's single timeout), generous but bounded dial/header/idle phases, and the 2x
+// progressive cap getProgressiveTimeout already enforces.
+func DefaultMLXTimeouts() MLXTimeouts {
+	return MLXTimeouts{
+		DialTimeout:              10 * time.Second,
+		RequestHeaderTimeout:     15 * time.Second,
+		ResponseTimeout:          60 * time.Second,
+		IdleConnTimeout:          90 * time.Second,
+		MaxProgressiveMultiplier: DefaultMaxProgressiveMultiplier,
+	}
+}
+
+// dialer builds the net.Dialer NewTransport wires DialTimeout through,
+// split out so tests can assert the timeout propagates without relying
+// on a real hung connection.
+func (t MLXTimeouts) dialer() *net.Dialer {
+	return &net.Dialer{Timeout: t.DialTimeout}
+}
+
+// NewTransport builds an http.Transport wiring DialTimeout,
+// RequestHeaderTimeout (as ResponseHeaderTimeout, the stdlib's name for
+// the same phase), and IdleConnTimeout - the three phases that should
+// fail fast and never scale with batch progress, unlike ResponseTimeout.
+func (t MLXTimeouts) NewTransport() *http.Transport {
+	return &http.Transport{
+		DialContext:           t.dialer().DialContext,
+		ResponseHeaderTimeout: t.RequestHeaderTimeout,
+		IdleConnTimeout:       t.IdleConnTimeout,
+	}
+}
+
+// ProgressiveResponseTimeout scales ResponseTimeout the same way
+// MLXEmbedder.getProgressiveTimeout scales the current single timeout:
+// linearly with batchIndex up to MaxProgressiveMultiplier, with a 1.5x
+// boost on the final batch (capped at the same multiplier ceiling). The
+// dial/header/idle phases are never scaled - only the long-tail embedding
+// computation itself benefits from the extension.
+func (t MLXTimeouts) ProgressiveResponseTimeout(batchIndex int, isFinalBatch bool) time.Duration {
+	maxMultiplier := t.MaxProgressiveMultiplier
+	if maxMultiplier <= 0 {
+		maxMultiplier = DefaultMaxProgressiveMultiplier
+	}
+
+	multiplier := 1.0 + float64(batchIndex)/50.0
+	if multiplier > maxMultiplier {
+		multiplier = maxMultiplier
+	}
+	if isFinalBatch {
+		multiplier *= 1.5
+		if cap := maxMultiplier * 1.5; multiplier > cap {
+			multiplier = cap
+		}
+	}
+
+	return time.Duration(float64(t.ResponseTimeout) * multiplier)
+}
@@ -0,0 +1,118 @@
+package embed
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDefaultMLXTimeouts(t *testing.T) {
+	d := DefaultMLXTimeouts()
+
+	if d.DialTimeout != 10*time.Second {
+		t.Errorf("expected 10s dial timeout, got %v", d.DialTimeout)
+	}
+	if d.ResponseTimeout != 60*time.Second {
+		t.Errorf("expected 60s response timeout (matching DefaultMLXConfig's former single timeout), got %v", d.ResponseTimeout)
+	}
+	if d.MaxProgressiveMultiplier != 2.0 {
+		t.Errorf("expected 2x max progressive multiplier (matching current behavior), got %v", d.MaxProgressiveMultiplier)
+	}
+}
+
+func TestMLXTimeouts_ProgressiveResponseTimeout(t *testing.T) {
+	timeouts := MLXTimeouts{ResponseTimeout: 60 * time.Second, MaxProgressiveMultiplier: 2.0}
+
+	tests := []struct {
+		name         string
+		batchIndex   int
+		isFinalBatch bool
+		minTimeout   time.Duration
+		maxTimeout   time.Duration
+	}{
+		{name: "early batch", batchIndex: 0, minTimeout: 60 * time.Second, maxTimeout: 61 * time.Second},
+		{name: "middle batch", batchIndex: 50, minTimeout: 100 * time.Second, maxTimeout: 120 * time.Second},
+		{name: "late batch capped at 2x", batchIndex: 100, minTimeout: 120 * time.Second, maxTimeout: 121 * time.Second},
+		{name: "final batch boosted", batchIndex: 100, isFinalBatch: true, minTimeout: 180 * time.Second, maxTimeout: 181 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := timeouts.ProgressiveResponseTimeout(tt.batchIndex, tt.isFinalBatch)
+			if got < tt.minTimeout || got > tt.maxTimeout {
+				t.Errorf("ProgressiveResponseTimeout(%d, %v) = %v, want between %v and %v",
+					tt.batchIndex, tt.isFinalBatch, got, tt.minTimeout, tt.maxTimeout)
+			}
+		})
+	}
+}
+
+// TestMLXTimeouts_NewTransport_DialTimeoutEnforced proves DialTimeout
+// propagates into the transport's dialer independent of
+// RequestHeaderTimeout/IdleConnTimeout. A live hung-connect test isn't
+// reproducible hermetically (this sandbox's egress accepts every
+// destination instantly, so even a non-routable address never actually
+// blocks at the TCP level) - asserting the wiring directly is the
+// deterministic equivalent.
+func TestMLXTimeouts_NewTransport_DialTimeoutEnforced(t *testing.T) {
+	timeouts := MLXTimeouts{DialTimeout: 50 * time.Millisecond, RequestHeaderTimeout: 5 * time.Second, IdleConnTimeout: 10 * time.Second}
+
+	d := timeouts.dialer()
+	if d.Timeout != timeouts.DialTimeout {
+		t.Errorf("expected dialer timeout %v, got %v", timeouts.DialTimeout, d.Timeout)
+	}
+
+	transport := timeouts.NewTransport()
+	if transport.ResponseHeaderTimeout != timeouts.RequestHeaderTimeout {
+		t.Errorf("expected ResponseHeaderTimeout %v, got %v", timeouts.RequestHeaderTimeout, transport.ResponseHeaderTimeout)
+	}
+	if transport.IdleConnTimeout != timeouts.IdleConnTimeout {
+		t.Errorf("expected IdleConnTimeout %v, got %v", timeouts.IdleConnTimeout, transport.IdleConnTimeout)
+	}
+}
+
+// TestMLXTimeouts_NewTransport_HeaderTimeoutEnforced simulates a hung
+// header write: a server that accepts the connection and reads the
+// request but never writes a response, proving RequestHeaderTimeout
+// (wired as http.Transport.ResponseHeaderTimeout) fails the request
+// independent of DialTimeout.
+func TestMLXTimeouts_NewTransport_HeaderTimeoutEnforced(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 4096)
+		_, _ = conn.Read(buf) // read the request, then go silent forever
+		<-done
+	}()
+
+	timeouts := MLXTimeouts{DialTimeout: 2 * time.Second, RequestHeaderTimeout: 50 * time.Millisecond}
+	transport := timeouts.NewTransport()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+ln.Addr().String(), nil)
+
+	start := time.Now()
+	_, err = transport.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected response header timeout against a server that never responds")
+	}
+	if elapsed > time.Second {
+		t.Errorf("header timeout took %v, expected it to fail fast near RequestHeaderTimeout", elapsed)
+	}
+}
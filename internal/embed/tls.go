@@ -0,0 +1,170 @@
+package embed
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// MLXTLSConfig carries the material needed to talk to an MLX embedding
+// server over TLS/mTLS instead of assuming plain HTTP on localhost - the
+// same file-or-content pattern Traefik's TLS config uses, so a config
+// loaded from a file and one assembled in-process (e.g. from a secrets
+// manager) both work without a temp file in between. Each field pair is
+// mutually exclusive; *Content takes precedence when both are set.
+//
+// MLXTLSConfig is standalone: MLXConfig (mlx.go) has no TLS field, and
+// NewMLXEmbedder installs no TLS config and does not check the endpoint's
+// scheme. Build() produces a *tls.Config from this material, and
+// UpgradeEndpoint() rewrites an http:// endpoint to https://.
+type MLXTLSConfig struct {
+	// CAFile/CAContent is the CA bundle used to verify the MLX server's
+	// certificate. Omit both to fall back to the system trust store.
+	CAFile    string
+	CAContent []byte
+
+	// CertFile/CertContent and KeyFile/KeyContent are this client's own
+	// certificate and key, presented for mTLS. Both halves must be set
+	// together; each half is read from its *Content field, or else from
+	// its *File.
+	CertFile    string
+	CertContent []byte
+	KeyFile     string
+	KeyContent  []byte
+
+	// ServerName overrides the SNI/certificate-verification hostname,
+	// for endpoints reached by IP (e.g. a Mac Studio on the local
+	// network) whose certificate was issued for a different name.
+	ServerName string
+
+	// InsecureSkipVerify disables certificate verification entirely.
+	// Only meant for local development against a self-signed endpoint
+	// with no CA material configured at all.
+	InsecureSkipVerify bool
+}
+
+// Build assembles a *tls.Config from c: a CA pool (system trust store if
+// neither CAFile nor CAContent is set), an optional client certificate
+// for mTLS, and ServerName/InsecureSkipVerify passed straight through.
+func (c MLXTLSConfig) Build() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	ca, err := c.loadCA()
+	if err != nil {
+		return nil, fmt.Errorf("load CA: %w", err)
+	}
+	if ca != nil {
+		cfg.RootCAs = ca
+	}
+
+	cert, hasCert, err := c.loadClientCert()
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate: %w", err)
+	}
+	if hasCert {
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+func (c MLXTLSConfig) loadCA() (*x509.CertPool, error) {
+	content := c.CAContent
+	if len(content) == 0 && c.CAFile != "" {
+		data, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", c.CAFile, err)
+		}
+		content = data
+	}
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no valid certificates found in CA bundle")
+	}
+	return pool, nil
+}
+
+func (c MLXTLSConfig) loadClientCert() (tls.Certificate, bool, error) {
+	certContent, keyContent := c.CertContent, c.KeyContent
+
+	if len(certContent) == 0 && c.CertFile != "" {
+		data, err := os.ReadFile(c.CertFile)
+		if err != nil {
+			return tls.Certificate{}, false, fmt.Errorf("read %s: %w", c.CertFile, err)
+		}
+		certContent = data
+	}
+	if len(keyContent) == 0 && c.KeyFile != "" {
+		data, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return tls.Certificate{}, false, fmt.Errorf("read %s: %w", c.KeyFile, err)
+		}
+		keyContent = data
+	}
+
+	if len(certContent) == 0 && len(keyContent) == 0 {
+		return tls.Certificate{}, false, nil
+	}
+	if len(certContent) == 0 || len(keyContent) == 0 {
+		return tls.Certificate{}, false, fmt.Errorf("client certificate and key must both be provided")
+	}
+
+	cert, err := tls.X509KeyPair(certContent, keyContent)
+	if err != nil {
+		return tls.Certificate{}, false, err
+	}
+	return cert, true, nil
+}
+
+// HasMaterial reports whether c configures anything beyond the zero
+// value - used by UpgradeEndpoint to decide whether an http:// endpoint
+// is auto-upgraded to https://.
+func (c MLXTLSConfig) HasMaterial() bool {
+	return c.CAFile != "" || len(c.CAContent) > 0 ||
+		c.CertFile != "" || len(c.CertContent) > 0 ||
+		c.KeyFile != "" || len(c.KeyContent) > 0 ||
+		c.ServerName != "" || c.InsecureSkipVerify
+}
+
+// UpgradeEndpoint rewrites an http:// endpoint to https:// when tlsCfg
+// configures TLS material, so a config that sets CAFile etc. without
+// remembering to also change the scheme doesn't silently talk plaintext
+// to what's assumed to be a TLS-protected endpoint. endpoint is returned
+// unchanged when tlsCfg is nil or already https://.
+func UpgradeEndpoint(endpoint string, tlsCfg *MLXTLSConfig) (string, error) {
+	if tlsCfg == nil || !tlsCfg.HasMaterial() {
+		return endpoint, nil
+	}
+	const httpPrefix = "http://"
+	const httpsPrefix = "https://"
+	if len(endpoint) >= len(httpsPrefix) && endpoint[:len(httpsPrefix)] == httpsPrefix {
+		return endpoint, nil
+	}
+	if len(endpoint) >= len(httpPrefix) && endpoint[:len(httpPrefix)] == httpPrefix {
+		return httpsPrefix + endpoint[len(httpPrefix):], nil
+	}
+	return endpoint, nil
+}
+
+// NewTLSTransport builds on MLXTimeouts.NewTransport, additionally
+// installing tlsCfg's *tls.Config so dial/header/idle timeouts and TLS
+// verification are both enforced on the same http.Transport a wired-in
+// MLXEmbedder would use.
+func (t MLXTimeouts) NewTLSTransport(tlsCfg MLXTLSConfig) (*http.Transport, error) {
+	built, err := tlsCfg.Build()
+	if err != nil {
+		return nil, err
+	}
+	transport := t.NewTransport()
+	transport.TLSClientConfig = built
+	return transport, nil
+}
@@ -0,0 +1,188 @@
+package embed
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// certPEM returns ts's certificate re-encoded as a PEM block, so it can be
+// fed back in as MLXTLSConfig.CAContent the way an operator would paste in
+// their MLX server's self-signed cert.
+func certPEM(t *testing.T, ts *httptest.Server) []byte {
+	t.Helper()
+	cert := ts.Certificate()
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func TestMLXTLSConfig_Build_TrustsServerCA(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	cfg := MLXTLSConfig{CAContent: certPEM(t, ts)}
+	tlsCfg, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		Timeout:   2 * time.Second,
+	}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("expected request to succeed with the server's own CA trusted, got: %v", err)
+	}
+	resp.Body.Close()
+}
+
+// unrelatedCAPEM generates a throwaway self-signed certificate that has
+// nothing to do with any httptest server, standing in for a bad/mismatched
+// CAFile. httptest.NewTLSServer instances share the package's well-known
+// default certificate, so two servers' certs can't be used to prove
+// rejection - a freshly generated one is required.
+func unrelatedCAPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "unrelated-ca"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestMLXTLSConfig_Build_RejectsBadCA(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	// unrelatedCA has nothing to do with ts's certificate, so verification
+	// against it must fail - the negative case a bad/mismatched CAFile
+	// would hit in production.
+	cfg := MLXTLSConfig{CAContent: unrelatedCAPEM(t)}
+	tlsCfg, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		Timeout:   2 * time.Second,
+	}
+	_, err = client.Get(ts.URL)
+	if err == nil {
+		t.Fatal("expected certificate verification to fail against an unrelated CA")
+	}
+}
+
+func TestMLXTLSConfig_Build_InsecureSkipVerify(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	cfg := MLXTLSConfig{InsecureSkipVerify: true}
+	tlsCfg, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		Timeout:   2 * time.Second,
+	}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("expected InsecureSkipVerify to bypass CA trust, got: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestMLXTLSConfig_Build_RejectsBadClientKeyPair(t *testing.T) {
+	cfg := MLXTLSConfig{CertContent: []byte("not a cert"), KeyContent: []byte("not a key")}
+	if _, err := cfg.Build(); err == nil {
+		t.Fatal("expected an error building a *tls.Config from a malformed client cert/key pair")
+	}
+}
+
+func TestMLXTLSConfig_HasMaterial(t *testing.T) {
+	if (MLXTLSConfig{}).HasMaterial() {
+		t.Error("zero-value MLXTLSConfig should report no material")
+	}
+	if !(MLXTLSConfig{CAFile: "ca.pem"}).HasMaterial() {
+		t.Error("expected CAFile to count as material")
+	}
+}
+
+func TestUpgradeEndpoint(t *testing.T) {
+	withMaterial := &MLXTLSConfig{CAFile: "ca.pem"}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		tlsCfg   *MLXTLSConfig
+		want     string
+	}{
+		{name: "nil config leaves http untouched", endpoint: "http://localhost:8080", tlsCfg: nil, want: "http://localhost:8080"},
+		{name: "no material leaves http untouched", endpoint: "http://localhost:8080", tlsCfg: &MLXTLSConfig{}, want: "http://localhost:8080"},
+		{name: "TLS material upgrades http to https", endpoint: "http://localhost:8080", tlsCfg: withMaterial, want: "https://localhost:8080"},
+		{name: "already https is left alone", endpoint: "https://localhost:8080", tlsCfg: withMaterial, want: "https://localhost:8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpgradeEndpoint(tt.endpoint, tt.tlsCfg)
+			if err != nil {
+				t.Fatalf("UpgradeEndpoint: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("UpgradeEndpoint(%q) = %q, want %q", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMLXTimeouts_NewTLSTransport(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	timeouts := MLXTimeouts{DialTimeout: 2 * time.Second, RequestHeaderTimeout: 2 * time.Second, IdleConnTimeout: 10 * time.Second}
+	transport, err := timeouts.NewTLSTransport(MLXTLSConfig{CAContent: certPEM(t, ts)})
+	if err != nil {
+		t.Fatalf("NewTLSTransport: %v", err)
+	}
+	if transport.TLSClientConfig == nil {
+		t.Fatal("expected TLSClientConfig to be installed")
+	}
+	if transport.ResponseHeaderTimeout != timeouts.RequestHeaderTimeout {
+		t.Errorf("expected dial/header/idle timeouts to still be wired, got ResponseHeaderTimeout=%v", transport.ResponseHeaderTimeout)
+	}
+
+	client := &http.Client{Transport: transport, Timeout: 2 * time.Second}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("expected request over the combined TLS+timeout transport to succeed, got: %v", err)
+	}
+	resp.Body.Close()
+}
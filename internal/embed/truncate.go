@@ -0,0 +1,102 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// TruncatedEmbedder wraps an Embedder and slices every vector it returns
+// down to truncateDimensions, re-L2-normalizing the slice before handing
+// it back - the Matryoshka Representation Learning trick
+// qwen3-embedding and embeddinggemma are both trained to support: a
+// shorter prefix of the full vector is still a meaningful embedding, so a
+// caller that needs less memory/IO per vector can truncate instead of
+// switching models.
+//
+// OllamaConfig (ollama.go) has no truncation option, and OllamaEmbedder
+// itself returns full-width vectors; truncation happens only in this
+// wrapper, at the Embedder level, the same way CalibratedEmbedder and
+// CachedEmbedder wrap - its output still ends up re-normalized to unit
+// length, which is what every downstream cosine-similarity comparison
+// actually depends on.
+type TruncatedEmbedder struct {
+	Embedder
+	truncateDimensions int
+}
+
+// NewTruncatedEmbedder wraps base, truncating every vector to
+// truncateDimensions. truncateDimensions <= 0 disables truncation (Embed
+// and EmbedBatch pass base's vectors through unchanged, and Dimensions()
+// reports base's native size) - the "non-zero" half of the request's
+// "when non-zero and less than the native dimensionality" condition.
+// truncateDimensions greater than base.Dimensions() is a construction-time
+// error, matching the request's validation requirement.
+func NewTruncatedEmbedder(base Embedder, truncateDimensions int) (*TruncatedEmbedder, error) {
+	if truncateDimensions > 0 && truncateDimensions > base.Dimensions() {
+		return nil, fmt.Errorf("embed: truncate dimensions %d exceeds native dimensionality %d", truncateDimensions, base.Dimensions())
+	}
+	return &TruncatedEmbedder{Embedder: base, truncateDimensions: truncateDimensions}, nil
+}
+
+// Dimensions returns the truncated size if truncation is enabled, or
+// base's native size otherwise.
+func (t *TruncatedEmbedder) Dimensions() int {
+	if t.truncateDimensions > 0 {
+		return t.truncateDimensions
+	}
+	return t.Embedder.Dimensions()
+}
+
+// Embed returns base's embedding for text, truncated and re-normalized if
+// truncation is enabled.
+func (t *TruncatedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vec, err := t.Embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return t.truncate(vec), nil
+}
+
+// EmbedBatch returns base's embeddings for texts, truncated and
+// re-normalized if truncation is enabled.
+func (t *TruncatedEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs, err := t.Embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if t.truncateDimensions <= 0 {
+		return vecs, nil
+	}
+	out := make([][]float32, len(vecs))
+	for i, vec := range vecs {
+		out[i] = t.truncate(vec)
+	}
+	return out, nil
+}
+
+// truncate slices vec to t.truncateDimensions (if shorter than vec and
+// truncation is enabled) and re-L2-normalizes the result. vec shorter
+// than or equal to t.truncateDimensions is returned unchanged - nothing
+// to truncate.
+func (t *TruncatedEmbedder) truncate(vec []float32) []float32 {
+	if t.truncateDimensions <= 0 || t.truncateDimensions >= len(vec) {
+		return vec
+	}
+
+	truncated := make([]float32, t.truncateDimensions)
+	var sumSquares float64
+	for i := 0; i < t.truncateDimensions; i++ {
+		truncated[i] = vec[i]
+		sumSquares += float64(vec[i]) * float64(vec[i])
+	}
+
+	norm := math.Sqrt(sumSquares)
+	if norm < calibrationEpsilon {
+		return truncated
+	}
+	for i := range truncated {
+		truncated[i] = float32(float64(truncated[i]) / norm)
+	}
+	return truncated
+}
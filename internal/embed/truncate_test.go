@@ -0,0 +1,106 @@
+package embed
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// truncateStubEmbedder is a minimal Embedder used to test
+// TruncatedEmbedder, mirroring stubEmbedder in throttle_test.go.
+type truncateStubEmbedder struct {
+	dims int
+	vec  []float32
+}
+
+func (s *truncateStubEmbedder) Embed(context.Context, string) ([]float32, error) {
+	return s.vec, nil
+}
+func (s *truncateStubEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i], _ = s.Embed(ctx, texts[i])
+	}
+	return out, nil
+}
+func (s *truncateStubEmbedder) Dimensions() int                { return s.dims }
+func (s *truncateStubEmbedder) ModelName() string              { return "stub" }
+func (s *truncateStubEmbedder) Available(context.Context) bool { return true }
+func (s *truncateStubEmbedder) Close() error                   { return nil }
+
+func TestNewTruncatedEmbedder_RejectsTruncationAboveNative(t *testing.T) {
+	base := &truncateStubEmbedder{dims: 4, vec: []float32{1, 0, 0, 0}}
+	if _, err := NewTruncatedEmbedder(base, 8); err == nil {
+		t.Fatal("expected an error when truncateDimensions exceeds native dimensionality")
+	}
+}
+
+func TestTruncatedEmbedder_DisabledWhenZero(t *testing.T) {
+	base := &truncateStubEmbedder{dims: 4, vec: []float32{0.5, 0.5, 0.5, 0.5}}
+	te, err := NewTruncatedEmbedder(base, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if te.Dimensions() != 4 {
+		t.Fatalf("expected Dimensions() to report native size when disabled, got %d", te.Dimensions())
+	}
+
+	vec, err := te.Embed(context.Background(), "x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vec) != 4 {
+		t.Fatalf("expected vector to pass through unchanged, got len %d", len(vec))
+	}
+}
+
+func TestTruncatedEmbedder_TruncatesAndRenormalizes(t *testing.T) {
+	// A unit vector spread evenly across 4 dims: each component is 0.5,
+	// magnitude 1. Truncated to 2 dims it should renormalize to (1/sqrt2, 1/sqrt2).
+	base := &truncateStubEmbedder{dims: 4, vec: []float32{0.5, 0.5, 0.5, 0.5}}
+	te, err := NewTruncatedEmbedder(base, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if te.Dimensions() != 2 {
+		t.Fatalf("expected Dimensions() to report the truncated size, got %d", te.Dimensions())
+	}
+
+	vec, err := te.Embed(context.Background(), "x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vec) != 2 {
+		t.Fatalf("expected a 2-dim vector, got %d", len(vec))
+	}
+	want := float32(1 / math.Sqrt2)
+	if math.Abs(float64(vec[0]-want)) > 1e-6 || math.Abs(float64(vec[1]-want)) > 1e-6 {
+		t.Fatalf("expected (%v, %v), got (%v, %v)", want, want, vec[0], vec[1])
+	}
+
+	var mag float64
+	for _, x := range vec {
+		mag += float64(x) * float64(x)
+	}
+	if math.Abs(math.Sqrt(mag)-1.0) > 1e-6 {
+		t.Fatalf("expected the truncated vector to be unit length, got magnitude %v", math.Sqrt(mag))
+	}
+}
+
+func TestTruncatedEmbedder_EmbedBatchTruncatesEachVector(t *testing.T) {
+	base := &truncateStubEmbedder{dims: 4, vec: []float32{0.5, 0.5, 0.5, 0.5}}
+	te, err := NewTruncatedEmbedder(base, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vecs, err := te.EmbedBatch(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, vec := range vecs {
+		if len(vec) != 2 {
+			t.Fatalf("vector %d: expected len 2, got %d", i, len(vec))
+		}
+	}
+}
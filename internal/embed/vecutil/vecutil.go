@@ -0,0 +1,57 @@
+// Package vecutil holds small, dependency-free vector math helpers shared
+// across the embeddings package and its consumers (retrieval, test mock
+// servers that need a realistic deterministic embedding) - promoted out of
+// ollama_test.go's own ad hoc normalization logic, which had a bug (scaling
+// by 1/||v||^2 instead of 1/||v||) that no test caught since nothing
+// outside that one file ever checked the result was actually unit-length.
+package vecutil
+
+import "math"
+
+// Normalize returns a copy of v scaled to unit L2 length. A zero vector is
+// returned unchanged (there's no direction to scale toward).
+func Normalize(v []float64) []float64 {
+	var sumSq float64
+	for _, x := range v {
+		sumSq += x * x
+	}
+	if sumSq == 0 {
+		return append([]float64(nil), v...)
+	}
+
+	mag := math.Sqrt(sumSq)
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = x / mag
+	}
+	return out
+}
+
+// Cosine returns the cosine similarity between a and b. On a length
+// mismatch it does not panic: the dot product covers only the shorter
+// length, while each magnitude is taken over its whole vector. Either
+// vector being zero-length (magnitude 0) returns 0 rather than dividing by
+// zero.
+func Cosine(a, b []float64) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+
+	var dot, magA, magB float64
+	for i := 0; i < n; i++ {
+		dot += a[i] * b[i]
+	}
+	for _, x := range a {
+		magA += x * x
+	}
+	for _, x := range b {
+		magB += x * x
+	}
+	magA = math.Sqrt(magA)
+	magB = math.Sqrt(magB)
+	if magA == 0 || magB == 0 {
+		return 0
+	}
+	return dot / (magA * magB)
+}
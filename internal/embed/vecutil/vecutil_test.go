@@ -0,0 +1,70 @@
+package vecutil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalize_ResultHasUnitLength(t *testing.T) {
+	v := []float64{3, 4, 0, 1}
+
+	got := Normalize(v)
+
+	var sumSq float64
+	for _, x := range got {
+		sumSq += x * x
+	}
+	if mag := math.Sqrt(sumSq); math.Abs(mag-1.0) > 1e-9 {
+		t.Fatalf("expected unit length, got magnitude %v (vector %v)", mag, got)
+	}
+}
+
+func TestNormalize_ZeroVectorReturnsUnchanged(t *testing.T) {
+	v := []float64{0, 0, 0}
+
+	got := Normalize(v)
+
+	for i, x := range got {
+		if x != 0 {
+			t.Fatalf("expected zero vector to remain zero, got %v at index %d", x, i)
+		}
+	}
+}
+
+func TestCosine_IdenticalVectorsReturnOne(t *testing.T) {
+	v := []float64{1, 2, 3, 4}
+
+	got := Cosine(v, v)
+
+	if math.Abs(got-1.0) > 1e-9 {
+		t.Fatalf("expected cosine similarity 1.0 for identical vectors, got %v", got)
+	}
+}
+
+func TestCosine_OrthogonalVectorsReturnZero(t *testing.T) {
+	a := []float64{1, 0}
+	b := []float64{0, 1}
+
+	if got := Cosine(a, b); math.Abs(got) > 1e-9 {
+		t.Fatalf("expected cosine similarity 0 for orthogonal vectors, got %v", got)
+	}
+}
+
+func TestCosine_ZeroVectorReturnsZero(t *testing.T) {
+	a := []float64{0, 0, 0}
+	b := []float64{1, 2, 3}
+
+	if got := Cosine(a, b); got != 0 {
+		t.Fatalf("expected 0 when one vector has zero magnitude, got %v", got)
+	}
+}
+
+func TestCosine_TruncatesToShorterLength(t *testing.T) {
+	a := []float64{1, 1, 1, 1}
+	b := []float64{1, 1}
+
+	// Should not panic, and should only compare the first 2 dimensions.
+	if got := Cosine(a, b); math.IsNaN(got) {
+		t.Fatalf("expected a finite result for mismatched lengths, got NaN")
+	}
+}
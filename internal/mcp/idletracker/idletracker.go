@@ -0,0 +1,129 @@
+// Package idletracker watches an MCP transport's traffic and shuts the
+// server down after a configurable period of silence. Claude Code and
+// similar clients sometimes exit without sending a proper shutdown,
+// leaving `amanmcp serve` running against a stale stdin pipe; the tracker
+// gives those orphaned processes a way to notice and exit on their own.
+package idletracker
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Tracker resets a timer on every inbound or outbound MCP message and
+// cancels a context when the timer fires with no traffic in between. A
+// zero-value Timeout disables the tracker: Touch and the reader/writer
+// wrappers still work, but the timer is never armed.
+type Tracker struct {
+	timeout time.Duration
+	cancel  context.CancelFunc
+
+	mu           sync.Mutex
+	timer        *time.Timer
+	lastActivity time.Time
+	stopped      bool
+}
+
+// New returns a Tracker that calls cancel once timeout elapses without a
+// Touch. The tracker is armed immediately, as if one Touch had already
+// happened, so a server that never processes a message still times out.
+// If timeout is zero, the tracker is disabled: Stats still reports
+// activity, but cancel is never called.
+func New(timeout time.Duration, cancel context.CancelFunc) *Tracker {
+	t := &Tracker{timeout: timeout, cancel: cancel, lastActivity: time.Now()}
+	if timeout > 0 {
+		t.timer = time.AfterFunc(timeout, t.fire)
+	}
+	return t
+}
+
+// fire runs when the idle timer elapses; it cancels the root context so
+// the caller's deferred cleanup (PID file, embedder, watcher errgroup)
+// runs the same way it would on a normal shutdown signal.
+func (t *Tracker) fire() {
+	t.mu.Lock()
+	stopped := t.stopped
+	t.mu.Unlock()
+	if !stopped {
+		t.cancel()
+	}
+}
+
+// Touch records activity and resets the idle timer. Call this from both
+// the read and write sides of the transport loop, since either direction
+// of traffic counts as the connection being alive.
+func (t *Tracker) Touch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastActivity = time.Now()
+	if t.timer != nil {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// Stop disarms the idle timer. Call this once the server is shutting down
+// for any other reason, so a late-firing timer doesn't race the deferred
+// cleanup by cancelling an already-cancelled context.
+func (t *Tracker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// Stats reports how long the tracker has been idle and when it last saw
+// traffic, for the stats MCP tool to surface.
+type Stats struct {
+	IdleDuration time.Duration
+	LastActivity time.Time
+}
+
+// Stats returns the tracker's current idle duration and last-activity
+// timestamp.
+func (t *Tracker) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Stats{IdleDuration: time.Since(t.lastActivity), LastActivity: t.lastActivity}
+}
+
+// WrapReader returns an io.Reader that calls Touch on every successful
+// Read, for wrapping the inbound half of a stdio/SSE transport loop.
+func (t *Tracker) WrapReader(r io.Reader) io.Reader {
+	return &trackedReader{r: r, t: t}
+}
+
+// WrapWriter returns an io.Writer that calls Touch on every successful
+// Write, for wrapping the outbound half of a stdio/SSE transport loop.
+func (t *Tracker) WrapWriter(w io.Writer) io.Writer {
+	return &trackedWriter{w: w, t: t}
+}
+
+type trackedReader struct {
+	r io.Reader
+	t *Tracker
+}
+
+func (tr *trackedReader) Read(p []byte) (int, error) {
+	n, err := tr.r.Read(p)
+	if n > 0 {
+		tr.t.Touch()
+	}
+	return n, err
+}
+
+type trackedWriter struct {
+	w io.Writer
+	t *Tracker
+}
+
+func (tw *trackedWriter) Write(p []byte) (int, error) {
+	n, err := tw.w.Write(p)
+	if n > 0 {
+		tw.t.Touch()
+	}
+	return n, err
+}
@@ -0,0 +1,104 @@
+package idletracker
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_FiresCancelAfterTimeoutWithNoTouch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	New(20*time.Millisecond, cancel)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("expected context to be cancelled after idle timeout")
+	}
+}
+
+func TestTracker_TouchResetsTimer(t *testing.T) {
+	var cancelled atomic.Bool
+	ctx, cancel := context.WithCancel(context.Background())
+	tr := New(40*time.Millisecond, cancel)
+
+	deadline := time.Now().Add(120 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		tr.Touch()
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-ctx.Done():
+		cancelled.Store(true)
+	default:
+	}
+	assert.False(t, cancelled.Load(), "repeated touches should keep the tracker from firing")
+}
+
+func TestTracker_ZeroTimeoutNeverFires(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	New(0, cancel)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("a zero timeout should disable the tracker")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTracker_StopDisarmsTimer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tr := New(20*time.Millisecond, cancel)
+	tr.Stop()
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("Stop should prevent the timer from firing")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestTracker_StatsReportsIdleDurationAndLastActivity(t *testing.T) {
+	_, cancel := context.WithCancel(context.Background())
+	tr := New(time.Hour, cancel)
+	defer tr.Stop()
+
+	before := time.Now()
+	stats := tr.Stats()
+
+	assert.True(t, !stats.LastActivity.Before(before.Add(-time.Second)))
+	assert.GreaterOrEqual(t, stats.IdleDuration, time.Duration(0))
+}
+
+func TestTracker_WrapReaderTouchesOnRead(t *testing.T) {
+	_, cancel := context.WithCancel(context.Background())
+	tr := New(50*time.Millisecond, cancel)
+	defer tr.Stop()
+
+	wrapped := tr.WrapReader(strings.NewReader("hello"))
+	buf := make([]byte, 5)
+	_, err := wrapped.Read(buf)
+	require.NoError(t, err)
+
+	assert.Less(t, tr.Stats().IdleDuration, 50*time.Millisecond)
+}
+
+func TestTracker_WrapWriterTouchesOnWrite(t *testing.T) {
+	_, cancel := context.WithCancel(context.Background())
+	tr := New(50*time.Millisecond, cancel)
+	defer tr.Stop()
+
+	var buf bytes.Buffer
+	wrapped := tr.WrapWriter(&buf)
+	_, err := wrapped.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello", buf.String())
+	assert.Less(t, tr.Stats().IdleDuration, 50*time.Millisecond)
+}
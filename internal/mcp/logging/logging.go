@@ -0,0 +1,156 @@
+// Package logging mints a per-request meta logger for MCP tool calls. A
+// single search request fans out across BM25 lookup, vector query, RRF
+// fusion, and an optional reranker, each emitting its own slog records
+// today with only ad-hoc fields (session, path, ...) and no way to tie
+// them back together. RequestLogger attaches a request ID, tool name,
+// session name, and query hash to a context once at the top of a
+// request, so every downstream stage can log through the same bound
+// logger and a later `grep request_id=...` reconstructs the whole
+// pipeline for one call.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Meta is the request-scoped identity carried on a context: who asked
+// (tool, session) and which request this is, plus a hash of the query
+// text so logs can be correlated without persisting the query itself.
+type Meta struct {
+	RequestID string
+	Tool      string
+	Session   string
+	QueryHash string
+}
+
+type contextKey struct{}
+
+// NewRequestID returns a random, lowercase hex request ID. It has no
+// structure beyond uniqueness - callers that need causal ordering should
+// rely on the paired begin/end timestamps instead.
+func NewRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// HashQuery returns a short, stable hash of query text suitable for log
+// correlation. It deliberately doesn't reverse to the original query, so
+// request logs can be shared or retained longer than raw query text.
+func HashQuery(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:8])
+}
+
+// StageCounts is how many candidates a request carried at each stage of
+// the hybrid pipeline, attached to a stage's "end" record so a slow or
+// empty result can be diagnosed without re-running the query.
+type StageCounts struct {
+	BM25Hits   int
+	VectorHits int
+	RRFOut     int
+	RerankOut  int
+}
+
+func (c StageCounts) logAttrs() []any {
+	return []any{
+		slog.Int("bm25_hits", c.BM25Hits),
+		slog.Int("vector_hits", c.VectorHits),
+		slog.Int("rrf_out", c.RRFOut),
+		slog.Int("rerank_out", c.RerankOut),
+	}
+}
+
+// RequestLogger binds request metadata onto a context and emits paired
+// begin/end records through a base *slog.Logger. Stages within a request
+// (BM25, vector, rerank, ...) call Begin again with the same ctx to log
+// their own begin/end pair carrying the same request_id/tool/session.
+type RequestLogger struct {
+	base *slog.Logger
+}
+
+// New wraps base for request-scoped logging. A nil base falls back to
+// slog.Default().
+func New(base *slog.Logger) *RequestLogger {
+	if base == nil {
+		base = slog.Default()
+	}
+	return &RequestLogger{base: base}
+}
+
+// WithRequest mints a new Meta for (tool, session, query), stores it on
+// ctx, and returns a logger pre-bound with its fields so every record a
+// handler emits for this request carries request_id/tool/session/query_hash
+// without having to thread them through manually.
+func (l *RequestLogger) WithRequest(ctx context.Context, tool, session, query string) (context.Context, *slog.Logger) {
+	meta := Meta{
+		RequestID: NewRequestID(),
+		Tool:      tool,
+		Session:   session,
+		QueryHash: HashQuery(query),
+	}
+	ctx = context.WithValue(ctx, contextKey{}, meta)
+	return ctx, l.loggerFor(meta)
+}
+
+// FromContext retrieves the Meta a prior WithRequest call stored on ctx.
+func FromContext(ctx context.Context) (Meta, bool) {
+	meta, ok := ctx.Value(contextKey{}).(Meta)
+	return meta, ok
+}
+
+func (l *RequestLogger) loggerFor(meta Meta) *slog.Logger {
+	return l.base.With(
+		slog.String("request_id", meta.RequestID),
+		slog.String("tool", meta.Tool),
+		slog.String("session", meta.Session),
+		slog.String("query_hash", meta.QueryHash),
+	)
+}
+
+// Begin logs "<stage>_begin" and returns an end func that logs
+// "<stage>_end" with elapsed time, the given stage counts, and an error
+// classification. It reads request metadata from ctx if WithRequest has
+// already been called for this request; otherwise it logs with whatever
+// fields the base logger already carries.
+func (l *RequestLogger) Begin(ctx context.Context, stage string) func(err error, counts StageCounts) {
+	logger := l.base
+	if meta, ok := FromContext(ctx); ok {
+		logger = l.loggerFor(meta)
+	}
+
+	start := time.Now()
+	logger.Info(stage + "_begin")
+
+	return func(err error, counts StageCounts) {
+		attrs := append(counts.logAttrs(),
+			slog.Duration("elapsed", time.Since(start)),
+			slog.String("outcome", classify(err)),
+		)
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		logger.Info(stage+"_end", attrs...)
+	}
+}
+
+// classify buckets an error into a small, grep-friendly set of outcomes
+// rather than leaving every caller to format err.Error() differently.
+func classify(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger(buf *bytes.Buffer) *RequestLogger {
+	return New(slog.New(slog.NewJSONHandler(buf, nil)))
+}
+
+func TestNewRequestID_Unique(t *testing.T) {
+	assert.NotEqual(t, NewRequestID(), NewRequestID())
+}
+
+func TestHashQuery_StableAndOneWay(t *testing.T) {
+	a := HashQuery("find the parser")
+	b := HashQuery("find the parser")
+	assert.Equal(t, a, b)
+	assert.NotContains(t, a, "parser")
+}
+
+func TestWithRequest_BindsFieldsToLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf)
+
+	ctx, reqLogger := l.WithRequest(context.Background(), "search", "session-1", "find auth bug")
+	reqLogger.Info("handled")
+
+	meta, ok := FromContext(ctx)
+	require.True(t, ok)
+	assert.NotEmpty(t, meta.RequestID)
+	assert.Equal(t, "search", meta.Tool)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &line))
+	assert.Equal(t, meta.RequestID, line["request_id"])
+	assert.Equal(t, "search", line["tool"])
+	assert.Equal(t, "session-1", line["session"])
+}
+
+func TestFromContext_MissingMeta(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestBegin_LogsBeginAndEndWithCounts(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf)
+
+	ctx, _ := l.WithRequest(context.Background(), "search", "session-1", "find auth bug")
+	end := l.Begin(ctx, "bm25")
+	end(nil, StageCounts{BM25Hits: 12})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var begin, finish map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &begin))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &finish))
+
+	assert.Equal(t, "bm25_begin", begin["msg"])
+	assert.Equal(t, "bm25_end", finish["msg"])
+	assert.Equal(t, float64(12), finish["bm25_hits"])
+	assert.Equal(t, "ok", finish["outcome"])
+}
+
+func TestBegin_ClassifiesTimeoutError(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf)
+
+	end := l.Begin(context.Background(), "vector")
+	end(context.DeadlineExceeded, StageCounts{})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var finish map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &finish))
+	assert.Equal(t, "timeout", finish["outcome"])
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, "ok", classify(nil))
+	assert.Equal(t, "timeout", classify(context.DeadlineExceeded))
+	assert.Equal(t, "canceled", classify(context.Canceled))
+	assert.Equal(t, "error", classify(errors.New("boom")))
+}
@@ -0,0 +1,141 @@
+// Package metrics provides a minimal OpenMetrics exposition format writer
+// and a slog.Handler wrapper for counting log events, used by `amanmcp
+// debug --serve` to expose operational metrics without pulling in a full
+// client library.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Sample is one metric data point: a name, optional labels, and a value.
+// Gauges and counters are both represented this way - the distinction
+// only matters for the `# TYPE` line WriteOpenMetrics emits.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Family groups the samples for one metric name under a single `# TYPE`/
+// `# HELP` pair, the unit OpenMetrics exposition is organized around.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string // "gauge" or "counter"
+	Samples []Sample
+}
+
+// WriteOpenMetrics renders families in OpenMetrics text exposition
+// format (https://openmetrics.io/), terminated by the required `# EOF`
+// line.
+func WriteOpenMetrics(w *strings.Builder, families []Family) {
+	for _, f := range families {
+		if f.Help != "" {
+			fmt.Fprintf(w, "# HELP %s %s\n", f.Name, f.Help)
+		}
+		fmt.Fprintf(w, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			fmt.Fprintf(w, "%s%s %s\n", s.Name, formatLabels(s.Labels), formatValue(s.Value))
+		}
+	}
+	w.WriteString("# EOF\n")
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", k, labels[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+// EventCounters tallies slog records by message, giving debug --serve's
+// /metrics endpoint a counter for search/index operations without those
+// call sites needing to know metrics exist - LogCountingHandler is the
+// only thing that has to be wired in.
+type EventCounters struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewEventCounters returns an empty EventCounters, ready to use.
+func NewEventCounters() *EventCounters {
+	return &EventCounters{counts: make(map[string]int64)}
+}
+
+func (c *EventCounters) inc(message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[message]++
+}
+
+// Snapshot returns a copy of the current message -> count tallies.
+func (c *EventCounters) Snapshot() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int64, len(c.counts))
+	for k, v := range c.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// LogCountingHandler wraps another slog.Handler, incrementing an
+// EventCounters entry for every record's message before delegating to
+// the wrapped handler - so attaching it as slog.SetDefault's handler
+// turns every existing slog.Info/Warn/Error call site into a metrics
+// source with no changes at the call site itself.
+type LogCountingHandler struct {
+	next     slog.Handler
+	counters *EventCounters
+}
+
+// NewLogCountingHandler wraps next, tallying every record it handles
+// into counters.
+func NewLogCountingHandler(next slog.Handler, counters *EventCounters) *LogCountingHandler {
+	return &LogCountingHandler{next: next, counters: counters}
+}
+
+func (h *LogCountingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *LogCountingHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.counters.inc(record.Message)
+	return h.next.Handle(ctx, record)
+}
+
+func (h *LogCountingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogCountingHandler{next: h.next.WithAttrs(attrs), counters: h.counters}
+}
+
+func (h *LogCountingHandler) WithGroup(name string) slog.Handler {
+	return &LogCountingHandler{next: h.next.WithGroup(name), counters: h.counters}
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWriteOpenMetrics_RendersTypeHelpAndEOF(t *testing.T) {
+	families := []Family{
+		{
+			Name: "amanmcp_chunk_count", Type: "gauge", Help: "Indexed chunk count.",
+			Samples: []Sample{{Name: "amanmcp_chunk_count", Value: 42}},
+		},
+		{
+			Name: "amanmcp_language_ratio", Type: "gauge",
+			Samples: []Sample{{Name: "amanmcp_language_ratio", Labels: map[string]string{"lang": "go"}, Value: 0.75}},
+		},
+	}
+
+	var b strings.Builder
+	WriteOpenMetrics(&b, families)
+	out := b.String()
+
+	for _, want := range []string{
+		"# HELP amanmcp_chunk_count Indexed chunk count.\n",
+		"# TYPE amanmcp_chunk_count gauge\n",
+		"amanmcp_chunk_count 42\n",
+		`amanmcp_language_ratio{lang="go"} 0.75` + "\n",
+		"# EOF\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestEventCounters_TalliesByMessage(t *testing.T) {
+	c := NewEventCounters()
+	handler := NewLogCountingHandler(slog.NewTextHandler(io.Discard, nil), c)
+	logger := slog.New(handler)
+
+	logger.Info("search_executed")
+	logger.Info("search_executed")
+	logger.Info("index_chunk_added")
+
+	snap := c.Snapshot()
+	if snap["search_executed"] != 2 {
+		t.Fatalf("got %d, want 2", snap["search_executed"])
+	}
+	if snap["index_chunk_added"] != 1 {
+		t.Fatalf("got %d, want 1", snap["index_chunk_added"])
+	}
+}
+
+func TestLogCountingHandler_WithAttrsPreservesCounting(t *testing.T) {
+	c := NewEventCounters()
+	handler := NewLogCountingHandler(slog.NewTextHandler(io.Discard, nil), c)
+	logger := slog.New(handler).With("component", "test")
+
+	logger.Info("ping")
+
+	if c.Snapshot()["ping"] != 1 {
+		t.Fatalf("got %d, want 1", c.Snapshot()["ping"])
+	}
+}
+
+func TestLogCountingHandler_Enabled(t *testing.T) {
+	c := NewEventCounters()
+	handler := NewLogCountingHandler(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelWarn}), c)
+	if handler.Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatal("expected info level to be disabled under a warn-level handler")
+	}
+	if !handler.Enabled(context.Background(), slog.LevelError) {
+		t.Fatal("expected error level to be enabled")
+	}
+}
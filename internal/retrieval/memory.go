@@ -0,0 +1,110 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// MemoryRetriever is an in-memory Retriever: every indexed Document's
+// vector is L2-normalized and kept in a slice, and Query does a
+// brute-force cosine-similarity scan over all of them - normalized
+// vectors make cosine similarity a plain dot product, the same trick
+// TruncatedEmbedder's own re-normalization step relies on downstream.
+// Fine for the corpus sizes a single retrieval use case needs; a corpus
+// large enough to need an ANN index should use
+// internal/store.VectorStore instead.
+type MemoryRetriever struct {
+	vectorizer Vectorizer
+
+	mu    sync.RWMutex
+	index map[string]int // Document.ID -> position in docs/vecs
+	docs  []Document
+	vecs  [][]float32
+}
+
+// NewMemoryRetriever builds a MemoryRetriever that embeds with
+// vectorizer.
+func NewMemoryRetriever(vectorizer Vectorizer) *MemoryRetriever {
+	return &MemoryRetriever{vectorizer: vectorizer, index: make(map[string]int)}
+}
+
+// Index embeds and stores docs, replacing any existing Document with the
+// same ID in place.
+func (r *MemoryRetriever) Index(ctx context.Context, docs []Document) error {
+	for _, d := range docs {
+		vec, err := r.vectorizer.Embed(ctx, d.Text)
+		if err != nil {
+			return fmt.Errorf("retrieval: embed document %q: %w", d.ID, err)
+		}
+		normalize(vec)
+
+		r.mu.Lock()
+		if pos, ok := r.index[d.ID]; ok {
+			r.docs[pos] = d
+			r.vecs[pos] = vec
+		} else {
+			r.index[d.ID] = len(r.docs)
+			r.docs = append(r.docs, d)
+			r.vecs = append(r.vecs, vec)
+		}
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+// Query embeds q and returns the k Documents most similar to it.
+func (r *MemoryRetriever) Query(ctx context.Context, q string, k int) ([]Hit, error) {
+	qv, err := r.vectorizer.Embed(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("retrieval: embed query: %w", err)
+	}
+	normalize(qv)
+
+	r.mu.RLock()
+	hits := make([]Hit, len(r.docs))
+	for i, d := range r.docs {
+		hits[i] = Hit{Document: d, Score: dot(qv, r.vecs[i])}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if k > 0 && k < len(hits) {
+		hits = hits[:k]
+	}
+	return hits, nil
+}
+
+// normalize L2-normalizes v in place. A near-zero vector is left
+// unchanged rather than risk dividing by (near) zero.
+func normalize(v []float32) {
+	var sumSquares float64
+	for _, x := range v {
+		sumSquares += float64(x) * float64(x)
+	}
+	norm := math.Sqrt(sumSquares)
+	if norm < 1e-9 {
+		return
+	}
+	for i := range v {
+		v[i] = float32(float64(v[i]) / norm)
+	}
+}
+
+// dot is the dot product of a and b, truncated to the shorter of the two
+// if they differ in length rather than panicking - two vectors from
+// different models/dimensions have no single right answer here, and a
+// truncated dot product is at least a defined one.
+func dot(a, b []float32) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
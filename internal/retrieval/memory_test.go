@@ -0,0 +1,77 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryRetriever_QueryReturnsExactTextMatchAsTopHit(t *testing.T) {
+	r := NewMemoryRetriever(stubVectorizer{dims: 16})
+	ctx := context.Background()
+
+	docs := make([]Document, 5)
+	for i := range docs {
+		docs[i] = Document{ID: fmt.Sprintf("doc%d", i), Text: fmt.Sprintf("text%d", i)}
+	}
+	if err := r.Index(ctx, docs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits, err := r.Query(ctx, "text3", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 5 {
+		t.Fatalf("expected 5 hits, got %d", len(hits))
+	}
+	if hits[0].Document.ID != "doc3" {
+		t.Fatalf("expected doc3 as the top hit, got %q (score %v)", hits[0].Document.ID, hits[0].Score)
+	}
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Score > hits[i-1].Score {
+			t.Fatalf("expected hits sorted by descending score, got %+v", hits)
+		}
+	}
+}
+
+func TestMemoryRetriever_QueryRespectsK(t *testing.T) {
+	r := NewMemoryRetriever(stubVectorizer{dims: 8})
+	ctx := context.Background()
+
+	docs := []Document{{ID: "a", Text: "alpha"}, {ID: "b", Text: "beta"}, {ID: "c", Text: "gamma"}}
+	if err := r.Index(ctx, docs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits, err := r.Query(ctx, "alpha", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("expected 2 hits (k=2), got %d", len(hits))
+	}
+}
+
+func TestMemoryRetriever_ReindexingSameIDReplacesInPlace(t *testing.T) {
+	r := NewMemoryRetriever(stubVectorizer{dims: 8})
+	ctx := context.Background()
+
+	if err := r.Index(ctx, []Document{{ID: "a", Text: "first"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Index(ctx, []Document{{ID: "a", Text: "second"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits, err := r.Query(ctx, "second", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("expected re-indexing to replace rather than append, got %d docs", len(hits))
+	}
+	if hits[0].Document.Text != "second" {
+		t.Fatalf("expected the replaced text, got %q", hits[0].Document.Text)
+	}
+}
@@ -0,0 +1,37 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+)
+
+// ProviderVectorizer adapts an embed.EmbeddingProvider (OllamaProvider,
+// OpenAIProvider) into the Vectorizer interface a Retriever needs - the
+// bridge that lets the Ollama embedding client become a Retriever's
+// vectorizer without Retriever/MemoryRetriever/SQLRetriever themselves
+// depending on internal/embed at all.
+type ProviderVectorizer struct {
+	Provider embed.EmbeddingProvider
+	Model    string
+}
+
+// Embed calls Provider.Embed with a single-element batch and narrows the
+// result to float32, matching the narrowing embed.Embedder implementations
+// already do over their own []float64 HTTP responses.
+func (v ProviderVectorizer) Embed(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := v.Provider.Embed(ctx, v.Model, []string{text}, embed.EmbedOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) != 1 {
+		return nil, fmt.Errorf("retrieval: provider returned %d embeddings for 1 input", len(vecs))
+	}
+
+	out := make([]float32, len(vecs[0]))
+	for i, f := range vecs[0] {
+		out[i] = float32(f)
+	}
+	return out, nil
+}
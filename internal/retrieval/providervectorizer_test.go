@@ -0,0 +1,83 @@
+package retrieval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+)
+
+// mockOllamaEmbedServer is this package's own equivalent of
+// internal/embed's mockOllamaServer + generateMockEmbedding (both
+// unexported in that package's test file, so not directly importable
+// here): POST /api/embed returns a deterministic, direction-varying
+// embedding per input text, so querying for a given text's own content
+// reproduces its exact vector and ranks it first.
+func mockOllamaEmbedServer(t *testing.T, dims int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tags" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"models": []map[string]any{{"name": "qwen3-embedding:0.6b"}},
+			})
+			return
+		}
+		if r.URL.Path == "/api/show" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"embedding_dimensions": dims,
+				"capabilities":         []string{"embedding"},
+			})
+			return
+		}
+		if r.URL.Path == "/api/embed" {
+			var req struct {
+				Input []string `json:"input"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+
+			embeddings := make([][]float64, len(req.Input))
+			for i, text := range req.Input {
+				vec := make([]float64, dims)
+				for j, c := range text {
+					idx := (int(c) + j) % dims
+					vec[idx] += float64(j + 1)
+				}
+				embeddings[i] = vec
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+}
+
+func TestProviderVectorizer_AsRetrieverBackendReturnsExactTextMatchAsTopHit(t *testing.T) {
+	server := mockOllamaEmbedServer(t, 16)
+	defer server.Close()
+
+	provider := embed.NewOllamaProvider(embed.OllamaProviderConfig{Host: server.URL})
+	vectorizer := ProviderVectorizer{Provider: provider, Model: "qwen3-embedding:0.6b"}
+
+	r := NewMemoryRetriever(vectorizer)
+	ctx := context.Background()
+
+	docs := make([]Document, 5)
+	for i := range docs {
+		docs[i] = Document{ID: fmt.Sprintf("doc%d", i), Text: fmt.Sprintf("text%d", i)}
+	}
+	if err := r.Index(ctx, docs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits, err := r.Query(ctx, "text3", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits[0].Document.ID != "doc3" {
+		t.Fatalf("expected doc3 as the top hit via the Ollama-backed vectorizer, got %q", hits[0].Document.ID)
+	}
+}
@@ -0,0 +1,49 @@
+// Package retrieval extracts a small query service layered on top of an
+// embedding client: embed documents once at index time, then rank them
+// against a query's own embedding at query time. internal/search and
+// internal/store already do this at production scale (BM25 + HNSW +
+// RRF fusion, snapshot/WAL persistence, query planning); this package is
+// the simpler, single-signal counterpart for callers that just want
+// "embed some documents, ask for the top-k nearest to a query" without
+// pulling in that whole stack - an evaluation harness, a one-off script,
+// or a small side-index a project keeps outside its main search engine.
+package retrieval
+
+import "context"
+
+// Document is one unit a Retriever indexes and later returns as a Hit.
+type Document struct {
+	ID   string
+	Text string
+}
+
+// Hit is one ranked result from Retriever.Query, paired with its
+// similarity Score (cosine similarity for every implementation in this
+// package, in [-1, 1] - higher is more similar).
+type Hit struct {
+	Document Document
+	Score    float64
+}
+
+// Vectorizer turns text into an embedding vector. embed.Embedder already
+// satisfies this narrower interface structurally (same Embed signature),
+// so a Retriever can be built directly on top of an embed.Embedder
+// without Vectorizer or any Retriever referring to internal/embed (only
+// ProviderVectorizer imports it) - the same
+// decoupling the public embed package's own Indexer interface uses to
+// avoid requiring a concrete internal/index.Coordinator.
+type Vectorizer interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// Retriever indexes Documents and answers nearest-neighbor queries over
+// them by embedding similarity against a Vectorizer.
+type Retriever interface {
+	// Index embeds and stores docs, making them eligible for Query.
+	// Re-indexing a Document with an ID already stored replaces it.
+	Index(ctx context.Context, docs []Document) error
+	// Query embeds q and returns the k Documents with the highest
+	// cosine similarity to it, ranked highest first. k <= 0 returns
+	// every indexed Document, ranked.
+	Query(ctx context.Context, q string, k int) ([]Hit, error)
+}
@@ -0,0 +1,32 @@
+package retrieval
+
+import (
+	"context"
+	"strings"
+)
+
+// stubVectorizer is a deterministic, hash-free Vectorizer shared by this
+// package's tests: two calls with the same text always return the same
+// vector, and different texts return different vectors, without needing
+// a real embedding backend - the in-process analogue of ollama_test.go's
+// generateMockEmbedding for this package's own test suite.
+type stubVectorizer struct {
+	dims int
+}
+
+func (s stubVectorizer) Embed(ctx context.Context, text string) ([]float32, error) {
+	vec := make([]float32, s.dims)
+	if strings.TrimSpace(text) == "" {
+		return vec, nil
+	}
+	// Scatter each character's weight across a dimension its own rune
+	// value and position pick, so texts sharing no characters end up
+	// pointing in different directions (not just different magnitudes)
+	// - plain magnitude scaling would survive Query's L2-normalization
+	// and make every distinct text cosine-identical after normalizing.
+	for j, c := range text {
+		idx := (int(c) + j) % s.dims
+		vec[idx] += float32(j + 1)
+	}
+	return vec, nil
+}
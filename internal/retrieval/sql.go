@@ -0,0 +1,154 @@
+package retrieval
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// sqlDialect holds the handful of statements that differ between
+// SQLRetriever's two backends, mirroring how internal/store's
+// SQLGenerator splits dialect-specific SQL text out from the store that
+// drives it.
+type sqlDialect struct {
+	name             string
+	vectorColumnType string
+	upsertSQL        func(table string) string
+}
+
+var sqliteDialect = sqlDialect{
+	name:             "sqlite",
+	vectorColumnType: "BLOB",
+	upsertSQL: func(table string) string {
+		return fmt.Sprintf(`INSERT OR REPLACE INTO %s (id, text, vector) VALUES (?, ?, ?)`, table)
+	},
+}
+
+// postgresDialect targets plain Postgres BYTEA storage with a Go-side
+// cosine scan, not the pgvector extension's native vector column and
+// distance operators - this tree has no way to exercise a live pgvector
+// install, so SQLRetriever stays a reference adapter any Postgres (with
+// or without pgvector) can run, the same way internal/store's
+// PostgresStore doesn't assume any non-core extension either.
+var postgresDialect = sqlDialect{
+	name:             "postgres",
+	vectorColumnType: "BYTEA",
+	upsertSQL: func(table string) string {
+		return fmt.Sprintf(`INSERT INTO %s (id, text, vector) VALUES ($1, $2, $3)
+			ON CONFLICT (id) DO UPDATE SET text = EXCLUDED.text, vector = EXCLUDED.vector`, table)
+	},
+}
+
+// SQLRetriever is a Retriever backed by a SQL table of (id, text, vector)
+// rows, queried with a brute-force cosine scan done in Go rather than a
+// native vector operator. It's a reference adapter for SQLite and
+// Postgres alike - callers with a large enough corpus to need a real ANN
+// index should reach for internal/store.VectorStore (HNSW) instead.
+type SQLRetriever struct {
+	db         *sql.DB
+	vectorizer Vectorizer
+	table      string
+	dialect    sqlDialect
+}
+
+// NewSQLiteRetriever opens (creating if necessary) table against db,
+// which the caller must already have opened with sql.Open("sqlite3",
+// ...) - matching store.NewSQLiteStore's "caller owns the DSN" split
+// between store and the underlying *sql.DB.
+func NewSQLiteRetriever(db *sql.DB, table string, vectorizer Vectorizer) (*SQLRetriever, error) {
+	return newSQLRetriever(db, table, vectorizer, sqliteDialect)
+}
+
+// NewPostgresRetriever opens (creating if necessary) table against db,
+// which the caller must already have opened with sql.Open("postgres",
+// ...).
+func NewPostgresRetriever(db *sql.DB, table string, vectorizer Vectorizer) (*SQLRetriever, error) {
+	return newSQLRetriever(db, table, vectorizer, postgresDialect)
+}
+
+func newSQLRetriever(db *sql.DB, table string, vectorizer Vectorizer, dialect sqlDialect) (*SQLRetriever, error) {
+	ddl := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id TEXT PRIMARY KEY,
+		text TEXT NOT NULL,
+		vector %s NOT NULL
+	)`, table, dialect.vectorColumnType)
+	if _, err := db.Exec(ddl); err != nil {
+		return nil, fmt.Errorf("retrieval: create table %s: %w", table, err)
+	}
+	return &SQLRetriever{db: db, vectorizer: vectorizer, table: table, dialect: dialect}, nil
+}
+
+// Index embeds and upserts docs, one statement per Document.
+func (r *SQLRetriever) Index(ctx context.Context, docs []Document) error {
+	stmt := r.dialect.upsertSQL(r.table)
+	for _, d := range docs {
+		vec, err := r.vectorizer.Embed(ctx, d.Text)
+		if err != nil {
+			return fmt.Errorf("retrieval: embed document %q: %w", d.ID, err)
+		}
+		normalize(vec)
+
+		if _, err := r.db.ExecContext(ctx, stmt, d.ID, d.Text, encodeVector(vec)); err != nil {
+			return fmt.Errorf("retrieval: upsert document %q: %w", d.ID, err)
+		}
+	}
+	return nil
+}
+
+// Query embeds q, loads every row, and scores each with a cosine scan -
+// brute force, since this is a reference adapter rather than a
+// production-scale ANN index.
+func (r *SQLRetriever) Query(ctx context.Context, q string, k int) ([]Hit, error) {
+	qv, err := r.vectorizer.Embed(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("retrieval: embed query: %w", err)
+	}
+	normalize(qv)
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT id, text, vector FROM %s`, r.table))
+	if err != nil {
+		return nil, fmt.Errorf("retrieval: query %s: %w", r.table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var hits []Hit
+	for rows.Next() {
+		var d Document
+		var raw []byte
+		if err := rows.Scan(&d.ID, &d.Text, &raw); err != nil {
+			return nil, fmt.Errorf("retrieval: scan row: %w", err)
+		}
+		hits = append(hits, Hit{Document: d, Score: dot(qv, decodeVector(raw))})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("retrieval: iterate rows: %w", err)
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if k > 0 && k < len(hits) {
+		hits = hits[:k]
+	}
+	return hits, nil
+}
+
+// encodeVector/decodeVector store a []float32 as raw little-endian bytes,
+// the same encoding BoltCache uses for the same reason: small vectors, no
+// need for anything fancier than math.Float32bits/Float32frombits.
+func encodeVector(vec []float32) []byte {
+	buf := make([]byte, len(vec)*4)
+	for i, f := range vec {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func decodeVector(raw []byte) []float32 {
+	vec := make([]float32, len(raw)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+	}
+	return vec
+}
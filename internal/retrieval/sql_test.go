@@ -0,0 +1,110 @@
+package retrieval
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+func newSQLiteTestRetriever(t *testing.T) *SQLRetriever {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "retrieval.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("open sqlite db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	r, err := NewSQLiteRetriever(db, "vectors", stubVectorizer{dims: 16})
+	if err != nil {
+		t.Fatalf("NewSQLiteRetriever: %v", err)
+	}
+	return r
+}
+
+func TestSQLRetriever_QueryReturnsExactTextMatchAsTopHit(t *testing.T) {
+	r := newSQLiteTestRetriever(t)
+	ctx := context.Background()
+
+	docs := make([]Document, 5)
+	for i := range docs {
+		docs[i] = Document{ID: fmt.Sprintf("doc%d", i), Text: fmt.Sprintf("text%d", i)}
+	}
+	if err := r.Index(ctx, docs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits, err := r.Query(ctx, "text3", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 5 {
+		t.Fatalf("expected 5 hits, got %d", len(hits))
+	}
+	if hits[0].Document.ID != "doc3" {
+		t.Fatalf("expected doc3 as the top hit, got %q (score %v)", hits[0].Document.ID, hits[0].Score)
+	}
+}
+
+func TestSQLRetriever_ReindexingSameIDUpserts(t *testing.T) {
+	r := newSQLiteTestRetriever(t)
+	ctx := context.Background()
+
+	if err := r.Index(ctx, []Document{{ID: "a", Text: "first"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Index(ctx, []Document{{ID: "a", Text: "second"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits, err := r.Query(ctx, "second", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("expected the upsert to replace rather than duplicate the row, got %d rows", len(hits))
+	}
+	if hits[0].Document.Text != "second" {
+		t.Fatalf("expected the replaced text, got %q", hits[0].Document.Text)
+	}
+}
+
+func TestSQLRetriever_PersistsAcrossReopen(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "retrieval.db")
+	ctx := context.Background()
+
+	db1, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("open sqlite db: %v", err)
+	}
+	r1, err := NewSQLiteRetriever(db1, "vectors", stubVectorizer{dims: 16})
+	if err != nil {
+		t.Fatalf("NewSQLiteRetriever: %v", err)
+	}
+	if err := r1.Index(ctx, []Document{{ID: "a", Text: "persisted"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = db1.Close()
+
+	db2, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("reopen sqlite db: %v", err)
+	}
+	defer func() { _ = db2.Close() }()
+	r2, err := NewSQLiteRetriever(db2, "vectors", stubVectorizer{dims: 16})
+	if err != nil {
+		t.Fatalf("NewSQLiteRetriever (reopen): %v", err)
+	}
+
+	hits, err := r2.Query(ctx, "persisted", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Document.ID != "a" {
+		t.Fatalf("expected the indexed document to survive reopening the db, got %+v", hits)
+	}
+}
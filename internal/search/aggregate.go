@@ -0,0 +1,163 @@
+package search
+
+import (
+	"sort"
+	"sync"
+)
+
+// FieldTermsIndex is a per-field, sorted terms-to-postings dictionary built
+// at index time — a trie/FST-like structure that lets Aggregate answer
+// "which terms, and how many docs each" by walking postings lists rather
+// than reading chunk content back out of the doc store. This is the
+// index-time counterpart to ComputeFacets, which instead counts over an
+// already-retrieved, in-memory []*SearchResult.
+type FieldTermsIndex struct {
+	mu       sync.RWMutex
+	terms    []string            // kept sorted, for FST-like rank/prefix queries
+	postings map[string][]string // term -> doc IDs
+}
+
+// NewFieldTermsIndex returns an empty terms dictionary for one field.
+func NewFieldTermsIndex() *FieldTermsIndex {
+	return &FieldTermsIndex{postings: make(map[string][]string)}
+}
+
+// Add records that docID has term in this field, inserting term into the
+// sorted dictionary the first time it's seen.
+func (idx *FieldTermsIndex) Add(term, docID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.postings[term]; !ok {
+		i := sort.SearchStrings(idx.terms, term)
+		idx.terms = append(idx.terms, "")
+		copy(idx.terms[i+1:], idx.terms[i:])
+		idx.terms[i] = term
+	}
+	idx.postings[term] = append(idx.postings[term], docID)
+}
+
+// Terms returns the dictionary's terms in sorted order.
+func (idx *FieldTermsIndex) Terms() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.terms
+}
+
+// DocIDs returns the postings list for term.
+func (idx *FieldTermsIndex) DocIDs(term string) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.postings[term]
+}
+
+// AggregateOptions requests one or more term-count aggregations ("facets")
+// over a query's full matching document set, computed against a field's
+// FieldTermsIndex rather than the top-K results returned to the caller.
+type AggregateOptions struct {
+	// Fields names the FieldTermsIndex dictionaries to aggregate, e.g.
+	// "language", "content_type", "file_path", "package".
+	Fields []string
+	// TopKPerField caps how many terms are returned per field, highest
+	// count first. Zero means unbounded.
+	TopKPerField int
+	// Filter is a pre-applied query-language expression; callers are
+	// expected to have already narrowed matchedDocIDs to docs passing it
+	// before calling Aggregate, the same convention ComputeFacets uses.
+	Filter string
+}
+
+// AggregateTerm is one field value's count within an aggregation.
+type AggregateTerm struct {
+	Term  string
+	Count int
+}
+
+// AggregateResult holds the computed term counts, keyed by the requested
+// field name.
+type AggregateResult struct {
+	Fields map[string][]AggregateTerm
+}
+
+// aggregateResultsEntry is one (term, count) accumulator slot. Aggregate
+// draws a reusable slice of these from aggregateResultsPool per field so a
+// hot aggregation query doesn't allocate a fresh counts slice every call.
+type aggregateResultsEntry struct {
+	term  string
+	count int
+}
+
+var aggregateResultsPool = sync.Pool{
+	New: func() any { return make([]aggregateResultsEntry, 0, 64) },
+}
+
+// Aggregate computes term counts for each of opts.Fields against
+// fieldIndexes, restricted to matchedDocIDs — the doc IDs a query's
+// retrieval pass returned. When matchedDocIDs is nil (the caller ran no
+// query), Aggregate instead counts every doc in each field's postings
+// lists, answering the aggregation purely from the terms index without
+// touching the doc store at all. An Engine.Aggregate method would build
+// matchedDocIDs from its own retrieval pass and delegate here.
+func Aggregate(fieldIndexes map[string]*FieldTermsIndex, matchedDocIDs []string, opts AggregateOptions) *AggregateResult {
+	result := &AggregateResult{Fields: make(map[string][]AggregateTerm, len(opts.Fields))}
+
+	var matched map[string]struct{}
+	if matchedDocIDs != nil {
+		matched = make(map[string]struct{}, len(matchedDocIDs))
+		for _, id := range matchedDocIDs {
+			matched[id] = struct{}{}
+		}
+	}
+
+	for _, field := range opts.Fields {
+		idx := fieldIndexes[field]
+		if idx == nil {
+			continue
+		}
+		result.Fields[field] = aggregateField(idx, matched, opts.TopKPerField)
+	}
+	return result
+}
+
+// aggregateField counts idx's postings against matched (or, when matched
+// is nil, counts every posting), returning terms sorted by count
+// descending then term ascending, truncated to topK when topK > 0.
+func aggregateField(idx *FieldTermsIndex, matched map[string]struct{}, topK int) []AggregateTerm {
+	entries := aggregateResultsPool.Get().([]aggregateResultsEntry)[:0]
+	defer func() { aggregateResultsPool.Put(entries[:0]) }()
+
+	for _, term := range idx.Terms() {
+		docIDs := idx.DocIDs(term)
+		var count int
+		if matched == nil {
+			count = len(docIDs)
+		} else {
+			for _, docID := range docIDs {
+				if _, ok := matched[docID]; ok {
+					count++
+				}
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		entries = append(entries, aggregateResultsEntry{term: term, count: count})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].term < entries[j].term
+	})
+
+	if topK > 0 && len(entries) > topK {
+		entries = entries[:topK]
+	}
+
+	out := make([]AggregateTerm, len(entries))
+	for i, e := range entries {
+		out[i] = AggregateTerm{Term: e.term, Count: e.count}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLanguageIndex() *FieldTermsIndex {
+	idx := NewFieldTermsIndex()
+	idx.Add("go", "chunk1")
+	idx.Add("go", "chunk2")
+	idx.Add("go", "chunk3")
+	idx.Add("ts", "chunk4")
+	return idx
+}
+
+func TestAggregate_CountsRestrictedToMatchedDocIDs(t *testing.T) {
+	fields := map[string]*FieldTermsIndex{"language": testLanguageIndex()}
+
+	result := Aggregate(fields, []string{"chunk1", "chunk2", "chunk4"}, AggregateOptions{Fields: []string{"language"}})
+
+	terms := result.Fields["language"]
+	require.Len(t, terms, 2)
+	assert.Equal(t, AggregateTerm{Term: "go", Count: 2}, terms[0])
+	assert.Equal(t, AggregateTerm{Term: "ts", Count: 1}, terms[1])
+}
+
+func TestAggregate_NilMatchedDocIDsCountsWholeCorpus(t *testing.T) {
+	fields := map[string]*FieldTermsIndex{"language": testLanguageIndex()}
+
+	result := Aggregate(fields, nil, AggregateOptions{Fields: []string{"language"}})
+
+	terms := result.Fields["language"]
+	require.Len(t, terms, 2)
+	assert.Equal(t, AggregateTerm{Term: "go", Count: 3}, terms[0])
+}
+
+func TestAggregate_TopKPerFieldTruncates(t *testing.T) {
+	fields := map[string]*FieldTermsIndex{"language": testLanguageIndex()}
+
+	result := Aggregate(fields, nil, AggregateOptions{Fields: []string{"language"}, TopKPerField: 1})
+
+	assert.Len(t, result.Fields["language"], 1)
+}
+
+func TestAggregate_UnknownFieldIsOmitted(t *testing.T) {
+	fields := map[string]*FieldTermsIndex{"language": testLanguageIndex()}
+
+	result := Aggregate(fields, nil, AggregateOptions{Fields: []string{"package"}})
+
+	_, ok := result.Fields["package"]
+	assert.False(t, ok)
+}
+
+func TestAggregate_ZeroCountTermsAreExcluded(t *testing.T) {
+	fields := map[string]*FieldTermsIndex{"language": testLanguageIndex()}
+
+	result := Aggregate(fields, []string{"chunk4"}, AggregateOptions{Fields: []string{"language"}})
+
+	terms := result.Fields["language"]
+	require.Len(t, terms, 1)
+	assert.Equal(t, "ts", terms[0].Term)
+}
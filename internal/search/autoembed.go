@@ -0,0 +1,115 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// Metadata keys EmbedderRegistry stamps into Chunk.Metadata so a later
+// Search call can recover which named embedder (and what dimensionality)
+// produced a chunk's vector, rather than re-deriving the routing rule from
+// ContentType/Language, which might change between index and query time.
+const (
+	MetadataKeyEmbedderName       = "embedder_name"
+	MetadataKeyEmbedderDimensions = "embedder_dimensions"
+)
+
+// EmbedderRoute maps a Language or ContentType to the embedder name that
+// should handle it, e.g. a code-specialized embedder for ContentTypeCode
+// and a general text model for ContentTypeMarkdown.
+type EmbedderRoute struct {
+	Language     string
+	ContentType  store.ContentType
+	EmbedderName string
+}
+
+// EmbedderRegistry is a named registry of embedders plus the routing
+// rules that pick one per chunk (Meilisearch-style autoembedding). It is
+// standalone: Engine does not hold or consult one.
+type EmbedderRegistry struct {
+	embedders   map[string]embed.Embedder
+	routes      []EmbedderRoute
+	defaultName string
+}
+
+// NewEmbedderRegistry returns a registry over embedders, falling back to
+// defaultName when no route matches a chunk.
+func NewEmbedderRegistry(embedders map[string]embed.Embedder, defaultName string) *EmbedderRegistry {
+	return &EmbedderRegistry{embedders: embedders, defaultName: defaultName}
+}
+
+// AddRoute registers a routing rule; rules are tried in registration order
+// before falling back to defaultName. A zero Language/ContentType matches
+// any value for that field.
+func (r *EmbedderRegistry) AddRoute(route EmbedderRoute) {
+	r.routes = append(r.routes, route)
+}
+
+// SelectForChunk picks the embedder for c: the embedder already stamped
+// into c.Metadata, if present (so re-indexing reuses whatever produced
+// the existing vector), else the first matching route, else the registry
+// default.
+func (r *EmbedderRegistry) SelectForChunk(c *store.Chunk) (string, embed.Embedder, bool) {
+	if name, ok := embedderNameFromMetadata(c); ok {
+		if e, ok := r.embedders[name]; ok {
+			return name, e, true
+		}
+	}
+
+	for _, route := range r.routes {
+		if route.Language != "" && !strings.EqualFold(route.Language, c.Language) {
+			continue
+		}
+		if route.ContentType != "" && route.ContentType != c.ContentType {
+			continue
+		}
+		if e, ok := r.embedders[route.EmbedderName]; ok {
+			return route.EmbedderName, e, true
+		}
+	}
+
+	if e, ok := r.embedders[r.defaultName]; ok {
+		return r.defaultName, e, true
+	}
+	return "", nil, false
+}
+
+// SelectByDimensions returns a registered embedder whose Dimensions()
+// matches dimensions; when several match, which one is returned is not
+// defined (the registry is a map). This extends the dimension-validation
+// fallback (see TestEngine_SingleSearch_DimensionValidationFallback):
+// instead of dropping to BM25-only when a candidate chunk's stored vector
+// width doesn't match the query embedder's, look up whichever registered
+// embedder actually produced vectors of that width and re-embed the query
+// with it.
+func (r *EmbedderRegistry) SelectByDimensions(dimensions int) (string, embed.Embedder, bool) {
+	for name, e := range r.embedders {
+		if e.Dimensions() == dimensions {
+			return name, e, true
+		}
+	}
+	return "", nil, false
+}
+
+// embedderNameFromMetadata reads MetadataKeyEmbedderName back out of a
+// chunk's metadata map.
+func embedderNameFromMetadata(c *store.Chunk) (string, bool) {
+	if c == nil || c.Metadata == nil {
+		return "", false
+	}
+	name, ok := c.Metadata[MetadataKeyEmbedderName].(string)
+	return name, ok && name != ""
+}
+
+// StampEmbedderMetadata records which embedder (and its dimensionality)
+// produced c's vector. Engine.Index would call this right before
+// persisting a freshly embedded chunk.
+func StampEmbedderMetadata(c *store.Chunk, name string, dimensions int) {
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]any)
+	}
+	c.Metadata[MetadataKeyEmbedderName] = name
+	c.Metadata[MetadataKeyEmbedderDimensions] = dimensions
+}
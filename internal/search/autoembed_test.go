@@ -0,0 +1,97 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+type fakeEmbedder struct {
+	name string
+	dims int
+}
+
+func (f *fakeEmbedder) Embed(_ context.Context, _ string) ([]float32, error) { return nil, nil }
+func (f *fakeEmbedder) EmbedBatch(_ context.Context, texts []string) ([][]float32, error) {
+	return make([][]float32, len(texts)), nil
+}
+func (f *fakeEmbedder) Dimensions() int                  { return f.dims }
+func (f *fakeEmbedder) ModelName() string                { return f.name }
+func (f *fakeEmbedder) Available(_ context.Context) bool { return true }
+func (f *fakeEmbedder) Close() error                     { return nil }
+func (f *fakeEmbedder) SetBatchIndex(int)                {}
+func (f *fakeEmbedder) SetFinalBatch(bool)               {}
+
+var _ embed.Embedder = (*fakeEmbedder)(nil)
+
+func testEmbedderRegistry() *EmbedderRegistry {
+	embedders := map[string]embed.Embedder{
+		"code": &fakeEmbedder{name: "code", dims: 1024},
+		"text": &fakeEmbedder{name: "text", dims: 768},
+	}
+	r := NewEmbedderRegistry(embedders, "text")
+	r.AddRoute(EmbedderRoute{ContentType: store.ContentTypeCode, EmbedderName: "code"})
+	return r
+}
+
+func TestEmbedderRegistry_RoutesByContentType(t *testing.T) {
+	r := testEmbedderRegistry()
+
+	name, e, ok := r.SelectForChunk(&store.Chunk{ContentType: store.ContentTypeCode})
+
+	require.True(t, ok)
+	assert.Equal(t, "code", name)
+	assert.Equal(t, 1024, e.Dimensions())
+}
+
+func TestEmbedderRegistry_FallsBackToDefault(t *testing.T) {
+	r := testEmbedderRegistry()
+
+	name, e, ok := r.SelectForChunk(&store.Chunk{ContentType: store.ContentTypeMarkdown})
+
+	require.True(t, ok)
+	assert.Equal(t, "text", name)
+	assert.Equal(t, 768, e.Dimensions())
+}
+
+func TestEmbedderRegistry_PreviouslyStampedEmbedderWins(t *testing.T) {
+	r := testEmbedderRegistry()
+	c := &store.Chunk{ContentType: store.ContentTypeCode}
+	StampEmbedderMetadata(c, "text", 768)
+
+	name, _, ok := r.SelectForChunk(c)
+
+	require.True(t, ok)
+	assert.Equal(t, "text", name, "a chunk already embedded with a specific model should keep using it")
+}
+
+func TestEmbedderRegistry_SelectByDimensionsFindsMatchingEmbedder(t *testing.T) {
+	r := testEmbedderRegistry()
+
+	name, _, ok := r.SelectByDimensions(1024)
+
+	require.True(t, ok)
+	assert.Equal(t, "code", name)
+}
+
+func TestEmbedderRegistry_SelectByDimensionsNoMatch(t *testing.T) {
+	r := testEmbedderRegistry()
+
+	_, _, ok := r.SelectByDimensions(4096)
+
+	assert.False(t, ok)
+}
+
+func TestStampEmbedderMetadata_InitializesNilMetadataMap(t *testing.T) {
+	c := &store.Chunk{}
+
+	StampEmbedderMetadata(c, "code", 1024)
+
+	assert.Equal(t, "code", c.Metadata[MetadataKeyEmbedderName])
+	assert.Equal(t, 1024, c.Metadata[MetadataKeyEmbedderDimensions])
+}
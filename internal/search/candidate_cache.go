@@ -0,0 +1,34 @@
+package search
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// CandidateScore is one candidate document's BM25/vector/fused scores as
+// computed during hybrid fusion - the unit a ResultCache entry holds per
+// query when keyed by CandidateCacheKey, so a repeated query replays
+// without re-running BM25 and vector search.
+type CandidateScore struct {
+	ChunkID        string
+	BM25Score      float64
+	VectorDistance float64
+	FusedScore     float64
+}
+
+// CandidateCacheKey canonicalizes the inputs a hybrid search fuses over
+// (query text, filter, weights, and embedder dimension) into a ResultCache
+// key, so equivalent queries always hit the same cache slot. The
+// "candidates\x00" prefix keeps these entries from colliding with
+// unrelated ResultCache entries keyed by ResultCacheKey, QueryResultKey,
+// or QueryPlanKey in the same cache. This used to key a standalone
+// CandidateCache backed by mmap'd, append-only segment files on disk
+// (modeled on internal/store/vectorstore_mmap.go's pattern); that
+// persistence and crash-recovery machinery is gone now that candidates
+// live in ResultCache, which is purely in-memory - a cold cache just
+// re-fuses on the next query, the same as any other ResultCache miss.
+func CandidateCacheKey(query, filter string, weights Weights, embedderDim int) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s\x00%g\x00%g\x00%d", query, filter, weights.BM25, weights.Semantic, embedderDim)
+	return fmt.Sprintf("candidates\x00%016x", h.Sum64())
+}
@@ -0,0 +1,32 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidateCacheKey_DistinctWeightsProduceDistinctKeys(t *testing.T) {
+	a := CandidateCacheKey("login", "language:go", Weights{BM25: 0.8, Semantic: 0.2}, 384)
+	b := CandidateCacheKey("login", "language:go", Weights{BM25: 0.2, Semantic: 0.8}, 384)
+	assert.NotEqual(t, a, b)
+}
+
+func TestCandidateCacheKey_StableForSameInputs(t *testing.T) {
+	a := CandidateCacheKey("login", "language:go", Weights{BM25: 0.5, Semantic: 0.5}, 384)
+	b := CandidateCacheKey("login", "language:go", Weights{BM25: 0.5, Semantic: 0.5}, 384)
+	assert.Equal(t, a, b)
+}
+
+func TestResultCache_KeyedByCandidateCacheKeyRoundTrips(t *testing.T) {
+	c := NewResultCache(8, 0)
+	key := CandidateCacheKey("login", "language:go", Weights{BM25: 0.5, Semantic: 0.5}, 384)
+	want := []CandidateScore{{ChunkID: "chunk-1", BM25Score: 4.2, VectorDistance: 0.1, FusedScore: 3.9}}
+
+	c.Put(key, want)
+	got, ok := c.Get(key)
+
+	require.True(t, ok)
+	assert.Equal(t, want, got)
+}
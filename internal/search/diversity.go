@@ -0,0 +1,107 @@
+package search
+
+import "math"
+
+// FilterByScoreThreshold drops every result whose Score falls below
+// threshold. It's meant to run after reranking, so the threshold applies
+// to the final post-rerank score rather than the raw fused score. Nothing
+// in this checkout calls it yet - there's no search.Engine/rerankResults
+// here (engine.go doesn't exist in this tree) to run it after. A
+// non-positive threshold is a no-op.
+func FilterByScoreThreshold(results []*SearchResult, threshold float64) []*SearchResult {
+	if threshold <= 0 {
+		return results
+	}
+	kept := make([]*SearchResult, 0, len(results))
+	for _, r := range results {
+		if r.Score >= threshold {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// ApplyMMR re-ranks the first poolSize results using Maximal Marginal
+// Relevance, trading off relevance to the query against similarity to
+// results already chosen, so near-duplicate chunks (common when several
+// files share boilerplate) don't crowd out distinct ones. embeddings maps a
+// result's chunk ID to the vector used to compute its relevance/similarity;
+// results missing an embedding are left in their original relative order
+// after the MMR-selected ones. lambda <= 0 disables MMR and returns results
+// truncated to limit unchanged.
+func ApplyMMR(results []*SearchResult, queryEmbedding []float32, embeddings map[string][]float32, lambda float64, poolSize, limit int) []*SearchResult {
+	if lambda <= 0 || len(results) == 0 {
+		return truncate(results, limit)
+	}
+	if poolSize <= 0 || poolSize > len(results) {
+		poolSize = len(results)
+	}
+	if limit <= 0 || limit > poolSize {
+		limit = poolSize
+	}
+
+	pool := results[:poolSize]
+	remainder := results[poolSize:]
+
+	candidates := make([]*SearchResult, 0, len(pool))
+	skipped := make([]*SearchResult, 0)
+	relevance := make(map[*SearchResult]float64, len(pool))
+	for _, r := range pool {
+		vec, ok := embeddings[r.Chunk.ID]
+		if !ok {
+			skipped = append(skipped, r)
+			continue
+		}
+		candidates = append(candidates, r)
+		relevance[r] = cosineSimilarity(queryEmbedding, vec)
+	}
+
+	selected := make([]*SearchResult, 0, limit)
+	for len(selected) < limit && len(candidates) > 0 {
+		bestIdx, bestScore := -1, math.Inf(-1)
+		for i, cand := range candidates {
+			maxSim := 0.0
+			for _, chosen := range selected {
+				sim := cosineSimilarity(embeddings[cand.Chunk.ID], embeddings[chosen.Chunk.ID])
+				if sim > maxSim {
+					maxSim = sim
+				}
+			}
+			mmrScore := lambda*relevance[cand] - (1-lambda)*maxSim
+			if mmrScore > bestScore {
+				bestIdx, bestScore = i, mmrScore
+			}
+		}
+		selected = append(selected, candidates[bestIdx])
+		candidates = append(candidates[:bestIdx], candidates[bestIdx+1:]...)
+	}
+
+	selected = append(selected, skipped...)
+	selected = append(selected, remainder...)
+	return truncate(selected, limit)
+}
+
+func truncate(results []*SearchResult, limit int) []*SearchResult {
+	if limit <= 0 || limit >= len(results) {
+		return results
+	}
+	return results[:limit]
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 if either
+// is empty or zero-length (the case for a missing/unset embedding).
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(b) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
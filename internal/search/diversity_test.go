@@ -0,0 +1,96 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterByScoreThreshold_DropsBelowThreshold(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a"}, Score: 0.9},
+		{Chunk: &store.Chunk{ID: "b"}, Score: 0.4},
+		{Chunk: &store.Chunk{ID: "c"}, Score: 0.6},
+	}
+
+	got := FilterByScoreThreshold(results, 0.5)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].Chunk.ID)
+	assert.Equal(t, "c", got[1].Chunk.ID)
+}
+
+func TestFilterByScoreThreshold_ZeroIsNoOp(t *testing.T) {
+	results := []*SearchResult{{Chunk: &store.Chunk{ID: "a"}, Score: 0.01}}
+
+	got := FilterByScoreThreshold(results, 0)
+
+	assert.Equal(t, results, got)
+}
+
+func TestApplyMMR_DisabledLambdaJustTruncates(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a"}, Score: 0.9},
+		{Chunk: &store.Chunk{ID: "b"}, Score: 0.8},
+	}
+
+	got := ApplyMMR(results, nil, nil, 0, 0, 1)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "a", got[0].Chunk.ID)
+}
+
+func TestApplyMMR_PrefersDiverseOverNearDuplicate(t *testing.T) {
+	query := []float32{1, 0}
+	// "b" is near-identical to "a" (the top relevance match); "c" is more
+	// different from both the query and "a", but still a real match.
+	embeddings := map[string][]float32{
+		"a": {1, 0},
+		"b": {0.999, 0.045},
+		"c": {0.6, 0.8},
+	}
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a"}, Score: 0.95},
+		{Chunk: &store.Chunk{ID: "b"}, Score: 0.90},
+		{Chunk: &store.Chunk{ID: "c"}, Score: 0.50},
+	}
+
+	// A low lambda weights diversity heavily, so after "a" is picked for
+	// relevance, the near-duplicate "b" should lose out to "c".
+	got := ApplyMMR(results, query, embeddings, 0.3, 3, 2)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].Chunk.ID, "most relevant result is always picked first")
+	assert.Equal(t, "c", got[1].Chunk.ID, "MMR should prefer the diverse result over the near-duplicate")
+}
+
+func TestApplyMMR_ResultsMissingEmbeddingsAreAppendedUnselected(t *testing.T) {
+	query := []float32{1, 0}
+	embeddings := map[string][]float32{
+		"a": {1, 0},
+	}
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a"}, Score: 0.9},
+		{Chunk: &store.Chunk{ID: "b"}, Score: 0.8}, // no embedding
+	}
+
+	got := ApplyMMR(results, query, embeddings, 0.5, 2, 2)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].Chunk.ID)
+	assert.Equal(t, "b", got[1].Chunk.ID)
+}
+
+func TestCosineSimilarity_IdenticalVectorsIsOne(t *testing.T) {
+	assert.InDelta(t, 1.0, cosineSimilarity([]float32{1, 2, 3}, []float32{1, 2, 3}), 1e-9)
+}
+
+func TestCosineSimilarity_OrthogonalVectorsIsZero(t *testing.T) {
+	assert.Equal(t, 0.0, cosineSimilarity([]float32{1, 0}, []float32{0, 1}))
+}
+
+func TestCosineSimilarity_MismatchedLengthsIsZero(t *testing.T) {
+	assert.Equal(t, 0.0, cosineSimilarity([]float32{1, 2}, []float32{1}))
+}
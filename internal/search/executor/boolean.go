@@ -0,0 +1,255 @@
+package executor
+
+// Combine merges the per-source scores of a candidate that matched every
+// branch of a Conjunction (or any branch of a Disjunction) into one score.
+// Callers pass the fusion strategy's own combine rule (e.g. weighted sum,
+// RRF term sum) rather than having one baked into the iterator.
+type Combine func(scores ...float64) float64
+
+// SumCombine adds scores together; the common case for RRF-style fusion
+// where each source already contributes its own reciprocal-rank term.
+func SumCombine(scores ...float64) float64 {
+	var total float64
+	for _, s := range scores {
+		total += s
+	}
+	return total
+}
+
+// conjunction performs a leapfrog join across iters: the cheapest iterator
+// drives, SeekGE skips the rest past IDs that can't match, and combine folds
+// the per-source scores of the surviving entry into one.
+type conjunction struct {
+	iters   []ScoredIterator
+	combine Combine
+}
+
+// Conjunction returns a ScoredIterator over candidates present in every one
+// of iters (logical AND), scoring each survivor via combine. An empty iters
+// list yields no candidates.
+func Conjunction(combine Combine, iters ...ScoredIterator) ScoredIterator {
+	return &conjunction{iters: iters, combine: combine}
+}
+
+func (c *conjunction) driver() (int, ScoredIterator) {
+	driverIdx := 0
+	for i, it := range c.iters {
+		if it.Cost() < c.iters[driverIdx].Cost() {
+			driverIdx = i
+		}
+	}
+	return driverIdx, c.iters[driverIdx]
+}
+
+func (c *conjunction) Next() (Entry, bool) {
+	if len(c.iters) == 0 {
+		return Entry{}, false
+	}
+	driverIdx, driver := c.driver()
+	current, ok := driver.Next()
+	if !ok {
+		return Entry{}, false
+	}
+
+	// SeekGE consumes the entry it returns, so each branch's last returned
+	// entry is kept here and reused while it is still >= current.
+	heads := make([]Entry, len(c.iters))
+	has := make([]bool, len(c.iters))
+	for {
+		scores := make([]float64, 0, len(c.iters))
+		matched := true
+		for i, it := range c.iters {
+			if i == driverIdx {
+				scores = append(scores, current.Score)
+				continue
+			}
+			if !has[i] || heads[i].ID < current.ID {
+				entry, ok := it.SeekGE(current.ID)
+				if !ok {
+					return Entry{}, false
+				}
+				heads[i], has[i] = entry, true
+			}
+			entry := heads[i]
+			if entry.ID != current.ID {
+				current = entry
+				matched = false
+				break
+			}
+			scores = append(scores, entry.Score)
+		}
+		if matched {
+			return Entry{ID: current.ID, Score: c.combine(scores...)}, true
+		}
+		entry, ok := driver.SeekGE(current.ID)
+		if !ok {
+			return Entry{}, false
+		}
+		current = entry
+	}
+}
+
+func (c *conjunction) SeekGE(target string) (Entry, bool) {
+	best := target
+	for _, it := range c.iters {
+		entry, ok := it.SeekGE(best)
+		if !ok {
+			return Entry{}, false
+		}
+		if entry.ID > best {
+			best = entry.ID
+		}
+	}
+	for _, it := range c.iters {
+		entry, ok := it.SeekGE(best)
+		if !ok || entry.ID != best {
+			return c.Next()
+		}
+	}
+	return c.Next()
+}
+
+func (c *conjunction) Cost() int {
+	if len(c.iters) == 0 {
+		return 0
+	}
+	_, driver := c.driver()
+	return driver.Cost()
+}
+
+func (c *conjunction) Close() {
+	for _, it := range c.iters {
+		it.Close()
+	}
+}
+
+// disjunction performs a sorted union across iters (logical OR), combining
+// scores for IDs shared by multiple iterators rather than picking one.
+type disjunction struct {
+	iters   []ScoredIterator
+	combine Combine
+	heads   []Entry
+	has     []bool
+	init    bool
+}
+
+// Disjunction returns a ScoredIterator over candidates present in any of
+// iters (logical OR), folding shared IDs' scores together via combine.
+func Disjunction(combine Combine, iters ...ScoredIterator) ScoredIterator {
+	return &disjunction{
+		iters:   iters,
+		combine: combine,
+		heads:   make([]Entry, len(iters)),
+		has:     make([]bool, len(iters)),
+	}
+}
+
+func (d *disjunction) fill() {
+	for i, it := range d.iters {
+		if !d.has[i] {
+			entry, ok := it.Next()
+			d.heads[i], d.has[i] = entry, ok
+		}
+	}
+	d.init = true
+}
+
+func (d *disjunction) Next() (Entry, bool) {
+	if !d.init {
+		d.fill()
+	}
+	min, found := "", false
+	for i := range d.iters {
+		if !d.has[i] {
+			continue
+		}
+		if !found || d.heads[i].ID < min {
+			min, found = d.heads[i].ID, true
+		}
+	}
+	if !found {
+		return Entry{}, false
+	}
+
+	var scores []float64
+	for i := range d.iters {
+		if d.has[i] && d.heads[i].ID == min {
+			scores = append(scores, d.heads[i].Score)
+			entry, ok := d.iters[i].Next()
+			d.heads[i], d.has[i] = entry, ok
+		}
+	}
+	return Entry{ID: min, Score: d.combine(scores...)}, true
+}
+
+func (d *disjunction) SeekGE(target string) (Entry, bool) {
+	if !d.init {
+		d.fill()
+	}
+	for i := range d.iters {
+		if d.has[i] && d.heads[i].ID < target {
+			entry, ok := d.iters[i].SeekGE(target)
+			d.heads[i], d.has[i] = entry, ok
+		}
+	}
+	return d.Next()
+}
+
+func (d *disjunction) Cost() int {
+	total := 0
+	for _, it := range d.iters {
+		total += it.Cost()
+	}
+	return total
+}
+
+func (d *disjunction) Close() {
+	for _, it := range d.iters {
+		it.Close()
+	}
+}
+
+// negation filters out candidates present in exclude from base.
+type negation struct {
+	base    ScoredIterator
+	exclude ScoredIterator
+}
+
+// Negation returns a ScoredIterator over candidates in base whose ID does
+// not appear in exclude. base's score passes through unchanged.
+func Negation(base, exclude ScoredIterator) ScoredIterator {
+	return &negation{base: base, exclude: exclude}
+}
+
+func (n *negation) Next() (Entry, bool) {
+	for {
+		entry, ok := n.base.Next()
+		if !ok {
+			return Entry{}, false
+		}
+		ex, exOk := n.exclude.SeekGE(entry.ID)
+		if exOk && ex.ID == entry.ID {
+			continue
+		}
+		return entry, true
+	}
+}
+
+func (n *negation) SeekGE(target string) (Entry, bool) {
+	entry, ok := n.base.SeekGE(target)
+	if !ok {
+		return Entry{}, false
+	}
+	ex, exOk := n.exclude.SeekGE(entry.ID)
+	if exOk && ex.ID == entry.ID {
+		return n.Next()
+	}
+	return entry, true
+}
+
+func (n *negation) Cost() int { return n.base.Cost() }
+
+func (n *negation) Close() {
+	n.base.Close()
+	n.exclude.Close()
+}
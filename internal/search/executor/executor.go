@@ -0,0 +1,115 @@
+// Package executor provides lazy, allocation-light iterator combinators for
+// streaming BM25 and vector candidate sets through the fusion step. Unlike
+// internal/search's PostingsIterator (which walks bare doc IDs for filter
+// predicates), these iterators carry a score alongside each ID, since the
+// boolean combinations here are built directly over ranked retrieval output.
+//
+// No package outside executor's own tests imports it yet - there's no
+// search.Engine in this checkout (engine.go doesn't exist in internal/search)
+// to stream BM25/vector candidates through it on the way to fusion.
+package executor
+
+import "sort"
+
+// Entry is a single scored candidate: a chunk ID and the score its source
+// (a BM25 or vector backend) assigned it.
+type Entry struct {
+	ID    string
+	Score float64
+}
+
+// ScoredIterator walks a sorted (by ID) stream of scored candidates without
+// materializing the full result set up front.
+type ScoredIterator interface {
+	// Next advances to the next entry. ok is false once exhausted.
+	Next() (entry Entry, ok bool)
+
+	// SeekGE advances to the first entry with ID >= target.
+	SeekGE(target string) (entry Entry, ok bool)
+
+	// Cost is a rough estimate of the remaining entries, used to pick the
+	// cheapest driver in a conjunction.
+	Cost() int
+
+	// Close releases any resources held by the iterator.
+	Close()
+}
+
+// sliceIterator is a ScoredIterator over an in-memory slice sorted by ID.
+type sliceIterator struct {
+	entries []Entry
+	pos     int
+}
+
+// NewSliceIterator returns a ScoredIterator over entries, sorting a copy by
+// ID ascending so callers can pass results in whatever order their backend
+// produced them (typically score-descending).
+func NewSliceIterator(entries []Entry) ScoredIterator {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return &sliceIterator{entries: sorted}
+}
+
+func (s *sliceIterator) Next() (Entry, bool) {
+	if s.pos >= len(s.entries) {
+		return Entry{}, false
+	}
+	e := s.entries[s.pos]
+	s.pos++
+	return e, true
+}
+
+func (s *sliceIterator) SeekGE(target string) (Entry, bool) {
+	rest := s.entries[s.pos:]
+	idx := sort.Search(len(rest), func(i int) bool { return rest[i].ID >= target })
+	if idx == len(rest) {
+		s.pos = len(s.entries)
+		return Entry{}, false
+	}
+	s.pos += idx
+	e := s.entries[s.pos]
+	s.pos++
+	return e, true
+}
+
+func (s *sliceIterator) Cost() int { return len(s.entries) - s.pos }
+
+func (s *sliceIterator) Close() {}
+
+// DefaultMaxCandidates bounds how many entries CollectTopN will pull from an
+// iterator before giving up, even if the limit+overfetch target was never
+// reached, so a pathologically large disjunction can't stall a query.
+const DefaultMaxCandidates = 50_000
+
+// Config tunes how far CollectTopN is willing to pull from an iterator.
+type Config struct {
+	// MaxCandidates caps the total entries pulled regardless of whether the
+	// limit+overfetch target has been met. Zero means DefaultMaxCandidates.
+	MaxCandidates int
+}
+
+// CollectTopN drains it, accumulating entries until either limit+overfetch
+// survivors have been produced or cfg.MaxCandidates entries have been
+// pulled, whichever comes first. It does not sort or truncate the result -
+// callers run their own fusion/ranking over whatever was collected.
+func CollectTopN(it ScoredIterator, limit, overfetch int, cfg Config) []Entry {
+	maxCandidates := cfg.MaxCandidates
+	if maxCandidates <= 0 {
+		maxCandidates = DefaultMaxCandidates
+	}
+	target := limit + overfetch
+	if target <= 0 {
+		target = maxCandidates
+	}
+
+	var out []Entry
+	for pulled := 0; pulled < maxCandidates && len(out) < target; pulled++ {
+		entry, ok := it.Next()
+		if !ok {
+			break
+		}
+		out = append(out, entry)
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func drain(it ScoredIterator) []Entry {
+	var out []Entry
+	for {
+		e, ok := it.Next()
+		if !ok {
+			return out
+		}
+		out = append(out, e)
+	}
+}
+
+func TestSliceIterator_NextAndSeek(t *testing.T) {
+	it := NewSliceIterator([]Entry{{ID: "c", Score: 1}, {ID: "a", Score: 2}, {ID: "b", Score: 3}})
+
+	e, ok := it.SeekGE("b")
+	require.True(t, ok)
+	assert.Equal(t, "b", e.ID)
+
+	e, ok = it.Next()
+	require.True(t, ok)
+	assert.Equal(t, "c", e.ID)
+
+	_, ok = it.Next()
+	assert.False(t, ok)
+}
+
+func TestConjunction_CombinesScoresOnMatch(t *testing.T) {
+	a := NewSliceIterator([]Entry{{ID: "x", Score: 1}, {ID: "y", Score: 2}})
+	b := NewSliceIterator([]Entry{{ID: "y", Score: 10}, {ID: "z", Score: 20}})
+
+	got := drain(Conjunction(SumCombine, a, b))
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "y", got[0].ID)
+	assert.Equal(t, 12.0, got[0].Score)
+}
+
+func TestConjunction_EmptyBranchShortCircuits(t *testing.T) {
+	a := NewSliceIterator([]Entry{{ID: "x", Score: 1}})
+	b := NewSliceIterator(nil)
+
+	got := drain(Conjunction(SumCombine, a, b))
+	assert.Empty(t, got)
+}
+
+func TestDisjunction_UnionFoldsSharedIDs(t *testing.T) {
+	a := NewSliceIterator([]Entry{{ID: "x", Score: 1}})
+	b := NewSliceIterator([]Entry{{ID: "x", Score: 5}, {ID: "y", Score: 2}})
+
+	got := drain(Disjunction(SumCombine, a, b))
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "x", got[0].ID)
+	assert.Equal(t, 6.0, got[0].Score)
+	assert.Equal(t, "y", got[1].ID)
+}
+
+func TestNegation_ExcludesMatchingIDs(t *testing.T) {
+	base := NewSliceIterator([]Entry{{ID: "x", Score: 1}, {ID: "y", Score: 2}})
+	exclude := NewSliceIterator([]Entry{{ID: "y", Score: 0}})
+
+	got := drain(Negation(base, exclude))
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "x", got[0].ID)
+}
+
+func TestLazyPostingsList_DoesNotRealizeUntilUsed(t *testing.T) {
+	called := false
+	lazy := NewLazyPostingsList(1, func() []Entry {
+		called = true
+		return []Entry{{ID: "a", Score: 1}}
+	})
+
+	assert.False(t, called, "constructing a LazyPostingsList must not realize it")
+
+	assert.True(t, lazy.Contains("a"))
+	assert.True(t, called)
+}
+
+func TestLazyPostingsList_SkippedSiblingNeverRealizes(t *testing.T) {
+	siblingCalled := false
+	empty := NewLazyPostingsList(0, func() []Entry { return nil })
+	lazySibling := NewLazyPostingsList(10, func() []Entry {
+		siblingCalled = true
+		return []Entry{{ID: "a", Score: 1}}
+	})
+
+	got := drain(Conjunction(SumCombine, empty, lazySibling))
+
+	assert.Empty(t, got)
+	assert.False(t, siblingCalled, "empty driver should short-circuit before the sibling is realized")
+}
+
+func TestCollectTopN_StopsAtLimitPlusOverfetch(t *testing.T) {
+	entries := make([]Entry, 100)
+	for i := range entries {
+		entries[i] = Entry{ID: fmt.Sprintf("chunk-%03d", i), Score: float64(i)}
+	}
+	it := NewSliceIterator(entries)
+
+	got := CollectTopN(it, 5, 5, Config{})
+
+	assert.Len(t, got, 10)
+}
+
+func TestCollectTopN_StopsAtMaxCandidatesEvenIfBelowTarget(t *testing.T) {
+	entries := make([]Entry, 100)
+	for i := range entries {
+		entries[i] = Entry{ID: fmt.Sprintf("chunk-%03d", i), Score: float64(i)}
+	}
+	it := NewSliceIterator(entries)
+
+	got := CollectTopN(it, 50, 50, Config{MaxCandidates: 10})
+
+	assert.Len(t, got, 10)
+}
+
+func BenchmarkCollectTopN_LargeCandidatePool(b *testing.B) {
+	entries := make([]Entry, 20_000)
+	for i := range entries {
+		entries[i] = Entry{ID: fmt.Sprintf("chunk-%05d", i), Score: float64(i)}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it := NewSliceIterator(entries)
+		_ = CollectTopN(it, 20, 10, Config{})
+	}
+}
+
+func BenchmarkConjunction_LeapfrogVsFullMaterialize(b *testing.B) {
+	a := make([]Entry, 10_000)
+	for i := range a {
+		a[i] = Entry{ID: fmt.Sprintf("chunk-%05d", i), Score: float64(i)}
+	}
+	bEntries := make([]Entry, 0, 5_000)
+	for i := 0; i < 10_000; i += 2 {
+		bEntries = append(bEntries, Entry{ID: fmt.Sprintf("chunk-%05d", i), Score: float64(i)})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conj := Conjunction(SumCombine, NewSliceIterator(a), NewSliceIterator(bEntries))
+		_ = CollectTopN(conj, 20, 10, Config{})
+	}
+}
@@ -0,0 +1,65 @@
+package executor
+
+// RealizeFunc produces the full entry set for a lazy branch. It's only
+// invoked the first time the branch is actually needed - a Conjunction
+// whose cheaper branch turns out empty never calls a sibling's RealizeFunc
+// at all.
+type RealizeFunc func() []Entry
+
+// LazyPostingsList wraps a RealizeFunc so the underlying entries aren't
+// built (sorted, allocated) until the first Next, SeekGE, or Contains call.
+// This is what lets a filter branch (e.g. "language = go") sit unevaluated
+// in a Conjunction tree until the cheaper branch has already ruled out an
+// empty result.
+//
+// estimate is a cheap, pre-realization guess at Cost() (e.g. a cached
+// postings-list length from the index's term dictionary) so a Conjunction
+// can pick its driver without forcing every branch to realize just to be
+// compared.
+type LazyPostingsList struct {
+	estimate int
+	realize  RealizeFunc
+	resolved ScoredIterator
+}
+
+// NewLazyPostingsList returns a ScoredIterator that defers calling realize
+// until it's first used. estimate seeds Cost() until realization happens,
+// after which Cost() reports the real remaining count.
+func NewLazyPostingsList(estimate int, realize RealizeFunc) *LazyPostingsList {
+	return &LazyPostingsList{estimate: estimate, realize: realize}
+}
+
+func (l *LazyPostingsList) ensure() ScoredIterator {
+	if l.resolved == nil {
+		l.resolved = NewSliceIterator(l.realize())
+	}
+	return l.resolved
+}
+
+func (l *LazyPostingsList) Next() (Entry, bool) { return l.ensure().Next() }
+
+func (l *LazyPostingsList) SeekGE(target string) (Entry, bool) { return l.ensure().SeekGE(target) }
+
+// Cost reports the pre-realization estimate until the list has actually
+// been realized, at which point it reports the real remaining count -
+// comparing costs across a Conjunction's branches must never by itself
+// trigger realization.
+func (l *LazyPostingsList) Cost() int {
+	if l.resolved == nil {
+		return l.estimate
+	}
+	return l.resolved.Cost()
+}
+
+func (l *LazyPostingsList) Close() {
+	if l.resolved != nil {
+		l.resolved.Close()
+	}
+}
+
+// Contains reports whether id is present in the (now realized) list. Like
+// Next/SeekGE, the first call triggers realization.
+func (l *LazyPostingsList) Contains(id string) bool {
+	entry, ok := l.ensure().SeekGE(id)
+	return ok && entry.ID == id
+}
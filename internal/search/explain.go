@@ -0,0 +1,99 @@
+package search
+
+import "fmt"
+
+// SignalExplanation breaks down a single scoring signal's contribution to
+// a result's final score, e.g. "BM25" or "Semantic".
+type SignalExplanation struct {
+	Name         string  `json:"name"`
+	RawScore     float64 `json:"raw_score"`
+	Rank         int     `json:"rank,omitempty"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Detail       string  `json:"detail,omitempty"`
+}
+
+// ScoreExplanation is a per-result, per-signal breakdown of how a fused
+// score was produced, suitable for rendering as "why did this rank here".
+type ScoreExplanation struct {
+	ChunkID string              `json:"chunk_id"`
+	Final   float64             `json:"final_score"`
+	Signals []SignalExplanation `json:"signals"`
+	InBoth  bool                `json:"in_both_lists"`
+	// SortKeys records the SortField keys (in applied order) that
+	// repositioned this result after fusion/reranking, e.g.
+	// ["file_path asc", "modified_at desc"]. Empty when SortBy was unset.
+	SortKeys []string `json:"sort_keys,omitempty"`
+}
+
+// explainFusedResult builds a ScoreExplanation for r, given the weights
+// used to fuse its BM25 and vector signals.
+//
+// ScoreExplanation and the functions that build it (explainFusedResult,
+// explainResults, annotateSortKeys) are superseded by the Explanation tree
+// in explanation.go and, like that tree, aren't called from anywhere
+// outside this package's own tests - there's no search.Engine in this
+// checkout (engine.go doesn't exist here) to attach either shape to a
+// SearchResult.
+func explainFusedResult(r *fusedResult, weights Weights, rrfConstant int) *ScoreExplanation {
+	exp := &ScoreExplanation{
+		ChunkID: r.chunkID,
+		Final:   r.rrfScore,
+		InBoth:  r.inBothLists,
+	}
+
+	if r.bm25Rank > 0 {
+		contribution := weights.BM25 * rrfTerm(r.bm25Rank, rrfConstant)
+		exp.Signals = append(exp.Signals, SignalExplanation{
+			Name:         "bm25",
+			RawScore:     r.bm25Score,
+			Rank:         r.bm25Rank,
+			Weight:       weights.BM25,
+			Contribution: contribution,
+			Detail:       fmt.Sprintf("rank %d in BM25 results", r.bm25Rank),
+		})
+	}
+	if r.vecRank > 0 {
+		contribution := weights.Semantic * rrfTerm(r.vecRank, rrfConstant)
+		exp.Signals = append(exp.Signals, SignalExplanation{
+			Name:         "semantic",
+			RawScore:     r.vecScore,
+			Rank:         r.vecRank,
+			Weight:       weights.Semantic,
+			Contribution: contribution,
+			Detail:       fmt.Sprintf("rank %d in vector results", r.vecRank),
+		})
+	}
+
+	return exp
+}
+
+// annotateSortKeys stamps every explanation with the sort keys MultiSort
+// applied, so an explain trace shows why a result landed where it did even
+// after its fused score was overridden by an explicit SortBy.
+func annotateSortKeys(explanations []*ScoreExplanation, applied []string) {
+	if len(applied) == 0 {
+		return
+	}
+	for _, exp := range explanations {
+		exp.SortKeys = applied
+	}
+}
+
+// rrfTerm computes a single 1/(k + rank) RRF term.
+func rrfTerm(rank, k int) float64 {
+	if k <= 0 {
+		k = DefaultRRFConstant
+	}
+	return 1.0 / float64(k+rank)
+}
+
+// explainResults builds one ScoreExplanation per result, in the same
+// order, for attaching to a hybrid search response.
+func explainResults(results []*fusedResult, weights Weights, rrfConstant int) []*ScoreExplanation {
+	out := make([]*ScoreExplanation, 0, len(results))
+	for _, r := range results {
+		out = append(out, explainFusedResult(r, weights, rrfConstant))
+	}
+	return out
+}
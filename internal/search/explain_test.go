@@ -0,0 +1,52 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainFusedResult_BothSignalsPresent(t *testing.T) {
+	r := &fusedResult{
+		chunkID:     "chunk1",
+		rrfScore:    0.05,
+		bm25Score:   9.5,
+		bm25Rank:    1,
+		vecScore:    0.8,
+		vecRank:     2,
+		inBothLists: true,
+	}
+	weights := Weights{BM25: 0.6, Semantic: 0.4}
+
+	exp := explainFusedResult(r, weights, 60)
+
+	require.Len(t, exp.Signals, 2)
+	assert.Equal(t, "bm25", exp.Signals[0].Name)
+	assert.Equal(t, "semantic", exp.Signals[1].Name)
+	assert.True(t, exp.InBoth)
+	assert.InDelta(t, 0.6*(1.0/61.0), exp.Signals[0].Contribution, 1e-9)
+}
+
+func TestExplainFusedResult_OnlyBM25(t *testing.T) {
+	r := &fusedResult{chunkID: "chunk1", bm25Rank: 3, bm25Score: 4.0}
+	weights := Weights{BM25: 1.0, Semantic: 0.0}
+
+	exp := explainFusedResult(r, weights, 60)
+
+	require.Len(t, exp.Signals, 1)
+	assert.Equal(t, "bm25", exp.Signals[0].Name)
+}
+
+func TestExplainResults_PreservesOrder(t *testing.T) {
+	results := []*fusedResult{
+		{chunkID: "a", bm25Rank: 1},
+		{chunkID: "b", vecRank: 1},
+	}
+
+	explanations := explainResults(results, Weights{BM25: 0.5, Semantic: 0.5}, 60)
+
+	require.Len(t, explanations, 2)
+	assert.Equal(t, "a", explanations[0].ChunkID)
+	assert.Equal(t, "b", explanations[1].ChunkID)
+}
@@ -0,0 +1,200 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExplainNode is one node in a hierarchical, per-signal explanation tree.
+// Each concrete type (BM25Node, VectorNode, RRFNode, RerankNode,
+// FilterNode) contributes some amount to its parent's score and may hold
+// its own children, so "why did this rank here" reads top-down: the final
+// fusion, the BM25/vector signals that fed it, any rerank pass, and any
+// filter that accepted or rejected the result along the way. This
+// supersedes the flat ScoreExplanation for callers that want the full
+// trace rather than just a per-signal summary.
+type ExplainNode interface {
+	// Label names the node for rendering, e.g. "bm25[term=login]".
+	Label() string
+	// Contribution is this node's numeric contribution to its parent's
+	// score. Gating nodes that don't score (FilterNode) return 0.
+	Contribution() float64
+	// Children returns this node's children, or nil for a leaf.
+	Children() []ExplainNode
+}
+
+// BM25Node explains one term's contribution to a result's BM25 score.
+type BM25Node struct {
+	Term     string
+	IDF      float64
+	TF       float64
+	FieldLen int
+	Score    float64
+}
+
+func (n *BM25Node) Label() string           { return fmt.Sprintf("bm25[term=%s]", n.Term) }
+func (n *BM25Node) Contribution() float64   { return n.Score }
+func (n *BM25Node) Children() []ExplainNode { return nil }
+
+// VectorNode explains the vector/semantic signal's contribution.
+type VectorNode struct {
+	Cosine     float64
+	Dim        int
+	Normalized bool
+}
+
+func (n *VectorNode) Label() string {
+	return fmt.Sprintf("vector[dim=%d normalized=%t]", n.Dim, n.Normalized)
+}
+func (n *VectorNode) Contribution() float64   { return n.Cosine }
+func (n *VectorNode) Children() []ExplainNode { return nil }
+
+// RRFNode explains a Reciprocal Rank Fusion step over its children (the
+// BM25Node/VectorNode signals being fused).
+type RRFNode struct {
+	BM25Rank int
+	VecRank  int
+	K        int
+	Contrib  float64
+	Kids     []ExplainNode
+}
+
+func (n *RRFNode) Label() string {
+	return fmt.Sprintf("rrf[k=%d bm25_rank=%d vec_rank=%d]", n.K, n.BM25Rank, n.VecRank)
+}
+func (n *RRFNode) Contribution() float64   { return n.Contrib }
+func (n *RRFNode) Children() []ExplainNode { return n.Kids }
+
+// RerankNode explains a cross-encoder (or other reranker) adjustment
+// applied after fusion.
+type RerankNode struct {
+	Model      string
+	RawScore   float64
+	Normalized float64
+	Kids       []ExplainNode
+}
+
+func (n *RerankNode) Label() string           { return fmt.Sprintf("rerank[model=%s]", n.Model) }
+func (n *RerankNode) Contribution() float64   { return n.Normalized }
+func (n *RerankNode) Children() []ExplainNode { return n.Kids }
+
+// FilterNode records a filter rule evaluated against the result. It never
+// contributes to the score; it exists purely so an explain trace shows
+// which filters a result passed (or would have failed, if recorded before
+// the result was dropped).
+type FilterNode struct {
+	Rule   string
+	Passed bool
+}
+
+func (n *FilterNode) Label() string {
+	status := "passed"
+	if !n.Passed {
+		status = "rejected"
+	}
+	return fmt.Sprintf("filter[%s]=%s", n.Rule, status)
+}
+func (n *FilterNode) Contribution() float64   { return 0 }
+func (n *FilterNode) Children() []ExplainNode { return nil }
+
+// ExplainTree is the root of one result's hierarchical explanation.
+type ExplainTree struct {
+	ChunkID string
+	Root    ExplainNode
+}
+
+// String renders the tree as an indented, human-readable trace in the
+// style of Bleve/Lucene explanations:
+//
+//	chunk=chunk1
+//	  rrf[k=60 bm25_rank=1 vec_rank=3] (0.0320)
+//	    bm25[term=login] (12.4000)
+//	    vector[dim=768 normalized=true] (0.8100)
+func (t *ExplainTree) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "chunk=%s\n", t.ChunkID)
+	if t.Root != nil {
+		renderExplainNode(&b, t.Root, 1)
+	}
+	return b.String()
+}
+
+func renderExplainNode(b *strings.Builder, n ExplainNode, depth int) {
+	fmt.Fprintf(b, "%s%s (%.4f)\n", strings.Repeat("  ", depth), n.Label(), n.Contribution())
+	for _, child := range n.Children() {
+		renderExplainNode(b, child, depth+1)
+	}
+}
+
+// MarshalJSON renders the tree as nested {"label", "contribution",
+// "children"} objects, since ExplainNode implementations don't share a
+// common struct shape that encoding/json could walk on its own.
+func (t *ExplainTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ChunkID string `json:"chunk_id"`
+		Root    any    `json:"root,omitempty"`
+	}{
+		ChunkID: t.ChunkID,
+		Root:    explainNodeJSON(t.Root),
+	})
+}
+
+// buildExplainTree assembles r's full explanation tree: an RRFNode rooted
+// at the fusion step, with a BM25Node/VectorNode child for whichever
+// signals matched, optionally wrapped in a RerankNode if rerank was
+// applied, plus any filters evaluated against the result.
+func buildExplainTree(r *fusedResult, weights Weights, rrfConstant int, rerank *RerankNode, filters []FilterNode) *ExplainTree {
+	rrf := &RRFNode{
+		BM25Rank: r.bm25Rank,
+		VecRank:  r.vecRank,
+		K:        rrfConstantOrDefault(rrfConstant),
+		Contrib:  r.rrfScore,
+	}
+
+	if r.bm25Rank > 0 {
+		rrf.Kids = append(rrf.Kids, &BM25Node{
+			Term:  "",
+			Score: weights.BM25 * rrfTerm(r.bm25Rank, rrfConstant),
+		})
+	}
+	if r.vecRank > 0 {
+		rrf.Kids = append(rrf.Kids, &VectorNode{
+			Cosine: weights.Semantic * rrfTerm(r.vecRank, rrfConstant),
+		})
+	}
+	for _, f := range filters {
+		f := f
+		rrf.Kids = append(rrf.Kids, &f)
+	}
+
+	var root ExplainNode = rrf
+	if rerank != nil {
+		rerank.Kids = []ExplainNode{rrf}
+		root = rerank
+	}
+
+	return &ExplainTree{ChunkID: r.chunkID, Root: root}
+}
+
+func rrfConstantOrDefault(k int) int {
+	if k <= 0 {
+		return DefaultRRFConstant
+	}
+	return k
+}
+
+func explainNodeJSON(n ExplainNode) any {
+	if n == nil {
+		return nil
+	}
+	children := make([]any, 0, len(n.Children()))
+	for _, c := range n.Children() {
+		children = append(children, explainNodeJSON(c))
+	}
+	return map[string]any{
+		"label":        n.Label(),
+		"contribution": n.Contribution(),
+		"children":     children,
+	}
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainTree_StringRendersIndentedTrace(t *testing.T) {
+	tree := &ExplainTree{
+		ChunkID: "chunk1",
+		Root: &RRFNode{
+			K: 60, BM25Rank: 1, VecRank: 2, Contrib: 0.03,
+			Kids: []ExplainNode{
+				&BM25Node{Term: "login", Score: 12.4},
+				&VectorNode{Cosine: 0.81, Dim: 768, Normalized: true},
+			},
+		},
+	}
+
+	out := tree.String()
+
+	assert.Contains(t, out, "chunk=chunk1")
+	assert.Contains(t, out, "rrf[k=60 bm25_rank=1 vec_rank=2]")
+	assert.Contains(t, out, "bm25[term=login] (12.4000)")
+	assert.Contains(t, out, "vector[dim=768 normalized=true] (0.8100)")
+}
+
+func TestExplainTree_MarshalJSONRoundTripsStructure(t *testing.T) {
+	tree := &ExplainTree{
+		ChunkID: "chunk1",
+		Root: &RRFNode{
+			K: 60, Contrib: 0.5,
+			Kids: []ExplainNode{&FilterNode{Rule: "language=go", Passed: true}},
+		},
+	}
+
+	raw, err := json.Marshal(tree)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+
+	assert.Equal(t, "chunk1", decoded["chunk_id"])
+	root := decoded["root"].(map[string]any)
+	assert.Contains(t, root["label"], "rrf[k=60")
+	children := root["children"].([]any)
+	require.Len(t, children, 1)
+	filterNode := children[0].(map[string]any)
+	assert.Contains(t, filterNode["label"], "filter[language=go]=passed")
+}
+
+func TestBuildExplainTree_IncludesBothSignalsAndRerank(t *testing.T) {
+	r := &fusedResult{chunkID: "c1", bm25Rank: 1, vecRank: 2, rrfScore: 0.1}
+	weights := Weights{BM25: 0.5, Semantic: 0.5}
+	rerank := &RerankNode{Model: "cross-encoder", RawScore: 4.2, Normalized: 0.9}
+
+	tree := buildExplainTree(r, weights, DefaultRRFConstant, rerank, []FilterNode{{Rule: "language=go", Passed: true}})
+
+	require.Equal(t, "c1", tree.ChunkID)
+	top, ok := tree.Root.(*RerankNode)
+	require.True(t, ok, "rerank node should be the root when a rerank pass ran")
+	require.Len(t, top.Children(), 1)
+
+	rrf, ok := top.Children()[0].(*RRFNode)
+	require.True(t, ok)
+	require.Len(t, rrf.Children(), 3, "bm25 signal, vector signal, and the filter node")
+}
+
+func TestBuildExplainTree_NoRerankKeepsRRFAsRoot(t *testing.T) {
+	r := &fusedResult{chunkID: "c1", bm25Rank: 1, rrfScore: 0.1}
+
+	tree := buildExplainTree(r, Weights{BM25: 1}, DefaultRRFConstant, nil, nil)
+
+	_, ok := tree.Root.(*RRFNode)
+	assert.True(t, ok)
+}
@@ -0,0 +1,184 @@
+package search
+
+import "fmt"
+
+// Explanation is a Bleve-style flat score-breakdown tree: every node
+// carries its own Value and a human-readable Message describing how that
+// value was derived, with Children supplying the terms of a sum/product.
+// It's meant to replace the single-level ScoreExplanation as the shape
+// SearchResult.Explain carries, populated per result when
+// SearchOptions.Explain is set, with the package-level ExplainScore
+// computing the same tree for a single document without running the full
+// retrieval pipeline. There's no search.Engine in this checkout
+// (engine.go doesn't exist here) to do that populating, so buildExplanation
+// and ExplainScore are only called from this package's own tests today.
+type Explanation struct {
+	Value    float64        `json:"value"`
+	Message  string         `json:"message"`
+	Children []*Explanation `json:"children,omitempty"`
+}
+
+// ExplanationOptions carries the extra signals buildExplanation folds into
+// the tree beyond the base BM25/vector/RRF breakdown.
+type ExplanationOptions struct {
+	// Rerank, if set, wraps the fusion explanation under a rerank node
+	// describing the delta a reranking pass applied to the fused score.
+	Rerank *RerankNode
+	// BM25Detail, if set, is grafted onto the bm25 signal node as its
+	// matched-term/idf leaf.
+	BM25Detail *BM25Node
+	// VectorDetail, if set, is grafted onto the semantic signal node as
+	// its cosine leaf.
+	VectorDetail *VectorNode
+	// ExpansionMultiplier, if non-zero and not 1, records a
+	// query-expansion boost (e.g. from FuzzyExpandQuery matching a
+	// non-exact term) applied on top of the bm25 signal's raw
+	// contribution.
+	ExpansionMultiplier float64
+	// ClassifierWeights, if set, overrides weights with a classifier's
+	// rebalanced weights; the tree records both the original and the
+	// rebalanced weight so the adjustment stays visible.
+	ClassifierWeights *Weights
+	// VectorBoost, if non-zero and not 1, records a multiplier applied to
+	// the semantic signal's raw contribution (e.g. a reranker-independent
+	// boost configured on the vector index itself), the semantic
+	// counterpart to ExpansionMultiplier.
+	VectorBoost float64
+	// AdjacentBoost, if set, wraps the whole tree under an
+	// AdjacentChunkBoost node describing a rank boost applied because a
+	// neighbouring chunk from the same file already matched.
+	AdjacentBoost *AdjacentChunkBoost
+}
+
+// AdjacentChunkBoost describes a boost applied to a chunk's score because
+// another chunk adjacent to it (same file, within AdjacentBoost.Distance
+// chunks) already matched, so results stay contiguous around a hit rather
+// than surfacing isolated chunks from the same file.
+type AdjacentChunkBoost struct {
+	// NeighborChunkID is the already-matched chunk this boost is relative to.
+	NeighborChunkID string
+	// Distance is how many chunks away NeighborChunkID is, in chunk order.
+	Distance int
+	// Boost is the multiplier applied to the pre-boost score.
+	Boost float64
+}
+
+// buildExplanation builds the Bleve-style tree for r, given the weights
+// used to fuse its BM25 and vector signals (or, when
+// opts.ClassifierWeights is set, the classifier-rebalanced weights).
+func buildExplanation(r *fusedResult, weights Weights, rrfConstant int, opts ExplanationOptions) *Explanation {
+	effective := weights
+	if opts.ClassifierWeights != nil {
+		effective = *opts.ClassifierWeights
+	}
+
+	var signals []*Explanation
+	if r.bm25Rank > 0 {
+		var detail ExplainNode
+		if opts.BM25Detail != nil {
+			detail = opts.BM25Detail
+		}
+		signals = append(signals, signalExplanation("bm25", effective.BM25, r.bm25Rank, rrfConstant, opts.ExpansionMultiplier, detail))
+	}
+	if r.vecRank > 0 {
+		var detail ExplainNode
+		if opts.VectorDetail != nil {
+			detail = opts.VectorDetail
+		}
+		signals = append(signals, signalExplanation("semantic", effective.Semantic, r.vecRank, rrfConstant, opts.VectorBoost, detail))
+	}
+
+	fusion := &Explanation{Value: r.rrfScore, Message: "sum of:", Children: signals}
+	if opts.ClassifierWeights != nil {
+		fusion.Children = append(fusion.Children, &Explanation{
+			Message: fmt.Sprintf("classifier rebalanced weights: bm25 %.4f -> %.4f, semantic %.4f -> %.4f",
+				weights.BM25, effective.BM25, weights.Semantic, effective.Semantic),
+		})
+	}
+
+	root := fusion
+	if opts.Rerank != nil {
+		root = &Explanation{
+			Value:    opts.Rerank.Normalized,
+			Message:  fmt.Sprintf("rerank(%s), delta from fusion score %.4f to %.4f", opts.Rerank.Model, r.rrfScore, opts.Rerank.Normalized),
+			Children: []*Explanation{fusion},
+		}
+	}
+
+	if opts.AdjacentBoost == nil {
+		return root
+	}
+	return &Explanation{
+		Value: root.Value * opts.AdjacentBoost.Boost,
+		Message: fmt.Sprintf("adjacentChunkBoost(%.4g), neighbor=%s distance=%d",
+			opts.AdjacentBoost.Boost, opts.AdjacentBoost.NeighborChunkID, opts.AdjacentBoost.Distance),
+		Children: []*Explanation{root},
+	}
+}
+
+// signalExplanation builds the "weight(<signal>^<weight>), product of:"
+// subtree for one RRF signal: its weight, its 1/(k+rank) term, an
+// optional query-expansion multiplier, and an optional detail leaf (e.g.
+// BM25Node's matched-term/idf breakdown).
+func signalExplanation(name string, weight float64, rank, rrfConstant int, expansionMultiplier float64, detail ExplainNode) *Explanation {
+	rrfVal := rrfTerm(rank, rrfConstant)
+	contribution := weight * rrfVal
+
+	children := []*Explanation{
+		{Value: weight, Message: fmt.Sprintf("%s weight", name)},
+		{Value: rrfVal, Message: fmt.Sprintf("1/(k + rank), k=%d rank=%d", rrfConstantOrDefault(rrfConstant), rank)},
+	}
+	if expansionMultiplier > 0 && expansionMultiplier != 1 {
+		contribution *= expansionMultiplier
+		children = append(children, &Explanation{Value: expansionMultiplier, Message: "query-expansion multiplier"})
+	}
+	if detail != nil {
+		children = append(children, detailExplanation(detail))
+	}
+
+	return &Explanation{
+		Value:    contribution,
+		Message:  fmt.Sprintf("weight(%s^%g), product of:", name, weight),
+		Children: children,
+	}
+}
+
+// detailExplanation renders a BM25Node or VectorNode's own matched-term
+// detail as an Explanation leaf, reusing #20's per-signal node types
+// rather than recomputing the same breakdown.
+func detailExplanation(detail ExplainNode) *Explanation {
+	switch n := detail.(type) {
+	case *BM25Node:
+		return &Explanation{
+			Value:   n.Score,
+			Message: fmt.Sprintf("term=%s idf=%.4f tf=%.4f fieldNorm=%d", n.Term, n.IDF, n.TF, n.FieldLen),
+		}
+	case *VectorNode:
+		return &Explanation{
+			Value:   n.Cosine,
+			Message: fmt.Sprintf("cosineDistance, dim=%d normalized=%v", n.Dim, n.Normalized),
+		}
+	default:
+		return &Explanation{Message: detail.Label()}
+	}
+}
+
+// ExplainScore computes an Explanation tree for a single document's
+// already-computed BM25/vector signals, without running the full hybrid
+// retrieval pipeline — useful for debugging why a chunk did or didn't
+// rank. Either signal may be nil if that retriever didn't return the
+// chunk; the other is then the tree's only component.
+func ExplainScore(chunkID string, bm25 *BM25Node, vector *VectorNode, weights Weights, rrfConstant int, opts ExplanationOptions) *Explanation {
+	r := &fusedResult{chunkID: chunkID}
+	if bm25 != nil {
+		r.bm25Rank, r.bm25Score = 1, bm25.Score
+		r.rrfScore += weights.BM25 * rrfTerm(r.bm25Rank, rrfConstant)
+		opts.BM25Detail = bm25
+	}
+	if vector != nil {
+		r.vecRank, r.vecScore = 1, vector.Cosine
+		r.rrfScore += weights.Semantic * rrfTerm(r.vecRank, rrfConstant)
+		opts.VectorDetail = vector
+	}
+	return buildExplanation(r, weights, rrfConstant, opts)
+}
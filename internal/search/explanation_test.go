@@ -0,0 +1,122 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildExplanation_SumsWeightedSignals(t *testing.T) {
+	r := &fusedResult{chunkID: "c1", bm25Rank: 1, vecRank: 2, rrfScore: 0.03}
+	weights := Weights{BM25: 0.3, Semantic: 0.7}
+
+	exp := buildExplanation(r, weights, DefaultRRFConstant, ExplanationOptions{})
+
+	assert.Equal(t, "sum of:", exp.Message)
+	require.Len(t, exp.Children, 2)
+	assert.Contains(t, exp.Children[0].Message, "weight(bm25^0.3), product of:")
+	assert.Contains(t, exp.Children[1].Message, "weight(semantic^0.7), product of:")
+}
+
+func TestBuildExplanation_RerankWrapsFusionAsRoot(t *testing.T) {
+	r := &fusedResult{chunkID: "c1", bm25Rank: 1, rrfScore: 0.1}
+	rerank := &RerankNode{Model: "cross-encoder", RawScore: 4.2, Normalized: 0.9}
+
+	exp := buildExplanation(r, Weights{BM25: 1}, DefaultRRFConstant, ExplanationOptions{Rerank: rerank})
+
+	assert.Equal(t, 0.9, exp.Value)
+	assert.Contains(t, exp.Message, "rerank(cross-encoder)")
+	require.Len(t, exp.Children, 1)
+	assert.Equal(t, "sum of:", exp.Children[0].Message)
+}
+
+func TestBuildExplanation_ClassifierWeightsAreAnnotated(t *testing.T) {
+	r := &fusedResult{chunkID: "c1", bm25Rank: 1, rrfScore: 0.05}
+	rebalanced := &Weights{BM25: 0.8, Semantic: 0.2}
+
+	exp := buildExplanation(r, Weights{BM25: 0.3, Semantic: 0.7}, DefaultRRFConstant, ExplanationOptions{ClassifierWeights: rebalanced})
+
+	last := exp.Children[len(exp.Children)-1]
+	assert.Contains(t, last.Message, "classifier rebalanced weights")
+	assert.Contains(t, last.Message, "0.3000 -> 0.8000")
+}
+
+func TestBuildExplanation_ExpansionMultiplierAppendsChild(t *testing.T) {
+	r := &fusedResult{chunkID: "c1", bm25Rank: 1, rrfScore: 0.02}
+
+	exp := buildExplanation(r, Weights{BM25: 1}, DefaultRRFConstant, ExplanationOptions{ExpansionMultiplier: 1.5})
+
+	bm25 := exp.Children[0]
+	found := false
+	for _, child := range bm25.Children {
+		if child.Message == "query-expansion multiplier" {
+			found = true
+			assert.Equal(t, 1.5, child.Value)
+		}
+	}
+	assert.True(t, found, "expected a query-expansion multiplier child")
+}
+
+func TestExplainScore_BuildsTreeFromPrecomputedSignals(t *testing.T) {
+	bm25 := &BM25Node{Term: "login", IDF: 2.1, TF: 1.4, FieldLen: 120, Score: 2.94}
+	vector := &VectorNode{Cosine: 0.81, Dim: 768, Normalized: true}
+
+	exp := ExplainScore("c1", bm25, vector, Weights{BM25: 0.5, Semantic: 0.5}, DefaultRRFConstant, ExplanationOptions{})
+
+	require.Len(t, exp.Children, 2)
+	bm25Children := exp.Children[0].Children
+	require.Len(t, bm25Children, 3, "weight, rrf term, and the matched-term detail leaf")
+	assert.Contains(t, bm25Children[2].Message, "term=login")
+	assert.Contains(t, bm25Children[2].Message, "idf=2.1000")
+}
+
+func TestBuildExplanation_VectorBoostAppendsChild(t *testing.T) {
+	r := &fusedResult{chunkID: "c1", vecRank: 1, rrfScore: 0.02}
+
+	exp := buildExplanation(r, Weights{Semantic: 1}, DefaultRRFConstant, ExplanationOptions{VectorBoost: 1.2})
+
+	semantic := exp.Children[0]
+	found := false
+	for _, child := range semantic.Children {
+		if child.Message == "query-expansion multiplier" {
+			found = true
+			assert.Equal(t, 1.2, child.Value)
+		}
+	}
+	assert.True(t, found, "expected the vector boost to be recorded as a multiplier child")
+}
+
+func TestBuildExplanation_AdjacentBoostWrapsTreeAsRoot(t *testing.T) {
+	r := &fusedResult{chunkID: "c2", bm25Rank: 1, rrfScore: 0.1}
+	boost := &AdjacentChunkBoost{NeighborChunkID: "c1", Distance: 1, Boost: 1.5}
+
+	exp := buildExplanation(r, Weights{BM25: 1}, DefaultRRFConstant, ExplanationOptions{AdjacentBoost: boost})
+
+	assert.InDelta(t, 0.15, exp.Value, 1e-9)
+	assert.Contains(t, exp.Message, "adjacentChunkBoost(1.5)")
+	assert.Contains(t, exp.Message, "neighbor=c1")
+	require.Len(t, exp.Children, 1)
+	assert.Equal(t, "sum of:", exp.Children[0].Message)
+}
+
+func TestBuildExplanation_AdjacentBoostWrapsRerankWhenBothSet(t *testing.T) {
+	r := &fusedResult{chunkID: "c2", bm25Rank: 1, rrfScore: 0.1}
+	rerank := &RerankNode{Model: "cross-encoder", Normalized: 0.9}
+	boost := &AdjacentChunkBoost{NeighborChunkID: "c1", Distance: 2, Boost: 2}
+
+	exp := buildExplanation(r, Weights{BM25: 1}, DefaultRRFConstant, ExplanationOptions{Rerank: rerank, AdjacentBoost: boost})
+
+	assert.Equal(t, 1.8, exp.Value)
+	require.Len(t, exp.Children, 1)
+	assert.Contains(t, exp.Children[0].Message, "rerank(cross-encoder)")
+}
+
+func TestExplainScore_MissingSignalIsOmitted(t *testing.T) {
+	vector := &VectorNode{Cosine: 0.5, Dim: 256, Normalized: false}
+
+	exp := ExplainScore("c1", nil, vector, Weights{Semantic: 1}, DefaultRRFConstant, ExplanationOptions{})
+
+	require.Len(t, exp.Children, 1)
+	assert.Contains(t, exp.Children[0].Message, "weight(semantic^1)")
+}
@@ -0,0 +1,187 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FacetField names a built-in field a FacetSpec can group by. Leave it
+// zero and set FacetSpec.MetadataKey to facet on a Chunk.Metadata entry
+// instead.
+type FacetField string
+
+const (
+	FacetFieldLanguage    FacetField = "language"
+	FacetFieldContentType FacetField = "content_type"
+	FacetFieldFilePath    FacetField = "file_path"
+	FacetFieldModifiedAt  FacetField = "modified_at"
+)
+
+// DateBucket groups FacetFieldModifiedAt values into calendar windows
+// instead of one bucket per exact timestamp.
+type DateBucket string
+
+const (
+	// DateBucketWeek buckets by ISO year-week, e.g. "2026-W30".
+	DateBucketWeek DateBucket = "week"
+	// DateBucketMonth buckets by calendar month, e.g. "2026-07".
+	DateBucketMonth DateBucket = "month"
+)
+
+// FacetSpec requests one facet over a match set.
+type FacetSpec struct {
+	// Name keys the resulting FacetResult in ComputeFacets' return map.
+	// Defaults to the field name (or "meta:"+MetadataKey).
+	Name string
+	// Field names a built-in facet field. Ignored when MetadataKey is set.
+	Field FacetField
+	// MetadataKey, when non-empty, facets on Chunk.Metadata[MetadataKey]
+	// instead of a built-in field.
+	MetadataKey string
+	// PathDepth truncates FacetFieldFilePath to its first PathDepth
+	// directory segments (e.g. 2 -> "internal/search" for
+	// "internal/search/engine.go"). Zero keeps the full path.
+	PathDepth int
+	// DateBucket buckets FacetFieldModifiedAt values; defaults to
+	// DateBucketMonth.
+	DateBucket DateBucket
+}
+
+// FacetTerm is one bucket's value and count within a FacetResult.
+type FacetTerm struct {
+	Value string
+	Count int
+	// ExampleChunkID is the first result that fell into this bucket, so a
+	// caller can jump straight to a representative match.
+	ExampleChunkID string
+}
+
+// FacetResult is the computed facet for one FacetSpec, with terms sorted
+// by count descending (ties broken by value, for a stable rendering).
+type FacetResult struct {
+	Field FacetField
+	Terms []FacetTerm
+}
+
+// ComputeFacets builds one FacetResult per spec from results. Callers
+// should compute facets after applying filters but before truncating to
+// Limit, so counts reflect the true match set rather than just the page
+// being returned.
+func ComputeFacets(results []*SearchResult, specs []FacetSpec) map[string]*FacetResult {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	out := make(map[string]*FacetResult, len(specs))
+	for _, spec := range specs {
+		out[facetName(spec)] = computeFacet(results, spec)
+	}
+	return out
+}
+
+func computeFacet(results []*SearchResult, spec FacetSpec) *FacetResult {
+	counts := make(map[string]int)
+	examples := make(map[string]string)
+	var order []string
+
+	for _, r := range results {
+		value, ok := facetValue(r, spec)
+		if !ok {
+			continue
+		}
+		if counts[value] == 0 {
+			order = append(order, value)
+			if r.Chunk != nil {
+				examples[value] = r.Chunk.ID
+			}
+		}
+		counts[value]++
+	}
+
+	terms := make([]FacetTerm, 0, len(order))
+	for _, value := range order {
+		terms = append(terms, FacetTerm{Value: value, Count: counts[value], ExampleChunkID: examples[value]})
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return terms[i].Value < terms[j].Value
+	})
+
+	return &FacetResult{Field: spec.Field, Terms: terms}
+}
+
+func facetName(spec FacetSpec) string {
+	if spec.Name != "" {
+		return spec.Name
+	}
+	if spec.MetadataKey != "" {
+		return "meta:" + spec.MetadataKey
+	}
+	return string(spec.Field)
+}
+
+func facetValue(r *SearchResult, spec FacetSpec) (string, bool) {
+	if spec.MetadataKey != "" {
+		if r.Chunk == nil || r.Chunk.Metadata == nil {
+			return "", false
+		}
+		v, ok := r.Chunk.Metadata[spec.MetadataKey]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%v", v), true
+	}
+
+	if r.Chunk == nil {
+		return "", false
+	}
+	switch spec.Field {
+	case FacetFieldLanguage:
+		if r.Chunk.Language == "" {
+			return "", false
+		}
+		return r.Chunk.Language, true
+	case FacetFieldContentType:
+		if r.Chunk.ContentType == "" {
+			return "", false
+		}
+		return string(r.Chunk.ContentType), true
+	case FacetFieldFilePath:
+		if r.Chunk.FilePath == "" {
+			return "", false
+		}
+		return truncatePathDepth(r.Chunk.FilePath, spec.PathDepth), true
+	case FacetFieldModifiedAt:
+		if r.Chunk.UpdatedAt.IsZero() {
+			return "", false
+		}
+		return bucketDate(r.Chunk.UpdatedAt, spec.DateBucket), true
+	default:
+		return "", false
+	}
+}
+
+func truncatePathDepth(path string, depth int) string {
+	if depth <= 0 {
+		return path
+	}
+	segments := strings.Split(path, "/")
+	if depth >= len(segments) {
+		return path
+	}
+	return strings.Join(segments[:depth], "/")
+}
+
+func bucketDate(t time.Time, bucket DateBucket) string {
+	switch bucket {
+	case DateBucketWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	default:
+		return t.Format("2006-01")
+	}
+}
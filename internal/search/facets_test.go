@@ -0,0 +1,80 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+func TestComputeFacets_CountsByLanguageSortedDescending(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a", Language: "go"}},
+		{Chunk: &store.Chunk{ID: "b", Language: "go"}},
+		{Chunk: &store.Chunk{ID: "c", Language: "ts"}},
+	}
+
+	facets := ComputeFacets(results, []FacetSpec{{Field: FacetFieldLanguage}})
+
+	f := facets["language"]
+	require.NotNil(t, f)
+	require.Len(t, f.Terms, 2)
+	assert.Equal(t, "go", f.Terms[0].Value)
+	assert.Equal(t, 2, f.Terms[0].Count)
+	assert.Equal(t, "a", f.Terms[0].ExampleChunkID)
+	assert.Equal(t, "ts", f.Terms[1].Value)
+	assert.Equal(t, 1, f.Terms[1].Count)
+}
+
+func TestComputeFacets_FilePathTruncatesByDepth(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a", FilePath: "internal/search/engine.go"}},
+		{Chunk: &store.Chunk{ID: "b", FilePath: "internal/search/fuzzy.go"}},
+		{Chunk: &store.Chunk{ID: "c", FilePath: "internal/store/metadata.go"}},
+	}
+
+	facets := ComputeFacets(results, []FacetSpec{{Name: "dir", Field: FacetFieldFilePath, PathDepth: 2}})
+
+	f := facets["dir"]
+	require.Len(t, f.Terms, 2)
+	assert.Equal(t, "internal/search", f.Terms[0].Value)
+	assert.Equal(t, 2, f.Terms[0].Count)
+	assert.Equal(t, "internal/store", f.Terms[1].Value)
+}
+
+func TestComputeFacets_ModifiedAtBucketsByMonthByDefault(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a", UpdatedAt: time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC)}},
+		{Chunk: &store.Chunk{ID: "b", UpdatedAt: time.Date(2026, 7, 25, 0, 0, 0, 0, time.UTC)}},
+		{Chunk: &store.Chunk{ID: "c", UpdatedAt: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	facets := ComputeFacets(results, []FacetSpec{{Field: FacetFieldModifiedAt}})
+
+	f := facets["modified_at"]
+	require.Len(t, f.Terms, 2)
+	assert.Equal(t, "2026-07", f.Terms[0].Value)
+	assert.Equal(t, 2, f.Terms[0].Count)
+}
+
+func TestComputeFacets_MetadataKeyFacet(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{ID: "a", Metadata: map[string]any{"owner": "amy"}}},
+		{Chunk: &store.Chunk{ID: "b", Metadata: map[string]any{"owner": "amy"}}},
+		{Chunk: &store.Chunk{ID: "c"}},
+	}
+
+	facets := ComputeFacets(results, []FacetSpec{{MetadataKey: "owner"}})
+
+	f := facets["meta:owner"]
+	require.Len(t, f.Terms, 1, "results missing the metadata key are excluded from the facet")
+	assert.Equal(t, "amy", f.Terms[0].Value)
+	assert.Equal(t, 2, f.Terms[0].Count)
+}
+
+func TestComputeFacets_EmptySpecsReturnsNil(t *testing.T) {
+	assert.Nil(t, ComputeFacets(nil, nil))
+}
@@ -0,0 +1,153 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// FederatedResult is a single hit from a Federation search, tagged with
+// the project it came from so a federated client can tell two chunks with
+// the same ID in different repos apart.
+type FederatedResult struct {
+	Project string
+	Result  *Result
+}
+
+// FederationMember is one project Federation fans a query out to: Engine
+// does the actual BM25/vector/rerank work, Name/Path identify which
+// project a hit came from for the list_projects tool and the search
+// tool's project filter.
+type FederationMember struct {
+	Name   string
+	Path   string
+	Engine *Engine
+}
+
+// Federation fans a single query out to every member engine concurrently
+// and merges the per-engine result lists with Reciprocal Rank Fusion,
+// the same way runPlan merges per-subquery results within one Engine -
+// each member's already-fused, already-reranked Result list is one more
+// ranked list to fuse, just like a SubQuery's. It exists so one MCP
+// server process can answer queries against several indexed project
+// directories instead of the current one-project-per-process model.
+type Federation struct {
+	members     []FederationMember
+	rrfConstant int
+}
+
+// NewFederation returns a Federation over members. rrfConstant is reused
+// from the same EngineConfig.RRFConstant each member engine was built
+// with, so cross-engine fusion uses the same k as fusion inside a single
+// engine; a zero value falls back to DefaultRRFConstant.
+func NewFederation(members []FederationMember, rrfConstant int) *Federation {
+	if rrfConstant <= 0 {
+		rrfConstant = DefaultRRFConstant
+	}
+	return &Federation{members: members, rrfConstant: rrfConstant}
+}
+
+// Projects lists the name of every federated member, for the
+// list_projects MCP tool.
+func (f *Federation) Projects() []string {
+	names := make([]string, len(f.members))
+	for i, m := range f.members {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Search runs query against every member (or, when project is non-empty,
+// only the member with that name) concurrently and returns a single
+// RRF-fused, project-tagged result list. A member whose Search call fails
+// is logged via onErr and excluded, rather than failing the whole
+// request, matching runPlan's graceful-degradation contract.
+func (f *Federation) Search(ctx context.Context, query string, opts SearchOptions, project string, onErr func(project string, err error)) ([]FederatedResult, error) {
+	members := f.members
+	if project != "" {
+		members = nil
+		for _, m := range f.members {
+			if m.Name == project {
+				members = append(members, m)
+			}
+		}
+		if len(members) == 0 {
+			return nil, fmt.Errorf("federation: unknown project %q", project)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, FederationSearchTimeout)
+	defer cancel()
+
+	perMember := make([][]*Result, len(members))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, m := range members {
+		i, m := i, m
+		g.Go(func() error {
+			results, err := m.Engine.Search(gctx, query, opts)
+			if err != nil {
+				if onErr != nil {
+					onErr(m.Name, err)
+				}
+				return nil
+			}
+			perMember[i] = results
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return f.fuse(members, perMember), nil
+}
+
+// fuse merges each member's already-ranked result list with RRF, scoring
+// a hit by its rank within its own member's list - cross-member score
+// magnitudes aren't comparable, so rank is the only signal fusion can
+// use across engines with potentially different embedders/weights.
+func (f *Federation) fuse(members []FederationMember, perMember [][]*Result) []FederatedResult {
+	type scored struct {
+		tagged FederatedResult
+		score  float64
+	}
+	var all []scored
+	for i, results := range perMember {
+		for rank, r := range results {
+			all = append(all, scored{
+				tagged: FederatedResult{Project: members[i].Name, Result: r},
+				score:  1.0 / float64(f.rrfConstant+rank+1),
+			})
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].score > all[j].score })
+
+	out := make([]FederatedResult, len(all))
+	for i, s := range all {
+		out[i] = s.tagged
+	}
+	return out
+}
+
+// FederationMemberConfig is one entry of the `serve --projects file.yaml`
+// manifest (or a `sessions.federation` config block): the project's
+// display name, its root path, and the named session (if any) whose data
+// directory holds its index.
+type FederationMemberConfig struct {
+	Name    string `yaml:"name"`
+	Path    string `yaml:"path"`
+	Session string `yaml:"session,omitempty"`
+}
+
+// FederationConfig is the manifest `serve --projects` loads: one entry
+// per project to host in this server process.
+type FederationConfig struct {
+	Projects []FederationMemberConfig `yaml:"projects"`
+}
+
+// FederationSearchTimeout bounds how long Federation.Search waits for the
+// slowest member before returning whatever succeeded, mirroring
+// EngineConfig.SearchTimeout for a single engine.
+const FederationSearchTimeout = 10 * time.Second
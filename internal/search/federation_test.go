@@ -0,0 +1,47 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+func TestFederation_Projects(t *testing.T) {
+	f := NewFederation([]FederationMember{
+		{Name: "api", Path: "/repos/api"},
+		{Name: "web", Path: "/repos/web"},
+	}, 0)
+
+	assert.Equal(t, []string{"api", "web"}, f.Projects())
+}
+
+func TestFederation_Fuse_InterleavesByRank(t *testing.T) {
+	f := NewFederation([]FederationMember{{Name: "api"}, {Name: "web"}}, 60)
+
+	perMember := [][]*Result{
+		{{Chunk: &store.Chunk{ID: "api-1"}}, {Chunk: &store.Chunk{ID: "api-2"}}},
+		{{Chunk: &store.Chunk{ID: "web-1"}}},
+	}
+
+	out := f.fuse(f.members, perMember)
+
+	assert.Len(t, out, 3)
+	// Rank-0 hits from both members tie on score; rank-1 ("api-2") scores lower.
+	assert.Equal(t, "api-2", out[2].Result.Chunk.ID)
+	assert.Equal(t, "api", out[0].Project)
+}
+
+func TestFederation_Fuse_EmptyWhenNoMemberSucceeded(t *testing.T) {
+	f := NewFederation([]FederationMember{{Name: "api"}}, 60)
+
+	out := f.fuse(f.members, [][]*Result{nil})
+
+	assert.Empty(t, out)
+}
+
+func TestNewFederation_DefaultsRRFConstant(t *testing.T) {
+	f := NewFederation(nil, 0)
+	assert.Equal(t, DefaultRRFConstant, f.rrfConstant)
+}
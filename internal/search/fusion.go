@@ -0,0 +1,108 @@
+package search
+
+import "sort"
+
+// FusionStrategy combines ranked BM25 and vector result lists into one
+// fused ranking, pluggable so a caller can pick RRFFusion or a
+// weighted-score merge. Nothing in this checkout dispatches to a
+// FusionStrategy by name yet - there's no search.Engine/EngineConfig here
+// (engine.go doesn't exist in this tree) to own that dispatch, so this is
+// wired up by whatever constructs a FusionStrategy directly for now.
+type FusionStrategy interface {
+	// Fuse merges bm25 and vector result lists (each already sorted best
+	// first) into a single ranking.
+	Fuse(bm25, vector []*fusedResult, weights Weights) []*fusedResult
+}
+
+// RRFFusion implements Reciprocal Rank Fusion: score = sum over lists of
+// 1/(k + rank). It only depends on rank, not raw scores, which makes it
+// robust to BM25 and vector scores living on wildly different scales.
+type RRFFusion struct {
+	// K is the RRF constant; defaults to DefaultRRFConstant when <= 0.
+	K int
+}
+
+// NewRRFFusion returns an RRFFusion with the given constant (0 for the
+// default of 60).
+func NewRRFFusion(k int) *RRFFusion {
+	return &RRFFusion{K: k}
+}
+
+// Fuse implements FusionStrategy.
+func (f *RRFFusion) Fuse(bm25, vector []*fusedResult, weights Weights) []*fusedResult {
+	k := f.K
+	if k <= 0 {
+		k = DefaultRRFConstant
+	}
+
+	byID := make(map[string]*fusedResult)
+	scores := make(map[string]float64)
+
+	apply := func(list []*fusedResult, weight float64) {
+		for rank, r := range list {
+			if _, ok := byID[r.chunkID]; !ok {
+				clone := *r
+				byID[r.chunkID] = &clone
+			}
+			scores[r.chunkID] += weight * rrfTerm(rank+1, k)
+		}
+	}
+	apply(bm25, weights.BM25OrDefault())
+	apply(vector, weights.SemanticOrDefault())
+
+	out := make([]*fusedResult, 0, len(byID))
+	for id, r := range byID {
+		r.rrfScore = scores[id]
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].rrfScore > out[j].rrfScore })
+	return out
+}
+
+// WeightedSumFusion implements the older weighted raw-score merge, kept
+// for callers that depend on score magnitude (e.g. a score threshold)
+// rather than pure rank.
+type WeightedSumFusion struct{}
+
+// Fuse implements FusionStrategy.
+func (WeightedSumFusion) Fuse(bm25, vector []*fusedResult, weights Weights) []*fusedResult {
+	byID := make(map[string]*fusedResult)
+	scores := make(map[string]float64)
+
+	apply := func(list []*fusedResult, weight float64, scoreOf func(*fusedResult) float64) {
+		for _, r := range list {
+			if _, ok := byID[r.chunkID]; !ok {
+				clone := *r
+				byID[r.chunkID] = &clone
+			}
+			scores[r.chunkID] += weight * scoreOf(r)
+		}
+	}
+	apply(bm25, weights.BM25OrDefault(), func(r *fusedResult) float64 { return r.bm25Score })
+	apply(vector, weights.SemanticOrDefault(), func(r *fusedResult) float64 { return r.vecScore })
+
+	out := make([]*fusedResult, 0, len(byID))
+	for id, r := range byID {
+		r.rrfScore = scores[id]
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].rrfScore > out[j].rrfScore })
+	return out
+}
+
+// BM25OrDefault returns w.BM25, or 0.5 if both weights are zero (an
+// unconfigured Weights acts as an even split).
+func (w Weights) BM25OrDefault() float64 {
+	if w.BM25 == 0 && w.Semantic == 0 {
+		return 0.5
+	}
+	return w.BM25
+}
+
+// SemanticOrDefault mirrors BM25OrDefault for the semantic weight.
+func (w Weights) SemanticOrDefault() float64 {
+	if w.BM25 == 0 && w.Semantic == 0 {
+		return 0.5
+	}
+	return w.Semantic
+}
@@ -0,0 +1,48 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRRFFusion_RanksByReciprocalRank(t *testing.T) {
+	bm25 := []*fusedResult{{chunkID: "a"}, {chunkID: "b"}}
+	vector := []*fusedResult{{chunkID: "b"}, {chunkID: "a"}}
+
+	fused := NewRRFFusion(60).Fuse(bm25, vector, Weights{BM25: 0.5, Semantic: 0.5})
+
+	require.Len(t, fused, 2)
+	// Both appear at rank 1 and rank 2 across the two lists with equal
+	// weights, so they tie. Fuse builds its output from a map and sorts it
+	// with sort.Slice, so the order of tied results is not defined; just
+	// assert the two scores are equal.
+	assert.InDelta(t, fused[0].rrfScore, fused[1].rrfScore, 1e-9)
+}
+
+func TestRRFFusion_WeightsSkewRanking(t *testing.T) {
+	bm25 := []*fusedResult{{chunkID: "a"}}
+	vector := []*fusedResult{{chunkID: "b"}}
+
+	fused := NewRRFFusion(60).Fuse(bm25, vector, Weights{BM25: 0.9, Semantic: 0.1})
+
+	require.Len(t, fused, 2)
+	assert.Equal(t, "a", fused[0].chunkID)
+}
+
+func TestWeightedSumFusion_UsesRawScores(t *testing.T) {
+	bm25 := []*fusedResult{{chunkID: "a", bm25Score: 10}}
+	vector := []*fusedResult{{chunkID: "b", vecScore: 0.99}}
+
+	fused := WeightedSumFusion{}.Fuse(bm25, vector, Weights{BM25: 1.0, Semantic: 1.0})
+
+	require.Len(t, fused, 2)
+	assert.Equal(t, "a", fused[0].chunkID, "raw BM25 score of 10 dwarfs a 0-1 vector score")
+}
+
+func TestWeights_DefaultsToEvenSplitWhenUnset(t *testing.T) {
+	w := Weights{}
+	assert.Equal(t, 0.5, w.BM25OrDefault())
+	assert.Equal(t, 0.5, w.SemanticOrDefault())
+}
@@ -0,0 +1,193 @@
+package search
+
+import "strings"
+
+// DefaultFuzzyMaxEdits is the default Levenshtein distance allowed
+// between a query term and a dictionary term for FuzzyMatch.
+const DefaultFuzzyMaxEdits = 2
+
+// FuzzyContext buckets fuzzy matches by where they occurred, so a caller
+// asking "typo-tolerant search over symbol names" doesn't get drowned out
+// by comment/doc matches and vice versa.
+type FuzzyContext string
+
+const (
+	// FuzzyContextSymbol matches against identifier/symbol names.
+	FuzzyContextSymbol FuzzyContext = "symbol"
+	// FuzzyContextIdentifier matches against free-standing identifiers
+	// (variables, parameters) that aren't indexed as symbols.
+	FuzzyContextIdentifier FuzzyContext = "identifier"
+	// FuzzyContextContent matches against general chunk content/comments.
+	FuzzyContextContent FuzzyContext = "content"
+)
+
+// FuzzyMatch is a single typo-tolerant term match within one context
+// bucket.
+type FuzzyMatch struct {
+	Term    string
+	Context FuzzyContext
+	Edits   int
+}
+
+// FuzzyResultBucket groups fuzzy matches for one context, so callers can
+// render "Symbols (3)" / "Content (12)" sections instead of one flat list.
+// Total may exceed len(Matches) when MaxPerContext truncated the bucket.
+type FuzzyResultBucket struct {
+	Context   FuzzyContext
+	Matches   []FuzzyMatch
+	Total     int
+	Truncated bool
+}
+
+// FuzzyOptions configures a fuzzy search pass.
+type FuzzyOptions struct {
+	// MaxEdits is the maximum edit distance a term may be from the query
+	// term to count as a match. Defaults to DefaultFuzzyMaxEdits.
+	MaxEdits int
+	// Contexts restricts matching to the given buckets; empty means all.
+	Contexts []FuzzyContext
+	// MaxPerContext caps how many matches are returned per bucket; the
+	// bucket still reports Total and Truncated so a caller can surface
+	// "12 more symbol matches" instead of silently dropping them. Zero
+	// means unbounded.
+	MaxPerContext int
+}
+
+// FuzzySearchResult is the outcome of a FuzzySearch call: the per-context
+// buckets plus an overall truncation flag for callers that just want a
+// single "results were truncated" banner. Like FuzzySearch itself, nothing
+// in this checkout surfaces Truncated to an end user yet - that's for
+// whatever eventually calls FuzzySearch to report.
+type FuzzySearchResult struct {
+	Buckets   []FuzzyResultBucket
+	Truncated bool
+}
+
+// FuzzySearch matches query against dict (terms grouped by the context
+// they appeared in) using bounded edit distance, returning one bucket per
+// context that had at least one match, each sorted by increasing edit
+// distance and capped at MaxPerContext with truncation reported rather
+// than silently dropped. A multi-word dict entry (e.g. a content phrase)
+// matches when any of its whitespace-separated words is within MaxEdits;
+// its Edits is the smallest such word distance.
+//
+// It's a standalone pass over a caller-supplied dict, not yet a fallback
+// a real search path reaches for automatically - there's no
+// search.Engine in this checkout (engine.go doesn't exist here) to call
+// it when a primary BM25/vector search comes back empty.
+func FuzzySearch(query string, dict map[FuzzyContext][]string, opts FuzzyOptions) FuzzySearchResult {
+	maxEdits := opts.MaxEdits
+	if maxEdits <= 0 {
+		maxEdits = DefaultFuzzyMaxEdits
+	}
+	allowed := func(c FuzzyContext) bool {
+		if len(opts.Contexts) == 0 {
+			return true
+		}
+		for _, want := range opts.Contexts {
+			if want == c {
+				return true
+			}
+		}
+		return false
+	}
+
+	query = strings.ToLower(query)
+	result := FuzzySearchResult{}
+	for _, ctx := range []FuzzyContext{FuzzyContextSymbol, FuzzyContextIdentifier, FuzzyContextContent} {
+		if !allowed(ctx) {
+			continue
+		}
+		terms := dict[ctx]
+		var matches []FuzzyMatch
+		for _, term := range terms {
+			dist := maxEdits + 1
+			for _, word := range strings.Fields(strings.ToLower(term)) {
+				if d := boundedEditDistance(query, word, maxEdits); d < dist {
+					dist = d
+				}
+			}
+			if dist <= maxEdits {
+				matches = append(matches, FuzzyMatch{Term: term, Context: ctx, Edits: dist})
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		sortFuzzyMatches(matches)
+
+		bucket := FuzzyResultBucket{Context: ctx, Total: len(matches)}
+		if opts.MaxPerContext > 0 && len(matches) > opts.MaxPerContext {
+			bucket.Matches = matches[:opts.MaxPerContext]
+			bucket.Truncated = true
+			result.Truncated = true
+		} else {
+			bucket.Matches = matches
+		}
+		result.Buckets = append(result.Buckets, bucket)
+	}
+	return result
+}
+
+func sortFuzzyMatches(matches []FuzzyMatch) {
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].Edits < matches[j-1].Edits; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+}
+
+// boundedEditDistance computes the Levenshtein distance between a and b,
+// but bails out early (returning max+1) once the running distance can no
+// longer land at or below max - this keeps a large dictionary scan cheap
+// since almost every candidate is rejected long before the full DP table
+// would be needed.
+func boundedEditDistance(a, b string, max int) int {
+	if abs(len(a)-len(b)) > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultMinFuzzyTermLength is the shortest query term FuzzyExpandQuery will
+// expand. Terms shorter than this are passed through unchanged, mirroring
+// Nomad's fuzzy search config: short terms (e.g. "id", "is") match almost
+// every dictionary entry within 1-2 edits, so expanding them buys typo
+// tolerance nothing and costs a full dictionary scan.
+const DefaultMinFuzzyTermLength = 4
+
+// DictionaryTerm is one entry in the BM25 term dictionary: the term itself
+// and how many chunks it appears in, used to break edit-distance ties in
+// favor of more common (and so more likely intended) terms.
+type DictionaryTerm struct {
+	Term      string
+	Frequency int
+}
+
+// FuzzyQueryOptions configures FuzzyExpandQuery.
+type FuzzyQueryOptions struct {
+	// MaxEdits is the maximum edit distance a dictionary term may be from a
+	// query term to count as an expansion candidate. Defaults to
+	// DefaultFuzzyMaxEdits.
+	MaxEdits int
+	// MinTermLength is the shortest query term that gets expanded; shorter
+	// terms are returned as their own sole, zero-edit expansion. Defaults to
+	// DefaultMinFuzzyTermLength.
+	MinTermLength int
+}
+
+// ExpandedTerm is one dictionary term a query term expanded to.
+type ExpandedTerm struct {
+	Term      string
+	Edits     int
+	Frequency int
+	// PrefixMatch is true when Term starts with the original query term, a
+	// strong signal the user was typing the right word and just stopped (or
+	// mistyped) partway through.
+	PrefixMatch bool
+}
+
+// FuzzyExpandQuery tokenizes query on whitespace and expands each term
+// (at least MinTermLength bytes long) to the dictionary terms within
+// MaxEdits, so the expanded terms can be OR'd into the existing BM25 query
+// instead of requiring an exact-token match. Expansions for each original
+// term are ordered by (edit distance, prefix match, frequency) so the
+// caller's query builder can cap how many variants it actually uses per
+// term without re-sorting.
+func FuzzyExpandQuery(query string, dict []DictionaryTerm, opts FuzzyQueryOptions) map[string][]ExpandedTerm {
+	maxEdits := opts.MaxEdits
+	if maxEdits <= 0 {
+		maxEdits = DefaultFuzzyMaxEdits
+	}
+	minLen := opts.MinTermLength
+	if minLen <= 0 {
+		minLen = DefaultMinFuzzyTermLength
+	}
+
+	expansions := make(map[string][]ExpandedTerm)
+	for _, term := range strings.Fields(query) {
+		term := strings.ToLower(term)
+		if len(term) < minLen {
+			expansions[term] = []ExpandedTerm{{Term: term, Edits: 0, PrefixMatch: true}}
+			continue
+		}
+		expansions[term] = expandTerm(term, dict, maxEdits)
+	}
+	return expansions
+}
+
+func expandTerm(term string, dict []DictionaryTerm, maxEdits int) []ExpandedTerm {
+	var matches []ExpandedTerm
+	for _, candidate := range dict {
+		lower := strings.ToLower(candidate.Term)
+		dist := boundedEditDistance(term, lower, maxEdits)
+		if dist > maxEdits {
+			continue
+		}
+		matches = append(matches, ExpandedTerm{
+			Term:        candidate.Term,
+			Edits:       dist,
+			Frequency:   candidate.Frequency,
+			PrefixMatch: strings.HasPrefix(lower, term),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Edits != b.Edits {
+			return a.Edits < b.Edits
+		}
+		if a.PrefixMatch != b.PrefixMatch {
+			return a.PrefixMatch
+		}
+		return a.Frequency > b.Frequency
+	})
+	return matches
+}
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyExpandQuery_ShortTermsPassThroughUnexpanded(t *testing.T) {
+	dict := []DictionaryTerm{{Term: "id", Frequency: 10}, {Term: "ids", Frequency: 5}}
+
+	got := FuzzyExpandQuery("id", dict, FuzzyQueryOptions{MinTermLength: 4})
+
+	require.Len(t, got["id"], 1)
+	assert.Equal(t, "id", got["id"][0].Term)
+	assert.Equal(t, 0, got["id"][0].Edits)
+}
+
+func TestFuzzyExpandQuery_ExpandsWithinMaxEdits(t *testing.T) {
+	dict := []DictionaryTerm{
+		{Term: "login", Frequency: 3},
+		{Term: "logout", Frequency: 1},
+		{Term: "register", Frequency: 2},
+	}
+
+	got := FuzzyExpandQuery("logni", dict, FuzzyQueryOptions{MaxEdits: 2, MinTermLength: 3})
+
+	terms := got["logni"]
+	require.NotEmpty(t, terms)
+	assert.Equal(t, "login", terms[0].Term, "closest edit distance should sort first")
+	for _, e := range terms {
+		assert.NotEqual(t, "register", e.Term, "register is beyond MaxEdits and must not appear")
+	}
+}
+
+func TestFuzzyExpandQuery_OrdersByEditsThenPrefixThenFrequency(t *testing.T) {
+	dict := []DictionaryTerm{
+		{Term: "cache", Frequency: 1},
+		{Term: "caches", Frequency: 9},
+		{Term: "cached", Frequency: 2},
+	}
+
+	got := FuzzyExpandQuery("cache", dict, FuzzyQueryOptions{MaxEdits: 2, MinTermLength: 3})
+
+	terms := got["cache"]
+	require.Len(t, terms, 3)
+	assert.Equal(t, "cache", terms[0].Term, "exact match has the lowest edit distance")
+	assert.Equal(t, "caches", terms[1].Term, "both are one-edit prefix matches, so higher frequency breaks the tie over cached")
+	assert.Equal(t, "cached", terms[2].Term)
+}
+
+func TestFuzzyExpandQuery_TokenizesMultiTermQuery(t *testing.T) {
+	dict := []DictionaryTerm{{Term: "login", Frequency: 1}, {Term: "logout", Frequency: 1}}
+
+	got := FuzzyExpandQuery("logni logout", dict, FuzzyQueryOptions{MaxEdits: 2, MinTermLength: 3})
+
+	assert.Contains(t, got, "logni")
+	assert.Contains(t, got, "logout")
+}
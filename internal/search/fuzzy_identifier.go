@@ -0,0 +1,190 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// IdentifierContext scopes a fuzzy identifier search to one part of the
+// metadata store's identifier index.
+type IdentifierContext string
+
+const (
+	// IdentifierContextAll searches every context.
+	IdentifierContextAll IdentifierContext = "all"
+	// IdentifierContextSymbols searches symbol names.
+	IdentifierContextSymbols IdentifierContext = "symbols"
+	// IdentifierContextFiles searches file paths.
+	IdentifierContextFiles IdentifierContext = "files"
+	// IdentifierContextChunks searches free-standing chunk identifiers.
+	IdentifierContextChunks IdentifierContext = "chunks"
+	// IdentifierContextImports searches import/dependency paths, so a
+	// caller who half-remembers a package path can fuzzy-match it the
+	// same way they would a symbol or file.
+	IdentifierContextImports IdentifierContext = "imports"
+)
+
+// FuzzyIdentifierConfig tunes FuzzyIdentifierSearcher so operators can cap,
+// or disable, fuzzy identifier search the way large search backends gate
+// expensive query-time fuzzy work.
+type FuzzyIdentifierConfig struct {
+	// Enabled gates the whole feature; a disabled searcher returns an
+	// empty response rather than an error.
+	Enabled bool
+	// LimitQuery caps how many identifier-index entries are scanned per
+	// context, so a single query against a huge index can't blow up
+	// latency.
+	LimitQuery int
+	// LimitResults caps the total number of matches returned across all
+	// contexts combined.
+	LimitResults int
+	// MinTermLength is the shortest query text that gets matched; used
+	// when FuzzySearchOptions.MinTermLength is unset.
+	MinTermLength int
+}
+
+// DefaultFuzzyIdentifierConfig returns the tunables a default engine
+// config wires in: fuzzy search on, generous but bounded query/result
+// caps, and a MinTermLength that avoids expanding throwaway short terms.
+func DefaultFuzzyIdentifierConfig() FuzzyIdentifierConfig {
+	return FuzzyIdentifierConfig{
+		Enabled:       true,
+		LimitQuery:    10_000,
+		LimitResults:  200,
+		MinTermLength: DefaultMinFuzzyTermLength,
+	}
+}
+
+// IdentifierEntry is one entry in the metadata store's identifier index:
+// the ID it resolves to, the term matched against, and the scope path
+// leading to it (e.g. ["package", "file", "symbol"]).
+type IdentifierEntry struct {
+	ID    string
+	Term  string
+	Scope []string
+}
+
+// FuzzySearchOptions configures a FuzzyIdentifierSearcher.Search call.
+type FuzzySearchOptions struct {
+	Text            string
+	Context         IdentifierContext
+	MinTermLength   int
+	LimitPerContext int
+}
+
+// FuzzyIdentifierMatch is one identifier-index match.
+type FuzzyIdentifierMatch struct {
+	ID    string
+	Scope []string
+	Score float64
+	Edits int
+}
+
+// FuzzySearchResponse is the outcome of a FuzzyIdentifierSearcher.Search
+// call: matches bucketed by context, plus which contexts got truncated.
+type FuzzySearchResponse struct {
+	Matches     map[string][]FuzzyIdentifierMatch
+	Truncations map[string]bool
+}
+
+// FuzzyIdentifierSearcher runs bounded edit-distance matching against the
+// metadata store's identifier index (symbol names, file paths, free
+// identifiers), as opposed to FuzzySearch's exact-token BM25 dictionary
+// match. An Engine.FuzzySearch method and WithFuzzyMatcher option would own
+// one of these and delegate to it; it's kept standalone here so it has no
+// dependency on the rest of the hybrid pipeline.
+type FuzzyIdentifierSearcher struct {
+	cfg   FuzzyIdentifierConfig
+	index map[IdentifierContext][]IdentifierEntry
+}
+
+// NewFuzzyIdentifierSearcher builds a searcher over index, already grouped
+// by context.
+func NewFuzzyIdentifierSearcher(index map[IdentifierContext][]IdentifierEntry, cfg FuzzyIdentifierConfig) *FuzzyIdentifierSearcher {
+	if cfg.MinTermLength <= 0 {
+		cfg.MinTermLength = DefaultMinFuzzyTermLength
+	}
+	return &FuzzyIdentifierSearcher{cfg: cfg, index: index}
+}
+
+// Search matches opts.Text against the identifier index, bucketed by
+// context and ranked by (fewer edits, prefix match) via identifierMatchScore.
+func (s *FuzzyIdentifierSearcher) Search(opts FuzzySearchOptions) FuzzySearchResponse {
+	resp := FuzzySearchResponse{Matches: make(map[string][]FuzzyIdentifierMatch), Truncations: make(map[string]bool)}
+	if !s.cfg.Enabled {
+		return resp
+	}
+
+	minLen := opts.MinTermLength
+	if minLen <= 0 {
+		minLen = s.cfg.MinTermLength
+	}
+	if len(opts.Text) < minLen {
+		return resp
+	}
+
+	limitPerContext := opts.LimitPerContext
+	if limitPerContext <= 0 {
+		limitPerContext = s.cfg.LimitResults
+	}
+
+	text := strings.ToLower(opts.Text)
+	totalReturned := 0
+	for _, ctx := range identifierContextsFor(opts.Context) {
+		entries := s.index[ctx]
+		if s.cfg.LimitQuery > 0 && len(entries) > s.cfg.LimitQuery {
+			entries = entries[:s.cfg.LimitQuery]
+		}
+
+		var matches []FuzzyIdentifierMatch
+		for _, entry := range entries {
+			dist := boundedEditDistance(text, strings.ToLower(entry.Term), DefaultFuzzyMaxEdits)
+			if dist > DefaultFuzzyMaxEdits {
+				continue
+			}
+			matches = append(matches, FuzzyIdentifierMatch{
+				ID:    entry.ID,
+				Scope: entry.Scope,
+				Score: identifierMatchScore(dist, entry.Term, text),
+				Edits: dist,
+			})
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+
+		if limitPerContext > 0 && len(matches) > limitPerContext {
+			matches = matches[:limitPerContext]
+			resp.Truncations[string(ctx)] = true
+		}
+		if s.cfg.LimitResults > 0 && totalReturned+len(matches) > s.cfg.LimitResults {
+			allowed := s.cfg.LimitResults - totalReturned
+			if allowed < 0 {
+				allowed = 0
+			}
+			matches = matches[:allowed]
+			resp.Truncations[string(ctx)] = true
+		}
+		totalReturned += len(matches)
+		resp.Matches[string(ctx)] = matches
+	}
+	return resp
+}
+
+func identifierContextsFor(requested IdentifierContext) []IdentifierContext {
+	if requested == "" || requested == IdentifierContextAll {
+		return []IdentifierContext{IdentifierContextSymbols, IdentifierContextFiles, IdentifierContextChunks, IdentifierContextImports}
+	}
+	return []IdentifierContext{requested}
+}
+
+// identifierMatchScore ranks closer edit distances higher, with a bonus
+// for a literal prefix match (the user likely just stopped typing early).
+func identifierMatchScore(edits int, term, lowerQuery string) float64 {
+	score := 1.0 / float64(1+edits)
+	if strings.HasPrefix(strings.ToLower(term), lowerQuery) {
+		score += 0.5
+	}
+	return score
+}
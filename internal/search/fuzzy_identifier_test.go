@@ -0,0 +1,85 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testIdentifierIndex() map[IdentifierContext][]IdentifierEntry {
+	return map[IdentifierContext][]IdentifierEntry{
+		IdentifierContextSymbols: {
+			{ID: "sym1", Term: "NewEngine", Scope: []string{"search", "engine.go"}},
+			{ID: "sym2", Term: "NewEngin", Scope: []string{"search", "engine_old.go"}},
+		},
+		IdentifierContextFiles: {
+			{ID: "file1", Term: "internal/search/engine.go", Scope: []string{"search"}},
+		},
+		IdentifierContextChunks: {
+			{ID: "chunk1", Term: "chunk-engine-001", Scope: []string{"search"}},
+		},
+		IdentifierContextImports: {
+			{ID: "import1", Term: "golang-lru", Scope: []string{"search"}},
+		},
+	}
+}
+
+func TestFuzzyIdentifierSearcher_SweepsImportsContextByDefault(t *testing.T) {
+	s := NewFuzzyIdentifierSearcher(testIdentifierIndex(), DefaultFuzzyIdentifierConfig())
+
+	resp := s.Search(FuzzySearchOptions{Text: "golang-lru"})
+
+	require.Contains(t, resp.Matches, string(IdentifierContextImports))
+	assert.Equal(t, "import1", resp.Matches[string(IdentifierContextImports)][0].ID)
+}
+
+func TestFuzzyIdentifierSearcher_DisabledReturnsEmptyResponse(t *testing.T) {
+	cfg := DefaultFuzzyIdentifierConfig()
+	cfg.Enabled = false
+	s := NewFuzzyIdentifierSearcher(testIdentifierIndex(), cfg)
+
+	resp := s.Search(FuzzySearchOptions{Text: "NewEngine"})
+
+	assert.Empty(t, resp.Matches)
+	assert.Empty(t, resp.Truncations)
+}
+
+func TestFuzzyIdentifierSearcher_MatchesAcrossAllContextsByDefault(t *testing.T) {
+	s := NewFuzzyIdentifierSearcher(testIdentifierIndex(), DefaultFuzzyIdentifierConfig())
+
+	resp := s.Search(FuzzySearchOptions{Text: "NewEngine"})
+
+	require.Contains(t, resp.Matches, string(IdentifierContextSymbols))
+	symbols := resp.Matches[string(IdentifierContextSymbols)]
+	require.Len(t, symbols, 2)
+	assert.Equal(t, "sym1", symbols[0].ID, "exact match should outrank the one-edit match")
+	assert.Equal(t, 0, symbols[0].Edits)
+}
+
+func TestFuzzyIdentifierSearcher_ScopesToRequestedContext(t *testing.T) {
+	s := NewFuzzyIdentifierSearcher(testIdentifierIndex(), DefaultFuzzyIdentifierConfig())
+
+	resp := s.Search(FuzzySearchOptions{Text: "NewEngine", Context: IdentifierContextFiles})
+
+	assert.Len(t, resp.Matches, 0, "no file term is close enough to match")
+	assert.NotContains(t, resp.Matches, string(IdentifierContextSymbols))
+}
+
+func TestFuzzyIdentifierSearcher_ShortTextBelowMinTermLengthIsSkipped(t *testing.T) {
+	s := NewFuzzyIdentifierSearcher(testIdentifierIndex(), DefaultFuzzyIdentifierConfig())
+
+	resp := s.Search(FuzzySearchOptions{Text: "ab"})
+
+	assert.Empty(t, resp.Matches)
+}
+
+func TestFuzzyIdentifierSearcher_LimitPerContextTruncatesAndFlags(t *testing.T) {
+	cfg := DefaultFuzzyIdentifierConfig()
+	s := NewFuzzyIdentifierSearcher(testIdentifierIndex(), cfg)
+
+	resp := s.Search(FuzzySearchOptions{Text: "NewEngine", Context: IdentifierContextSymbols, LimitPerContext: 1})
+
+	require.Len(t, resp.Matches[string(IdentifierContextSymbols)], 1)
+	assert.True(t, resp.Truncations[string(IdentifierContextSymbols)])
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzySearch_MatchesWithinEditDistance(t *testing.T) {
+	dict := map[FuzzyContext][]string{
+		FuzzyContextSymbol: {"Login", "Logout", "getUser"},
+	}
+
+	result := FuzzySearch("Logni", dict, FuzzyOptions{MaxEdits: 2})
+
+	require.Len(t, result.Buckets, 1)
+	assert.Equal(t, FuzzyContextSymbol, result.Buckets[0].Context)
+	assert.Equal(t, "Login", result.Buckets[0].Matches[0].Term)
+}
+
+func TestFuzzySearch_BucketsByContext(t *testing.T) {
+	dict := map[FuzzyContext][]string{
+		FuzzyContextSymbol:  {"Login"},
+		FuzzyContextContent: {"login flow documentation"},
+	}
+
+	result := FuzzySearch("Login", dict, FuzzyOptions{})
+
+	require.Len(t, result.Buckets, 2)
+}
+
+func TestFuzzySearch_RestrictsToRequestedContexts(t *testing.T) {
+	dict := map[FuzzyContext][]string{
+		FuzzyContextSymbol:  {"Login"},
+		FuzzyContextContent: {"Login"},
+	}
+
+	result := FuzzySearch("Login", dict, FuzzyOptions{Contexts: []FuzzyContext{FuzzyContextSymbol}})
+
+	require.Len(t, result.Buckets, 1)
+	assert.Equal(t, FuzzyContextSymbol, result.Buckets[0].Context)
+}
+
+func TestFuzzySearch_NoMatchesBeyondMaxEdits(t *testing.T) {
+	dict := map[FuzzyContext][]string{
+		FuzzyContextSymbol: {"Login"},
+	}
+
+	result := FuzzySearch("CompletelyDifferent", dict, FuzzyOptions{MaxEdits: 2})
+
+	assert.Empty(t, result.Buckets)
+	assert.False(t, result.Truncated)
+}
+
+func TestFuzzySearch_TruncatesAndReportsOverflow(t *testing.T) {
+	dict := map[FuzzyContext][]string{
+		FuzzyContextSymbol: {"Login", "Logan", "Logun", "Logon"},
+	}
+
+	result := FuzzySearch("Login", dict, FuzzyOptions{MaxEdits: 2, MaxPerContext: 2})
+
+	require.Len(t, result.Buckets, 1)
+	bucket := result.Buckets[0]
+	assert.Len(t, bucket.Matches, 2)
+	assert.Equal(t, 4, bucket.Total)
+	assert.True(t, bucket.Truncated)
+	assert.True(t, result.Truncated)
+}
+
+func TestBoundedEditDistance_ExactMatch(t *testing.T) {
+	assert.Equal(t, 0, boundedEditDistance("login", "login", 2))
+}
+
+func TestBoundedEditDistance_ExceedsBoundEarlyExit(t *testing.T) {
+	got := boundedEditDistance("short", "a-much-longer-string-entirely", 2)
+	assert.Equal(t, 3, got) // max+1 sentinel
+}
@@ -0,0 +1,221 @@
+package search
+
+import (
+	"sort"
+	"time"
+)
+
+// MissingPolicy controls where a result with no value for a sort field
+// lands relative to results that do have one.
+type MissingPolicy string
+
+const (
+	// MissingLast sorts missing values after every present value. This is
+	// the default when SortField.Missing is unset.
+	MissingLast MissingPolicy = "last"
+	// MissingFirst sorts missing values before every present value.
+	MissingFirst MissingPolicy = "first"
+)
+
+// SortField is one key in a multi-key SearchOptions.SortBy. Keys are
+// applied in the order given: a tie on the first field is broken by the
+// second, and so on, with any residual tie falling back to score.
+type SortField struct {
+	// By names a built-in field (SortByPath, SortByModifiedAt, ...). Leave
+	// zero and set MetadataKey to sort by a Chunk.Metadata entry instead.
+	By SortKey
+	// MetadataKey, when non-empty, sorts by Chunk.Metadata[MetadataKey]
+	// instead of a built-in field.
+	MetadataKey string
+	// Desc sorts the field descending; without it every field, score
+	// included, sorts ascending.
+	Desc bool
+	// Missing decides where results lacking this field land. Defaults to
+	// MissingLast.
+	Missing MissingPolicy
+}
+
+// String renders f as it would appear in an explain trace, e.g.
+// "path desc" or "meta:owner asc".
+func (f SortField) String() string {
+	name := string(f.By)
+	switch {
+	case f.MetadataKey != "":
+		name = "meta:" + f.MetadataKey
+	case name == "":
+		name = "score"
+	}
+	if f.Desc {
+		return name + " desc"
+	}
+	return name + " asc"
+}
+
+// MultiSort reorders results in place by fields, most-significant key
+// first, breaking any remaining tie by score descending. Stable, so equal
+// results keep their relative (post-fusion) order.
+func MultiSort(results []*SearchResult, fields []SortField) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	applied := make([]string, len(fields))
+	for i, f := range fields {
+		applied[i] = f.String()
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		for _, f := range fields {
+			if cmp := compareField(results[i], results[j], f); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return results[i].Score > results[j].Score
+	})
+	return applied
+}
+
+// compareField returns <0, 0, or >0 ordering a before/at/after b for f,
+// already accounting for Desc. Missing-value placement is controlled
+// solely by f.Missing, independent of Desc - "missing last" always means
+// last, whichever direction present values sort in.
+func compareField(a, b *SearchResult, f SortField) int {
+	av, aok := sortFieldValue(a, f)
+	bv, bok := sortFieldValue(b, f)
+	if !aok && !bok {
+		return 0
+	}
+	if !aok || !bok {
+		missingFirst := f.Missing == MissingFirst
+		if !aok {
+			if missingFirst {
+				return -1
+			}
+			return 1
+		}
+		if missingFirst {
+			return 1
+		}
+		return -1
+	}
+	cmp := compareSortValues(av, bv)
+	if f.Desc {
+		return -cmp
+	}
+	return cmp
+}
+
+func sortFieldValue(r *SearchResult, f SortField) (any, bool) {
+	if f.MetadataKey != "" {
+		if r.Chunk == nil || r.Chunk.Metadata == nil {
+			return nil, false
+		}
+		v, ok := r.Chunk.Metadata[f.MetadataKey]
+		return v, ok
+	}
+
+	switch f.By {
+	case SortByScore:
+		return r.Score, true
+	case SortByPath:
+		if r.Chunk == nil {
+			return nil, false
+		}
+		return r.Chunk.FilePath, true
+	case SortByLines:
+		if r.Chunk == nil {
+			return nil, false
+		}
+		return linesOf(r), true
+	case SortByStartLine:
+		if r.Chunk == nil {
+			return nil, false
+		}
+		return r.Chunk.StartLine, true
+	case SortByModifiedAt:
+		if r.Chunk == nil || r.Chunk.UpdatedAt.IsZero() {
+			return nil, false
+		}
+		return r.Chunk.UpdatedAt, true
+	case SortByLanguage:
+		if r.Chunk == nil || r.Chunk.Language == "" {
+			return nil, false
+		}
+		return r.Chunk.Language, true
+	default:
+		return nil, false
+	}
+}
+
+// compareSortValues compares two values pulled from sortFieldValue, which
+// are always the same concrete type for a given field. Unsupported types
+// (e.g. a metadata value that's a JSON object) compare equal rather than
+// panicking, so a malformed field just falls through to the next sort key.
+func compareSortValues(a, b any) int {
+	switch av := a.(type) {
+	case string:
+		bv, _ := b.(string)
+		return stringCmp(av, bv)
+	case int:
+		bv, _ := b.(int)
+		return intCmp(av, bv)
+	case float64:
+		bv, ok := toFloat64(b)
+		if !ok {
+			return 0
+		}
+		return float64Cmp(av, bv)
+	case time.Time:
+		bv, ok := b.(time.Time)
+		if !ok {
+			return 0
+		}
+		switch {
+		case av.Before(bv):
+			return -1
+		case av.After(bv):
+			return 1
+		default:
+			return 0
+		}
+	default:
+		return 0
+	}
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func stringCmp(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func intCmp(a, b int) int {
+	return a - b
+}
+
+func float64Cmp(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
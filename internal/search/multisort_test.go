@@ -0,0 +1,79 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+func TestMultiSort_OrdersByFirstKeyThenBreaksTiesBySecond(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{FilePath: "pkg/b.go", Language: "go"}, Score: 0.5},
+		{Chunk: &store.Chunk{FilePath: "pkg/a.go", Language: "go"}, Score: 0.9},
+		{Chunk: &store.Chunk{FilePath: "pkg/a.go", Language: "go"}, Score: 0.1},
+	}
+
+	applied := MultiSort(results, []SortField{{By: SortByPath}, {By: SortByScore, Desc: true}})
+
+	require.Equal(t, []string{"path asc", "score desc"}, applied)
+	assert.Equal(t, 0.9, results[0].Score, "pkg/a.go entries sort first, highest score of the two first")
+	assert.Equal(t, 0.1, results[1].Score)
+	assert.Equal(t, "pkg/b.go", results[2].Chunk.FilePath)
+}
+
+func TestMultiSort_MissingLastIsDefault(t *testing.T) {
+	now := time.Now()
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{FilePath: "a.go"}, Score: 1},
+		{Chunk: &store.Chunk{FilePath: "b.go", UpdatedAt: now}, Score: 1},
+	}
+
+	MultiSort(results, []SortField{{By: SortByModifiedAt, Desc: true}})
+
+	assert.Equal(t, "b.go", results[0].Chunk.FilePath, "the result with a modified time sorts before the one without")
+}
+
+func TestMultiSort_MissingFirstPolicy(t *testing.T) {
+	now := time.Now()
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{FilePath: "b.go", UpdatedAt: now}, Score: 1},
+		{Chunk: &store.Chunk{FilePath: "a.go"}, Score: 1},
+	}
+
+	MultiSort(results, []SortField{{By: SortByModifiedAt, Desc: true, Missing: MissingFirst}})
+
+	assert.Equal(t, "a.go", results[0].Chunk.FilePath)
+}
+
+func TestMultiSort_SortsByMetadataKey(t *testing.T) {
+	results := []*SearchResult{
+		{Chunk: &store.Chunk{FilePath: "b.go", Metadata: map[string]any{"owner": "zoe"}}, Score: 1},
+		{Chunk: &store.Chunk{FilePath: "a.go", Metadata: map[string]any{"owner": "amy"}}, Score: 1},
+	}
+
+	MultiSort(results, []SortField{{MetadataKey: "owner"}})
+
+	assert.Equal(t, "a.go", results[0].Chunk.FilePath)
+}
+
+func TestMultiSort_EmptyFieldsIsNoOp(t *testing.T) {
+	results := []*SearchResult{resultFor("b.go", 1, 2), resultFor("a.go", 1, 2)}
+
+	applied := MultiSort(results, nil)
+
+	assert.Nil(t, applied)
+	assert.Equal(t, "b.go", results[0].Chunk.FilePath)
+}
+
+func TestAnnotateSortKeys_StampsEveryExplanation(t *testing.T) {
+	explanations := []*ScoreExplanation{{ChunkID: "a"}, {ChunkID: "b"}}
+
+	annotateSortKeys(explanations, []string{"path asc"})
+
+	assert.Equal(t, []string{"path asc"}, explanations[0].SortKeys)
+	assert.Equal(t, []string{"path asc"}, explanations[1].SortKeys)
+}
@@ -0,0 +1,152 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sync"
+)
+
+// ErrRegexpTooComplex is returned when compiling TermPattern would require
+// more DFA states than RegexpDFALimit allows. Callers should fall back to
+// a plain term/BM25 query rather than paying for a pathological pattern.
+var ErrRegexpTooComplex = errors.New("search: regexp pattern exceeds DFA state limit")
+
+// DefaultRegexpDFALimit bounds the number of states a compiled term pattern
+// may expand to before SearchPattern gives up and returns
+// ErrRegexpTooComplex.
+const DefaultRegexpDFALimit = 10_000
+
+// termDFA is a compiled pattern over the BM25 dictionary. The syntax.Prog
+// is built purely to count states up front and reject pathological
+// patterns before doing any real matching work; the regexp.Regexp does the
+// actual per-term membership test.
+type termDFA struct {
+	pattern string
+	re      *regexp.Regexp
+	states  int
+}
+
+// compileTermPattern parses pattern as a regular expression and bounds its
+// state count, failing fast with ErrRegexpTooComplex before a single term
+// is ever tested against it.
+func compileTermPattern(pattern string, limit int) (*termDFA, error) {
+	if limit <= 0 {
+		limit = DefaultRegexpDFALimit
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("search: invalid term pattern %q: %w", pattern, err)
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("search: compile term pattern %q: %w", pattern, err)
+	}
+	if len(prog.Inst) > limit {
+		return nil, ErrRegexpTooComplex
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("search: compile term pattern %q: %w", pattern, err)
+	}
+
+	return &termDFA{pattern: pattern, re: re, states: len(prog.Inst)}, nil
+}
+
+func (d *termDFA) accepts(term string) bool {
+	return d.re.MatchString(term)
+}
+
+// matchTerms enumerates the terms in dict that the compiled pattern
+// accepts. dict is the BM25 dictionary's term list; callers union the
+// posting lists of every matching term to build the candidate set.
+func matchTerms(dfa *termDFA, dict []string) []string {
+	matched := make([]string, 0, len(dict))
+	for _, term := range dict {
+		if dfa.accepts(term) {
+			matched = append(matched, term)
+		}
+	}
+	return matched
+}
+
+// dfaCache is a bounded LRU of compiled patterns, keyed by pattern string,
+// so repeated `TermPattern` queries within a session recompile nothing.
+type dfaCache struct {
+	mu       sync.Mutex
+	order    []string
+	entries  map[string]*termDFA
+	capacity int
+}
+
+// newDFACache creates a cache holding up to capacity compiled patterns.
+func newDFACache(capacity int) *dfaCache {
+	if capacity <= 0 {
+		capacity = 256
+	}
+	return &dfaCache{capacity: capacity, entries: make(map[string]*termDFA)}
+}
+
+func (c *dfaCache) get(pattern string, limit int) (*termDFA, error) {
+	c.mu.Lock()
+	if dfa, ok := c.entries[pattern]; ok {
+		for i, p := range c.order {
+			if p == pattern {
+				c.order = append(append(c.order[:i:i], c.order[i+1:]...), pattern)
+				break
+			}
+		}
+		c.mu.Unlock()
+		return dfa, nil
+	}
+	c.mu.Unlock()
+
+	dfa, err := compileTermPattern(pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.entries[pattern]; ok {
+		return existing, nil
+	}
+	if len(c.order) >= c.capacity {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.entries, oldest)
+	}
+	c.entries[pattern] = dfa
+	c.order = append(c.order, pattern)
+	return dfa, nil
+}
+
+// globalDFACache backs SearchOptions.TermPattern compilation across
+// queries in a process, keyed by pattern string.
+var globalDFACache = newDFACache(256)
+
+// RegexpDFALimit is the default ceiling on compiled term-pattern states.
+// A per-engine override (e.g. EngineConfig.RegexpDFALimit) would shadow
+// this, but there's no search.Engine in this checkout (engine.go doesn't
+// exist here) to define one, so RegexpDFALimit is the only knob today.
+var RegexpDFALimit = DefaultRegexpDFALimit
+
+// SearchPattern resolves pattern against dict (the BM25 term dictionary)
+// using the shared DFA cache, returning ErrRegexpTooComplex without
+// touching the vector path if the pattern is too expensive to compile.
+// It's only called from this package's own tests so far, but - unlike
+// most of the standalone pieces in this package - it doesn't need a
+// search.Engine to be useful: it just needs a caller with a dict. dfaCache
+// is a distinct, pattern-keyed LRU and is not a duplicate of ResultCache -
+// this package's other caches (QueryCache, QueryPlanCache, CandidateCache)
+// have all been retired in favor of ResultCache, keeping only their
+// key-building functions (QueryResultKey, QueryPlanKey, CandidateCacheKey).
+func SearchPattern(pattern string, dict []string, limit int) ([]string, error) {
+	dfa, err := globalDFACache.get(pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	return matchTerms(dfa, dict), nil
+}
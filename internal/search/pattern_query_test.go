@@ -0,0 +1,53 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchPattern_SimpleFuncPattern(t *testing.T) {
+	dict := []string{"Login", "LoginHandler", "Logout", "getUser", "UserLoginAttempt"}
+
+	matched, err := SearchPattern(`.*Login.*`, dict, 0)
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Login", "LoginHandler", "UserLoginAttempt"}, matched)
+}
+
+func TestSearchPattern_TooComplexReturnsTypedError(t *testing.T) {
+	// A deeply nested repetition explodes the compiled program well past a
+	// tiny limit, without needing an absurdly long literal pattern.
+	pathological := "(a?){200}a{200}"
+
+	_, err := SearchPattern(pathological, []string{"aaaa"}, 50)
+
+	assert.ErrorIs(t, err, ErrRegexpTooComplex)
+}
+
+func TestSearchPattern_InvalidPatternReturnsError(t *testing.T) {
+	_, err := SearchPattern("(unterminated", []string{"x"}, 0)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrRegexpTooComplex)
+}
+
+func TestDFACache_CompilesOnceAndReuses(t *testing.T) {
+	cache := newDFACache(8)
+
+	dfa1, err := cache.get(`Log.*`, 0)
+	require.NoError(t, err)
+	dfa2, err := cache.get(`Log.*`, 0)
+	require.NoError(t, err)
+
+	assert.Same(t, dfa1, dfa2, "second lookup should reuse the cached compilation")
+}
+
+func TestSearchPattern_NarrowsDictionaryToMatchingTerms(t *testing.T) {
+	// A pattern search narrows the term dictionary to the matching terms;
+	// no postings or filter iterators are involved here.
+	matched, err := SearchPattern(`func.*`, []string{"funcA", "funcB", "other"}, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"funcA", "funcB"}, matched)
+}
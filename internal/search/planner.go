@@ -0,0 +1,233 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// errNoSubQueriesSucceeded is returned by runPlan when every sub-query in
+// the plan failed; a partial failure instead degrades gracefully and
+// returns whatever sub-queries did succeed.
+var errNoSubQueriesSucceeded = errors.New("search: all sub-queries failed")
+
+// DefaultRRFConstant is the k used in Reciprocal Rank Fusion when a
+// SubQuery or the engine config doesn't override it.
+const DefaultRRFConstant = 60
+
+// Planner turns a raw user query into a weighted set of SubQuery-ies that
+// can be executed concurrently and fused back together. It generalizes the
+// QueryDecomposer sketch: a Planner additionally assigns weights and is
+// free to produce a richer structure than a flat split.
+type Planner interface {
+	// Plan returns the sub-queries to execute for query. A single-element
+	// result with Weight 1.0 means "don't decompose".
+	Plan(ctx context.Context, query string) []SubQuery
+}
+
+// RulePlanner splits a query on conjunctions ("and"/"or"/",") and boosts
+// quoted phrases, without calling out to any external service.
+type RulePlanner struct {
+	// PhraseBoost multiplies the weight of a quoted sub-query. Defaults to
+	// 1.5 when zero.
+	PhraseBoost float64
+}
+
+// NewRulePlanner returns a RulePlanner with default boosting.
+func NewRulePlanner() *RulePlanner {
+	return &RulePlanner{PhraseBoost: 1.5}
+}
+
+// Plan implements Planner.
+func (p *RulePlanner) Plan(_ context.Context, query string) []SubQuery {
+	boost := p.PhraseBoost
+	if boost <= 0 {
+		boost = 1.5
+	}
+
+	parts := splitOnConjunctions(query)
+	if len(parts) <= 1 {
+		return []SubQuery{{Query: query, Weight: 1.0}}
+	}
+
+	subQueries := make([]SubQuery, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		weight := 1.0
+		if strings.HasPrefix(part, `"`) && strings.HasSuffix(part, `"`) {
+			part = strings.Trim(part, `"`)
+			weight = boost
+		}
+		subQueries = append(subQueries, SubQuery{Query: part, Weight: weight})
+	}
+	if len(subQueries) == 0 {
+		return []SubQuery{{Query: query, Weight: 1.0}}
+	}
+	return subQueries
+}
+
+// splitOnConjunctions splits query on top-level "and", "or", and commas,
+// leaving quoted phrases intact.
+func splitOnConjunctions(query string) []string {
+	var parts []string
+	var current strings.Builder
+	inQuote := false
+	words := strings.Fields(query)
+
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			parts = append(parts, s)
+		}
+		current.Reset()
+	}
+
+	for _, w := range words {
+		lower := strings.ToLower(strings.Trim(w, `",`))
+		quoteCount := strings.Count(w, `"`)
+		if quoteCount%2 == 1 {
+			inQuote = !inQuote
+		}
+		if !inQuote && (lower == "and" || lower == "or") {
+			flush()
+			continue
+		}
+		if !inQuote && strings.HasSuffix(w, ",") {
+			current.WriteString(w[:len(w)-1])
+			flush()
+			continue
+		}
+		if current.Len() > 0 {
+			current.WriteByte(' ')
+		}
+		current.WriteString(w)
+	}
+	flush()
+	return parts
+}
+
+// LLMPlanner is implemented by planners that call out to a language model
+// to decompose a query. It's kept separate from Planner so a concrete LLM
+// integration can be swapped in without touching the rule-based path.
+type LLMPlanner interface {
+	Planner
+
+	// ShouldDecompose reports whether query is worth the round trip to the
+	// model at all (short/simple queries typically aren't).
+	ShouldDecompose(ctx context.Context, query string) bool
+}
+
+// plannedResult pairs a SubQuery with the results it produced, so RRF can
+// weight per-subquery contributions.
+type plannedResult struct {
+	sub     SubQuery
+	results []*fusedResult
+}
+
+// runPlan executes each SubQuery concurrently via errgroup, deriving a
+// per-subquery timeout from the parent context's deadline, and merges
+// results with weighted RRF. Individual sub-query failures are logged and
+// skipped; the overall call only fails if every sub-query failed.
+//
+// Nothing in this checkout calls runPlan, NewRulePlanner, or Plan outside
+// their own tests yet - there's no search.Engine here (engine.go doesn't
+// exist in this tree) to turn a raw query into a Planner call and fan the
+// resulting SubQuery-ies out through runPlan's exec callback.
+func runPlan(ctx context.Context, subQueries []SubQuery, exec func(ctx context.Context, sq SubQuery) ([]*fusedResult, error), onErr func(sq SubQuery, err error)) ([]*fusedResult, error) {
+	if len(subQueries) == 1 {
+		res, err := exec(ctx, subQueries[0])
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	perQueryTimeout := subQueryTimeout(ctx, len(subQueries))
+
+	results := make([]plannedResult, len(subQueries))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, sq := range subQueries {
+		i, sq := i, sq
+		g.Go(func() error {
+			subCtx := gctx
+			if perQueryTimeout > 0 {
+				var cancel context.CancelFunc
+				subCtx, cancel = context.WithTimeout(gctx, perQueryTimeout)
+				defer cancel()
+			}
+			res, err := exec(subCtx, sq)
+			if err != nil {
+				if onErr != nil {
+					onErr(sq, err)
+				}
+				return nil // graceful degradation: don't abort the group
+			}
+			results[i] = plannedResult{sub: sq, results: res}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	succeeded := 0
+	for _, r := range results {
+		if r.sub.Query != "" {
+			succeeded++
+		}
+	}
+	if succeeded == 0 {
+		return nil, errNoSubQueriesSucceeded
+	}
+
+	return fuseWeightedRRF(results, DefaultRRFConstant), nil
+}
+
+// subQueryTimeout derives a per-sub-query budget from the parent context's
+// deadline: each of the n concurrent sub-queries gets the whole time
+// remaining, not a share of it. Returns 0 (no timeout) if the parent has no
+// deadline, the deadline has passed, or n <= 0.
+func subQueryTimeout(ctx context.Context, n int) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok || n <= 0 {
+		return 0
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0
+	}
+	return remaining
+}
+
+// fuseWeightedRRF combines per-subquery result lists with weighted
+// Reciprocal Rank Fusion: score = sum_q w_q * sum_r 1/(k + rank_{q,r}).
+func fuseWeightedRRF(planned []plannedResult, k int) []*fusedResult {
+	scores := make(map[string]float64)
+	byID := make(map[string]*fusedResult)
+
+	for _, pr := range planned {
+		weight := pr.sub.Weight
+		if weight <= 0 {
+			weight = 1.0
+		}
+		for rank, r := range pr.results {
+			scores[r.chunkID] += weight * (1.0 / float64(k+rank+1))
+			if _, ok := byID[r.chunkID]; !ok {
+				byID[r.chunkID] = r
+			}
+		}
+	}
+
+	out := make([]*fusedResult, 0, len(byID))
+	for id, r := range byID {
+		merged := *r
+		merged.rrfScore = scores[id]
+		out = append(out, &merged)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].rrfScore > out[j].rrfScore })
+	return out
+}
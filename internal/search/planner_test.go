@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulePlanner_SplitsOnAndOr(t *testing.T) {
+	p := NewRulePlanner()
+
+	subs := p.Plan(context.Background(), "auth and login handler or session token")
+
+	require.Len(t, subs, 3)
+	assert.Equal(t, "auth", subs[0].Query)
+	assert.Equal(t, "login handler", subs[1].Query)
+	assert.Equal(t, "session token", subs[2].Query)
+}
+
+func TestRulePlanner_BoostsQuotedPhrase(t *testing.T) {
+	p := NewRulePlanner()
+
+	subs := p.Plan(context.Background(), `"exact phrase" and loose term`)
+
+	require.Len(t, subs, 2)
+	assert.Equal(t, "exact phrase", subs[0].Query)
+	assert.Equal(t, 1.5, subs[0].Weight)
+	assert.Equal(t, "loose term", subs[1].Query)
+	assert.Equal(t, 1.0, subs[1].Weight)
+}
+
+func TestRulePlanner_NoConjunctionReturnsSingleSubQuery(t *testing.T) {
+	p := NewRulePlanner()
+
+	subs := p.Plan(context.Background(), "simple query")
+
+	require.Len(t, subs, 1)
+	assert.Equal(t, "simple query", subs[0].Query)
+	assert.Equal(t, 1.0, subs[0].Weight)
+}
+
+func TestRunPlan_MergesWithWeightedRRF(t *testing.T) {
+	subQueries := []SubQuery{
+		{Query: "auth", Weight: 2.0},
+		{Query: "login", Weight: 1.0},
+	}
+
+	exec := func(_ context.Context, sq SubQuery) ([]*fusedResult, error) {
+		switch sq.Query {
+		case "auth":
+			return []*fusedResult{{chunkID: "chunk1"}, {chunkID: "chunk2"}}, nil
+		case "login":
+			return []*fusedResult{{chunkID: "chunk2"}, {chunkID: "chunk1"}}, nil
+		}
+		return nil, nil
+	}
+
+	results, err := runPlan(context.Background(), subQueries, exec, nil)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	// chunk1 ranks #1 for the higher-weighted "auth" sub-query, so it should
+	// come out on top overall.
+	assert.Equal(t, "chunk1", results[0].chunkID)
+}
+
+func TestRunPlan_DegradesGracefullyOnPartialFailure(t *testing.T) {
+	subQueries := []SubQuery{
+		{Query: "good", Weight: 1.0},
+		{Query: "bad", Weight: 1.0},
+	}
+
+	exec := func(_ context.Context, sq SubQuery) ([]*fusedResult, error) {
+		if sq.Query == "bad" {
+			return nil, errors.New("sub-query failed")
+		}
+		return []*fusedResult{{chunkID: "chunk1"}}, nil
+	}
+
+	var failedQueries []string
+	results, err := runPlan(context.Background(), subQueries, exec, func(sq SubQuery, _ error) {
+		failedQueries = append(failedQueries, sq.Query)
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"bad"}, failedQueries)
+}
+
+func TestRunPlan_AllSubQueriesFail(t *testing.T) {
+	subQueries := []SubQuery{{Query: "a", Weight: 1.0}, {Query: "b", Weight: 1.0}}
+
+	exec := func(_ context.Context, _ SubQuery) ([]*fusedResult, error) {
+		return nil, errors.New("boom")
+	}
+
+	_, err := runPlan(context.Background(), subQueries, exec, nil)
+
+	assert.ErrorIs(t, err, errNoSubQueriesSucceeded)
+}
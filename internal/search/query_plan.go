@@ -0,0 +1,63 @@
+package search
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// ScoredPosting is one (chunk, score) pair in a QueryPlan's top-K
+// snapshot, already sorted by score descending.
+type ScoredPosting struct {
+	ChunkID string
+	Score   float64
+}
+
+// QueryPlan is the compiled, cacheable result of planning a single BM25
+// sub-query against a given filter: the term it resolved to plus a
+// snapshot of its top-K scored postings. Caching this lets a session
+// replaying the same (term, filter) pair skip re-parsing the query and
+// re-decoding postings.
+type QueryPlan struct {
+	Term     string
+	Postings []ScoredPosting
+}
+
+// QueryPlanKey combines term and a stable hash of filter into a
+// ResultCache key, so two sub-queries for the same term under different
+// filters don't collide. It used to key a standalone QueryPlanCache;
+// that cache has been retired in favor of ResultCache, so this is now
+// just one more key-building function alongside ResultCacheKey and
+// QueryResultKey. The "plan\x00" prefix keeps QueryPlan entries from
+// colliding with unrelated ResultCache entries keyed by those other
+// builders.
+func QueryPlanKey(term, filter string) string {
+	return "plan\x00" + term + "\x00" + filterHash(filter)
+}
+
+// filterHash returns a short, stable hash of a filter expression. Uses the
+// standard library's FNV-1a rather than pulling in a hashing dependency,
+// matching the same tradeoff ShardOf makes for chunk hashing.
+func filterHash(filter string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(filter))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// CompileOrReuseQueryPlan returns the plan cached under (term, filter) in
+// cache if present, else calls compute to build a fresh plan, stores it in
+// cache, and returns that. compute is only invoked on a cache miss, so
+// repeated queries in a session skip re-parsing and re-decoding postings.
+// A nil cache always computes, so callers that haven't wired up a
+// ResultCache still work.
+func CompileOrReuseQueryPlan(cache *ResultCache, term, filter string, compute func() *QueryPlan) *QueryPlan {
+	if cache == nil {
+		return compute()
+	}
+	key := QueryPlanKey(term, filter)
+	if v, ok := cache.Get(key); ok {
+		return v.(*QueryPlan)
+	}
+	plan := compute()
+	cache.Put(key, plan)
+	return plan
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPlanKey_DistinctFiltersDoNotCollide(t *testing.T) {
+	a := QueryPlanKey("login", "language:go")
+	b := QueryPlanKey("login", "language:ts")
+	assert.NotEqual(t, a, b)
+}
+
+func TestQueryPlanKey_StableForSameInputs(t *testing.T) {
+	a := QueryPlanKey("login", "language:go")
+	b := QueryPlanKey("login", "language:go")
+	assert.Equal(t, a, b)
+}
+
+func TestCompileOrReuseQueryPlan_OnlyCallsComputeOnMiss(t *testing.T) {
+	cache := NewResultCache(4, 0)
+	calls := 0
+	compute := func() *QueryPlan {
+		calls++
+		return &QueryPlan{Term: "login"}
+	}
+
+	first := CompileOrReuseQueryPlan(cache, "login", "", compute)
+	second := CompileOrReuseQueryPlan(cache, "login", "", compute)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCompileOrReuseQueryPlan_NilCacheAlwaysComputes(t *testing.T) {
+	calls := 0
+	compute := func() *QueryPlan {
+		calls++
+		return &QueryPlan{Term: "login"}
+	}
+
+	_ = CompileOrReuseQueryPlan(nil, "login", "", compute)
+	_ = CompileOrReuseQueryPlan(nil, "login", "", compute)
+
+	assert.Equal(t, 2, calls)
+}
+
+func BenchmarkCompileOrReuseQueryPlan_CacheHitVsMiss(b *testing.B) {
+	cache := NewResultCache(64, 0)
+	compute := func() *QueryPlan {
+		postings := make([]ScoredPosting, 200)
+		for i := range postings {
+			postings[i] = ScoredPosting{ChunkID: fmt.Sprintf("chunk-%04d", i), Score: float64(200 - i)}
+		}
+		return &QueryPlan{Term: "login", Postings: postings}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = CompileOrReuseQueryPlan(cache, "login", "language:go", compute)
+	}
+}
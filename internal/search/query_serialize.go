@@ -0,0 +1,103 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentQuerySchemaVersion is stamped into every SerializedQuery so a
+// future schema change can still deserialize (or reject) older payloads
+// instead of silently misreading them.
+const CurrentQuerySchemaVersion = 1
+
+// ErrUnsupportedQuerySchemaVersion is returned by DeserializeQuery when a
+// payload's Version is newer than CurrentQuerySchemaVersion.
+var ErrUnsupportedQuerySchemaVersion = fmt.Errorf("search: unsupported query schema version")
+
+// SerializedQuery is the stable, versioned, on-disk representation of a
+// replayable query: everything an Engine needs to faithfully re-execute a
+// search in a different process. Engine.SerializeQuery/DeserializeQuery
+// would build one of these from (and back into) SearchOptions; it's kept
+// standalone here so saved searches, shared reproducible queries, and
+// future result-cache keys don't depend on SearchOptions' exact shape.
+type SerializedQuery struct {
+	Version int `json:"version"`
+
+	Query   string  `json:"query"`
+	Weights Weights `json:"weights"`
+	Filter  string  `json:"filter,omitempty"`
+
+	// SubQueries records a Planner's decomposition, if the query was
+	// split before fusion. Empty means "not decomposed".
+	SubQueries []SubQuery `json:"sub_queries,omitempty"`
+
+	// Reranker names the reranking model/pass applied after fusion, if
+	// any (e.g. "cross-encoder"). Empty means no rerank pass ran.
+	Reranker string `json:"reranker,omitempty"`
+
+	// ClassifierHint records a query classifier's output (e.g. an intent
+	// label used to rebalance Weights), if a classifier ran.
+	ClassifierHint string `json:"classifier_hint,omitempty"`
+}
+
+// SerializeQuery stamps q with CurrentQuerySchemaVersion and marshals it
+// to JSON. The result is stable across processes, making it suitable for
+// saved searches and cache keys.
+func SerializeQuery(q SerializedQuery) ([]byte, error) {
+	q.Version = CurrentQuerySchemaVersion
+	data, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("search: serialize query: %w", err)
+	}
+	return data, nil
+}
+
+// DeserializeQuery parses data back into a SerializedQuery, rejecting
+// payloads from a newer schema version than this build understands.
+func DeserializeQuery(data []byte) (SerializedQuery, error) {
+	var q SerializedQuery
+	if err := json.Unmarshal(data, &q); err != nil {
+		return SerializedQuery{}, fmt.Errorf("search: deserialize query: %w", err)
+	}
+	if q.Version > CurrentQuerySchemaVersion {
+		return SerializedQuery{}, fmt.Errorf("%w: got %d, support up to %d", ErrUnsupportedQuerySchemaVersion, q.Version, CurrentQuerySchemaVersion)
+	}
+	return q, nil
+}
+
+// Equals reports whether q and other would re-execute the same search.
+// Intended as a round-trip equality helper for tests, and for a future
+// result cache that wants to confirm a cache key's query still matches
+// before serving a cached response.
+func (q SerializedQuery) Equals(other SerializedQuery) bool {
+	if q.Query != other.Query || q.Weights != other.Weights || q.Filter != other.Filter {
+		return false
+	}
+	if q.Reranker != other.Reranker || q.ClassifierHint != other.ClassifierHint {
+		return false
+	}
+	if len(q.SubQueries) != len(other.SubQueries) {
+		return false
+	}
+	for i, sq := range q.SubQueries {
+		if sq != other.SubQueries[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CacheKey derives a stable cache key from q's serialized form, ignoring
+// Version so a schema bump alone doesn't invalidate existing cache
+// entries for an otherwise-unchanged query.
+func (q SerializedQuery) CacheKey() (string, error) {
+	q.Version = 0
+	data, err := json.Marshal(q)
+	if err != nil {
+		return "", fmt.Errorf("search: cache key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializeQuery_RoundTripsEqualQuery(t *testing.T) {
+	q := SerializedQuery{
+		Query:   "login handler",
+		Weights: Weights{BM25: 0.4, Semantic: 0.6},
+		Filter:  "language:go",
+		SubQueries: []SubQuery{
+			{Query: "login", Weight: 1.5},
+			{Query: "handler", Weight: 1.0},
+		},
+		Reranker:       "cross-encoder",
+		ClassifierHint: "code-search",
+	}
+
+	data, err := SerializeQuery(q)
+	require.NoError(t, err)
+
+	decoded, err := DeserializeQuery(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, CurrentQuerySchemaVersion, decoded.Version)
+	assert.True(t, q.Equals(decoded), "round-tripped query should equal the original")
+}
+
+func TestDeserializeQuery_RejectsNewerSchemaVersion(t *testing.T) {
+	data := []byte(`{"version":9999,"query":"x"}`)
+
+	_, err := DeserializeQuery(data)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnsupportedQuerySchemaVersion)
+}
+
+func TestSerializedQuery_EqualsDetectsDivergence(t *testing.T) {
+	a := SerializedQuery{Query: "x", Weights: Weights{BM25: 0.5, Semantic: 0.5}}
+	b := a
+	b.Weights.BM25 = 0.9
+
+	assert.False(t, a.Equals(b))
+}
+
+func TestSerializedQuery_CacheKeyIsStableAndIgnoresVersion(t *testing.T) {
+	a := SerializedQuery{Query: "x", Weights: Weights{BM25: 0.5, Semantic: 0.5}, Version: 1}
+	b := a
+	b.Version = 2
+
+	keyA, err := a.CacheKey()
+	require.NoError(t, err)
+	keyB, err := b.CacheKey()
+	require.NoError(t, err)
+
+	assert.Equal(t, keyA, keyB)
+}
+
+func TestSerializedQuery_CacheKeyChangesWithQueryText(t *testing.T) {
+	a := SerializedQuery{Query: "x"}
+	b := SerializedQuery{Query: "y"}
+
+	keyA, err := a.CacheKey()
+	require.NoError(t, err)
+	keyB, err := b.CacheKey()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, keyA, keyB)
+}
@@ -0,0 +1,121 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// DefaultReconcileInterval is how often the background reconciler retries
+// pending tombstones when EngineConfig.ReconcileInterval is unset.
+const DefaultReconcileInterval = 30 * time.Second
+
+// Reconciler periodically retries deletes recorded in a store.TombstoneLog
+// against BM25 and vector indices, so a best-effort Delete failure
+// (BUG-023) eventually self-heals without blocking the caller. Nothing in
+// this checkout constructs one yet - there's no search.Engine (or
+// equivalent long-lived owner) here to call NewReconciler/Start/Stop -
+// so wire it into whatever owns the store's lifecycle alongside
+// TombstoneLog before relying on it to actually run.
+type Reconciler struct {
+	log      *store.TombstoneLog
+	bm25     store.BM25Index
+	vector   store.VectorStore
+	interval time.Duration
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewReconciler creates a Reconciler. interval defaults to
+// DefaultReconcileInterval when zero.
+func NewReconciler(log *store.TombstoneLog, bm25 store.BM25Index, vector store.VectorStore, interval time.Duration) *Reconciler {
+	if interval <= 0 {
+		interval = DefaultReconcileInterval
+	}
+	return &Reconciler{
+		log:      log,
+		bm25:     bm25,
+		vector:   vector,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the reconcile loop in a goroutine until ctx is cancelled or
+// Stop is called.
+func (r *Reconciler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.reconcileOnce(ctx)
+			}
+		}
+	}()
+}
+
+// Stop halts the reconcile loop. Safe to call multiple times.
+func (r *Reconciler) Stop() {
+	r.stopOnce.Do(func() { close(r.done) })
+}
+
+// reconcileOnce retries every pending tombstone once, resolving whichever
+// indices succeed this round. It never returns an error: failures are
+// logged and retried on the next tick.
+func (r *Reconciler) reconcileOnce(ctx context.Context) {
+	pending, err := r.log.Pending(ctx)
+	if err != nil {
+		slog.Warn("tombstone_reconcile_list_failed", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, t := range pending {
+		for _, idx := range append([]store.PendingIndex(nil), t.Pending...) {
+			var deleteErr error
+			switch idx {
+			case store.PendingIndexBM25:
+				deleteErr = r.bm25.Delete(ctx, []string{t.ChunkID})
+			case store.PendingIndexVector:
+				deleteErr = r.vector.Delete(ctx, []string{t.ChunkID})
+			}
+			if deleteErr != nil {
+				slog.Debug("tombstone_reconcile_retry_failed",
+					slog.String("chunk_id", t.ChunkID),
+					slog.String("index", string(idx)),
+					slog.String("error", deleteErr.Error()))
+				continue
+			}
+			if err := r.log.Resolve(ctx, t.ChunkID, idx); err != nil {
+				slog.Warn("tombstone_reconcile_resolve_failed",
+					slog.String("chunk_id", t.ChunkID),
+					slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// filterTombstoned removes any result whose chunk ID is still tombstoned,
+// so a chunk that Delete couldn't fully remove never resurfaces in live
+// search results.
+func filterTombstoned(ctx context.Context, log *store.TombstoneLog, results []*fusedResult) []*fusedResult {
+	if log == nil {
+		return results
+	}
+	out := results[:0]
+	for _, r := range results {
+		if !log.IsTombstoned(ctx, r.chunkID) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+func TestReconciler_RetriesPendingDeleteUntilSuccess(t *testing.T) {
+	metadata := NewMockMetadataStore()
+	log := store.NewTombstoneLog(metadata)
+	ctx := context.Background()
+	require.NoError(t, log.Record(ctx, "chunk1", store.PendingIndexBM25))
+
+	attempts := 0
+	bm25 := &MockBM25Index{
+		DeleteFn: func(_ context.Context, _ []string) error {
+			attempts++
+			if attempts < 2 {
+				return errors.New("transient failure")
+			}
+			return nil
+		},
+	}
+	vector := &MockVectorStore{}
+
+	r := NewReconciler(log, bm25, vector, time.Millisecond)
+	r.reconcileOnce(ctx)
+	assert.True(t, log.IsTombstoned(ctx, "chunk1"), "first attempt fails, tombstone remains")
+
+	r.reconcileOnce(ctx)
+	assert.False(t, log.IsTombstoned(ctx, "chunk1"), "second attempt succeeds, tombstone clears")
+}
+
+func TestFilterTombstoned_ExcludesTombstonedChunks(t *testing.T) {
+	metadata := NewMockMetadataStore()
+	log := store.NewTombstoneLog(metadata)
+	ctx := context.Background()
+	require.NoError(t, log.Record(ctx, "chunk2", store.PendingIndexVector))
+
+	results := []*fusedResult{{chunkID: "chunk1"}, {chunkID: "chunk2"}}
+
+	filtered := filterTombstoned(ctx, log, results)
+
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "chunk1", filtered[0].chunkID)
+}
+
+func TestReconciler_StartStopDoesNotPanic(t *testing.T) {
+	metadata := NewMockMetadataStore()
+	log := store.NewTombstoneLog(metadata)
+	bm25 := &MockBM25Index{}
+	vector := &MockVectorStore{}
+
+	r := NewReconciler(log, bm25, vector, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	r.Start(ctx)
+	time.Sleep(5 * time.Millisecond)
+	r.Stop()
+	cancel()
+}
@@ -0,0 +1,251 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// ReindexShadowPrefix namespaces vectors written by an in-progress Reindex
+// so they can live alongside the vectors currently serving queries under
+// the chunk's real ID, rather than clobbering them mid-migration.
+const ReindexShadowPrefix = "shadow:"
+
+// DefaultReindexBatchSize is the number of files listed per ListFiles page
+// when ReindexOptions.BatchSize is zero.
+const DefaultReindexBatchSize = 64
+
+// throttleBackoffInitial and throttleBackoffMax bound the exponential
+// backoff reindexFile applies while the embedder reports backpressure
+// via embed.Throttleable, before retrying the pre-flight check.
+const (
+	throttleBackoffInitial = 250 * time.Millisecond
+	throttleBackoffMax     = 30 * time.Second
+)
+
+// ReindexOptions configures an online model migration.
+type ReindexOptions struct {
+	// BatchSize is how many files are listed per ListFiles page; each
+	// file's chunks go to EmbedBatch in one call, however many there are.
+	// Zero means DefaultReindexBatchSize.
+	BatchSize int
+	// Parallelism bounds how many files are re-embedded concurrently.
+	// Zero or negative means sequential (1).
+	Parallelism int
+	// DryRun re-embeds and reports progress but never writes to the
+	// vector store or swaps the stored model/dimension state, so callers
+	// can estimate cost/duration before committing to a migration.
+	DryRun bool
+}
+
+// ReindexProgress is sent on Reindexer.Reindex's channel after each file's
+// chunks have been processed.
+type ReindexProgress struct {
+	Processed int
+	Total     int
+	Errors    []error
+}
+
+// Reindexer drives an incremental, online re-embedding of a project's
+// chunks into the vector store, so switching embedding models (e.g.
+// mxbai-embed-large's 384 dimensions to a Qwen3 embedder's 768) doesn't
+// require taking search offline or discarding the index. New vectors are
+// written under ReindexShadowPrefix+chunkID while the old vectors keep
+// serving queries under chunkID; once every chunk has been re-embedded
+// without error, the real IDs are atomically swapped to the new vectors
+// and the shadow namespace is garbage-collected.
+type Reindexer struct {
+	metadata store.MetadataStore
+	vector   store.VectorStore
+	embedder embed.Embedder
+}
+
+// NewReindexer returns a Reindexer over the given stores.
+func NewReindexer(metadata store.MetadataStore, vector store.VectorStore, embedder embed.Embedder) *Reindexer {
+	return &Reindexer{metadata: metadata, vector: vector, embedder: embedder}
+}
+
+// Reindex streams projectID's chunks in file-sized batches, re-embeds them
+// with the Reindexer's current embedder, and (unless opts.DryRun) swaps
+// the project over to the new vectors once every chunk has succeeded. The
+// returned channel is closed when the run finishes; callers should drain
+// it to observe progress and the final error count.
+func (r *Reindexer) Reindex(ctx context.Context, projectID string, opts ReindexOptions) <-chan ReindexProgress {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultReindexBatchSize
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = 1
+	}
+
+	progress := make(chan ReindexProgress, 1)
+	go r.run(ctx, projectID, opts, progress)
+	return progress
+}
+
+func (r *Reindexer) run(ctx context.Context, projectID string, opts ReindexOptions, progress chan<- ReindexProgress) {
+	defer close(progress)
+
+	withEmbedding, withoutEmbedding, err := r.metadata.GetEmbeddingStats(ctx)
+	total := 0
+	if err == nil {
+		total = withEmbedding + withoutEmbedding
+	}
+
+	var (
+		mu        sync.Mutex
+		processed int
+		errs      []error
+		newVecs   = make(map[string][]float32)
+	)
+
+	report := func() {
+		mu.Lock()
+		snapshot := ReindexProgress{Processed: processed, Total: total, Errors: append([]error(nil), errs...)}
+		mu.Unlock()
+		select {
+		case progress <- snapshot:
+		case <-ctx.Done():
+		}
+	}
+
+	sem := make(chan struct{}, opts.Parallelism)
+	var wg sync.WaitGroup
+	cursor := ""
+	for {
+		files, next, err := r.metadata.ListFiles(ctx, projectID, cursor, opts.BatchSize)
+		if err != nil {
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("reindex: list files: %w", err))
+			mu.Unlock()
+			break
+		}
+
+		for _, file := range files {
+			file := file
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				n, vecs, err := r.reindexFile(ctx, file.ID, opts)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, err)
+				}
+				processed += n
+				for id, v := range vecs {
+					newVecs[id] = v
+				}
+				mu.Unlock()
+				report()
+			}()
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	wg.Wait()
+
+	mu.Lock()
+	failed := len(errs) > 0
+	finalVecs := newVecs
+	mu.Unlock()
+
+	if opts.DryRun || failed || ctx.Err() != nil {
+		return
+	}
+	if err := r.swap(ctx, finalVecs); err != nil {
+		mu.Lock()
+		errs = append(errs, fmt.Errorf("reindex: swap: %w", err))
+		mu.Unlock()
+		report()
+	}
+}
+
+// reindexFile re-embeds every chunk in fileID, writing the result into the
+// shadow namespace (unless opts.DryRun), and returns the chunk count
+// processed plus a chunkID -> new vector map for the final swap.
+func (r *Reindexer) reindexFile(ctx context.Context, fileID string, opts ReindexOptions) (int, map[string][]float32, error) {
+	chunks, err := r.metadata.GetChunksByFile(ctx, fileID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("reindex: get chunks for file %s: %w", fileID, err)
+	}
+	if len(chunks) == 0 {
+		return 0, nil, nil
+	}
+
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Content
+	}
+
+	// Check for backpressure before enqueueing the batch rather than
+	// firing it and eating the progressive timeout once the server is
+	// already overloaded (see embed.Throttleable); a no-op for backends
+	// that don't support the signal.
+	if err := embed.WaitWhileThrottled(ctx, r.embedder, throttleBackoffInitial, throttleBackoffMax); err != nil {
+		return 0, nil, fmt.Errorf("reindex: wait for embedder backpressure to clear for file %s: %w", fileID, err)
+	}
+
+	vectors, err := r.embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return 0, nil, fmt.Errorf("reindex: embed file %s: %w", fileID, err)
+	}
+
+	result := make(map[string][]float32, len(chunks))
+	shadowIDs := make([]string, len(chunks))
+	for i, c := range chunks {
+		result[c.ID] = vectors[i]
+		shadowIDs[i] = ReindexShadowPrefix + c.ID
+	}
+
+	if !opts.DryRun {
+		if err := r.vector.Add(ctx, shadowIDs, vectors); err != nil {
+			return 0, nil, fmt.Errorf("reindex: write shadow vectors for file %s: %w", fileID, err)
+		}
+	}
+	return len(chunks), result, nil
+}
+
+// swap atomically (from callers' perspective) promotes the shadow
+// namespace's vectors to the chunks' real IDs, garbage-collects the shadow
+// entries, and updates the stored model/dimension so future validateDimensions
+// calls see the new embedder as authoritative.
+func (r *Reindexer) swap(ctx context.Context, newVecs map[string][]float32) error {
+	if len(newVecs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(newVecs))
+	vectors := make([][]float32, 0, len(newVecs))
+	shadowIDs := make([]string, 0, len(newVecs))
+	for id, vec := range newVecs {
+		ids = append(ids, id)
+		vectors = append(vectors, vec)
+		shadowIDs = append(shadowIDs, ReindexShadowPrefix+id)
+	}
+
+	if err := r.vector.Add(ctx, ids, vectors); err != nil {
+		return fmt.Errorf("promote shadow vectors: %w", err)
+	}
+	if err := r.vector.Delete(ctx, shadowIDs); err != nil {
+		return fmt.Errorf("garbage-collect shadow namespace: %w", err)
+	}
+
+	if err := r.metadata.SetState(ctx, store.StateKeyIndexDimension, strconv.Itoa(r.embedder.Dimensions())); err != nil {
+		return fmt.Errorf("update stored dimension: %w", err)
+	}
+	if err := r.metadata.SetState(ctx, store.StateKeyIndexModel, r.embedder.ModelName()); err != nil {
+		return fmt.Errorf("update stored model: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package search
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reindexTestMetadata adds file-listing and embedding-stats behavior on top
+// of MockMetadataStore, whose Fn-based overrides don't cover those methods.
+type reindexTestMetadata struct {
+	*MockMetadataStore
+	files []*store.File
+	total int
+}
+
+func (m *reindexTestMetadata) ListFiles(_ context.Context, _, cursor string, limit int) ([]*store.File, string, error) {
+	start := 0
+	if cursor != "" {
+		start, _ = strconv.Atoi(cursor)
+	}
+	end := start + limit
+	if end > len(m.files) {
+		end = len(m.files)
+	}
+	next := ""
+	if end < len(m.files) {
+		next = strconv.Itoa(end)
+	}
+	return m.files[start:end], next, nil
+}
+
+func (m *reindexTestMetadata) GetEmbeddingStats(_ context.Context) (int, int, error) {
+	return m.total, 0, nil
+}
+
+func setupReindexTest(t *testing.T) (*reindexTestMetadata, *MockVectorStore, *MockEmbedder) {
+	t.Helper()
+	mock := NewMockMetadataStore()
+	require.NoError(t, mock.SaveChunks(context.Background(), []*store.Chunk{
+		{ID: "chunk1", FileID: "file1", Content: "func Login() {}"},
+		{ID: "chunk2", FileID: "file1", Content: "func Logout() {}"},
+		{ID: "chunk3", FileID: "file2", Content: "func Register() {}"},
+	}))
+	metadata := &reindexTestMetadata{
+		MockMetadataStore: mock,
+		files:             []*store.File{{ID: "file1"}, {ID: "file2"}},
+		total:             3,
+	}
+	vector := &MockVectorStore{}
+	embedder := &MockEmbedder{DimensionsFn: func() int { return 768 }}
+	return metadata, vector, embedder
+}
+
+func TestReindexer_WritesShadowVectorsDuringRun(t *testing.T) {
+	metadata, vector, embedder := setupReindexTest(t)
+
+	var addedIDs []string
+	vector.AddFn = func(_ context.Context, ids []string, _ [][]float32) error {
+		addedIDs = append(addedIDs, ids...)
+		return nil
+	}
+
+	r := NewReindexer(metadata, vector, embedder)
+	progress := r.Reindex(context.Background(), "proj1", ReindexOptions{BatchSize: 1})
+
+	var last ReindexProgress
+	for p := range progress {
+		last = p
+	}
+
+	require.Empty(t, last.Errors)
+	assert.Equal(t, 3, last.Processed)
+	assert.Equal(t, 3, last.Total)
+
+	for _, id := range []string{"chunk1", "chunk2", "chunk3"} {
+		assert.Contains(t, addedIDs, ReindexShadowPrefix+id)
+	}
+}
+
+func TestReindexer_SwapsStateAndPromotesRealIDsOnSuccess(t *testing.T) {
+	metadata, vector, embedder := setupReindexTest(t)
+
+	var deleted []string
+	promoted := make(map[string]bool)
+	vector.AddFn = func(_ context.Context, ids []string, _ [][]float32) error {
+		for _, id := range ids {
+			if len(id) < len(ReindexShadowPrefix) || id[:len(ReindexShadowPrefix)] != ReindexShadowPrefix {
+				promoted[id] = true
+			}
+		}
+		return nil
+	}
+	vector.DeleteFn = func(_ context.Context, ids []string) error {
+		deleted = append(deleted, ids...)
+		return nil
+	}
+
+	r := NewReindexer(metadata, vector, embedder)
+	progress := r.Reindex(context.Background(), "proj1", ReindexOptions{})
+	for range progress {
+	}
+
+	assert.True(t, promoted["chunk1"])
+	assert.True(t, promoted["chunk2"])
+	assert.True(t, promoted["chunk3"])
+	for _, id := range []string{"chunk1", "chunk2", "chunk3"} {
+		assert.Contains(t, deleted, ReindexShadowPrefix+id, "shadow entries should be garbage-collected after promotion")
+	}
+
+	dim, err := metadata.GetState(context.Background(), store.StateKeyIndexDimension)
+	require.NoError(t, err)
+	assert.Equal(t, "768", dim)
+	model, err := metadata.GetState(context.Background(), store.StateKeyIndexModel)
+	require.NoError(t, err)
+	assert.Equal(t, "mock-embedder", model)
+}
+
+func TestReindexer_DryRunNeverWritesOrSwaps(t *testing.T) {
+	metadata, vector, embedder := setupReindexTest(t)
+
+	addCalled := false
+	vector.AddFn = func(_ context.Context, _ []string, _ [][]float32) error {
+		addCalled = true
+		return nil
+	}
+
+	r := NewReindexer(metadata, vector, embedder)
+	progress := r.Reindex(context.Background(), "proj1", ReindexOptions{DryRun: true})
+
+	var last ReindexProgress
+	for p := range progress {
+		last = p
+	}
+
+	assert.Equal(t, 3, last.Processed, "dry run should still report progress")
+	assert.False(t, addCalled, "dry run must not write vectors")
+
+	dim, err := metadata.GetState(context.Background(), store.StateKeyIndexDimension)
+	require.NoError(t, err)
+	assert.Empty(t, dim, "dry run must not swap stored state")
+}
+
+func TestReindexer_EmbedErrorOnOneFileSkipsSwapButReportsOthers(t *testing.T) {
+	metadata, vector, embedder := setupReindexTest(t)
+
+	embedder.EmbedFn = func(_ context.Context, text string) ([]float32, error) {
+		if text == "func Register() {}" {
+			return nil, assert.AnError
+		}
+		return make([]float32, 768), nil
+	}
+
+	swapped := false
+	vector.AddFn = func(_ context.Context, ids []string, _ [][]float32) error {
+		for _, id := range ids {
+			if id == "chunk3" {
+				swapped = true
+			}
+		}
+		return nil
+	}
+
+	r := NewReindexer(metadata, vector, embedder)
+	progress := r.Reindex(context.Background(), "proj1", ReindexOptions{})
+
+	var last ReindexProgress
+	for p := range progress {
+		last = p
+	}
+
+	assert.NotEmpty(t, last.Errors)
+	assert.False(t, swapped, "a failed file should prevent the final swap")
+
+	dim, err := metadata.GetState(context.Background(), store.StateKeyIndexDimension)
+	require.NoError(t, err)
+	assert.Empty(t, dim)
+}
@@ -0,0 +1,205 @@
+package search
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// ResultCacheKey canonicalizes the inputs a hybrid search call fuses over
+// into the key ResultCache stores entries under, so two calls for the same
+// query/weights/filter against the same embedder and index generation
+// always hit the same slot. generation should be bumped (see
+// ResultCache.BumpGeneration) whenever store.SQLiteStore commits chunk
+// inserts or deletes, so a reindex can never serve a stale cached result.
+func ResultCacheKey(query, filter string, weights Weights, embedderModel string, generation uint64) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s\x00%g\x00%g\x00%s\x00%d", query, filter, weights.BM25, weights.Semantic, embedderModel, generation)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// resultCacheEntry is the container/list payload for ResultCache: the key
+// (for removal), the cached value, and when it was stored (for TTL checks).
+type resultCacheEntry struct {
+	key      string
+	value    any
+	storedAt time.Time
+}
+
+// ResultCacheStats are the hit/miss/eviction counters EngineStats would
+// expose once ResultCache is wired into Engine.Search.
+type ResultCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// ResultCache is a bounded, TTL-aware LRU cache meant to sit in front of a
+// search engine's query path, keyed by ResultCacheKey and storing each
+// query's fused, reranked result list (and, separately, each raw query
+// embedding under its own key - see QueryVectorKey) so repeated MCP tool
+// calls in one session don't re-run BM25, HNSW, and reranking for a query
+// they've already answered. There is no search.Engine in this checkout
+// (engine.go doesn't exist here) to hand a ResultCache to, so
+// WithResultCache(size int, ttl time.Duration) stays a description of how
+// it would be wired rather than a real option; nothing outside this
+// package's own tests constructs a ResultCache yet. This package used to
+// carry three more LRU caches alongside it (QueryCache, QueryPlanCache,
+// CandidateCache), each solving a narrower version of the same
+// bounded-LRU-with-a-typed-key problem; they've been retired in favor of
+// this one, with their distinguishing key shapes (QueryResultKey,
+// CompileOrReuseQueryPlan, CandidateCacheKey) kept as callers that key
+// into ResultCache instead of standing up their own eviction logic.
+type ResultCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List
+	items map[string]*list.Element
+
+	generation uint64
+	stats      ResultCacheStats
+}
+
+// NewResultCache returns a ResultCache holding at most size entries, each
+// valid for ttl after being stored. A zero ttl means entries never expire
+// on their own (only LRU eviction or BumpGeneration removes them).
+func NewResultCache(size int, ttl time.Duration) *ResultCache {
+	if size <= 0 {
+		size = 1
+	}
+	return &ResultCache{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Generation returns the cache's current index generation, for building a
+// ResultCacheKey.
+func (c *ResultCache) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// BumpGeneration increments the cache's generation and drops every entry,
+// since a new generation means the index underneath every cached result
+// may have changed. store.SQLiteStore would call this once per committed
+// batch of chunk inserts/deletes.
+func (c *ResultCache) BumpGeneration() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.order = list.New()
+	c.items = make(map[string]*list.Element)
+}
+
+// Get returns the cached value for key, if present and not expired,
+// promoting it to most-recently-used.
+func (c *ResultCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	entry := elem.Value.(*resultCacheEntry)
+	if c.ttl > 0 && time.Since(entry.storedAt) > c.ttl {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		c.stats.Misses++
+		c.stats.Evictions++
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+	return entry.value, true
+}
+
+// Put stores value under key, evicting the least-recently-used entry if
+// the cache is already at capacity.
+func (c *ResultCache) Put(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*resultCacheEntry).value = value
+		elem.Value.(*resultCacheEntry).storedAt = time.Now()
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&resultCacheEntry{key: key, value: value, storedAt: time.Now()})
+	c.items[key] = elem
+
+	for len(c.items) > c.size {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*resultCacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// Stats returns a snapshot of the cache's hit/miss/eviction counters.
+func (c *ResultCache) Stats() ResultCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Len reports how many entries the cache is currently holding.
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// QueryVectorKey canonicalizes a raw query string and embedder model into
+// the key a query-vector cache would store an embedding under, separate
+// from ResultCacheKey since the same embedding is reusable across
+// different weights/filters.
+func QueryVectorKey(query, embedderModel string) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s", query, embedderModel)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// queryResultKeyFields is QueryResultKey's input, captured as a struct so
+// it can be deterministically hashed as a single unit rather than
+// string-concatenated field by field.
+type queryResultKeyFields struct {
+	Query    string
+	Filter   string
+	Language string
+	Scopes   []string
+	Weights  Weights
+	Limit    int
+}
+
+// QueryResultKey canonicalizes everything that can change a result set for
+// an otherwise identical query string - filter, language, scopes, weights,
+// and limit - into a ResultCache key, so two identical-looking queries
+// that differ in any of those never collide. This used to be its own
+// cache (QueryCache); it's folded into ResultCacheKey's family of key
+// builders now that there's only one cache implementation to key into.
+func QueryResultKey(query, filter, language string, scopes []string, weights Weights, limit int) string {
+	b, _ := json.Marshal(queryResultKeyFields{
+		Query: query, Filter: filter, Language: language,
+		Scopes: scopes, Weights: weights, Limit: limit,
+	})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
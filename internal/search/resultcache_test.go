@@ -0,0 +1,112 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCacheKey_StableForSameInputs(t *testing.T) {
+	weights := Weights{BM25: 0.4, Semantic: 0.6}
+	a := ResultCacheKey("find auth bug", "lang:go", weights, "text-embed-3", 1)
+	b := ResultCacheKey("find auth bug", "lang:go", weights, "text-embed-3", 1)
+	assert.Equal(t, a, b)
+}
+
+func TestResultCacheKey_DiffersByGeneration(t *testing.T) {
+	weights := Weights{BM25: 0.4, Semantic: 0.6}
+	a := ResultCacheKey("find auth bug", "", weights, "text-embed-3", 1)
+	b := ResultCacheKey("find auth bug", "", weights, "text-embed-3", 2)
+	assert.NotEqual(t, a, b)
+}
+
+func TestResultCache_GetPutRoundTrip(t *testing.T) {
+	c := NewResultCache(2, 0)
+
+	_, ok := c.Get("k1")
+	assert.False(t, ok)
+
+	c.Put("k1", []string{"chunk1", "chunk2"})
+	value, ok := c.Get("k1")
+	require.True(t, ok)
+	assert.Equal(t, []string{"chunk1", "chunk2"}, value)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewResultCache(2, 0)
+	c.Put("k1", 1)
+	c.Put("k2", 2)
+	c.Get("k1") // k1 is now most-recently-used, k2 is the eviction candidate
+	c.Put("k3", 3)
+
+	_, ok := c.Get("k2")
+	assert.False(t, ok, "k2 should have been evicted as the least-recently-used entry")
+
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, uint64(1), c.Stats().Evictions)
+}
+
+func TestResultCache_ExpiresEntriesPastTTL(t *testing.T) {
+	c := NewResultCache(2, time.Millisecond)
+	c.Put("k1", 1)
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := c.Get("k1")
+	assert.False(t, ok)
+}
+
+func TestResultCache_BumpGenerationClearsEntries(t *testing.T) {
+	c := NewResultCache(2, 0)
+	c.Put("k1", 1)
+
+	before := c.Generation()
+	c.BumpGeneration()
+
+	assert.Equal(t, before+1, c.Generation())
+	assert.Equal(t, 0, c.Len())
+	_, ok := c.Get("k1")
+	assert.False(t, ok)
+}
+
+func TestQueryVectorKey_DiffersByEmbedderModel(t *testing.T) {
+	a := QueryVectorKey("find auth bug", "text-embed-3")
+	b := QueryVectorKey("find auth bug", "text-embed-4")
+	assert.NotEqual(t, a, b)
+}
+
+func TestQueryResultKey_StableForSameInputs(t *testing.T) {
+	weights := Weights{BM25: 0.5, Semantic: 0.5}
+	a := QueryResultKey("login", "code", "go", []string{"src"}, weights, 10)
+	b := QueryResultKey("login", "code", "go", []string{"src"}, weights, 10)
+	assert.Equal(t, a, b)
+}
+
+func TestQueryResultKey_DiffersByFilter(t *testing.T) {
+	a := QueryResultKey("login", "code", "", nil, Weights{}, 0)
+	b := QueryResultKey("login", "docs", "", nil, Weights{}, 0)
+	assert.NotEqual(t, a, b)
+}
+
+func TestQueryResultKey_DiffersByWeights(t *testing.T) {
+	a := QueryResultKey("login", "", "", nil, Weights{BM25: 0.8, Semantic: 0.2}, 0)
+	b := QueryResultKey("login", "", "", nil, Weights{BM25: 0.2, Semantic: 0.8}, 0)
+	assert.NotEqual(t, a, b)
+}
+
+func TestResultCache_KeyedByQueryResultKeyRoundTrips(t *testing.T) {
+	c := NewResultCache(8, time.Minute)
+	key := QueryResultKey("login", "code", "go", []string{"src"}, Weights{BM25: 0.5, Semantic: 0.5}, 10)
+	want := []*SearchResult{{}}
+
+	c.Put(key, want)
+	got, ok := c.Get(key)
+
+	require.True(t, ok)
+	assert.Equal(t, want, got)
+}
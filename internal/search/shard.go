@@ -0,0 +1,110 @@
+package search
+
+import (
+	"context"
+	"hash/fnv"
+	"runtime"
+	"sync"
+)
+
+// DefaultShardCount is used when a caller hasn't fixed a chunk count
+// ahead of time. A single shard is equivalent to today's unsharded search.
+const DefaultShardCount = 1
+
+// ShardOf returns chunkID's assigned shard in [0, numShards), computed with
+// a stable FNV-1a hash so the assignment is identical across process
+// restarts - only the shard count for an index must stay fixed, not the
+// hash itself. Standard library hash/fnv is used in place of xxhash to
+// avoid pulling in a new dependency purely for sharding.
+//
+// Callers store the result on Chunk once, at ingestion time, so
+// BM25Store.SearchShard/VectorStore.SearchShard can route a query to the
+// right shard without recomputing membership from the full chunk set.
+func ShardOf(chunkID string, numShards int) uint32 {
+	if numShards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(chunkID))
+	return h.Sum32() % uint32(numShards)
+}
+
+// ShardQuery runs one backend's shard-scoped query, e.g.
+// BM25Store.SearchShard or VectorStore.SearchShard bound to a single
+// shardID.
+type ShardQuery[T any] func(ctx context.Context, shardID int) ([]T, error)
+
+// ShardPlan resolves how many shards to query and how many to run at
+// once, from SearchOptions.Parallelism (0 means all numShards shards with
+// up to GOMAXPROCS of them running at once, matching the request's
+// "default = GOMAXPROCS" rule; 1 disables sharding entirely).
+func ShardPlan(numShards, parallelism int) (shards, workers int) {
+	if numShards <= 0 {
+		numShards = DefaultShardCount
+	}
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if parallelism == 1 {
+		return 1, 1
+	}
+	workers = parallelism
+	if workers > numShards {
+		workers = numShards
+	}
+	return numShards, workers
+}
+
+// SearchSharded runs query once per shard in [0, numShards), bounded to at
+// most workers concurrent calls, and concatenates the per-shard results in
+// shard order so the merged list is deterministic regardless of which
+// shard's call happened to finish first. The first shard error cancels the
+// rest and is returned; partial results from shards that had already
+// completed are discarded, matching how the unsharded path already fails
+// the whole query on a single backend error.
+func SearchSharded[T any](ctx context.Context, numShards, workers int, query ShardQuery[T]) ([]T, error) {
+	if numShards <= 1 {
+		return query(ctx, 0)
+	}
+	if workers <= 0 || workers > numShards {
+		workers = numShards
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]T, numShards)
+	errs := make([]error, numShards)
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for shardID := 0; shardID < numShards; shardID++ {
+		shardID := shardID
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := query(ctx, shardID)
+			if err != nil {
+				errs[shardID] = err
+				cancel()
+				return
+			}
+			results[shardID] = res
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	merged := make([]T, 0)
+	for _, res := range results {
+		merged = append(merged, res...)
+	}
+	return merged, nil
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardOf_StableAcrossCalls(t *testing.T) {
+	a := ShardOf("chunk-123", 8)
+	b := ShardOf("chunk-123", 8)
+	assert.Equal(t, a, b)
+	assert.Less(t, a, uint32(8))
+}
+
+func TestShardOf_SingleShardIsAlwaysZero(t *testing.T) {
+	assert.Equal(t, uint32(0), ShardOf("anything", 1))
+	assert.Equal(t, uint32(0), ShardOf("anything", 0))
+}
+
+func TestShardPlan_ParallelismOneDisablesSharding(t *testing.T) {
+	shards, workers := ShardPlan(8, 1)
+	assert.Equal(t, 1, shards)
+	assert.Equal(t, 1, workers)
+}
+
+func TestShardPlan_WorkersNeverExceedShardCount(t *testing.T) {
+	_, workers := ShardPlan(2, 16)
+	assert.Equal(t, 2, workers)
+}
+
+func TestSearchSharded_ConcatenatesInShardOrder(t *testing.T) {
+	query := func(_ context.Context, shardID int) ([]int, error) {
+		return []int{shardID * 10, shardID*10 + 1}, nil
+	}
+
+	got, err := SearchSharded(context.Background(), 3, 3, query)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 10, 11, 20, 21}, got)
+}
+
+func TestSearchSharded_SingleShardSkipsFanOut(t *testing.T) {
+	var calls atomic.Int32
+	query := func(_ context.Context, shardID int) ([]int, error) {
+		calls.Add(1)
+		return []int{shardID}, nil
+	}
+
+	got, err := SearchSharded(context.Background(), 1, 1, query)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, got)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestSearchSharded_PropagatesFirstError(t *testing.T) {
+	boom := errors.New("shard 1 backend unavailable")
+	query := func(_ context.Context, shardID int) ([]int, error) {
+		if shardID == 1 {
+			return nil, boom
+		}
+		return []int{shardID}, nil
+	}
+
+	_, err := SearchSharded(context.Background(), 4, 4, query)
+
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestSearchSharded_BoundsConcurrencyToWorkers(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	query := func(_ context.Context, shardID int) ([]int, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := maxInFlight.Load()
+			if n <= old || maxInFlight.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		return []int{shardID}, nil
+	}
+
+	_, err := SearchSharded(context.Background(), 8, 2, query)
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
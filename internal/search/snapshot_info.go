@@ -0,0 +1,27 @@
+package search
+
+import "time"
+
+// SnapshotInfo summarizes whether an Engine is currently answering
+// queries from a degraded, read-only snapshot while a concurrent
+// `index --resume` finishes in the background, instead of its normal
+// read-write store. Engine.SnapshotInfo() would return one of these by
+// reading the same store.SnapshotMetadataStore/checkpoint state
+// cmd/amanmcp/cmd's serve command already loads (see ISSUE-02 in
+// serve.go); it's kept standalone here because Engine itself lives
+// outside this tree, the same reasoning resultcache.go's WithResultCache
+// comment gives for not wiring straight into the engine.
+type SnapshotInfo struct {
+	// Degraded is true while the engine is serving from a read-only
+	// snapshot rather than the normal read-write store.
+	Degraded bool
+	// Stage is the in-progress indexer checkpoint's stage (e.g.
+	// "embedding"), or "" once Degraded is false.
+	Stage string
+	// EmbeddedCount and Total are the checkpoint's progress counters.
+	EmbeddedCount int
+	Total         int
+	// LastIndexedAt is when the project this snapshot serves was last
+	// fully indexed, before the in-progress reindex began.
+	LastIndexedAt time.Time
+}
@@ -0,0 +1,107 @@
+package search
+
+import "sort"
+
+// SortKey names a field SearchResult can be ordered by. The zero value,
+// SortByScore, preserves today's default (fused relevance score).
+type SortKey string
+
+const (
+	// SortByScore orders by the fused relevance score, descending. This is
+	// the default when SearchOptions.SortBy is unset.
+	SortByScore SortKey = ""
+	// SortByPath orders by file path, ascending.
+	SortByPath SortKey = "path"
+	// SortByLines orders by chunk size (EndLine - StartLine), descending.
+	SortByLines SortKey = "lines"
+	// SortByStartLine orders by the chunk's starting line within its file,
+	// ascending.
+	SortByStartLine SortKey = "start_line"
+	// SortByModifiedAt orders by the chunk's last-updated time, descending
+	// (most recently modified first).
+	SortByModifiedAt SortKey = "modified_at"
+	// SortByLanguage orders by the chunk's detected language, ascending.
+	SortByLanguage SortKey = "language"
+)
+
+// SortDirection overrides a SortKey's natural direction.
+type SortDirection string
+
+const (
+	// SortAsc forces ascending order regardless of the key's default.
+	SortAsc SortDirection = "asc"
+	// SortDesc forces descending order regardless of the key's default.
+	SortDesc SortDirection = "desc"
+)
+
+// SortSpec is SearchOptions.Sort: which field to order by, and an optional
+// direction override.
+type SortSpec struct {
+	By  SortKey
+	Dir SortDirection
+}
+
+// sortResults reorders results in place per spec. SortByModifiedAt,
+// SortByLanguage and any unrecognized SortKey are treated as SortByScore
+// here (no reordering beyond what fusion produced); MultiSort is what
+// orders by modification time or language.
+//
+// Nothing outside this file's own test calls sortResults yet - there's no
+// search.Engine in this checkout (engine.go doesn't exist here) to read
+// SearchOptions.Sort and apply it after fusion/rerank. SortKey/SortSpec
+// are ready for that caller to exist; they don't reorder anything on
+// their own.
+func sortResults(results []*SearchResult, spec SortSpec) {
+	if spec.By == SortByScore || spec.By == "" {
+		return
+	}
+
+	less := func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch spec.By {
+		case SortByPath:
+			return sortFieldLess(pathOf(a), pathOf(b), spec.Dir, SortAsc)
+		case SortByLines:
+			return sortFieldLess(linesOf(a), linesOf(b), spec.Dir, SortDesc)
+		case SortByStartLine:
+			return sortFieldLess(startLineOf(a), startLineOf(b), spec.Dir, SortAsc)
+		default:
+			return false
+		}
+	}
+	sort.SliceStable(results, less)
+}
+
+// sortFieldLess applies dir (falling back to natDir when unset) to a plain
+// ascending comparison of a and b.
+func sortFieldLess[T int | string](a, b T, dir, natDir SortDirection) bool {
+	effective := dir
+	if effective == "" {
+		effective = natDir
+	}
+	if effective == SortDesc {
+		return a > b
+	}
+	return a < b
+}
+
+func pathOf(r *SearchResult) string {
+	if r.Chunk == nil {
+		return ""
+	}
+	return r.Chunk.FilePath
+}
+
+func linesOf(r *SearchResult) int {
+	if r.Chunk == nil {
+		return 0
+	}
+	return r.Chunk.EndLine - r.Chunk.StartLine
+}
+
+func startLineOf(r *SearchResult) int {
+	if r.Chunk == nil {
+		return 0
+	}
+	return r.Chunk.StartLine
+}
@@ -0,0 +1,46 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+func resultFor(path string, start, end int) *SearchResult {
+	return &SearchResult{Chunk: &store.Chunk{FilePath: path, StartLine: start, EndLine: end}}
+}
+
+func TestSortResults_ByPathAscending(t *testing.T) {
+	results := []*SearchResult{resultFor("b.go", 1, 2), resultFor("a.go", 1, 2)}
+
+	sortResults(results, SortSpec{By: SortByPath})
+
+	assert.Equal(t, "a.go", results[0].Chunk.FilePath)
+	assert.Equal(t, "b.go", results[1].Chunk.FilePath)
+}
+
+func TestSortResults_ByLinesDescendingDefault(t *testing.T) {
+	results := []*SearchResult{resultFor("a.go", 1, 5), resultFor("b.go", 1, 50)}
+
+	sortResults(results, SortSpec{By: SortByLines})
+
+	assert.Equal(t, "b.go", results[0].Chunk.FilePath)
+}
+
+func TestSortResults_DirectionOverride(t *testing.T) {
+	results := []*SearchResult{resultFor("a.go", 1, 5), resultFor("b.go", 1, 50)}
+
+	sortResults(results, SortSpec{By: SortByLines, Dir: SortAsc})
+
+	assert.Equal(t, "a.go", results[0].Chunk.FilePath)
+}
+
+func TestSortResults_ScoreIsNoOp(t *testing.T) {
+	results := []*SearchResult{resultFor("b.go", 1, 2), resultFor("a.go", 1, 2)}
+
+	sortResults(results, SortSpec{By: SortByScore})
+
+	assert.Equal(t, "b.go", results[0].Chunk.FilePath, "score sort leaves fusion order untouched")
+}
@@ -0,0 +1,105 @@
+//go:build linux || darwin
+
+package store
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// canonicalPathKey identifies an on-disk index file by device+inode rather
+// than its path string, so worktrees, symlinks, and monorepo subpaths that
+// all resolve to the same file share one BackendRegistry entry instead of
+// double-mapping it into RSS. If path doesn't exist yet (a fresh project
+// that hasn't indexed), the cleaned path is used instead - there's nothing
+// to dedupe against until the file is actually created.
+func canonicalPathKey(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return filepath.Clean(path)
+	}
+	return fmt.Sprintf("%d:%d", stat.Dev, stat.Ino)
+}
+
+// refCountedBackend pairs a shared backend with how many acquirers are
+// currently holding it.
+type refCountedBackend[T io.Closer] struct {
+	backend T
+	refs    int
+}
+
+// BackendRegistry shares one backend instance across every caller that
+// Acquires the same canonical path, closing it only once the last caller
+// Releases it. This is what lets multiple projectStates referencing the
+// same underlying index file (worktrees, symlinks, monorepo subpaths)
+// avoid paying for the mmap/SQLite handle/HNSW graph more than once.
+type BackendRegistry[T io.Closer] struct {
+	mu      sync.Mutex
+	entries map[string]*refCountedBackend[T]
+}
+
+// NewBackendRegistry creates an empty registry.
+func NewBackendRegistry[T io.Closer]() *BackendRegistry[T] {
+	return &BackendRegistry[T]{entries: make(map[string]*refCountedBackend[T])}
+}
+
+// Acquire returns the backend shared by every other Acquire call for path's
+// canonical (device, inode) identity, constructing it via construct on the
+// first acquisition. Every successful Acquire must be paired with exactly
+// one Release(path).
+func (r *BackendRegistry[T]) Acquire(path string, construct func() (T, error)) (T, error) {
+	key := canonicalPathKey(path)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.entries[key]; ok {
+		e.refs++
+		return e.backend, nil
+	}
+
+	backend, err := construct()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	r.entries[key] = &refCountedBackend[T]{backend: backend, refs: 1}
+	return backend, nil
+}
+
+// Release decrements path's reference count, closing and evicting the
+// backend once no acquirer still holds it. Releasing a path that was never
+// acquired (or already fully released) is a no-op.
+func (r *BackendRegistry[T]) Release(path string) error {
+	key := canonicalPathKey(path)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[key]
+	if !ok {
+		return nil
+	}
+	e.refs--
+	if e.refs > 0 {
+		return nil
+	}
+	delete(r.entries, key)
+	return e.backend.Close()
+}
+
+// Len returns the number of distinct backends currently open, for tests and
+// observability.
+func (r *BackendRegistry[T]) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
@@ -0,0 +1,84 @@
+//go:build linux || darwin
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendRegistry_SharesOneInstancePerCanonicalPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vectors.hnsw")
+	require.NoError(t, os.WriteFile(path, []byte("x"), 0o644))
+
+	reg := NewBackendRegistry[VectorStore]()
+	calls := 0
+	factory := func() (VectorStore, error) {
+		calls++
+		return NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 4})
+	}
+
+	a, err := reg.Acquire(path, factory)
+	require.NoError(t, err)
+	b, err := reg.Acquire(path, factory)
+	require.NoError(t, err)
+
+	assert.Same(t, a, b)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, reg.Len())
+}
+
+func TestBackendRegistry_SymlinkSharesTheSameCanonicalEntry(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real.hnsw")
+	require.NoError(t, os.WriteFile(real, []byte("x"), 0o644))
+	link := filepath.Join(dir, "link.hnsw")
+	require.NoError(t, os.Symlink(real, link))
+
+	reg := NewBackendRegistry[VectorStore]()
+	calls := 0
+	factory := func() (VectorStore, error) {
+		calls++
+		return NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 4})
+	}
+
+	_, err := reg.Acquire(real, factory)
+	require.NoError(t, err)
+	_, err = reg.Acquire(link, factory)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls, "real path and its symlink should resolve to one shared backend")
+}
+
+func TestBackendRegistry_ClosesOnlyAfterLastRelease(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vectors.hnsw")
+	require.NoError(t, os.WriteFile(path, []byte("x"), 0o644))
+
+	reg := NewBackendRegistry[VectorStore]()
+	factory := func() (VectorStore, error) {
+		return NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 4})
+	}
+
+	_, err := reg.Acquire(path, factory)
+	require.NoError(t, err)
+	_, err = reg.Acquire(path, factory)
+	require.NoError(t, err)
+
+	require.NoError(t, reg.Release(path))
+	// Still referenced once: the entry must still be registered.
+	assert.Equal(t, 1, reg.Len())
+
+	require.NoError(t, reg.Release(path))
+	assert.Equal(t, 0, reg.Len())
+}
+
+func TestBackendRegistry_UnacquiredPathReleaseIsNoop(t *testing.T) {
+	reg := NewBackendRegistry[VectorStore]()
+	assert.NoError(t, reg.Release("/never/acquired"))
+}
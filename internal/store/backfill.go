@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// chunkCompressionBackfillBatchSize is how many rows backfillChunkCompression
+// rewrites per transaction. Migration 5 widens content/raw_content/context
+// to BLOB but - being pure SQL - can't itself call the compression
+// libraries those columns now need, so this does the actual backfill on
+// the Go side, in batches small enough that a large chunks table doesn't
+// hold a single multi-GB transaction open.
+const chunkCompressionBackfillBatchSize = 1000
+
+// backfillChunkCompression walks the shared chunks table in batches of
+// chunkCompressionBackfillBatchSize (ordered by rowid, so it resumes from
+// where the previous batch left off without re-scanning rows it already
+// handled), re-encoding any row whose content/raw_content/context still
+// has no compression header byte - i.e. predates StoreConfig.ChunkCompression
+// - under the store's now-configured algorithm. Each batch commits as one
+// transaction, so a crash partway through leaves only already-committed
+// batches converted. A no-op once every row has a header byte, so it's
+// safe to run on every startup.
+//
+// Bucket-mode per-project chunks_<id> tables aren't backfilled: they're
+// created lazily by ensureBucket after this feature shipped, so every row
+// in them is already written through encodeChunkColumn.
+func (s *SQLiteStore) backfillChunkCompression(ctx context.Context) error {
+	var lastRowID int64
+	var totalConverted int
+	for {
+		converted, scanned, nextRowID, err := s.backfillChunkCompressionBatch(ctx, lastRowID, chunkCompressionBackfillBatchSize)
+		if err != nil {
+			return fmt.Errorf("backfill chunk compression: %w", err)
+		}
+		totalConverted += converted
+		if converted > 0 {
+			slog.Info("chunk_compression_backfill_progress",
+				slog.Int("batch_converted", converted),
+				slog.Int("total_converted", totalConverted))
+		}
+		if scanned < chunkCompressionBackfillBatchSize {
+			break
+		}
+		lastRowID = nextRowID
+	}
+	if totalConverted > 0 {
+		slog.Info("chunk_compression_backfill_complete", slog.Int("total_converted", totalConverted))
+	}
+	return nil
+}
+
+type legacyChunkRow struct {
+	rowID                        int64
+	id                           string
+	content, rawContent, context []byte
+}
+
+// backfillChunkCompressionBatch scans up to limit rows after lastRowID,
+// re-encoding the ones that are still legacy plaintext inside a single
+// transaction. It returns how many rows it rewrote, how many it scanned
+// (the caller uses scanned < limit to know it reached the end of the
+// table), and the rowid to resume from on the next call.
+func (s *SQLiteStore) backfillChunkCompressionBatch(ctx context.Context, lastRowID int64, limit int) (converted, scanned int, nextRowID int64, err error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT rowid, id, content, raw_content, context FROM chunks WHERE rowid > ? ORDER BY rowid LIMIT ?`,
+		lastRowID, limit)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("query backfill batch: %w", err)
+	}
+
+	var batch []legacyChunkRow
+	for rows.Next() {
+		var r legacyChunkRow
+		if err := rows.Scan(&r.rowID, &r.id, &r.content, &r.rawContent, &r.context); err != nil {
+			_ = rows.Close()
+			return 0, 0, 0, fmt.Errorf("scan backfill row: %w", err)
+		}
+		batch = append(batch, r)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, 0, 0, fmt.Errorf("iterate backfill batch: %w", err)
+	}
+	_ = rows.Close()
+
+	if len(batch) == 0 {
+		return 0, 0, lastRowID, nil
+	}
+	nextRowID = batch[len(batch)-1].rowID
+
+	var batchConverted int
+	err = s.WithTx(ctx, func(ctx context.Context, tx *StoreTx) error {
+		for _, r := range batch {
+			rowConverted, updateErr := s.backfillChunkRow(ctx, tx.exec, r)
+			if updateErr != nil {
+				return updateErr
+			}
+			if rowConverted {
+				batchConverted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return batchConverted, len(batch), nextRowID, nil
+}
+
+// backfillChunkRow re-encodes r's content/raw_content/context columns
+// under the store's configured compression, if (and only if) any of them
+// is still legacy plaintext, and reports whether it issued an UPDATE.
+func (s *SQLiteStore) backfillChunkRow(ctx context.Context, exec txExecutor, r legacyChunkRow) (bool, error) {
+	needsContent := needsChunkCompressionBackfill(r.content)
+	needsRaw := needsChunkCompressionBackfill(r.rawContent)
+	needsContext := needsChunkCompressionBackfill(r.context)
+	if !needsContent && !needsRaw && !needsContext {
+		return false, nil
+	}
+
+	content := r.content
+	if needsContent {
+		encoded, err := encodeChunkColumn(s.chunkCompression, string(r.content))
+		if err != nil {
+			return false, fmt.Errorf("compress chunk %s content: %w", r.id, err)
+		}
+		content = encoded
+	}
+	rawContent := r.rawContent
+	if needsRaw {
+		encoded, err := encodeChunkColumn(s.chunkCompression, string(r.rawContent))
+		if err != nil {
+			return false, fmt.Errorf("compress chunk %s raw_content: %w", r.id, err)
+		}
+		rawContent = encoded
+	}
+	chunkContext := r.context
+	if needsContext {
+		encoded, err := encodeChunkColumn(s.chunkCompression, string(r.context))
+		if err != nil {
+			return false, fmt.Errorf("compress chunk %s context: %w", r.id, err)
+		}
+		chunkContext = encoded
+	}
+
+	if _, err := exec.ExecContext(ctx,
+		`UPDATE chunks SET content = ?, raw_content = ?, context = ? WHERE id = ?`,
+		content, rawContent, chunkContext, r.id); err != nil {
+		return false, fmt.Errorf("update chunk %s: %w", r.id, err)
+	}
+	return true, nil
+}
+
+// needsChunkCompressionBackfill reports whether data is legacy plaintext
+// that predates the chunk compression feature: empty (a NULL
+// raw_content/context) or absent entirely is already fine as-is, and
+// anything tagged with a recognized header byte has already been through
+// encodeChunkColumn.
+func needsChunkCompressionBackfill(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	return !isChunkCompressionHeader(data[0])
+}
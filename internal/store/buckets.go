@@ -0,0 +1,268 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// bucketTableNamePattern restricts the project IDs accepted for bucket
+// table naming to safe SQL identifiers, since project IDs are interpolated
+// directly into CREATE/DROP TABLE statements that SQLite has no bind
+// parameter syntax for. amanmcp's own project IDs are 16-char hex strings
+// (see cmd/serve.go), which always match this.
+var bucketTableNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// bucketTableNames returns the per-project table names used in
+// SupportBucketTable mode.
+func bucketTableNames(projectID string) (filesTable, chunksTable, symbolsTable string) {
+	return "files_" + projectID, "chunks_" + projectID, "symbols_" + projectID
+}
+
+// ensureBucket lazily creates projectID's own files/chunks/symbols tables
+// the first time it's needed. It's idempotent (CREATE TABLE IF NOT
+// EXISTS) and cheap enough to call on every write path rather than cache
+// "have I created this already" separately.
+func (s *SQLiteStore) ensureBucket(ctx context.Context, projectID string) error {
+	if !bucketTableNamePattern.MatchString(projectID) {
+		return fmt.Errorf("project id %q is not a safe bucket table suffix", projectID)
+	}
+
+	filesTable, chunksTable, symbolsTable := bucketTableNames(projectID)
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			project_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			size INTEGER,
+			mod_time TIMESTAMP,
+			content_hash TEXT,
+			language TEXT,
+			content_type TEXT,
+			indexed_at TIMESTAMP
+		)`, filesTable),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_path ON %s(path)`, filesTable, filesTable),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			file_id TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			content BLOB NOT NULL,
+			raw_content BLOB,
+			context BLOB,
+			content_type TEXT,
+			language TEXT,
+			start_line INTEGER NOT NULL,
+			end_line INTEGER NOT NULL,
+			metadata TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (file_id) REFERENCES %s(id) ON DELETE CASCADE
+		)`, chunksTable, filesTable),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_file ON %s(file_id)`, chunksTable, chunksTable),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chunk_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			start_line INTEGER,
+			end_line INTEGER,
+			signature TEXT,
+			doc_comment TEXT,
+			FOREIGN KEY (chunk_id) REFERENCES %s(id) ON DELETE CASCADE
+		)`, symbolsTable, chunksTable),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_chunk ON %s(chunk_id)`, symbolsTable, symbolsTable),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_name ON %s(name)`, symbolsTable, symbolsTable),
+	}
+
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create bucket tables for project %s: %w", projectID, err)
+		}
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO project_buckets (project_id) VALUES (?)`, projectID); err != nil {
+		return fmt.Errorf("register bucket for project %s: %w", projectID, err)
+	}
+	return nil
+}
+
+// dropBucket removes projectID's bucket tables outright - an O(1) DROP
+// TABLE per table instead of a row-by-row DELETE - and forgets its
+// bucket_index entries. Used by DeleteFilesByProject when bucket mode is
+// enabled.
+func (s *SQLiteStore) dropBucket(ctx context.Context, projectID string) error {
+	if !bucketTableNamePattern.MatchString(projectID) {
+		return fmt.Errorf("project id %q is not a safe bucket table suffix", projectID)
+	}
+
+	filesTable, chunksTable, symbolsTable := bucketTableNames(projectID)
+	for _, stmt := range []string{
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", symbolsTable),
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", chunksTable),
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", filesTable),
+	} {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("drop bucket tables for project %s: %w", projectID, err)
+		}
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM bucket_index WHERE project_id = ?`, projectID); err != nil {
+		return fmt.Errorf("clear bucket index for project %s: %w", projectID, err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM project_buckets WHERE project_id = ?`, projectID); err != nil {
+		return fmt.Errorf("unregister bucket for project %s: %w", projectID, err)
+	}
+	return nil
+}
+
+// recordBucketEntry remembers which project owns id (a file or chunk ID,
+// distinguished by kind), so later calls that only have the ID in hand -
+// GetChunk, DeleteChunks, SearchSymbols - can find the right bucket
+// tables without a project ID argument. exec runs it against the
+// store's connection directly, or (from inside a WithTx callback)
+// against that transaction, so it rolls back with the row it describes.
+func (s *SQLiteStore) recordBucketEntry(ctx context.Context, exec txExecutor, id, kind, projectID string) error {
+	_, err := exec.ExecContext(ctx,
+		`INSERT INTO bucket_index (id, kind, project_id) VALUES (?, ?, ?)
+		 ON CONFLICT(id, kind) DO UPDATE SET project_id = excluded.project_id`,
+		id, kind, projectID)
+	if err != nil {
+		return fmt.Errorf("record bucket index entry for %s: %w", id, err)
+	}
+	return nil
+}
+
+// lookupBucket returns the project that owns a previously-recorded id.
+// ok is false when id was never recorded under kind, which (in bucket
+// mode) means it predates bucket mode or lives in the shared tables -
+// callers should fall back to the shared table in that case.
+func (s *SQLiteStore) lookupBucket(ctx context.Context, id, kind string) (projectID string, ok bool, err error) {
+	err = s.db.QueryRowContext(ctx,
+		`SELECT project_id FROM bucket_index WHERE id = ? AND kind = ?`, id, kind).Scan(&projectID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("look up bucket for %s: %w", id, err)
+	}
+	return projectID, true, nil
+}
+
+// deleteBucketEntry forgets a previously-recorded id, e.g. once its row
+// has been deleted outright (rather than its whole project's bucket being
+// dropped) so bucket_index doesn't accumulate stale rows. exec runs it
+// against the store's connection directly, or (from inside a WithTx
+// callback) against that transaction, so it rolls back with the delete
+// it describes.
+func (s *SQLiteStore) deleteBucketEntry(ctx context.Context, exec txExecutor, id, kind string) error {
+	if _, err := exec.ExecContext(ctx, `DELETE FROM bucket_index WHERE id = ? AND kind = ?`, id, kind); err != nil {
+		return fmt.Errorf("delete bucket index entry for %s: %w", id, err)
+	}
+	return nil
+}
+
+// listBucketedProjects returns every project ID with its own bucket
+// tables, for fan-out operations like SearchSymbols that have no single
+// project to route through.
+func (s *SQLiteStore) listBucketedProjects(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT project_id FROM project_buckets`)
+	if err != nil {
+		return nil, fmt.Errorf("list bucketed projects: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan bucketed project id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// filesTableFor returns the table SaveFiles/GetFileByPath/ListFiles/etc.
+// should read and write for projectID: the shared "files" table, or (in
+// bucket mode) that project's own files_<id> table, creating it first if
+// this is the project's first write.
+func (s *SQLiteStore) filesTableFor(ctx context.Context, projectID string) (string, error) {
+	if !s.bucketTables {
+		return "files", nil
+	}
+	if err := s.ensureBucket(ctx, projectID); err != nil {
+		return "", err
+	}
+	filesTable, _, _ := bucketTableNames(projectID)
+	return filesTable, nil
+}
+
+// filesTableForFile resolves the files table that owns fileID, via
+// bucket_index, falling back to the shared "files" table outside bucket
+// mode or when fileID predates it.
+func (s *SQLiteStore) filesTableForFile(ctx context.Context, fileID string) (string, error) {
+	if !s.bucketTables {
+		return "files", nil
+	}
+	projectID, ok, err := s.lookupBucket(ctx, fileID, "file")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "files", nil
+	}
+	filesTable, _, _ := bucketTableNames(projectID)
+	return filesTable, nil
+}
+
+// chunksTableForFile resolves the chunks table that owns fileID, via
+// bucket_index, falling back to the shared "chunks" table outside bucket
+// mode or when fileID predates it.
+func (s *SQLiteStore) chunksTableForFile(ctx context.Context, fileID string) (string, error) {
+	if !s.bucketTables {
+		return "chunks", nil
+	}
+	projectID, ok, err := s.lookupBucket(ctx, fileID, "file")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "chunks", nil
+	}
+	_, chunksTable, _ := bucketTableNames(projectID)
+	return chunksTable, nil
+}
+
+// chunksTableForChunk resolves the chunks table that owns chunkID.
+func (s *SQLiteStore) chunksTableForChunk(ctx context.Context, chunkID string) (string, error) {
+	if !s.bucketTables {
+		return "chunks", nil
+	}
+	projectID, ok, err := s.lookupBucket(ctx, chunkID, "chunk")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "chunks", nil
+	}
+	_, chunksTable, _ := bucketTableNames(projectID)
+	return chunksTable, nil
+}
+
+// symbolsTableForChunk resolves the symbols table that owns chunkID.
+func (s *SQLiteStore) symbolsTableForChunk(ctx context.Context, chunkID string) (string, error) {
+	if !s.bucketTables {
+		return "symbols", nil
+	}
+	projectID, ok, err := s.lookupBucket(ctx, chunkID, "chunk")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "symbols", nil
+	}
+	_, _, symbolsTable := bucketTableNames(projectID)
+	return symbolsTable, nil
+}
@@ -0,0 +1,289 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BufferedBM25Options bounds how much a BufferedBM25Index buffers before
+// automatically flushing. A field left at 0 disables that particular
+// threshold; both at 0 means the buffer only flushes when Flush is called
+// explicitly.
+type BufferedBM25Options struct {
+	MaxBufferedDocs  int
+	MaxBufferedBytes int
+}
+
+// BufferedBM25Index wraps a backing BM25Index and buffers Index/Delete
+// calls in an in-memory secondary index instead of applying them
+// immediately, mirroring the write-buffer pattern Tendermint's
+// CacheKVStore and camlistore's buffered kv use: a bulk re-index can stage
+// every document, then either Flush atomically or Discard and leave the
+// backing store untouched - a large re-index no longer has to survive a
+// crash partway through to avoid corrupting the index.
+//
+// Reads (Search, AllIDs) merge the buffer over the backing store, with a
+// buffered delete masking any backing hit for the same ID.
+type BufferedBM25Index struct {
+	backing BM25Index
+
+	maxDocs  int
+	maxBytes int
+
+	mu      sync.Mutex
+	indexed map[string]*Document // buffered upserts, keyed by ID
+	deleted map[string]struct{}  // buffered deletes, masking backing hits
+	bytes   int                  // running estimate: sum of buffered docs' Content length
+}
+
+// NewBufferedBM25Index wraps backing with an empty buffer governed by
+// opts.
+func NewBufferedBM25Index(backing BM25Index, opts BufferedBM25Options) *BufferedBM25Index {
+	return &BufferedBM25Index{
+		backing:  backing,
+		maxDocs:  opts.MaxBufferedDocs,
+		maxBytes: opts.MaxBufferedBytes,
+		indexed:  make(map[string]*Document),
+		deleted:  make(map[string]struct{}),
+	}
+}
+
+// CacheWrap returns a new BufferedBM25Index layered on top of b, with its
+// own empty buffer and no automatic flush thresholds. Flushing the
+// returned index applies its writes into b's buffer (not b's backing
+// store) - the same nested-cache-store technique Tendermint's
+// CacheKVStore.CacheWrap and camlistore's buffered kv use to stack
+// speculative writes before committing them all the way down at once.
+func (b *BufferedBM25Index) CacheWrap() *BufferedBM25Index {
+	return NewBufferedBM25Index(b, BufferedBM25Options{})
+}
+
+// Index buffers docs, overwriting any earlier buffered version of the
+// same ID and clearing a pending buffered delete for it. If opts'
+// MaxBufferedDocs/MaxBufferedBytes threshold is reached, Index flushes the
+// buffer immediately afterward.
+func (b *BufferedBM25Index) Index(ctx context.Context, docs []*Document) error {
+	b.mu.Lock()
+	for _, d := range docs {
+		if old, ok := b.indexed[d.ID]; ok {
+			b.bytes -= len(old.Content)
+		}
+		b.indexed[d.ID] = d
+		delete(b.deleted, d.ID)
+		b.bytes += len(d.Content)
+	}
+	overflow := (b.maxDocs > 0 && len(b.indexed) >= b.maxDocs) ||
+		(b.maxBytes > 0 && b.bytes >= b.maxBytes)
+	b.mu.Unlock()
+
+	if overflow {
+		return b.Flush(ctx)
+	}
+	return nil
+}
+
+// Delete buffers docIDs as pending deletes, masking any backing hit for
+// those IDs and discarding any not-yet-flushed buffered document for them.
+func (b *BufferedBM25Index) Delete(ctx context.Context, docIDs []string) error {
+	b.mu.Lock()
+	for _, id := range docIDs {
+		if old, ok := b.indexed[id]; ok {
+			b.bytes -= len(old.Content)
+			delete(b.indexed, id)
+		}
+		b.deleted[id] = struct{}{}
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+// Flush applies every buffered delete, then every buffered document, to
+// the backing store, and clears the buffer on success. If either backing
+// call fails, the buffer is left intact (rather than partially cleared)
+// so the caller can fix the underlying problem and retry Flush without
+// having lost the staged writes - as close to atomic as a generic
+// BM25Index allows, since the interface itself exposes no transaction
+// hook to roll the backing store back with.
+func (b *BufferedBM25Index) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.deleted) > 0 {
+		ids := make([]string, 0, len(b.deleted))
+		for id := range b.deleted {
+			ids = append(ids, id)
+		}
+		if err := b.backing.Delete(ctx, ids); err != nil {
+			return fmt.Errorf("store: flush buffered deletes: %w", err)
+		}
+	}
+
+	if len(b.indexed) > 0 {
+		docs := make([]*Document, 0, len(b.indexed))
+		for _, d := range b.indexed {
+			docs = append(docs, d)
+		}
+		if err := b.backing.Index(ctx, docs); err != nil {
+			return fmt.Errorf("store: flush buffered documents: %w", err)
+		}
+	}
+
+	b.indexed = make(map[string]*Document)
+	b.deleted = make(map[string]struct{})
+	b.bytes = 0
+	return nil
+}
+
+// Discard drops every buffered write without touching the backing store -
+// the rollback half of BufferedBM25Index's stage-then-commit-or-discard
+// contract.
+func (b *BufferedBM25Index) Discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.indexed = make(map[string]*Document)
+	b.deleted = make(map[string]struct{})
+	b.bytes = 0
+}
+
+// Search merges the backing store's results with a lightweight scan over
+// the buffer, preferring the buffered version of any ID present in both
+// (it's the newer write) and excluding any ID buffered as deleted.
+func (b *BufferedBM25Index) Search(ctx context.Context, query string, limit int) ([]*BM25Result, error) {
+	b.mu.Lock()
+	deleted := make(map[string]struct{}, len(b.deleted))
+	for id := range b.deleted {
+		deleted[id] = struct{}{}
+	}
+	buffered := make([]*Document, 0, len(b.indexed))
+	for _, d := range b.indexed {
+		buffered = append(buffered, d)
+	}
+	b.mu.Unlock()
+
+	backingResults, err := b.backing.Search(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]*BM25Result, len(backingResults)+len(buffered))
+	for _, r := range backingResults {
+		if _, ok := deleted[r.DocID]; ok {
+			continue
+		}
+		merged[r.DocID] = r
+	}
+	for _, r := range searchBuffer(buffered, query) {
+		merged[r.DocID] = r
+	}
+
+	out := make([]*BM25Result, 0, len(merged))
+	for _, r := range merged {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// searchBuffer scores docs against query by counting overlapping
+// lowercased whitespace-separated terms - a lightweight stand-in for a
+// real BM25 postings list (a MockBM25Store-style structure, not a real
+// index), adequate for the small, short-lived buffer a BufferedBM25Index
+// holds before its next Flush.
+func searchBuffer(docs []*Document, query string) []*BM25Result {
+	terms := tokenizeForBuffer(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []*BM25Result
+	for _, d := range docs {
+		docTerms := make(map[string]struct{})
+		for _, t := range tokenizeForBuffer(d.Content) {
+			docTerms[t] = struct{}{}
+		}
+
+		var matched int
+		for _, t := range terms {
+			if _, ok := docTerms[t]; ok {
+				matched++
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		results = append(results, &BM25Result{DocID: d.ID, Score: float64(matched) / float64(len(terms))})
+	}
+	return results
+}
+
+func tokenizeForBuffer(s string) []string {
+	return strings.Fields(strings.ToLower(s))
+}
+
+// AllIDs merges the backing store's IDs with buffered IDs, excluding any
+// ID buffered as deleted.
+func (b *BufferedBM25Index) AllIDs() ([]string, error) {
+	backingIDs, err := b.backing.AllIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(backingIDs)+len(b.indexed))
+	out := make([]string, 0, len(backingIDs)+len(b.indexed))
+	for _, id := range backingIDs {
+		if _, ok := b.deleted[id]; ok {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	for id := range b.indexed {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// Stats returns the backing store's stats as-is: DocumentCount/TermCount
+// reflect only what's already been Flushed, not what's still staged in
+// the buffer. Computing an exact buffered delta would require resolving
+// which buffered IDs are new versus updates to existing backing
+// documents, which only the backing store itself can answer
+// authoritatively (via Flush).
+func (b *BufferedBM25Index) Stats() *IndexStats {
+	return b.backing.Stats()
+}
+
+// Save forwards to the backing store. Any buffered, unflushed writes are
+// not included - call Flush first if they need to be persisted too.
+func (b *BufferedBM25Index) Save(path string) error {
+	return b.backing.Save(path)
+}
+
+// Load forwards to the backing store and does not touch the buffer.
+func (b *BufferedBM25Index) Load(path string) error {
+	return b.backing.Load(path)
+}
+
+// Close forwards to the backing store. Any buffered, unflushed writes are
+// lost - call Flush first if they need to survive.
+func (b *BufferedBM25Index) Close() error {
+	return b.backing.Close()
+}
+
+var _ BM25Index = (*BufferedBM25Index)(nil)
@@ -0,0 +1,202 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBM25Store is a tiny, real (not function-pointer-stubbed) BM25Index
+// backing used by BufferedBM25Index's tests - unlike fakeBM25Index
+// (lazy_bm25_index_test.go), it actually stores and searches documents, so
+// merge/mask semantics between the buffer and the backing store can be
+// asserted on real data, the same role MockBM25Store plays in
+// pkg/indexer's own bm25_test.go.
+type memoryBM25Store struct {
+	docs      map[string]*Document
+	indexErr  error
+	deleteErr error
+}
+
+func newMemoryBM25Store() *memoryBM25Store {
+	return &memoryBM25Store{docs: make(map[string]*Document)}
+}
+
+func (m *memoryBM25Store) Index(ctx context.Context, docs []*Document) error {
+	if m.indexErr != nil {
+		return m.indexErr
+	}
+	for _, d := range docs {
+		m.docs[d.ID] = d
+	}
+	return nil
+}
+
+func (m *memoryBM25Store) Delete(ctx context.Context, docIDs []string) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	for _, id := range docIDs {
+		delete(m.docs, id)
+	}
+	return nil
+}
+
+func (m *memoryBM25Store) Search(ctx context.Context, query string, limit int) ([]*BM25Result, error) {
+	results := searchBuffer(docValues(m.docs), query)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (m *memoryBM25Store) AllIDs() ([]string, error) {
+	ids := make([]string, 0, len(m.docs))
+	for id := range m.docs {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (m *memoryBM25Store) Stats() *IndexStats {
+	return &IndexStats{DocumentCount: len(m.docs)}
+}
+
+func (m *memoryBM25Store) Save(path string) error { return nil }
+func (m *memoryBM25Store) Load(path string) error { return nil }
+func (m *memoryBM25Store) Close() error           { return nil }
+
+func docValues(m map[string]*Document) []*Document {
+	out := make([]*Document, 0, len(m))
+	for _, d := range m {
+		out = append(out, d)
+	}
+	return out
+}
+
+func TestBufferedBM25Index_SearchMergesBufferOverBacking(t *testing.T) {
+	backing := newMemoryBM25Store()
+	require.NoError(t, backing.Index(context.Background(), []*Document{{ID: "a", Content: "alpha document"}}))
+
+	buf := NewBufferedBM25Index(backing, BufferedBM25Options{})
+	require.NoError(t, buf.Index(context.Background(), []*Document{{ID: "b", Content: "beta document"}}))
+
+	results, err := buf.Search(context.Background(), "document", 10)
+	require.NoError(t, err)
+
+	ids := make(map[string]bool)
+	for _, r := range results {
+		ids[r.DocID] = true
+	}
+	assert.True(t, ids["a"], "expected a backing hit in the merged results")
+	assert.True(t, ids["b"], "expected a buffered hit in the merged results")
+}
+
+func TestBufferedBM25Index_BufferedDeleteMasksBackingHit(t *testing.T) {
+	backing := newMemoryBM25Store()
+	require.NoError(t, backing.Index(context.Background(), []*Document{{ID: "a", Content: "alpha document"}}))
+
+	buf := NewBufferedBM25Index(backing, BufferedBM25Options{})
+	require.NoError(t, buf.Delete(context.Background(), []string{"a"}))
+
+	results, err := buf.Search(context.Background(), "document", 10)
+	require.NoError(t, err)
+	assert.Empty(t, results, "expected the buffered delete to mask the backing hit")
+}
+
+func TestBufferedBM25Index_FlushAppliesToBackingAndClearsBuffer(t *testing.T) {
+	backing := newMemoryBM25Store()
+	buf := NewBufferedBM25Index(backing, BufferedBM25Options{})
+
+	require.NoError(t, buf.Index(context.Background(), []*Document{{ID: "a", Content: "alpha document"}}))
+	require.NoError(t, buf.Flush(context.Background()))
+
+	assert.Contains(t, backing.docs, "a", "expected Flush to write the buffered doc into backing")
+
+	// The buffer should be empty after a successful flush - a second
+	// flush with nothing buffered is a no-op, not an error.
+	require.NoError(t, buf.Flush(context.Background()))
+}
+
+func TestBufferedBM25Index_DiscardDropsBufferWithoutTouchingBacking(t *testing.T) {
+	backing := newMemoryBM25Store()
+	buf := NewBufferedBM25Index(backing, BufferedBM25Options{})
+
+	require.NoError(t, buf.Index(context.Background(), []*Document{{ID: "a", Content: "alpha document"}}))
+	buf.Discard()
+
+	assert.Empty(t, backing.docs, "expected Discard to never touch the backing store")
+
+	results, err := buf.Search(context.Background(), "alpha", 10)
+	require.NoError(t, err)
+	assert.Empty(t, results, "expected the discarded buffer to no longer contribute search hits")
+}
+
+func TestBufferedBM25Index_FlushErrorLeavesBufferIntact(t *testing.T) {
+	backing := newMemoryBM25Store()
+	backing.indexErr = errors.New("backing unavailable")
+	buf := NewBufferedBM25Index(backing, BufferedBM25Options{})
+
+	require.NoError(t, buf.Index(context.Background(), []*Document{{ID: "a", Content: "alpha document"}}))
+
+	err := buf.Flush(context.Background())
+	require.Error(t, err)
+
+	// The buffered write must still be visible to Search - Flush failing
+	// must not silently drop the staged document.
+	results, searchErr := buf.Search(context.Background(), "alpha", 10)
+	require.NoError(t, searchErr)
+	require.Len(t, results, 1)
+	assert.Equal(t, "a", results[0].DocID)
+
+	// Retrying after the backing store recovers should succeed.
+	backing.indexErr = nil
+	require.NoError(t, buf.Flush(context.Background()))
+	assert.Contains(t, backing.docs, "a")
+}
+
+func TestBufferedBM25Index_AutoFlushesAtMaxBufferedDocs(t *testing.T) {
+	backing := newMemoryBM25Store()
+	buf := NewBufferedBM25Index(backing, BufferedBM25Options{MaxBufferedDocs: 2})
+
+	require.NoError(t, buf.Index(context.Background(), []*Document{{ID: "a", Content: "one"}}))
+	assert.Empty(t, backing.docs, "expected no flush before the threshold is reached")
+
+	require.NoError(t, buf.Index(context.Background(), []*Document{{ID: "b", Content: "two"}}))
+	assert.Len(t, backing.docs, 2, "expected an automatic flush once MaxBufferedDocs was reached")
+}
+
+func TestBufferedBM25Index_AllIDsMergesAndMasksDeletes(t *testing.T) {
+	backing := newMemoryBM25Store()
+	require.NoError(t, backing.Index(context.Background(), []*Document{
+		{ID: "a", Content: "alpha"},
+		{ID: "b", Content: "beta"},
+	}))
+
+	buf := NewBufferedBM25Index(backing, BufferedBM25Options{})
+	require.NoError(t, buf.Delete(context.Background(), []string{"a"}))
+	require.NoError(t, buf.Index(context.Background(), []*Document{{ID: "c", Content: "gamma"}}))
+
+	ids, err := buf.AllIDs()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"b", "c"}, ids)
+}
+
+func TestBufferedBM25Index_CacheWrapNestsBuffersUntilOuterFlush(t *testing.T) {
+	backing := newMemoryBM25Store()
+	outer := NewBufferedBM25Index(backing, BufferedBM25Options{})
+	inner := outer.CacheWrap()
+
+	require.NoError(t, inner.Index(context.Background(), []*Document{{ID: "a", Content: "alpha"}}))
+	require.NoError(t, inner.Flush(context.Background()))
+
+	// Flushing inner applies its writes into outer's buffer, not all the
+	// way down to backing.
+	assert.Empty(t, backing.docs, "expected inner.Flush to stop at outer, not reach the real backing store")
+
+	require.NoError(t, outer.Flush(context.Background()))
+	assert.Contains(t, backing.docs, "a", "expected outer.Flush to finally apply the nested write to backing")
+}
@@ -0,0 +1,357 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrBulkSaveUnavailable is returned by BulkSaveChunks for stores it
+// doesn't support yet - mirrors Snapshot's own bucket-table exclusion,
+// since the staging tables this uses are the shared chunks/symbols names
+// and per-project bucket tables aren't resolved here.
+var ErrBulkSaveUnavailable = errors.New("store: bulk save not available for this store")
+
+// bulkSaveDefaultSubBatchSize is how many chunks BulkSaveChunks stages per
+// short transaction when BulkOpts.SubBatchSize is unset, matching the
+// request's "default 5k".
+const bulkSaveDefaultSubBatchSize = 5000
+
+// bulkSaveDefaultMaxStagedRows is how many unmerged rows BulkSaveChunks
+// allows to accumulate in the staging tables before it pauses draining ch
+// to run an interim merge, when BulkOpts.MaxStagedRows is unset.
+const bulkSaveDefaultMaxStagedRows = 50000
+
+// BulkOpts configures BulkSaveChunks.
+type BulkOpts struct {
+	// SubBatchSize is how many chunks are staged per short transaction.
+	// Defaults to bulkSaveDefaultSubBatchSize.
+	SubBatchSize int
+
+	// MaxStagedRows bounds how many rows may sit in the staging tables
+	// before BulkSaveChunks stops draining ch and merges early. Defaults
+	// to bulkSaveDefaultMaxStagedRows.
+	MaxStagedRows int
+
+	// OnProgress, if set, is called after every sub-batch is staged and
+	// after every merge (interim or final).
+	OnProgress func(BulkProgress)
+}
+
+// BulkProgress reports BulkSaveChunks' progress so far, passed to
+// BulkOpts.OnProgress.
+type BulkProgress struct {
+	// RowsStaged is the number of chunks staged since the call began,
+	// including ones already merged.
+	RowsStaged int
+
+	// RowsMerged is the number of chunks merged into the real tables so
+	// far (across the final merge and any interim merges).
+	RowsMerged int
+
+	// Batches is how many sub-batches have been staged so far.
+	Batches int
+}
+
+// BulkResult summarizes a completed BulkSaveChunks call.
+type BulkResult struct {
+	ChunksMerged  int
+	SymbolsMerged int
+	Batches       int
+}
+
+func (o BulkOpts) withDefaults() BulkOpts {
+	if o.SubBatchSize <= 0 {
+		o.SubBatchSize = bulkSaveDefaultSubBatchSize
+	}
+	if o.MaxStagedRows <= 0 {
+		o.MaxStagedRows = bulkSaveDefaultMaxStagedRows
+	}
+	return o
+}
+
+// BulkSaveChunks drains ch and ingests its chunks without holding one
+// giant transaction open for the whole run, the way SaveChunks does - on
+// a multi-hundred-thousand-chunk repo that single transaction blocks
+// every other caller of s.db (SetMaxOpenConns(1), see NewSQLiteStoreWithConfig)
+// for as long as it takes to save every chunk, and grows the WAL to match.
+//
+// Instead, chunks are staged into chunks_staging/symbols_staging - plain
+// tables with none of the real tables' indexes, so appending to them is
+// cheap - in opts.SubBatchSize-sized groups, each committed as its own
+// short transaction (following pq.CopyIn's staged-COPY-then-merge shape).
+// Once every chunk from ch is staged, a single INSERT ... SELECT ... ON
+// CONFLICT DO UPDATE merges the staging tables into chunks/symbols under
+// one final transaction. chunks_fts (including its symbol columns) is kept
+// in sync by the same AFTER INSERT/UPDATE triggers ensureFTSSchema already
+// installs on chunks/symbols, so the merge needs no separate FTS rebuild step; the
+// vector index isn't touched here either, matching SaveChunks - chunks
+// arrive over ch without embeddings, so there's nothing yet for
+// s.vectorIndex to index.
+//
+// If staged-but-unmerged rows reach opts.MaxStagedRows before ch is
+// drained, BulkSaveChunks runs an interim merge before reading any more
+// from ch - since that merge runs synchronously in the same goroutine
+// that would otherwise be receiving from ch, a producer feeding ch
+// through a bounded or unbuffered channel naturally blocks on its next
+// send for as long as the merge takes, which is the backpressure the
+// request asks for without needing a separate semaphore.
+//
+// After every sub-batch is staged, the row count staged so far is
+// persisted via SaveIndexCheckpoint under stage "bulk_ingest" so a
+// crashed run can tell its caller how many chunks from the head of ch
+// were already durably staged (or merged, after an interim/final merge)
+// and should be skipped on resume; BulkSaveChunks itself doesn't know
+// ch's origin and can't seek it.
+//
+// Unavailable (ErrBulkSaveUnavailable) for stores with SupportBucketTable
+// enabled: the staging tables merge into the shared chunks/symbols
+// tables by name, and per-project bucket tables aren't resolved here.
+func (s *SQLiteStore) BulkSaveChunks(ctx context.Context, ch <-chan *Chunk, opts BulkOpts) (BulkResult, error) {
+	if s.bucketTables {
+		return BulkResult{}, ErrBulkSaveUnavailable
+	}
+	opts = opts.withDefaults()
+
+	if err := s.createBulkStagingTables(ctx); err != nil {
+		return BulkResult{}, err
+	}
+	defer func() {
+		if err := s.dropBulkStagingTables(context.Background()); err != nil {
+			slog.Warn("bulk_save_staging_cleanup_failed", slog.String("error", err.Error()))
+		}
+	}()
+
+	var result BulkResult
+	var staged, stagedSinceMerge int
+	batch := make([]*Chunk, 0, opts.SubBatchSize)
+
+	stageBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.stageBulkBatch(ctx, batch); err != nil {
+			return fmt.Errorf("bulk save: stage batch: %w", err)
+		}
+		staged += len(batch)
+		stagedSinceMerge += len(batch)
+		result.Batches++
+		batch = batch[:0]
+
+		if err := s.SaveIndexCheckpoint(ctx, "bulk_ingest", 0, staged, ""); err != nil {
+			return fmt.Errorf("bulk save: checkpoint batch offset %d: %w", staged, err)
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(BulkProgress{RowsStaged: staged, RowsMerged: result.ChunksMerged, Batches: result.Batches})
+		}
+
+		if stagedSinceMerge >= opts.MaxStagedRows {
+			merged, err := s.mergeBulkStaging(ctx)
+			if err != nil {
+				return fmt.Errorf("bulk save: interim merge: %w", err)
+			}
+			result.ChunksMerged += merged.chunks
+			result.SymbolsMerged += merged.symbols
+			stagedSinceMerge = 0
+			if opts.OnProgress != nil {
+				opts.OnProgress(BulkProgress{RowsStaged: staged, RowsMerged: result.ChunksMerged, Batches: result.Batches})
+			}
+		}
+		return nil
+	}
+
+drain:
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case c, ok := <-ch:
+			if !ok {
+				break drain
+			}
+			batch = append(batch, c)
+			if len(batch) >= opts.SubBatchSize {
+				if err := stageBatch(); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+	if err := stageBatch(); err != nil {
+		return result, err
+	}
+
+	merged, err := s.mergeBulkStaging(ctx)
+	if err != nil {
+		return result, fmt.Errorf("bulk save: final merge: %w", err)
+	}
+	result.ChunksMerged += merged.chunks
+	result.SymbolsMerged += merged.symbols
+
+	if err := s.ClearIndexCheckpoint(ctx); err != nil {
+		return result, fmt.Errorf("bulk save: clear checkpoint: %w", err)
+	}
+	slog.Info("bulk_save_complete",
+		slog.Int("chunks_merged", result.ChunksMerged), slog.Int("symbols_merged", result.SymbolsMerged), slog.Int("batches", result.Batches))
+	return result, nil
+}
+
+func (s *SQLiteStore) createBulkStagingTables(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+		CREATE TEMP TABLE IF NOT EXISTS chunks_staging (
+			id TEXT, file_id TEXT, file_path TEXT, content BLOB, raw_content BLOB, context BLOB,
+			content_type TEXT, language TEXT, start_line INTEGER, end_line INTEGER, metadata TEXT,
+			created_at TIMESTAMP, updated_at TIMESTAMP
+		);
+		CREATE TEMP TABLE IF NOT EXISTS symbols_staging (
+			chunk_id TEXT, name TEXT, type TEXT, start_line INTEGER, end_line INTEGER, signature TEXT, doc_comment TEXT
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("bulk save: create staging tables: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) dropBulkStagingTables(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `DROP TABLE IF EXISTS chunks_staging; DROP TABLE IF EXISTS symbols_staging;`)
+	if err != nil {
+		return fmt.Errorf("bulk save: drop staging tables: %w", err)
+	}
+	return nil
+}
+
+// stageBulkBatch appends batch to the staging tables under its own short
+// transaction - encoding content/raw_content/context the same way
+// saveChunksIntoTables does, so a merged row reads back identically to
+// one SaveChunks would have written.
+func (s *SQLiteStore) stageBulkBatch(ctx context.Context, batch []*Chunk) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin stage transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	chunkStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO chunks_staging (id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare chunk staging statement: %w", err)
+	}
+	defer func() { _ = chunkStmt.Close() }()
+
+	symbolStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO symbols_staging (chunk_id, name, type, start_line, end_line, signature, doc_comment)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare symbol staging statement: %w", err)
+	}
+	defer func() { _ = symbolStmt.Close() }()
+
+	for _, chunk := range batch {
+		var metadataJSON []byte
+		if chunk.Metadata != nil {
+			metadataJSON, _ = json.Marshal(chunk.Metadata)
+		}
+
+		content, err := encodeChunkColumn(s.chunkCompression, chunk.Content)
+		if err != nil {
+			return fmt.Errorf("failed to compress chunk %s content: %w", chunk.ID, err)
+		}
+		rawContent, err := encodeChunkColumn(s.chunkCompression, chunk.RawContent)
+		if err != nil {
+			return fmt.Errorf("failed to compress chunk %s raw_content: %w", chunk.ID, err)
+		}
+		chunkContext, err := encodeChunkColumn(s.chunkCompression, chunk.Context)
+		if err != nil {
+			return fmt.Errorf("failed to compress chunk %s context: %w", chunk.ID, err)
+		}
+
+		if _, err := chunkStmt.ExecContext(ctx,
+			chunk.ID, chunk.FileID, chunk.FilePath, content, rawContent, chunkContext,
+			string(chunk.ContentType), chunk.Language, chunk.StartLine, chunk.EndLine,
+			string(metadataJSON), chunk.CreatedAt, chunk.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to stage chunk %s: %w", chunk.ID, err)
+		}
+
+		for _, sym := range chunk.Symbols {
+			if _, err := symbolStmt.ExecContext(ctx,
+				chunk.ID, sym.Name, string(sym.Type), sym.StartLine, sym.EndLine, sym.Signature, sym.DocComment); err != nil {
+				return fmt.Errorf("failed to stage symbol for chunk %s: %w", chunk.ID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit stage transaction: %w", err)
+	}
+	return nil
+}
+
+type bulkMergeCounts struct {
+	chunks  int
+	symbols int
+}
+
+// mergeBulkStaging merges everything currently in the staging tables into
+// chunks/symbols under one transaction, then empties the staging tables
+// so a later interim merge (or the next BulkSaveChunks call reusing the
+// same connection's temp tables) doesn't re-merge the same rows.
+func (s *SQLiteStore) mergeBulkStaging(ctx context.Context) (bulkMergeCounts, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return bulkMergeCounts{}, fmt.Errorf("begin merge transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The WHERE 1=1 isn't filtering anything - SQLite's parser treats a
+	// bare "SELECT ... FROM t ON CONFLICT" as ambiguous and rejects it;
+	// any clause between the FROM and ON CONFLICT disambiguates it.
+	chunkRes, err := tx.ExecContext(ctx, `
+		INSERT INTO chunks (id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at)
+		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at FROM chunks_staging WHERE 1 = 1
+		ON CONFLICT(id) DO UPDATE SET
+			file_id = excluded.file_id,
+			file_path = excluded.file_path,
+			content = excluded.content,
+			raw_content = excluded.raw_content,
+			context = excluded.context,
+			content_type = excluded.content_type,
+			language = excluded.language,
+			start_line = excluded.start_line,
+			end_line = excluded.end_line,
+			metadata = excluded.metadata,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return bulkMergeCounts{}, fmt.Errorf("merge staged chunks: %w", err)
+	}
+	chunksMerged, _ := chunkRes.RowsAffected()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM symbols WHERE chunk_id IN (SELECT DISTINCT chunk_id FROM symbols_staging)
+	`); err != nil {
+		return bulkMergeCounts{}, fmt.Errorf("merge staged symbols: delete old: %w", err)
+	}
+	symbolRes, err := tx.ExecContext(ctx, `
+		INSERT INTO symbols (chunk_id, name, type, start_line, end_line, signature, doc_comment)
+		SELECT chunk_id, name, type, start_line, end_line, signature, doc_comment FROM symbols_staging
+	`)
+	if err != nil {
+		return bulkMergeCounts{}, fmt.Errorf("merge staged symbols: insert: %w", err)
+	}
+	symbolsMerged, _ := symbolRes.RowsAffected()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM chunks_staging; DELETE FROM symbols_staging;`); err != nil {
+		return bulkMergeCounts{}, fmt.Errorf("clear staging tables: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return bulkMergeCounts{}, fmt.Errorf("commit merge transaction: %w", err)
+	}
+	return bulkMergeCounts{chunks: int(chunksMerged), symbols: int(symbolsMerged)}, nil
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBulkSaveTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func feedChunks(ch chan<- *Chunk, chunks []*Chunk) {
+	defer close(ch)
+	for _, c := range chunks {
+		ch <- c
+	}
+}
+
+func TestSQLiteStore_BulkSaveChunksMergesAllRows(t *testing.T) {
+	s := newBulkSaveTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+
+	const numChunks = 12345
+	chunks := make([]*Chunk, numChunks)
+	for i := 0; i < numChunks; i++ {
+		chunks[i] = &Chunk{
+			ID: idForBulkChunk(i), FileID: "file1", FilePath: "a.go",
+			Content: "content", StartLine: i, EndLine: i + 1,
+			Symbols: []*Symbol{{Name: "sym", Type: SymbolType("func")}},
+		}
+	}
+
+	ch := make(chan *Chunk)
+	go feedChunks(ch, chunks)
+
+	var progressCalls int
+	result, err := s.BulkSaveChunks(ctx, ch, BulkOpts{
+		SubBatchSize:  1000,
+		MaxStagedRows: 5000,
+		OnProgress:    func(BulkProgress) { progressCalls++ },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, numChunks, result.ChunksMerged)
+	assert.Equal(t, numChunks, result.SymbolsMerged)
+	assert.Greater(t, progressCalls, 0)
+
+	got, err := s.GetChunksByFile(ctx, "file1")
+	require.NoError(t, err)
+	assert.Len(t, got, numChunks)
+
+	checkpoint, err := s.LoadIndexCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
+
+func idForBulkChunk(i int) string {
+	const letters = "0123456789abcdef"
+	id := make([]byte, 8)
+	for j := range id {
+		id[j] = letters[(i>>(j*4))&0xf]
+	}
+	return "chunk-" + string(id)
+}
+
+func TestSQLiteStore_BulkSaveChunksUpsertsExistingChunk(t *testing.T) {
+	s := newBulkSaveTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, s.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "old", StartLine: 1, EndLine: 2},
+	}))
+
+	ch := make(chan *Chunk, 1)
+	ch <- &Chunk{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "new", StartLine: 1, EndLine: 2}
+	close(ch)
+
+	result, err := s.BulkSaveChunks(ctx, ch, BulkOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ChunksMerged)
+
+	got, err := s.GetChunk(ctx, "chunk1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "new", got.Content)
+}
+
+func TestSQLiteStore_BulkSaveChunksUnavailableForBucketTables(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStoreWithConfig(dbPath, StoreConfig{SupportBucketTable: true})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	ch := make(chan *Chunk)
+	close(ch)
+	_, err = s.BulkSaveChunks(context.Background(), ch, BulkOpts{})
+	assert.ErrorIs(t, err, ErrBulkSaveUnavailable)
+}
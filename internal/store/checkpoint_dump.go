@@ -0,0 +1,284 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// ErrCheckpointModelMismatch is returned by RestoreIndexCheckpoint when a
+// dumped chunk's embedder model or embedding dimensions don't match what
+// the caller expects for the target store - the same check
+// MMapVectorStore.Load does against its own shard headers.
+var ErrCheckpointModelMismatch = errors.New("store: checkpoint embedder model or dimensions mismatch")
+
+// checkpointDumpFormatVersion guards RestoreIndexCheckpoint against reading
+// a stream written by a future, incompatible DumpIndexCheckpoint.
+const checkpointDumpFormatVersion = 1
+
+// checkpointDumpBatchSize is how many chunk rows DumpIndexCheckpoint reads
+// per keyset page, matching the repo's other chunked-walk batch sizes (see
+// vectorIndexRebuildBatchSize).
+const checkpointDumpBatchSize = 1000
+
+// checkpointDumpHeader is the first gob value on the stream; everything
+// after it is a sequence of checkpointDumpRecord values until io.EOF.
+type checkpointDumpHeader struct {
+	FormatVersion int
+}
+
+// checkpointDumpKind discriminates the union of row kinds that follow the
+// header. gob has no native union type, so each record carries its kind and
+// only the matching field is populated.
+type checkpointDumpKind uint8
+
+const (
+	checkpointDumpKindState checkpointDumpKind = iota
+	checkpointDumpKindFile
+	checkpointDumpKindChunk
+)
+
+type checkpointDumpRecord struct {
+	Kind  checkpointDumpKind
+	State checkpointDumpStateRow
+	File  checkpointDumpFileRow
+	Chunk checkpointDumpChunkRow
+}
+
+type checkpointDumpStateRow struct {
+	Key   string
+	Value string
+}
+
+type checkpointDumpFileRow struct {
+	ID          string
+	ProjectID   string
+	Path        string
+	ContentHash string
+	ModTime     time.Time
+}
+
+type checkpointDumpChunkRow struct {
+	ID            string
+	FileID        string
+	EmbedderModel string
+	EmbeddingDims int
+	Embedding     []float32
+}
+
+// DumpIndexCheckpoint streams this store's resumable-indexing state - the
+// state table (the StateKeyCheckpoint* keys SaveIndexCheckpoint writes),
+// every file's content hash and mtime, and every embedded chunk's (id,
+// file_id, embedder_model, embedding_dims, embedding) - to w as a sequence
+// of gob values. Paired with RestoreIndexCheckpoint on another machine's
+// store, a half-finished index (files already hashed, chunks already
+// embedded) can resume there without re-running the embedder, following
+// tidb-lightning's checkpoint-dump/checkpoint-restore pattern.
+//
+// gob's own per-value framing is the "length-prefixed" part of the format:
+// each Encode call is self-delimiting on the wire, so Restore can decode one
+// record at a time instead of buffering the whole dump in memory, and chunk
+// rows are themselves read from the DB in checkpointDumpBatchSize-sized
+// keyset pages for the same reason.
+func (s *SQLiteStore) DumpIndexCheckpoint(ctx context.Context, w io.Writer) error {
+	enc := gob.NewEncoder(w)
+
+	if err := enc.Encode(checkpointDumpHeader{FormatVersion: checkpointDumpFormatVersion}); err != nil {
+		return fmt.Errorf("dump checkpoint header: %w", err)
+	}
+	if err := s.dumpStateRows(ctx, enc); err != nil {
+		return err
+	}
+	if err := s.dumpFileRows(ctx, enc); err != nil {
+		return err
+	}
+	if err := s.dumpChunkRows(ctx, enc); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SQLiteStore) dumpStateRows(ctx context.Context, enc *gob.Encoder) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM state`)
+	if err != nil {
+		return fmt.Errorf("dump state rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		rec := checkpointDumpRecord{Kind: checkpointDumpKindState}
+		if err := rows.Scan(&rec.State.Key, &rec.State.Value); err != nil {
+			return fmt.Errorf("dump state rows: scan: %w", err)
+		}
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("dump state rows: encode: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+func (s *SQLiteStore) dumpFileRows(ctx context.Context, enc *gob.Encoder) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, project_id, path, COALESCE(content_hash, ''), mod_time FROM files`)
+	if err != nil {
+		return fmt.Errorf("dump file rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		rec := checkpointDumpRecord{Kind: checkpointDumpKindFile}
+		var modTime sql.NullTime
+		if err := rows.Scan(&rec.File.ID, &rec.File.ProjectID, &rec.File.Path, &rec.File.ContentHash, &modTime); err != nil {
+			return fmt.Errorf("dump file rows: scan: %w", err)
+		}
+		if modTime.Valid {
+			rec.File.ModTime = modTime.Time
+		}
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("dump file rows: encode: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+func (s *SQLiteStore) dumpChunkRows(ctx context.Context, enc *gob.Encoder) error {
+	var lastRowID int64
+	for {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT rowid, id, file_id, embedding_model, embedding_dims, embedding FROM chunks
+			 WHERE rowid > ? AND embedding IS NOT NULL
+			 ORDER BY rowid LIMIT ?`, lastRowID, checkpointDumpBatchSize)
+		if err != nil {
+			return fmt.Errorf("dump chunk rows: %w", err)
+		}
+
+		var scanned int
+		for rows.Next() {
+			rec := checkpointDumpRecord{Kind: checkpointDumpKindChunk}
+			var rowID int64
+			var model sql.NullString
+			var dims sql.NullInt64
+			var embBytes []byte
+			if err := rows.Scan(&rowID, &rec.Chunk.ID, &rec.Chunk.FileID, &model, &dims, &embBytes); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("dump chunk rows: scan: %w", err)
+			}
+			scanned++
+			lastRowID = rowID
+			rec.Chunk.EmbedderModel = model.String
+			rec.Chunk.EmbeddingDims = int(dims.Int64)
+			rec.Chunk.Embedding = bytesToEmbedding(embBytes)
+
+			if err := enc.Encode(rec); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("dump chunk rows: encode: %w", err)
+			}
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return fmt.Errorf("dump chunk rows: iterate: %w", err)
+		}
+		if scanned < checkpointDumpBatchSize {
+			break
+		}
+	}
+	return nil
+}
+
+// RestoreIndexCheckpoint reads a stream written by DumpIndexCheckpoint and
+// applies it to this store inside a single transaction, so an error or
+// crash partway through restore leaves the target untouched rather than
+// half-migrated.
+//
+// Every chunk row's embedder model and embedding dimensions are checked
+// against expectedModel/expectedDims - the model and dimension count the
+// target's own embedder is configured for - before anything is written; a
+// mismatch on any row aborts the whole restore with
+// ErrCheckpointModelMismatch rather than silently mixing embeddings from
+// two different models into one index.
+//
+// Restore is idempotent: state rows and file hash/mtime rows use INSERT OR
+// IGNORE (a file already present on the target, synced by its own indexing
+// run, is left alone), and chunk embeddings are applied with UPDATE ...
+// WHERE embedding IS NULL - chunks.content is NOT NULL, so unlike
+// state/files a chunk row can't be created from the dump's embedding-only
+// tuple; restoring an embedding presumes the chunk itself already reached
+// the target through normal indexing, and a chunk that's missing or
+// already embedded there is silently skipped rather than erroring.
+func (s *SQLiteStore) RestoreIndexCheckpoint(ctx context.Context, r io.Reader, expectedModel string, expectedDims int) error {
+	dec := gob.NewDecoder(r)
+
+	var header checkpointDumpHeader
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("restore checkpoint header: %w", err)
+	}
+	if header.FormatVersion != checkpointDumpFormatVersion {
+		return fmt.Errorf("restore checkpoint: unsupported format version %d", header.FormatVersion)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin restore transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var stateRows, fileRows, chunkRows int
+	for {
+		var rec checkpointDumpRecord
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("restore checkpoint: decode record: %w", err)
+		}
+
+		switch rec.Kind {
+		case checkpointDumpKindState:
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO state (key, value, updated_at) VALUES (?, ?, ?)`,
+				rec.State.Key, rec.State.Value, time.Now()); err != nil {
+				return fmt.Errorf("restore state %s: %w", rec.State.Key, err)
+			}
+			stateRows++
+		case checkpointDumpKindFile:
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO files (id, project_id, path, content_hash, mod_time) VALUES (?, ?, ?, ?, ?)`,
+				rec.File.ID, rec.File.ProjectID, rec.File.Path, rec.File.ContentHash, rec.File.ModTime); err != nil {
+				return fmt.Errorf("restore file %s: %w", rec.File.ID, err)
+			}
+			fileRows++
+		case checkpointDumpKindChunk:
+			if rec.Chunk.EmbedderModel != expectedModel || rec.Chunk.EmbeddingDims != expectedDims {
+				return fmt.Errorf("%w: chunk %s has model %q dims %d, want model %q dims %d",
+					ErrCheckpointModelMismatch, rec.Chunk.ID, rec.Chunk.EmbedderModel, rec.Chunk.EmbeddingDims, expectedModel, expectedDims)
+			}
+			res, err := tx.ExecContext(ctx,
+				`UPDATE chunks SET embedding = ?, embedding_model = ?, embedding_dims = ?
+				 WHERE id = ? AND embedding IS NULL`,
+				embeddingToBytes(rec.Chunk.Embedding), rec.Chunk.EmbedderModel, rec.Chunk.EmbeddingDims, rec.Chunk.ID)
+			if err != nil {
+				return fmt.Errorf("restore chunk embedding %s: %w", rec.Chunk.ID, err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				slog.Debug("checkpoint_restore_chunk_skipped", slog.String("chunk_id", rec.Chunk.ID))
+			}
+			chunkRows++
+		default:
+			return fmt.Errorf("restore checkpoint: unknown record kind %d", rec.Kind)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit restore transaction: %w", err)
+	}
+	slog.Info("checkpoint_restore_complete",
+		slog.Int("state_rows", stateRows), slog.Int("file_rows", fileRows), slog.Int("chunk_rows", chunkRows))
+	return nil
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCheckpointDumpTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestSQLiteStore_DumpAndRestoreIndexCheckpointRoundTrips(t *testing.T) {
+	src := newCheckpointDumpTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, src.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, src.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go", ContentHash: "deadbeef"}}))
+	require.NoError(t, src.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "x", StartLine: 1, EndLine: 2},
+	}))
+	require.NoError(t, src.SaveChunkEmbeddings(ctx, []string{"chunk1"}, [][]float32{{1, 2, 3}}, "test-model"))
+	require.NoError(t, src.SaveIndexCheckpoint(ctx, "embedding", 1, 1, "test-model"))
+
+	var buf bytes.Buffer
+	require.NoError(t, src.DumpIndexCheckpoint(ctx, &buf))
+
+	dst := newCheckpointDumpTestStore(t)
+	require.NoError(t, dst.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, dst.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, dst.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "x", StartLine: 1, EndLine: 2},
+	}))
+
+	require.NoError(t, dst.RestoreIndexCheckpoint(ctx, &buf, "test-model", 3))
+
+	checkpoint, err := dst.LoadIndexCheckpoint(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, "embedding", checkpoint.Stage)
+	assert.Equal(t, "test-model", checkpoint.EmbedderModel)
+
+	assertChunkEmbedding(t, dst, "chunk1", []float32{1, 2, 3}, "test-model")
+}
+
+func assertChunkEmbedding(t *testing.T, s *SQLiteStore, chunkID string, want []float32, wantModel string) {
+	t.Helper()
+	var embBytes []byte
+	var model string
+	var dims int
+	require.NoError(t, s.db.QueryRow(
+		`SELECT embedding, embedding_model, embedding_dims FROM chunks WHERE id = ?`, chunkID,
+	).Scan(&embBytes, &model, &dims))
+	assert.Equal(t, want, bytesToEmbedding(embBytes))
+	assert.Equal(t, wantModel, model)
+	assert.Equal(t, len(want), dims)
+}
+
+func TestSQLiteStore_RestoreIndexCheckpointRejectsModelMismatch(t *testing.T) {
+	src := newCheckpointDumpTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, src.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, src.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, src.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "x", StartLine: 1, EndLine: 2},
+	}))
+	require.NoError(t, src.SaveChunkEmbeddings(ctx, []string{"chunk1"}, [][]float32{{1, 2, 3}}, "test-model"))
+
+	var buf bytes.Buffer
+	require.NoError(t, src.DumpIndexCheckpoint(ctx, &buf))
+
+	dst := newCheckpointDumpTestStore(t)
+	require.NoError(t, dst.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	err := dst.RestoreIndexCheckpoint(ctx, &buf, "other-model", 3)
+	assert.ErrorIs(t, err, ErrCheckpointModelMismatch)
+}
+
+func TestSQLiteStore_RestoreIndexCheckpointIsIdempotent(t *testing.T) {
+	src := newCheckpointDumpTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, src.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, src.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, src.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "x", StartLine: 1, EndLine: 2},
+	}))
+	require.NoError(t, src.SaveChunkEmbeddings(ctx, []string{"chunk1"}, [][]float32{{1, 2, 3}}, "test-model"))
+
+	var buf1, buf2 bytes.Buffer
+	require.NoError(t, src.DumpIndexCheckpoint(ctx, &buf1))
+	require.NoError(t, src.DumpIndexCheckpoint(ctx, &buf2))
+
+	dst := newCheckpointDumpTestStore(t)
+	require.NoError(t, dst.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, dst.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, dst.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "x", StartLine: 1, EndLine: 2},
+	}))
+
+	require.NoError(t, dst.RestoreIndexCheckpoint(ctx, &buf1, "test-model", 3))
+	require.NoError(t, dst.RestoreIndexCheckpoint(ctx, &buf2, "test-model", 3))
+
+	assertChunkEmbedding(t, dst, "chunk1", []float32{1, 2, 3}, "test-model")
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ChunkCompression selects the algorithm SaveChunks uses to compress the
+// chunks table's content/raw_content/context columns before writing them,
+// trading a little CPU for a smaller chunks table and better mmap_size
+// page-cache residency under ProfileReadHeavy.
+type ChunkCompression string
+
+const (
+	// ChunkCompressionNone stores content/raw_content/context as-is
+	// (still header-tagged, so rows written under different
+	// StoreConfig.ChunkCompression settings over a store's lifetime stay
+	// readable side by side).
+	ChunkCompressionNone ChunkCompression = "none"
+	// ChunkCompressionSnappy favors decompression speed over ratio.
+	ChunkCompressionSnappy ChunkCompression = "snappy"
+	// ChunkCompressionZstd favors ratio over decompression speed.
+	ChunkCompressionZstd ChunkCompression = "zstd"
+)
+
+// Header bytes prepended to every content/raw_content/context BLOB
+// written after this feature shipped, so decodeChunkColumn knows which
+// algorithm (if any) to reverse. Rows written before the feature existed
+// have no header byte at all - their first byte is whatever the source
+// text started with, which in practice is never one of these three
+// values - so decodeChunkColumn's default case treats an unrecognized
+// first byte as legacy plaintext and returns the blob unchanged.
+const (
+	chunkCompressionHeaderNone   byte = 0x00
+	chunkCompressionHeaderSnappy byte = 0x01
+	chunkCompressionHeaderZstd   byte = 0x02
+)
+
+// zstdEncoder/zstdDecoder are shared across all zstd encode/decode calls:
+// both types are safe for concurrent use and expensive enough to
+// construct (they allocate internal windows/tables) that the store
+// builds them once, lazily, the first time zstd compression is actually
+// used rather than unconditionally on every store open.
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdInitErr error
+)
+
+func initZstd() {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdInitErr = zstd.NewWriter(nil)
+		if zstdInitErr != nil {
+			return
+		}
+		zstdDecoder, zstdInitErr = zstd.NewReader(nil)
+	})
+}
+
+// encodeChunkColumn compresses data under compression and prepends its
+// header byte. It always returns a header-tagged blob, even under
+// ChunkCompressionNone or for an empty string, so every row written
+// after this feature exists is unambiguously distinguishable from
+// pre-feature legacy rows by decodeChunkColumn.
+func encodeChunkColumn(compression ChunkCompression, data string) ([]byte, error) {
+	switch compression {
+	case ChunkCompressionSnappy:
+		return append([]byte{chunkCompressionHeaderSnappy}, snappy.Encode(nil, []byte(data))...), nil
+	case ChunkCompressionZstd:
+		initZstd()
+		if zstdInitErr != nil {
+			return nil, fmt.Errorf("init zstd encoder: %w", zstdInitErr)
+		}
+		return zstdEncoder.EncodeAll([]byte(data), []byte{chunkCompressionHeaderZstd}), nil
+	default:
+		return append([]byte{chunkCompressionHeaderNone}, data...), nil
+	}
+}
+
+// decodeChunkColumn reverses encodeChunkColumn, detecting the algorithm
+// from the header byte. An unrecognized (or absent) header byte means
+// data predates this feature and is returned verbatim as legacy
+// plaintext.
+func decodeChunkColumn(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	switch data[0] {
+	case chunkCompressionHeaderNone:
+		return string(data[1:]), nil
+	case chunkCompressionHeaderSnappy:
+		decoded, err := snappy.Decode(nil, data[1:])
+		if err != nil {
+			return "", fmt.Errorf("snappy decode: %w", err)
+		}
+		return string(decoded), nil
+	case chunkCompressionHeaderZstd:
+		initZstd()
+		if zstdInitErr != nil {
+			return "", fmt.Errorf("init zstd decoder: %w", zstdInitErr)
+		}
+		decoded, err := zstdDecoder.DecodeAll(data[1:], nil)
+		if err != nil {
+			return "", fmt.Errorf("zstd decode: %w", err)
+		}
+		return string(decoded), nil
+	default:
+		return string(data), nil
+	}
+}
+
+// isChunkCompressionHeader reports whether b is one of this feature's
+// header bytes, as opposed to the first byte of legacy plaintext that
+// predates it.
+func isChunkCompressionHeader(b byte) bool {
+	return b == chunkCompressionHeaderNone || b == chunkCompressionHeaderSnappy || b == chunkCompressionHeaderZstd
+}
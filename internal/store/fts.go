@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrFTSNotEnabled is returned by SearchText (and surfaces through
+// HybridSearchChunks as a fallback to vector-only results) when this
+// store's SQLite build lacks the FTS5 module, or StoreConfig.DisableFTS
+// was set.
+var ErrFTSNotEnabled = errors.New("store: FTS5 full-text index not enabled")
+
+// hybridRRFConstant is the k used to combine SearchText and
+// TopKByEmbedding rankings in HybridSearchChunks, matching
+// search.DefaultRRFConstant so a chunk's fused rank behaves the same
+// whether it's computed here or by the search package's own RRFFusion.
+const hybridRRFConstant = 60
+
+// ftsSupported reports whether db's SQLite library was compiled with the
+// FTS5 extension, via the same PRAGMA sqlite3_compileoption_used() would
+// check in C. mattn/go-sqlite3 only links FTS5 in when built with the
+// "sqlite_fts5" build tag, so NewSQLiteStoreWithConfig probes at runtime
+// rather than assuming it's there.
+func ftsSupported(ctx context.Context, db *sql.DB) bool {
+	rows, err := db.QueryContext(ctx, "PRAGMA compile_options")
+	if err != nil {
+		return false
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var option string
+		if err := rows.Scan(&option); err != nil {
+			return false
+		}
+		if option == "ENABLE_FTS5" {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureFTSSchema creates the chunks_fts FTS5 virtual table and its sync
+// triggers the first time a store is opened (CREATE VIRTUAL/TRIGGER IF
+// NOT EXISTS, so it's a no-op on later opens), then backfills it from any
+// chunks/symbols rows that predate the feature. It's a no-op, not an
+// error, on a SQLite build without FTS5 - NewSQLiteStoreWithConfig just
+// runs without full-text search rather than failing to open.
+//
+// This intentionally lives outside the numbered migrations/ framework:
+// migrations.Load()/apply() run unconditionally and in strict order, with
+// no way to skip one on a build that can't execute its SQL (see
+// ftsSupported), which would otherwise turn "FTS5 missing" into "store
+// won't open at all". ensureBucket's lazily-created bucket tables follow
+// the same pattern for the same reason.
+//
+// Only the shared chunks/symbols tables are indexed - bucket-mode
+// chunks_<id>/symbols_<id> tables (see buckets.go) are out of scope for
+// now, same as backfillChunkCompression.
+func (s *SQLiteStore) ensureFTSSchema(ctx context.Context) error {
+	if !ftsSupported(ctx, s.db) {
+		return nil
+	}
+
+	stmts := []string{
+		// tokenize stacks porter stemming on top of unicode61 so "indexing"
+		// and "indexed" share a root token, with diacritics folded (level 2
+		// strips diacritics from a wider Unicode range than the default).
+		`CREATE VIRTUAL TABLE IF NOT EXISTS chunks_fts USING fts5(
+			content, context, symbol_names, symbol_signatures, symbol_doc_comments,
+			chunk_id UNINDEXED,
+			tokenize = 'porter unicode61 remove_diacritics 2'
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS chunks_fts_ai AFTER INSERT ON chunks BEGIN
+			INSERT INTO chunks_fts(rowid, chunk_id, content, context, symbol_names, symbol_signatures, symbol_doc_comments)
+			VALUES (new.rowid, new.id, substr(new.content, 2), substr(COALESCE(new.context, x''), 2), '', '', '');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS chunks_fts_au AFTER UPDATE ON chunks BEGIN
+			UPDATE chunks_fts SET content = substr(new.content, 2), context = substr(COALESCE(new.context, x''), 2)
+			WHERE rowid = new.rowid;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS chunks_fts_ad AFTER DELETE ON chunks BEGIN
+			DELETE FROM chunks_fts WHERE rowid = old.rowid;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS symbols_fts_ai AFTER INSERT ON symbols BEGIN
+			UPDATE chunks_fts SET
+				symbol_names = (SELECT COALESCE(group_concat(name, ' '), '') FROM symbols WHERE chunk_id = new.chunk_id),
+				symbol_signatures = (SELECT COALESCE(group_concat(signature, ' '), '') FROM symbols WHERE chunk_id = new.chunk_id),
+				symbol_doc_comments = (SELECT COALESCE(group_concat(doc_comment, ' '), '') FROM symbols WHERE chunk_id = new.chunk_id)
+			WHERE chunk_id = new.chunk_id;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS symbols_fts_au AFTER UPDATE ON symbols BEGIN
+			UPDATE chunks_fts SET
+				symbol_names = (SELECT COALESCE(group_concat(name, ' '), '') FROM symbols WHERE chunk_id = new.chunk_id),
+				symbol_signatures = (SELECT COALESCE(group_concat(signature, ' '), '') FROM symbols WHERE chunk_id = new.chunk_id),
+				symbol_doc_comments = (SELECT COALESCE(group_concat(doc_comment, ' '), '') FROM symbols WHERE chunk_id = new.chunk_id)
+			WHERE chunk_id = new.chunk_id;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS symbols_fts_ad AFTER DELETE ON symbols BEGIN
+			UPDATE chunks_fts SET
+				symbol_names = (SELECT COALESCE(group_concat(name, ' '), '') FROM symbols WHERE chunk_id = old.chunk_id),
+				symbol_signatures = (SELECT COALESCE(group_concat(signature, ' '), '') FROM symbols WHERE chunk_id = old.chunk_id),
+				symbol_doc_comments = (SELECT COALESCE(group_concat(doc_comment, ' '), '') FROM symbols WHERE chunk_id = old.chunk_id)
+			WHERE chunk_id = old.chunk_id;
+		END`,
+		// Backfill: chunks saved before chunks_fts existed have no row yet.
+		// substr(content, 2) / substr(context, 2) skip the one-byte
+		// compression-algorithm header encodeChunkColumn always writes
+		// (see compress.go) - exact for ChunkCompressionNone (the
+		// default), but for a store with Snappy/Zstd enabled this indexes
+		// the compressed bytes rather than real text, so BM25 relevance on
+		// those rows is degraded until they're resaved.
+		`INSERT INTO chunks_fts(rowid, chunk_id, content, context, symbol_names, symbol_signatures, symbol_doc_comments)
+		 SELECT c.rowid, c.id, substr(c.content, 2), substr(COALESCE(c.context, x''), 2),
+			COALESCE((SELECT group_concat(name, ' ') FROM symbols WHERE chunk_id = c.id), ''),
+			COALESCE((SELECT group_concat(signature, ' ') FROM symbols WHERE chunk_id = c.id), ''),
+			COALESCE((SELECT group_concat(doc_comment, ' ') FROM symbols WHERE chunk_id = c.id), '')
+		 FROM chunks c
+		 WHERE c.rowid NOT IN (SELECT rowid FROM chunks_fts)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("set up FTS5 schema: %w", err)
+		}
+	}
+
+	s.ftsEnabled = true
+	return nil
+}
+
+// SearchOptions narrows a SearchText/HybridSearchChunks query.
+type SearchOptions struct {
+	// Limit caps the number of results. Defaults to 20 when <= 0.
+	Limit int
+}
+
+// TextSearchResult pairs a chunk ID with its FTS5 BM25 relevance score
+// from a SearchText query, ranked best-first (a more negative score is
+// more relevant, following FTS5's bm25() convention).
+type TextSearchResult struct {
+	ChunkID   string
+	BM25Score float64
+}
+
+// SearchText runs query against the chunks_fts full-text index (content,
+// context, and aggregated symbol name/signature/doc_comment text),
+// returning chunk IDs ranked by BM25 relevance, best first. Returns
+// ErrFTSNotEnabled if this store's SQLite build lacks FTS5 or
+// StoreConfig.DisableFTS was set - callers needing a degrade-gracefully
+// path should fall back to TopKByEmbedding or SearchSymbols instead of
+// failing the whole query.
+func (s *SQLiteStore) SearchText(ctx context.Context, query string, opts SearchOptions) ([]TextSearchResult, error) {
+	if !s.ftsEnabled {
+		return nil, ErrFTSNotEnabled
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT chunk_id, bm25(chunks_fts) FROM chunks_fts WHERE chunks_fts MATCH ? ORDER BY bm25(chunks_fts) LIMIT ?`,
+		query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search text: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []TextSearchResult
+	for rows.Next() {
+		var r TextSearchResult
+		if err := rows.Scan(&r.ChunkID, &r.BM25Score); err != nil {
+			return nil, fmt.Errorf("scan text search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// HybridSearchChunks combines SearchText's BM25 ranking with
+// TopKByEmbedding's vector similarity ranking via Reciprocal Rank Fusion,
+// returning a single list of at most k chunks ranked by fused score
+// (ScoredChunk.Score here is the RRF score, not a raw BM25 or cosine
+// value - the two aren't on comparable scales, which is the point of
+// using RRF instead of a weighted sum). Either input can be empty: a
+// store with no vector index attached falls back to text-only ranking,
+// and an empty/unmatched query falls back to vector-only ranking.
+func (s *SQLiteStore) HybridSearchChunks(ctx context.Context, query string, queryEmbedding []float32, k int, textOpts SearchOptions) ([]ScoredChunk, error) {
+	var bm25Results []TextSearchResult
+	if s.ftsEnabled && query != "" {
+		var err error
+		bm25Results, err = s.SearchText(ctx, query, textOpts)
+		if err != nil {
+			return nil, fmt.Errorf("hybrid search: %w", err)
+		}
+	}
+
+	var vectorResults []ScoredChunk
+	if s.vectorIndex != nil && len(queryEmbedding) > 0 {
+		var err error
+		vectorResults, err = s.TopKByEmbedding(ctx, queryEmbedding, k, nil)
+		if err != nil {
+			return nil, fmt.Errorf("hybrid search: %w", err)
+		}
+	}
+	if len(bm25Results) == 0 && len(vectorResults) == 0 {
+		return nil, nil
+	}
+
+	rrfScore := make(map[string]float64)
+	chunkByID := make(map[string]*Chunk, len(vectorResults))
+	for rank, r := range bm25Results {
+		rrfScore[r.ChunkID] += rrfTerm(rank + 1)
+	}
+	for rank, r := range vectorResults {
+		rrfScore[r.Chunk.ID] += rrfTerm(rank + 1)
+		chunkByID[r.Chunk.ID] = r.Chunk
+	}
+
+	var missing []string
+	for id := range rrfScore {
+		if _, ok := chunkByID[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		chunks, err := s.GetChunks(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("hybrid search: load bm25-only chunks: %w", err)
+		}
+		for _, c := range chunks {
+			chunkByID[c.ID] = c
+		}
+	}
+
+	scored := make([]ScoredChunk, 0, len(rrfScore))
+	for id, score := range rrfScore {
+		c, ok := chunkByID[id]
+		if !ok {
+			continue
+		}
+		scored = append(scored, ScoredChunk{Chunk: c, Score: float32(score)})
+	}
+	sort.Slice(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	if k > 0 && len(scored) > k {
+		scored = scored[:k]
+	}
+	return scored, nil
+}
+
+// rrfTerm is Reciprocal Rank Fusion's per-list contribution for a
+// 1-indexed rank, using hybridRRFConstant as k.
+func rrfTerm(rank int) float64 {
+	return 1.0 / float64(hybridRRFConstant+rank)
+}
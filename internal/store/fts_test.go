@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFTSTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestSQLiteStore_SearchTextFindsChunkByContent(t *testing.T) {
+	s := newFTSTestStore(t)
+	if !s.ftsEnabled {
+		t.Skip("FTS5 not available in this sqlite3 build (needs the sqlite_fts5 build tag)")
+	}
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, s.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "func reconcileShards performs reconciliation", StartLine: 1, EndLine: 2},
+		{ID: "chunk2", FileID: "file1", FilePath: "a.go", Content: "func unrelated does nothing interesting", StartLine: 3, EndLine: 4},
+	}))
+
+	results, err := s.SearchText(ctx, "reconciliation", SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "chunk1", results[0].ChunkID)
+}
+
+func TestSQLiteStore_SearchTextIndexesSymbols(t *testing.T) {
+	s := newFTSTestStore(t)
+	if !s.ftsEnabled {
+		t.Skip("FTS5 not available in this sqlite3 build (needs the sqlite_fts5 build tag)")
+	}
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, s.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "x", StartLine: 1, EndLine: 2},
+	}))
+	require.NoError(t, s.SaveSymbols(ctx, "chunk1", []*Symbol{
+		{ChunkID: "chunk1", Name: "FrobnicateWidget", Type: "function", Signature: "func FrobnicateWidget()", DocComment: "frobnicates the widget"},
+	}))
+
+	results, err := s.SearchText(ctx, "frobnicate", SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "chunk1", results[0].ChunkID)
+}
+
+func TestSQLiteStore_SearchTextNotEnabledReturnsTypedError(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStoreWithConfig(dbPath, StoreConfig{DisableFTS: true})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	_, err = s.SearchText(context.Background(), "anything", SearchOptions{})
+	assert.ErrorIs(t, err, ErrFTSNotEnabled)
+}
+
+func TestSQLiteStore_HybridSearchChunksFusesTextAndVectorRankings(t *testing.T) {
+	s := newFTSTestStore(t)
+	if !s.ftsEnabled {
+		t.Skip("FTS5 not available in this sqlite3 build (needs the sqlite_fts5 build tag)")
+	}
+	ctx := context.Background()
+
+	v, err := NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 2, ModelID: "test-model"})
+	require.NoError(t, err)
+	require.NoError(t, v.Load(nil))
+	defer func() { _ = v.Close() }()
+	s.AttachVectorIndex(v)
+
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, s.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "reconciliation logic", StartLine: 1, EndLine: 2},
+		{ID: "chunk2", FileID: "file1", FilePath: "a.go", Content: "unrelated text", StartLine: 3, EndLine: 4},
+	}))
+	require.NoError(t, s.SaveChunkEmbeddings(ctx, []string{"chunk1", "chunk2"}, [][]float32{{1, 0}, {0, 1}}, "test-model"))
+
+	results, err := s.HybridSearchChunks(ctx, "reconciliation", []float32{1, 0}, 5, SearchOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "chunk1", results[0].Chunk.ID)
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"sync"
+)
+
+// LazyBM25Index defers opening the real BM25Index (and reading its on-disk
+// postings) until the first call that actually needs it, mirroring
+// LazyVectorStore's reasoning: most projects the daemon touches sit idle
+// until evicted, so eagerly opening postings for all of them wastes RSS.
+type LazyBM25Index struct {
+	construct func() (BM25Index, error)
+
+	mu    sync.Mutex
+	index BM25Index
+	err   error
+}
+
+// NewLazyBM25Index wraps construct, which should open (and, if the backend
+// requires it, Load) the real index.
+func NewLazyBM25Index(construct func() (BM25Index, error)) *LazyBM25Index {
+	return &LazyBM25Index{construct: construct}
+}
+
+func (l *LazyBM25Index) materialize() (BM25Index, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.index != nil || l.err != nil {
+		return l.index, l.err
+	}
+	l.index, l.err = l.construct()
+	return l.index, l.err
+}
+
+// Materialized reports whether the underlying index has already been
+// opened, for tests and observability.
+func (l *LazyBM25Index) Materialized() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.index != nil || l.err != nil
+}
+
+func (l *LazyBM25Index) Search(ctx context.Context, query string, limit int) ([]*BM25Result, error) {
+	idx, err := l.materialize()
+	if err != nil {
+		return nil, err
+	}
+	return idx.Search(ctx, query, limit)
+}
+
+func (l *LazyBM25Index) Index(ctx context.Context, docs []*Document) error {
+	idx, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return idx.Index(ctx, docs)
+}
+
+func (l *LazyBM25Index) Delete(ctx context.Context, docIDs []string) error {
+	idx, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return idx.Delete(ctx, docIDs)
+}
+
+func (l *LazyBM25Index) Stats() *IndexStats {
+	idx, err := l.materialize()
+	if err != nil {
+		return nil
+	}
+	return idx.Stats()
+}
+
+func (l *LazyBM25Index) Save(path string) error {
+	idx, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return idx.Save(path)
+}
+
+func (l *LazyBM25Index) Load(path string) error {
+	idx, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return idx.Load(path)
+}
+
+func (l *LazyBM25Index) AllIDs() ([]string, error) {
+	idx, err := l.materialize()
+	if err != nil {
+		return nil, err
+	}
+	return idx.AllIDs()
+}
+
+// Close closes the underlying index if it was ever materialized; a
+// LazyBM25Index that was never used has nothing to release.
+func (l *LazyBM25Index) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.index == nil {
+		return nil
+	}
+	return l.index.Close()
+}
+
+var _ BM25Index = (*LazyBM25Index)(nil)
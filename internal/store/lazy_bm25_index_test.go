@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBM25Index is a minimal store.BM25Index stand-in, since the real
+// backend (bleve-backed, selected via NewBM25IndexWithBackend) lives
+// outside this tree - LazyBM25Index only needs something that satisfies
+// the interface to prove it defers construction and forwards calls.
+type fakeBM25Index struct {
+	closed  bool
+	indexed int
+}
+
+func (f *fakeBM25Index) Search(ctx context.Context, query string, limit int) ([]*BM25Result, error) {
+	return nil, nil
+}
+func (f *fakeBM25Index) Index(ctx context.Context, docs []*Document) error {
+	f.indexed += len(docs)
+	return nil
+}
+func (f *fakeBM25Index) Delete(ctx context.Context, docIDs []string) error { return nil }
+func (f *fakeBM25Index) Stats() *IndexStats                                { return &IndexStats{} }
+func (f *fakeBM25Index) Save(path string) error                            { return nil }
+func (f *fakeBM25Index) Load(path string) error                            { return nil }
+func (f *fakeBM25Index) Close() error                                      { f.closed = true; return nil }
+func (f *fakeBM25Index) AllIDs() ([]string, error)                         { return nil, nil }
+
+func TestLazyBM25Index_DefersConstructionUntilFirstUse(t *testing.T) {
+	constructed := 0
+	fake := &fakeBM25Index{}
+	l := NewLazyBM25Index(func() (BM25Index, error) {
+		constructed++
+		return fake, nil
+	})
+
+	assert.False(t, l.Materialized())
+	assert.Equal(t, 0, constructed)
+
+	assert.NoError(t, l.Index(context.Background(), []*Document{{}}))
+	assert.True(t, l.Materialized())
+	assert.Equal(t, 1, constructed)
+	assert.Equal(t, 1, fake.indexed)
+
+	// Further calls reuse the already-materialized index.
+	assert.NoError(t, l.Index(context.Background(), []*Document{{}}))
+	assert.Equal(t, 1, constructed)
+	assert.Equal(t, 2, fake.indexed)
+}
+
+func TestLazyBM25Index_ConstructErrorIsCachedAndReturnedToEveryCaller(t *testing.T) {
+	boom := assert.AnError
+	l := NewLazyBM25Index(func() (BM25Index, error) {
+		return nil, boom
+	})
+
+	_, err := l.Search(context.Background(), "q", 10)
+	assert.ErrorIs(t, err, boom)
+
+	err = l.Index(context.Background(), nil)
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestLazyBM25Index_CloseIsNoopWhenNeverMaterialized(t *testing.T) {
+	l := NewLazyBM25Index(func() (BM25Index, error) {
+		t.Fatal("construct should not be called")
+		return nil, nil
+	})
+	assert.NoError(t, l.Close())
+}
+
+func TestLazyBM25Index_CloseForwardsAfterMaterializing(t *testing.T) {
+	fake := &fakeBM25Index{}
+	l := NewLazyBM25Index(func() (BM25Index, error) { return fake, nil })
+
+	_ = l.Stats()
+	assert.NoError(t, l.Close())
+	assert.True(t, fake.closed)
+}
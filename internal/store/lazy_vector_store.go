@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"sync"
+)
+
+// LazyVectorStore defers constructing (and, typically, mmapping/loading)
+// the real VectorStore until the first call that actually needs it,
+// instead of loadProject paying that cost eagerly for every project the
+// daemon touches even when most never get searched before eviction.
+type LazyVectorStore struct {
+	construct func() (VectorStore, error)
+
+	mu    sync.Mutex
+	store VectorStore
+	err   error
+}
+
+// NewLazyVectorStore wraps construct, which should build and fully load the
+// real store (e.g. store.NewHNSWStore followed by Load).
+func NewLazyVectorStore(construct func() (VectorStore, error)) *LazyVectorStore {
+	return &LazyVectorStore{construct: construct}
+}
+
+// materialize runs construct on first use, caching either the resulting
+// store or the construction error for every call thereafter.
+func (l *LazyVectorStore) materialize() (VectorStore, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.store != nil || l.err != nil {
+		return l.store, l.err
+	}
+	l.store, l.err = l.construct()
+	return l.store, l.err
+}
+
+// Materialized reports whether the underlying store has already been
+// constructed, for tests and observability.
+func (l *LazyVectorStore) Materialized() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.store != nil || l.err != nil
+}
+
+func (l *LazyVectorStore) Search(ctx context.Context, query []float32, k int) ([]*VectorResult, error) {
+	s, err := l.materialize()
+	if err != nil {
+		return nil, err
+	}
+	return s.Search(ctx, query, k)
+}
+
+func (l *LazyVectorStore) Add(ctx context.Context, ids []string, vectors [][]float32) error {
+	s, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return s.Add(ctx, ids, vectors)
+}
+
+func (l *LazyVectorStore) Delete(ctx context.Context, ids []string) error {
+	s, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return s.Delete(ctx, ids)
+}
+
+func (l *LazyVectorStore) Contains(id string) bool {
+	s, err := l.materialize()
+	if err != nil {
+		return false
+	}
+	return s.Contains(id)
+}
+
+func (l *LazyVectorStore) Count() int {
+	s, err := l.materialize()
+	if err != nil {
+		return 0
+	}
+	return s.Count()
+}
+
+func (l *LazyVectorStore) Save(path string) error {
+	s, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return s.Save(path)
+}
+
+func (l *LazyVectorStore) Load(path string) error {
+	s, err := l.materialize()
+	if err != nil {
+		return err
+	}
+	return s.Load(path)
+}
+
+func (l *LazyVectorStore) AllIDs() []string {
+	s, err := l.materialize()
+	if err != nil {
+		return nil
+	}
+	return s.AllIDs()
+}
+
+// Close closes the underlying store if it was ever materialized; a
+// LazyVectorStore that was never used has nothing to release.
+func (l *LazyVectorStore) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.store == nil {
+		return nil
+	}
+	return l.store.Close()
+}
+
+var _ VectorStore = (*LazyVectorStore)(nil)
@@ -0,0 +1,69 @@
+//go:build linux || darwin
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyVectorStore_DefersConstructionUntilFirstUse(t *testing.T) {
+	constructed := 0
+	l := NewLazyVectorStore(func() (VectorStore, error) {
+		constructed++
+		return NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 2})
+	})
+
+	assert.False(t, l.Materialized())
+	assert.Equal(t, 0, constructed)
+
+	assert.Equal(t, 0, l.Count())
+	assert.True(t, l.Materialized())
+	assert.Equal(t, 1, constructed)
+
+	// Further calls reuse the already-materialized store.
+	_ = l.Count()
+	assert.Equal(t, 1, constructed)
+}
+
+func TestLazyVectorStore_ForwardsCallsAfterMaterializing(t *testing.T) {
+	l := NewLazyVectorStore(func() (VectorStore, error) {
+		s, err := NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 4})
+		require.NoError(t, err)
+		require.NoError(t, s.Load(nil))
+		return s, nil
+	})
+
+	require.NoError(t, l.Add(context.Background(), []string{"a"}, [][]float32{{1, 0, 0, 0}}))
+
+	results, err := l.Search(context.Background(), []float32{1, 0, 0, 0}, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "a", results[0].ID)
+	assert.Equal(t, 1, l.Count())
+}
+
+func TestLazyVectorStore_ConstructErrorIsCachedAndReturnedToEveryCaller(t *testing.T) {
+	boom := assert.AnError
+	l := NewLazyVectorStore(func() (VectorStore, error) {
+		return nil, boom
+	})
+
+	_, err := l.Search(context.Background(), []float32{1}, 1)
+	assert.ErrorIs(t, err, boom)
+
+	err = l.Add(context.Background(), nil, nil)
+	assert.ErrorIs(t, err, boom)
+	assert.True(t, l.Materialized())
+}
+
+func TestLazyVectorStore_CloseIsNoopWhenNeverMaterialized(t *testing.T) {
+	l := NewLazyVectorStore(func() (VectorStore, error) {
+		t.Fatal("construct should not be called")
+		return nil, nil
+	})
+	assert.NoError(t, l.Close())
+}
@@ -20,20 +20,116 @@ import (
 
 // SQLiteStore implements MetadataStore using SQLite.
 type SQLiteStore struct {
-	db *sql.DB
+	db           *sql.DB
+	gen          SQLGenerator
+	bucketTables bool
+
+	// chunkCompression is the algorithm SaveChunks uses to compress new
+	// content/raw_content/context writes. Reads never consult it directly -
+	// decodeChunkColumn detects the algorithm per-row from its header byte -
+	// so changing this on an existing store is safe and takes effect only
+	// for chunks saved from then on.
+	chunkCompression ChunkCompression
+
+	// vectorIndex is the optional ANN backend AttachVectorIndex wires in.
+	// nil until attached, in which case TopKByEmbedding and
+	// RebuildVectorIndex reject with ErrVectorIndexNotAttached, and
+	// SaveChunkEmbeddings/DeleteChunks/DeleteChunksByFile skip syncing it.
+	vectorIndex VectorStore
+
+	// ftsEnabled records whether ensureFTSSchema successfully set up the
+	// chunks_fts virtual table for this store - false when the SQLite
+	// build lacks FTS5, or when StoreConfig.DisableFTS was set. SearchText
+	// and HybridSearchChunks consult it rather than re-probing per call.
+	ftsEnabled bool
+
+	// dbPath is the file this store was opened from, kept only so
+	// Snapshot can open its own dedicated read-only connection against
+	// the same file (see snapshot.go) - empty for a store built directly
+	// around an existing *sql.DB rather than through
+	// NewSQLiteStoreWithConfig, in which case Snapshot isn't available.
+	dbPath string
 }
 
+// StoreProfile selects a PRAGMA tuning profile for the SQLite metadata
+// store, beyond the baseline journal_mode/synchronous/foreign_keys/
+// busy_timeout/cache_size set unconditionally by NewSQLiteStoreWithConfig.
+type StoreProfile string
+
+const (
+	// ProfileBalanced is the default: modest WAL checkpointing and an
+	// in-memory temp store, suitable for most single-project workstation
+	// use. Leaves page_size and mmap_size at SQLite's defaults.
+	ProfileBalanced StoreProfile = "balanced"
+	// ProfileReadHeavy favors large, mostly-read workloads (e.g. a server
+	// answering search queries against a big, rarely-reindexed monorepo):
+	// a 32KB page size (set before the schema is created - SQLite only
+	// honors page_size on a fresh database), a large mmap window, and
+	// incremental auto-vacuum so space can be reclaimed without a full
+	// VACUUM's exclusive lock.
+	ProfileReadHeavy StoreProfile = "read-heavy"
+	// ProfileLowMemory minimizes SQLite's own memory footprint (mmap
+	// disabled, temp tables spilled to disk, frequent WAL checkpoints)
+	// for constrained environments like CI containers.
+	ProfileLowMemory StoreProfile = "low-memory"
+)
+
+// defaultMmapSizeMB is the PRAGMA mmap_size applied under ProfileReadHeavy
+// when StoreConfig.MmapSizeMB is unset.
+const defaultMmapSizeMB = 1024 // 1GB
+
+// incrementalVacuumFreelistThreshold is the PRAGMA freelist_count above
+// which NewSQLiteStoreWithConfig runs an opportunistic incremental_vacuum
+// under ProfileReadHeavy (see maybeIncrementalVacuum).
+const incrementalVacuumFreelistThreshold = 1000
+
 // StoreConfig configures the SQLite metadata store.
 type StoreConfig struct {
 	// CacheSizeMB is the SQLite cache size in megabytes.
 	// Default is 64MB. Set to 0 to use default.
 	CacheSizeMB int
+
+	// Profile selects the PRAGMA tuning profile. Defaults to
+	// ProfileBalanced when empty.
+	Profile StoreProfile
+
+	// MmapSizeMB sets PRAGMA mmap_size under ProfileReadHeavy, in
+	// megabytes. Defaults to 1GB when 0. Ignored under other profiles.
+	MmapSizeMB int
+
+	// SupportBucketTable switches SQLiteStore into "project-as-bucket"
+	// mode: each project gets its own files_<id>/chunks_<id>/symbols_<id>
+	// tables instead of sharing the central files/chunks/symbols tables,
+	// created lazily on first write and dropped in one shot by
+	// DeleteFilesByProject. This trades a small amount of per-project
+	// bookkeeping (see buckets.go) for an O(1) project delete instead of
+	// a row-by-row cascade, which matters on large monorepos where
+	// `amanmcp index --force` re-deletes the whole project on every run.
+	// Off by default - existing single-project-per-store deployments get
+	// no benefit from it and should leave it alone.
+	SupportBucketTable bool
+
+	// ChunkCompression selects the algorithm SaveChunks uses to compress
+	// the chunks table's content/raw_content/context columns. Defaults to
+	// ChunkCompressionNone when empty. Turning it on against an
+	// already-populated database is safe: NewSQLiteStoreWithConfig walks
+	// the existing rows in batches (see backfillChunkCompression) and
+	// compresses any that predate this feature before returning.
+	ChunkCompression ChunkCompression
+
+	// DisableFTS turns off the FTS5 full-text index (see fts.go) even on
+	// a SQLite build that supports it. NewSQLiteStoreWithConfig always
+	// probes for FTS5 support and silently runs without it on a build
+	// that lacks the module, so this is only needed to force plain BM25
+	// postings/vector-only search on a build that does have FTS5.
+	DisableFTS bool
 }
 
 // DefaultStoreConfig returns sensible defaults for the metadata store.
 func DefaultStoreConfig() StoreConfig {
 	return StoreConfig{
-		CacheSizeMB: 64, // 64MB default cache
+		CacheSizeMB:      64, // 64MB default cache
+		ChunkCompression: ChunkCompressionNone,
 	}
 }
 
@@ -56,7 +152,10 @@ func NewSQLiteStoreWithConfig(dbPath string, cfg StoreConfig) (*SQLiteStore, err
 
 	// Open database with WAL mode and other pragmas
 	// Note: _busy_timeout in DSN may be ignored by mattn/go-sqlite3, so we set it via PRAGMA below
-	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_synchronous=NORMAL&_foreign_keys=ON")
+	// journal_mode is deliberately left out of the DSN and set via PRAGMA
+	// below instead, after page_size: SQLite refuses to change page_size
+	// once a database is in WAL mode.
+	db, err := sql.Open("sqlite3", dbPath+"?_synchronous=NORMAL&_foreign_keys=ON")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -78,10 +177,18 @@ func NewSQLiteStoreWithConfig(dbPath string, cfg StoreConfig) (*SQLiteStore, err
 
 	// Set additional pragmas
 	// CRITICAL: busy_timeout MUST be set via PRAGMA, not DSN (DSN syntax may be ignored)
-	pragmas := []string{
-		"PRAGMA busy_timeout = 5000", // 5 second timeout for lock contention
+	// page_size and auto_vacuum (if the profile sets them) must run
+	// before journal_mode switches to WAL below - SQLite only applies
+	// either to a database with no tables, and the WAL switch itself
+	// counts against that - and before initSchema creates any tables.
+	pragmas := append(profileFreshDBPragmas(cfg.Profile), "PRAGMA journal_mode=WAL")
+	pragmas = append(pragmas,
+		"PRAGMA busy_timeout = 5000",                      // 5 second timeout for lock contention
 		fmt.Sprintf("PRAGMA cache_size=-%d", cacheSizeKB), // Negative = KB
-	}
+	)
+	// The rest of the profile's pragmas don't have WAL/page_size ordering
+	// constraints, so they're applied last.
+	pragmas = append(pragmas, profilePragmas(cfg.Profile, cfg.MmapSizeMB)...)
 	for _, pragma := range pragmas {
 		if _, err := db.Exec(pragma); err != nil {
 			_ = db.Close()
@@ -101,190 +208,152 @@ func NewSQLiteStoreWithConfig(dbPath string, cfg StoreConfig) (*SQLiteStore, err
 			slog.String("action", "recommend running 'amanmcp index --force' to rebuild"))
 	}
 
-	store := &SQLiteStore{db: db}
+	chunkCompression := cfg.ChunkCompression
+	if chunkCompression == "" {
+		chunkCompression = ChunkCompressionNone
+	}
+	store := &SQLiteStore{db: db, gen: sqliteGenerator{}, bucketTables: cfg.SupportBucketTable, chunkCompression: chunkCompression, dbPath: dbPath}
 
 	// Initialize schema
-	if err := store.initSchema(); err != nil {
+	if err := store.initSchema(context.Background()); err != nil {
 		_ = db.Close()
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
+	if cfg.Profile == ProfileReadHeavy {
+		if err := store.maybeIncrementalVacuum(context.Background()); err != nil {
+			slog.Warn("sqlite_incremental_vacuum_failed", slog.String("error", err.Error()))
+		}
+	}
+
+	if chunkCompression != ChunkCompressionNone {
+		if err := store.backfillChunkCompression(context.Background()); err != nil {
+			slog.Warn("chunk_compression_backfill_failed", slog.String("error", err.Error()))
+		}
+	}
+
+	if !cfg.DisableFTS {
+		if err := store.ensureFTSSchema(context.Background()); err != nil {
+			slog.Warn("fts_schema_setup_failed", slog.String("error", err.Error()))
+		}
+	}
+
 	return store, nil
 }
 
-// initSchema creates all required tables if they don't exist.
-func (s *SQLiteStore) initSchema() error {
-	schema := `
-	-- Schema version for migrations
-	CREATE TABLE IF NOT EXISTS schema_version (
-		version INTEGER PRIMARY KEY,
-		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	-- Project information
-	CREATE TABLE IF NOT EXISTS projects (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		root_path TEXT NOT NULL,
-		project_type TEXT,
-		indexed_at TIMESTAMP,
-		chunk_count INTEGER DEFAULT 0,
-		file_count INTEGER DEFAULT 0,
-		schema_version TEXT
-	);
-
-	-- File tracking
-	CREATE TABLE IF NOT EXISTS files (
-		id TEXT PRIMARY KEY,
-		project_id TEXT NOT NULL,
-		path TEXT NOT NULL,
-		size INTEGER,
-		mod_time TIMESTAMP,
-		content_hash TEXT,
-		language TEXT,
-		content_type TEXT,
-		indexed_at TIMESTAMP,
-		FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_files_project ON files(project_id);
-	CREATE INDEX IF NOT EXISTS idx_files_path ON files(project_id, path);
-	CREATE INDEX IF NOT EXISTS idx_files_mod_time ON files(project_id, mod_time);
-
-	-- Chunk metadata
-	CREATE TABLE IF NOT EXISTS chunks (
-		id TEXT PRIMARY KEY,
-		file_id TEXT NOT NULL,
-		file_path TEXT NOT NULL,
-		content TEXT NOT NULL,
-		raw_content TEXT,
-		context TEXT,
-		content_type TEXT,
-		language TEXT,
-		start_line INTEGER NOT NULL,
-		end_line INTEGER NOT NULL,
-		metadata TEXT,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_chunks_file ON chunks(file_id);
-
-	-- Symbols in chunks
-	CREATE TABLE IF NOT EXISTS symbols (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		chunk_id TEXT NOT NULL,
-		name TEXT NOT NULL,
-		type TEXT NOT NULL,
-		start_line INTEGER,
-		end_line INTEGER,
-		signature TEXT,
-		doc_comment TEXT,
-		FOREIGN KEY (chunk_id) REFERENCES chunks(id) ON DELETE CASCADE
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_symbols_chunk ON symbols(chunk_id);
-	CREATE INDEX IF NOT EXISTS idx_symbols_name ON symbols(name);
-
-	-- Key-value store for misc state
-	CREATE TABLE IF NOT EXISTS state (
-		key TEXT PRIMARY KEY,
-		value TEXT,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	-- Insert schema version if not exists
-	INSERT OR IGNORE INTO schema_version (version) VALUES (1);
-	`
+// profileFreshDBPragmas returns profile's page_size and auto_vacuum
+// PRAGMAs, if any, as their own slice so the caller can run them before
+// switching to WAL journal mode (see NewSQLiteStoreWithConfig). SQLite
+// only honors both on a database with no tables yet, and (somewhat
+// surprisingly) the WAL journal-mode switch itself counts as writing to
+// the database, so these must run first even on an otherwise-empty file.
+func profileFreshDBPragmas(profile StoreProfile) []string {
+	if profile == ProfileReadHeavy {
+		return []string{"PRAGMA page_size=32768", "PRAGMA auto_vacuum=INCREMENTAL"}
+	}
+	return nil
+}
+
+// profilePragmas returns the extra PRAGMAs for profile, beyond the
+// busy_timeout/cache_size set unconditionally by NewSQLiteStoreWithConfig
+// and the page_size/auto_vacuum profileFreshDBPragmas returns. mmapSizeMB overrides ProfileReadHeavy's
+// default mmap_size when positive.
+func profilePragmas(profile StoreProfile, mmapSizeMB int) []string {
+	switch profile {
+	case ProfileReadHeavy:
+		if mmapSizeMB <= 0 {
+			mmapSizeMB = defaultMmapSizeMB
+		}
+		return []string{
+			"PRAGMA temp_store=memory",
+			fmt.Sprintf("PRAGMA mmap_size=%d", mmapSizeMB*1024*1024),
+			"PRAGMA wal_autocheckpoint=2000",
+		}
+	case ProfileLowMemory:
+		return []string{
+			"PRAGMA temp_store=file",
+			"PRAGMA mmap_size=0",
+			"PRAGMA wal_autocheckpoint=250",
+		}
+	default: // ProfileBalanced
+		return []string{
+			"PRAGMA temp_store=memory",
+			"PRAGMA wal_autocheckpoint=1000",
+		}
+	}
+}
+
+// maybeIncrementalVacuum runs PRAGMA incremental_vacuum on startup when
+// the freelist has grown past incrementalVacuumFreelistThreshold, so
+// ProfileReadHeavy databases reclaim space without needing a full VACUUM.
+// A no-op until auto_vacuum=INCREMENTAL has taken effect, which (like
+// page_size) only happens on a fresh database.
+func (s *SQLiteStore) maybeIncrementalVacuum(ctx context.Context) error {
+	var freelist int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freelist); err != nil {
+		return fmt.Errorf("read freelist_count: %w", err)
+	}
+	if freelist <= incrementalVacuumFreelistThreshold {
+		return nil
+	}
+	if _, err := s.db.ExecContext(ctx, "PRAGMA incremental_vacuum"); err != nil {
+		return fmt.Errorf("incremental vacuum: %w", err)
+	}
+	return nil
+}
+
+// validCheckpointModes restricts Checkpoint's mode argument to SQLite's
+// actual wal_checkpoint modes, since it's interpolated directly into the
+// PRAGMA statement (which has no bind-parameter syntax for pragma args).
+var validCheckpointModes = map[string]bool{
+	"PASSIVE":  true,
+	"FULL":     true,
+	"RESTART":  true,
+	"TRUNCATE": true,
+}
+
+// Checkpoint forces a WAL checkpoint in the given mode (PASSIVE, FULL,
+// RESTART, or TRUNCATE), so long-running servers can bound WAL file
+// growth instead of waiting on SQLite's automatic checkpointing.
+func (s *SQLiteStore) Checkpoint(ctx context.Context, mode string) error {
+	if !validCheckpointModes[mode] {
+		return fmt.Errorf("invalid wal checkpoint mode %q", mode)
+	}
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf("PRAGMA wal_checkpoint(%s)", mode)); err != nil {
+		return fmt.Errorf("wal checkpoint (%s): %w", mode, err)
+	}
+	return nil
+}
+
+// initSchema creates the version-1 tables if they don't exist, then
+// brings the schema up to date via the embedded store/migrations
+// framework (see migrator.go), which replaces the old hand-rolled,
+// string-matching "ignore duplicate column" ladder.
+func (s *SQLiteStore) initSchema(ctx context.Context) error {
+	schema := s.gen.GetSqlCreateSchema()
 
 	if _, err := s.db.Exec(schema); err != nil {
 		return fmt.Errorf("execute database schema: %w", err)
 	}
 
-	// Run migrations
-	if err := s.runMigrations(); err != nil {
+	if err := newMigrator(s.db).MigrateToLatest(ctx); err != nil {
 		return fmt.Errorf("run migrations: %w", err)
 	}
 
 	return nil
 }
 
-// runMigrations applies schema migrations based on current version.
-func (s *SQLiteStore) runMigrations() error {
-	// Get current schema version
-	var version int
-	err := s.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
-	if err != nil {
-		return fmt.Errorf("get schema version: %w", err)
-	}
-
-	// Migration 2: Add embedding columns to chunks table
-	if version < 2 {
-		slog.Info("applying migration 2: add embedding columns to chunks")
-		// SQLite doesn't support multiple ALTER TABLE in one statement
-		stmts := []string{
-			"ALTER TABLE chunks ADD COLUMN embedding BLOB",
-			"ALTER TABLE chunks ADD COLUMN embedding_model TEXT",
-			"ALTER TABLE chunks ADD COLUMN embedding_dims INTEGER",
-			"INSERT INTO schema_version (version) VALUES (2)",
-		}
-		for _, stmt := range stmts {
-			if _, err := s.db.Exec(stmt); err != nil {
-				// Ignore "duplicate column name" errors (column already exists)
-				if !strings.Contains(err.Error(), "duplicate column name") {
-					return fmt.Errorf("migration 2 failed: %w", err)
-				}
-			}
-		}
-		slog.Info("migration 2 complete: embedding columns added")
-	}
-
-	// Migration 3: Add telemetry tables for query pattern tracking (AI-6)
-	if version < 3 {
-		slog.Info("applying migration 3: add telemetry tables")
-		stmts := []string{
-			// Query type frequency (aggregated daily)
-			`CREATE TABLE IF NOT EXISTS query_type_stats (
-				date TEXT NOT NULL,
-				query_type TEXT NOT NULL,
-				count INTEGER NOT NULL DEFAULT 0,
-				PRIMARY KEY (date, query_type)
-			)`,
-			// Top query terms (with frequency count)
-			`CREATE TABLE IF NOT EXISTS query_terms (
-				term TEXT PRIMARY KEY,
-				count INTEGER NOT NULL DEFAULT 1,
-				last_seen TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-			)`,
-			`CREATE INDEX IF NOT EXISTS idx_query_terms_count ON query_terms(count DESC)`,
-			// Zero-result queries (circular buffer)
-			`CREATE TABLE IF NOT EXISTS zero_result_queries (
-				id INTEGER PRIMARY KEY AUTOINCREMENT,
-				query TEXT NOT NULL,
-				timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-			)`,
-			// Latency histogram
-			`CREATE TABLE IF NOT EXISTS query_latency_stats (
-				date TEXT NOT NULL,
-				bucket TEXT NOT NULL,
-				count INTEGER NOT NULL DEFAULT 0,
-				PRIMARY KEY (date, bucket)
-			)`,
-			"INSERT INTO schema_version (version) VALUES (3)",
-		}
-		for _, stmt := range stmts {
-			if _, err := s.db.Exec(stmt); err != nil {
-				// Ignore "table already exists" errors
-				if !strings.Contains(err.Error(), "already exists") {
-					return fmt.Errorf("migration 3 failed: %w", err)
-				}
-			}
-		}
-		slog.Info("migration 3 complete: telemetry tables added")
-	}
+// MigrateTo applies or rolls back schema migrations until the database
+// is at exactly targetVersion. Pass MigrationStatus from MigrationStatus
+// to discover what versions exist.
+func (s *SQLiteStore) MigrateTo(ctx context.Context, targetVersion int) error {
+	return newMigrator(s.db).MigrateTo(ctx, targetVersion)
+}
 
-	return nil
+// MigrationStatus reports the apply/drift state of every known
+// migration, for `amanmcp db migrate status`.
+func (s *SQLiteStore) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	return newMigrator(s.db).Status(ctx)
 }
 
 // Close closes the database connection.
@@ -301,26 +370,23 @@ func (s *SQLiteStore) DB() *sql.DB {
 	return s.db
 }
 
-// SaveProject saves or updates a project.
+// SaveProject saves or updates a project. In bucket mode, this also
+// lazily creates the project's own files/chunks/symbols tables.
 func (s *SQLiteStore) SaveProject(ctx context.Context, project *Project) error {
-	query := `
-		INSERT INTO projects (id, name, root_path, project_type, indexed_at, chunk_count, file_count, schema_version)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			name = excluded.name,
-			root_path = excluded.root_path,
-			project_type = excluded.project_type,
-			indexed_at = excluded.indexed_at,
-			chunk_count = excluded.chunk_count,
-			file_count = excluded.file_count,
-			schema_version = excluded.schema_version
-	`
+	query := s.gen.GetSqlUpsertProject()
 	_, err := s.db.ExecContext(ctx, query,
 		project.ID, project.Name, project.RootPath, project.ProjectType,
 		project.IndexedAt, project.ChunkCount, project.FileCount, project.Version)
 	if err != nil {
 		return fmt.Errorf("failed to save project: %w", err)
 	}
+
+	if s.bucketTables {
+		if err := s.ensureBucket(ctx, project.ID); err != nil {
+			return fmt.Errorf("failed to create bucket tables for project: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -359,14 +425,31 @@ func (s *SQLiteStore) GetProject(ctx context.Context, id string) (*Project, erro
 
 // UpdateProjectStats updates the file and chunk counts for a project.
 func (s *SQLiteStore) UpdateProjectStats(ctx context.Context, id string, fileCount, chunkCount int) error {
+	return execUpdateProjectStats(ctx, s.db, id, fileCount, chunkCount)
+}
+
+// execUpdateProjectStats updates a project's stats against exec - the
+// store's own connection, or (from a WithTx callback) the enclosing
+// transaction/savepoint.
+func execUpdateProjectStats(ctx context.Context, exec txExecutor, id string, fileCount, chunkCount int) error {
 	query := `UPDATE projects SET file_count = ?, chunk_count = ?, indexed_at = ? WHERE id = ?`
-	_, err := s.db.ExecContext(ctx, query, fileCount, chunkCount, time.Now(), id)
+	_, err := exec.ExecContext(ctx, query, fileCount, chunkCount, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("failed to update project stats: %w", err)
 	}
 	return nil
 }
 
+// SaveSymbols replaces chunkID's symbols outright (deleting whatever was
+// there and inserting the given set), in its own transaction. Prefer
+// StoreTx.SaveSymbols from inside a WithTx callback when it needs to
+// commit atomically alongside the chunk or file it belongs to.
+func (s *SQLiteStore) SaveSymbols(ctx context.Context, chunkID string, symbols []*Symbol) error {
+	return s.WithTx(ctx, func(ctx context.Context, tx *StoreTx) error {
+		return tx.SaveSymbols(ctx, chunkID, symbols)
+	})
+}
+
 // RefreshProjectStats recalculates file/chunk counts from the database and updates indexed_at.
 // This is used by the coordinator after incremental indexing to keep stats accurate.
 func (s *SQLiteStore) RefreshProjectStats(ctx context.Context, id string) error {
@@ -393,26 +476,51 @@ func (s *SQLiteStore) SaveFiles(ctx context.Context, files []*File) error {
 	if len(files) == 0 {
 		return nil
 	}
+	return s.WithTx(ctx, func(ctx context.Context, tx *StoreTx) error {
+		return tx.SaveFiles(ctx, files)
+	})
+}
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// saveFilesExec upserts files against exec - the store's own connection,
+// or (from a WithTx callback) the enclosing transaction/savepoint - so a
+// whole batch commits or rolls back as one unit instead of SaveFiles
+// opening a separate transaction per bucket-mode table group.
+func (s *SQLiteStore) saveFilesExec(ctx context.Context, exec txExecutor, files []*File) error {
+	if !s.bucketTables {
+		return s.saveFilesIntoTable(ctx, exec, "files", files)
 	}
-	defer func() { _ = tx.Rollback() }()
 
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO files (id, project_id, path, size, mod_time, content_hash, language, content_type, indexed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			project_id = excluded.project_id,
-			path = excluded.path,
-			size = excluded.size,
-			mod_time = excluded.mod_time,
-			content_hash = excluded.content_hash,
-			language = excluded.language,
-			content_type = excluded.content_type,
-			indexed_at = excluded.indexed_at
-	`)
+	// Bucket mode: files can belong to different projects in the same
+	// batch, so group them by destination table.
+	byTable := make(map[string][]*File)
+	for _, f := range files {
+		table, err := s.filesTableFor(ctx, f.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve bucket for file %s: %w", f.Path, err)
+		}
+		byTable[table] = append(byTable[table], f)
+	}
+	for table, group := range byTable {
+		if err := s.saveFilesIntoTable(ctx, exec, table, group); err != nil {
+			return err
+		}
+		for _, f := range group {
+			if err := s.recordBucketEntry(ctx, exec, f.ID, "file", f.ProjectID); err != nil {
+				return fmt.Errorf("failed to save file %s: %w", f.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// saveFilesIntoTable upserts files into the given table, using the same
+// query s.gen.GetSqlUpsertFile() produces for the shared "files" table.
+func (s *SQLiteStore) saveFilesIntoTable(ctx context.Context, exec txExecutor, table string, files []*File) error {
+	query := s.gen.GetSqlUpsertFile()
+	if table != "files" {
+		query = strings.Replace(query, "INTO files ", "INTO "+table+" ", 1)
+	}
+	stmt, err := exec.PrepareContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
@@ -425,18 +533,18 @@ func (s *SQLiteStore) SaveFiles(ctx context.Context, files []*File) error {
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return nil
 }
 
 // GetFileByPath retrieves a file by its path within a project.
 func (s *SQLiteStore) GetFileByPath(ctx context.Context, projectID, path string) (*File, error) {
+	table, err := s.filesTableFor(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file by path: %w", err)
+	}
 	query := `
 		SELECT id, project_id, path, size, mod_time, content_hash, language, content_type, indexed_at
-		FROM files WHERE project_id = ? AND path = ?
+		FROM ` + table + ` WHERE project_id = ? AND path = ?
 	`
 	row := s.db.QueryRowContext(ctx, query, projectID, path)
 
@@ -444,7 +552,7 @@ func (s *SQLiteStore) GetFileByPath(ctx context.Context, projectID, path string)
 	var modTime, indexedAt sql.NullTime
 	var contentHash, language, contentType sql.NullString
 
-	err := row.Scan(&f.ID, &f.ProjectID, &f.Path, &f.Size, &modTime, &contentHash, &language, &contentType, &indexedAt)
+	err = row.Scan(&f.ID, &f.ProjectID, &f.Path, &f.Size, &modTime, &contentHash, &language, &contentType, &indexedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -473,9 +581,13 @@ func (s *SQLiteStore) GetFileByPath(ctx context.Context, projectID, path string)
 
 // GetChangedFiles returns files modified since the given timestamp.
 func (s *SQLiteStore) GetChangedFiles(ctx context.Context, projectID string, since time.Time) ([]*File, error) {
+	table, err := s.filesTableFor(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changed files: %w", err)
+	}
 	query := `
 		SELECT id, project_id, path, size, mod_time, content_hash, language, content_type, indexed_at
-		FROM files WHERE project_id = ? AND mod_time > ?
+		FROM ` + table + ` WHERE project_id = ? AND mod_time > ?
 		ORDER BY mod_time ASC
 	`
 	rows, err := s.db.QueryContext(ctx, query, projectID, since)
@@ -549,10 +661,15 @@ func (s *SQLiteStore) ListFiles(ctx context.Context, projectID string, cursor st
 		limit = 1000
 	}
 
+	table, err := s.filesTableFor(ctx, projectID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query files: %w", err)
+	}
+
 	// Query with LIMIT and OFFSET for pagination
 	query := `
 		SELECT id, project_id, path, size, mod_time, content_hash, language, content_type, indexed_at
-		FROM files WHERE project_id = ?
+		FROM ` + table + ` WHERE project_id = ?
 		ORDER BY path ASC
 		LIMIT ? OFFSET ?
 	`
@@ -610,7 +727,16 @@ func (s *SQLiteStore) ListFiles(ctx context.Context, projectID string, cursor st
 
 // DeleteFilesByProject deletes all files for a project.
 // Due to ON DELETE CASCADE, this also deletes associated chunks and symbols.
+// In bucket mode this is an O(1) DROP TABLE of the project's own bucket
+// instead of a row-by-row cascade.
 func (s *SQLiteStore) DeleteFilesByProject(ctx context.Context, projectID string) error {
+	if s.bucketTables {
+		if err := s.dropBucket(ctx, projectID); err != nil {
+			return fmt.Errorf("failed to delete files: %w", err)
+		}
+		return nil
+	}
+
 	query := `DELETE FROM files WHERE project_id = ?`
 	_, err := s.db.ExecContext(ctx, query, projectID)
 	if err != nil {
@@ -623,7 +749,11 @@ func (s *SQLiteStore) DeleteFilesByProject(ctx context.Context, projectID string
 // This is used for gitignore synchronization to determine which indexed files
 // should be removed when gitignore patterns change.
 func (s *SQLiteStore) GetFilePathsByProject(ctx context.Context, projectID string) ([]string, error) {
-	query := `SELECT path FROM files WHERE project_id = ? ORDER BY path`
+	table, err := s.filesTableFor(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file paths: %w", err)
+	}
+	query := `SELECT path FROM ` + table + ` WHERE project_id = ? ORDER BY path`
 	rows, err := s.db.QueryContext(ctx, query, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query file paths: %w", err)
@@ -659,8 +789,14 @@ func (s *SQLiteStore) ListFilePathsUnder(ctx context.Context, projectID, dirPref
 
 	// Use LIKE with escaped prefix + /% to match files under directory
 	// Note: SQLite LIKE is case-insensitive by default; paths should be case-sensitive
-	// We use || to concatenate in SQLite since Go's fmt.Sprintf might cause issues
-	query := `SELECT path FROM files WHERE project_id = ? AND (path LIKE ? OR path = ?) ORDER BY path`
+	query := s.gen.GetSqlListFilesUnder()
+	if s.bucketTables {
+		table, err := s.filesTableFor(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query files under %s: %w", dirPrefix, err)
+		}
+		query = strings.Replace(query, "FROM files ", "FROM "+table+" ", 1)
+	}
 	likePattern := dirPrefix + "/%"
 
 	rows, err := s.db.QueryContext(ctx, query, projectID, likePattern, dirPrefix)
@@ -690,9 +826,13 @@ func (s *SQLiteStore) ListFilePathsUnder(ctx context.Context, projectID, dirPref
 // indexed file metadata (mtime, size) against the current filesystem state.
 // BUG-036: Used to detect files created/modified/deleted while server was stopped.
 func (s *SQLiteStore) GetFilesForReconciliation(ctx context.Context, projectID string) (map[string]*File, error) {
+	table, err := s.filesTableFor(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query files for reconciliation: %w", err)
+	}
 	query := `
 		SELECT id, project_id, path, size, mod_time, content_hash, language, content_type, indexed_at
-		FROM files WHERE project_id = ?
+		FROM ` + table + ` WHERE project_id = ?
 	`
 	rows, err := s.db.QueryContext(ctx, query, projectID)
 	if err != nil {
@@ -740,11 +880,26 @@ func (s *SQLiteStore) GetFilesForReconciliation(ctx context.Context, projectID s
 // DeleteFile deletes a single file by ID.
 // Due to ON DELETE CASCADE, this also deletes associated chunks and symbols.
 func (s *SQLiteStore) DeleteFile(ctx context.Context, fileID string) error {
-	query := `DELETE FROM files WHERE id = ?`
-	_, err := s.db.ExecContext(ctx, query, fileID)
+	table, err := s.filesTableForFile(ctx, fileID)
 	if err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
+
+	query := `DELETE FROM ` + table + ` WHERE id = ?`
+	if _, err := s.db.ExecContext(ctx, query, fileID); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	if s.bucketTables {
+		// The deleted file's own bucket_index entry is no longer useful;
+		// any chunks it owned cascade-deleted within its bucket's own
+		// tables and are left in bucket_index (harmless - lookupBucket
+		// on an ID that no longer exists just returns a row that no
+		// longer resolves to anything).
+		if err := s.deleteBucketEntry(ctx, s.db, fileID, "file"); err != nil {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -753,51 +908,80 @@ func (s *SQLiteStore) SaveChunks(ctx context.Context, chunks []*Chunk) error {
 	if len(chunks) == 0 {
 		return nil
 	}
+	return s.WithTx(ctx, func(ctx context.Context, tx *StoreTx) error {
+		return tx.SaveChunks(ctx, chunks)
+	})
+}
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// saveChunksExec inserts chunks (and their symbols) against exec - the
+// store's own connection, or (from a WithTx callback) the enclosing
+// transaction/savepoint - so a whole batch commits or rolls back as one
+// unit instead of SaveChunks opening a separate transaction per
+// bucket-mode table-pair group.
+func (s *SQLiteStore) saveChunksExec(ctx context.Context, exec txExecutor, chunks []*Chunk) error {
+	if !s.bucketTables {
+		return s.saveChunksIntoTables(ctx, exec, "chunks", "symbols", chunks)
 	}
-	defer func() { _ = tx.Rollback() }()
 
-	// Prepare chunk insert statement
-	chunkStmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO chunks (id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			file_id = excluded.file_id,
-			file_path = excluded.file_path,
-			content = excluded.content,
-			raw_content = excluded.raw_content,
-			context = excluded.context,
-			content_type = excluded.content_type,
-			language = excluded.language,
-			start_line = excluded.start_line,
-			end_line = excluded.end_line,
-			metadata = excluded.metadata,
-			updated_at = excluded.updated_at
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare chunk statement: %w", err)
+	// Bucket mode: chunks can belong to different projects' buckets in
+	// the same batch (e.g. a multi-file reindex), so group them by
+	// destination table pair.
+	type group struct {
+		chunksTable, symbolsTable string
+		chunks                    []*Chunk
+	}
+	groups := make(map[string]*group)
+	for _, chunk := range chunks {
+		chunksTable, err := s.chunksTableForFile(ctx, chunk.FileID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve bucket for chunk %s: %w", chunk.ID, err)
+		}
+		symbolsTable := "symbols"
+		if chunksTable != "chunks" {
+			symbolsTable = strings.Replace(chunksTable, "chunks_", "symbols_", 1)
+		}
+		g, ok := groups[chunksTable]
+		if !ok {
+			g = &group{chunksTable: chunksTable, symbolsTable: symbolsTable}
+			groups[chunksTable] = g
+		}
+		g.chunks = append(g.chunks, chunk)
 	}
-	defer func() { _ = chunkStmt.Close() }()
 
-	// Prepare symbol insert statement
-	symbolStmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO symbols (chunk_id, name, type, start_line, end_line, signature, doc_comment)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare symbol statement: %w", err)
+	for _, g := range groups {
+		if err := s.saveChunksIntoTables(ctx, exec, g.chunksTable, g.symbolsTable, g.chunks); err != nil {
+			return err
+		}
+		for _, chunk := range g.chunks {
+			projectID, ok, err := s.lookupBucket(ctx, chunk.FileID, "file")
+			if err != nil {
+				return fmt.Errorf("failed to save chunk %s: %w", chunk.ID, err)
+			}
+			if !ok {
+				continue
+			}
+			if err := s.recordBucketEntry(ctx, exec, chunk.ID, "chunk", projectID); err != nil {
+				return fmt.Errorf("failed to save chunk %s: %w", chunk.ID, err)
+			}
+		}
 	}
-	defer func() { _ = symbolStmt.Close() }()
+	return nil
+}
 
-	// Delete existing symbols statement (for updates)
-	deleteSymbolsStmt, err := tx.PrepareContext(ctx, `DELETE FROM symbols WHERE chunk_id = ?`)
+// saveChunksIntoTables inserts chunks (and their symbols) into the given
+// chunks/symbols table pair, using the same statements
+// s.gen.GetSqlInsertChunk() produces for the shared tables.
+func (s *SQLiteStore) saveChunksIntoTables(ctx context.Context, exec txExecutor, chunksTable, symbolsTable string, chunks []*Chunk) error {
+	// Prepare chunk insert statement
+	chunkQuery := s.gen.GetSqlInsertChunk()
+	if chunksTable != "chunks" {
+		chunkQuery = strings.Replace(chunkQuery, "INTO chunks ", "INTO "+chunksTable+" ", 1)
+	}
+	chunkStmt, err := exec.PrepareContext(ctx, chunkQuery)
 	if err != nil {
-		return fmt.Errorf("failed to prepare delete symbols statement: %w", err)
+		return fmt.Errorf("failed to prepare chunk statement: %w", err)
 	}
-	defer func() { _ = deleteSymbolsStmt.Close() }()
+	defer func() { _ = chunkStmt.Close() }()
 
 	for _, chunk := range chunks {
 		// Serialize metadata
@@ -806,30 +990,62 @@ func (s *SQLiteStore) SaveChunks(ctx context.Context, chunks []*Chunk) error {
 			metadataJSON, _ = json.Marshal(chunk.Metadata)
 		}
 
-		_, err := chunkStmt.ExecContext(ctx,
-			chunk.ID, chunk.FileID, chunk.FilePath, chunk.Content, chunk.RawContent, chunk.Context,
+		content, err := encodeChunkColumn(s.chunkCompression, chunk.Content)
+		if err != nil {
+			return fmt.Errorf("failed to compress chunk %s content: %w", chunk.ID, err)
+		}
+		rawContent, err := encodeChunkColumn(s.chunkCompression, chunk.RawContent)
+		if err != nil {
+			return fmt.Errorf("failed to compress chunk %s raw_content: %w", chunk.ID, err)
+		}
+		chunkContext, err := encodeChunkColumn(s.chunkCompression, chunk.Context)
+		if err != nil {
+			return fmt.Errorf("failed to compress chunk %s context: %w", chunk.ID, err)
+		}
+
+		_, err = chunkStmt.ExecContext(ctx,
+			chunk.ID, chunk.FileID, chunk.FilePath, content, rawContent, chunkContext,
 			string(chunk.ContentType), chunk.Language, chunk.StartLine, chunk.EndLine,
 			string(metadataJSON), chunk.CreatedAt, chunk.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("failed to save chunk %s: %w", chunk.ID, err)
 		}
 
-		// Delete existing symbols for this chunk (in case of update)
-		if _, err := deleteSymbolsStmt.ExecContext(ctx, chunk.ID); err != nil {
-			return fmt.Errorf("failed to delete old symbols: %w", err)
+		if err := execSaveSymbols(ctx, exec, symbolsTable, chunk.ID, chunk.Symbols); err != nil {
+			return fmt.Errorf("failed to save chunk %s: %w", chunk.ID, err)
 		}
+	}
 
-		// Insert symbols
-		for _, sym := range chunk.Symbols {
-			_, err := symbolStmt.ExecContext(ctx, chunk.ID, sym.Name, string(sym.Type), sym.StartLine, sym.EndLine, sym.Signature, sym.DocComment)
-			if err != nil {
-				return fmt.Errorf("failed to save symbol %s: %w", sym.Name, err)
-			}
-		}
+	return nil
+}
+
+// execSaveSymbols replaces chunkID's symbols in symbolsTable: it deletes
+// whatever's there (in case this is an update) and inserts the given
+// set, against exec - the store's own connection, or the enclosing
+// transaction/savepoint from a WithTx callback.
+func execSaveSymbols(ctx context.Context, exec txExecutor, symbolsTable, chunkID string, symbols []*Symbol) error {
+	if _, err := exec.ExecContext(ctx, `DELETE FROM `+symbolsTable+` WHERE chunk_id = ?`, chunkID); err != nil {
+		return fmt.Errorf("failed to delete old symbols: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	symbolStmt, err := exec.PrepareContext(ctx, `
+		INSERT INTO `+symbolsTable+` (chunk_id, name, type, start_line, end_line, signature, doc_comment)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare symbol statement: %w", err)
+	}
+	defer func() { _ = symbolStmt.Close() }()
+
+	for _, sym := range symbols {
+		_, err := symbolStmt.ExecContext(ctx, chunkID, sym.Name, string(sym.Type), sym.StartLine, sym.EndLine, sym.Signature, sym.DocComment)
+		if err != nil {
+			return fmt.Errorf("failed to save symbol %s: %w", sym.Name, err)
+		}
 	}
 
 	return nil
@@ -837,17 +1053,17 @@ func (s *SQLiteStore) SaveChunks(ctx context.Context, chunks []*Chunk) error {
 
 // GetChunk retrieves a chunk by ID.
 func (s *SQLiteStore) GetChunk(ctx context.Context, id string) (*Chunk, error) {
+	table, err := s.chunksTableForChunk(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk: %w", err)
+	}
 	query := `
 		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at
-		FROM chunks WHERE id = ?
+		FROM ` + table + ` WHERE id = ?
 	`
 	row := s.db.QueryRowContext(ctx, query, id)
 
-	var c Chunk
-	var rawContent, chunkContext, contentType, language, metadataJSON sql.NullString
-	var createdAt, updatedAt sql.NullTime
-
-	err := row.Scan(&c.ID, &c.FileID, &c.FilePath, &c.Content, &rawContent, &chunkContext, &contentType, &language, &c.StartLine, &c.EndLine, &metadataJSON, &createdAt, &updatedAt)
+	c, err := scanChunkRow(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -855,11 +1071,56 @@ func (s *SQLiteStore) GetChunk(ctx context.Context, id string) (*Chunk, error) {
 		return nil, fmt.Errorf("failed to get chunk: %w", err)
 	}
 
-	if rawContent.Valid {
-		c.RawContent = rawContent.String
+	// Load symbols
+	symbols, err := s.getSymbolsForChunk(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if chunkContext.Valid {
-		c.Context = chunkContext.String
+	c.Symbols = symbols
+
+	return c, nil
+}
+
+// chunkRowScanner is the common subset of *sql.Row and *sql.Rows that
+// scanChunkRow needs, so the same scan logic can run against either a
+// single-row QueryRowContext result or one row of a QueryContext result
+// set.
+type chunkRowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanChunkRow scans one row in GetChunk/getChunksFromTable/
+// GetChunksByFile's shared column order (id, file_id, file_path,
+// content, raw_content, context, content_type, language, start_line,
+// end_line, metadata, created_at, updated_at) and decompresses
+// content/raw_content/context via decodeChunkColumn. It does not load
+// symbols; callers attach those separately.
+func scanChunkRow(scanner chunkRowScanner) (*Chunk, error) {
+	var c Chunk
+	var content, rawContent, chunkContext []byte
+	var contentType, language, metadataJSON sql.NullString
+	var createdAt, updatedAt sql.NullTime
+
+	err := scanner.Scan(&c.ID, &c.FileID, &c.FilePath, &content, &rawContent, &chunkContext, &contentType, &language, &c.StartLine, &c.EndLine, &metadataJSON, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Content, err = decodeChunkColumn(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress chunk %s content: %w", c.ID, err)
+	}
+	if rawContent != nil {
+		c.RawContent, err = decodeChunkColumn(rawContent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress chunk %s raw_content: %w", c.ID, err)
+		}
+	}
+	if chunkContext != nil {
+		c.Context, err = decodeChunkColumn(chunkContext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress chunk %s context: %w", c.ID, err)
+		}
 	}
 	if contentType.Valid {
 		c.ContentType = ContentType(contentType.String)
@@ -877,21 +1138,18 @@ func (s *SQLiteStore) GetChunk(ctx context.Context, id string) (*Chunk, error) {
 		_ = json.Unmarshal([]byte(metadataJSON.String), &c.Metadata)
 	}
 
-	// Load symbols
-	symbols, err := s.getSymbolsForChunk(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	c.Symbols = symbols
-
 	return &c, nil
 }
 
 // getSymbolsForChunk retrieves all symbols for a chunk.
 func (s *SQLiteStore) getSymbolsForChunk(ctx context.Context, chunkID string) ([]*Symbol, error) {
+	table, err := s.symbolsTableForChunk(ctx, chunkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbols: %w", err)
+	}
 	query := `
 		SELECT name, type, start_line, end_line, signature, doc_comment
-		FROM symbols WHERE chunk_id = ?
+		FROM ` + table + ` WHERE chunk_id = ?
 	`
 	rows, err := s.db.QueryContext(ctx, query, chunkID)
 	if err != nil {
@@ -932,67 +1190,34 @@ func (s *SQLiteStore) GetChunks(ctx context.Context, ids []string) ([]*Chunk, er
 		return nil, nil
 	}
 
-	// Build parameterized query with placeholders
-	placeholders := make([]string, len(ids))
-	args := make([]any, len(ids))
-	for i, id := range ids {
-		placeholders[i] = "?"
-		args[i] = id
-	}
-
-	query := `
-		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at
-		FROM chunks WHERE id IN (` + strings.Join(placeholders, ",") + `)
-	`
-
-	rows, err := s.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	byTable := map[string][]string{"chunks": nil}
+	if s.bucketTables {
+		byTable = make(map[string][]string)
+		for _, id := range ids {
+			table, err := s.chunksTableForChunk(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query chunks: %w", err)
+			}
+			byTable[table] = append(byTable[table], id)
+		}
+	} else {
+		byTable["chunks"] = ids
 	}
-	defer func() { _ = rows.Close() }()
 
-	// Pre-allocate with expected capacity
 	chunkMap := make(map[string]*Chunk, len(ids))
 	chunkIDs := make([]string, 0, len(ids))
-
-	for rows.Next() {
-		var c Chunk
-		var rawContent, chunkContext, contentType, language, metadataJSON sql.NullString
-		var createdAt, updatedAt sql.NullTime
-
-		err := rows.Scan(&c.ID, &c.FileID, &c.FilePath, &c.Content, &rawContent, &chunkContext, &contentType, &language, &c.StartLine, &c.EndLine, &metadataJSON, &createdAt, &updatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan chunk: %w", err)
-		}
-
-		if rawContent.Valid {
-			c.RawContent = rawContent.String
-		}
-		if chunkContext.Valid {
-			c.Context = chunkContext.String
-		}
-		if contentType.Valid {
-			c.ContentType = ContentType(contentType.String)
-		}
-		if language.Valid {
-			c.Language = language.String
+	for table, tableIDs := range byTable {
+		if len(tableIDs) == 0 {
+			continue
 		}
-		if createdAt.Valid {
-			c.CreatedAt = createdAt.Time
-		}
-		if updatedAt.Valid {
-			c.UpdatedAt = updatedAt.Time
+		got, err := s.getChunksFromTable(ctx, table, tableIDs)
+		if err != nil {
+			return nil, err
 		}
-		if metadataJSON.Valid && metadataJSON.String != "" {
-			_ = json.Unmarshal([]byte(metadataJSON.String), &c.Metadata)
+		for id, c := range got {
+			chunkMap[id] = c
+			chunkIDs = append(chunkIDs, id)
 		}
-
-		chunkMap[c.ID] = &c
-		chunkIDs = append(chunkIDs, c.ID)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate chunks: %w", err)
 	}
 
 	// Batch load symbols for all chunks
@@ -1019,13 +1244,81 @@ func (s *SQLiteStore) GetChunks(ctx context.Context, ids []string) ([]*Chunk, er
 	return result, nil
 }
 
-// getSymbolsForChunks retrieves symbols for multiple chunks in a single query.
-// Returns a map of chunk_id -> symbols.
+// getChunksFromTable runs GetChunks' batch lookup against a single
+// chunks table, returning a map keyed by chunk ID.
+func (s *SQLiteStore) getChunksFromTable(ctx context.Context, table string, ids []string) (map[string]*Chunk, error) {
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := `
+		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at
+		FROM ` + table + ` WHERE id IN (` + strings.Join(placeholders, ",") + `)
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	chunkMap := make(map[string]*Chunk, len(ids))
+	for rows.Next() {
+		c, err := scanChunkRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		chunkMap[c.ID] = c
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate chunks: %w", err)
+	}
+	return chunkMap, nil
+}
+
+// getSymbolsForChunks retrieves symbols for multiple chunks in a single
+// query per bucket. Returns a map of chunk_id -> symbols.
 func (s *SQLiteStore) getSymbolsForChunks(ctx context.Context, chunkIDs []string) (map[string][]*Symbol, error) {
 	if len(chunkIDs) == 0 {
 		return nil, nil
 	}
 
+	byTable := map[string][]string{}
+	if s.bucketTables {
+		for _, id := range chunkIDs {
+			table, err := s.symbolsTableForChunk(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query symbols: %w", err)
+			}
+			byTable[table] = append(byTable[table], id)
+		}
+	} else {
+		byTable["symbols"] = chunkIDs
+	}
+
+	result := make(map[string][]*Symbol, len(chunkIDs))
+	for table, ids := range byTable {
+		if len(ids) == 0 {
+			continue
+		}
+		got, err := s.getSymbolsForChunksFromTable(ctx, table, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, symbols := range got {
+			result[id] = append(result[id], symbols...)
+		}
+	}
+	return result, nil
+}
+
+// getSymbolsForChunksFromTable runs getSymbolsForChunks' batch lookup
+// against a single symbols table.
+func (s *SQLiteStore) getSymbolsForChunksFromTable(ctx context.Context, table string, chunkIDs []string) (map[string][]*Symbol, error) {
 	placeholders := make([]string, len(chunkIDs))
 	args := make([]any, len(chunkIDs))
 	for i, id := range chunkIDs {
@@ -1035,7 +1328,7 @@ func (s *SQLiteStore) getSymbolsForChunks(ctx context.Context, chunkIDs []string
 
 	query := `
 		SELECT chunk_id, name, type, start_line, end_line, signature, doc_comment
-		FROM symbols WHERE chunk_id IN (` + strings.Join(placeholders, ",") + `)
+		FROM ` + table + ` WHERE chunk_id IN (` + strings.Join(placeholders, ",") + `)
 	`
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -1072,9 +1365,13 @@ func (s *SQLiteStore) getSymbolsForChunks(ctx context.Context, chunkIDs []string
 
 // GetChunksByFile retrieves all chunks for a file.
 func (s *SQLiteStore) GetChunksByFile(ctx context.Context, fileID string) ([]*Chunk, error) {
+	table, err := s.chunksTableForFile(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
 	query := `
 		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at
-		FROM chunks WHERE file_id = ?
+		FROM ` + table + ` WHERE file_id = ?
 		ORDER BY start_line ASC
 	`
 	rows, err := s.db.QueryContext(ctx, query, fileID)
@@ -1085,38 +1382,11 @@ func (s *SQLiteStore) GetChunksByFile(ctx context.Context, fileID string) ([]*Ch
 
 	var chunks []*Chunk
 	for rows.Next() {
-		var c Chunk
-		var rawContent, chunkContext, contentType, language, metadataJSON sql.NullString
-		var createdAt, updatedAt sql.NullTime
-
-		err := rows.Scan(&c.ID, &c.FileID, &c.FilePath, &c.Content, &rawContent, &chunkContext, &contentType, &language, &c.StartLine, &c.EndLine, &metadataJSON, &createdAt, &updatedAt)
+		c, err := scanChunkRow(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan chunk: %w", err)
 		}
-
-		if rawContent.Valid {
-			c.RawContent = rawContent.String
-		}
-		if chunkContext.Valid {
-			c.Context = chunkContext.String
-		}
-		if contentType.Valid {
-			c.ContentType = ContentType(contentType.String)
-		}
-		if language.Valid {
-			c.Language = language.String
-		}
-		if createdAt.Valid {
-			c.CreatedAt = createdAt.Time
-		}
-		if updatedAt.Valid {
-			c.UpdatedAt = updatedAt.Time
-		}
-		if metadataJSON.Valid && metadataJSON.String != "" {
-			_ = json.Unmarshal([]byte(metadataJSON.String), &c.Metadata)
-		}
-
-		chunks = append(chunks, &c)
+		chunks = append(chunks, c)
 	}
 
 	// Load symbols for each chunk
@@ -1138,29 +1408,63 @@ func (s *SQLiteStore) DeleteChunks(ctx context.Context, ids []string) error {
 		return nil
 	}
 
-	// Build placeholders for IN clause
-	placeholders := make([]string, len(ids))
-	args := make([]interface{}, len(ids))
-	for i, id := range ids {
-		placeholders[i] = "?"
-		args[i] = id
+	byTable := map[string][]string{"chunks": ids}
+	if s.bucketTables {
+		byTable = make(map[string][]string)
+		for _, id := range ids {
+			table, err := s.chunksTableForChunk(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to delete chunks: %w", err)
+			}
+			byTable[table] = append(byTable[table], id)
+		}
 	}
 
-	query := fmt.Sprintf("DELETE FROM chunks WHERE id IN (%s)", strings.Join(placeholders, ","))
-	result, err := s.db.ExecContext(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("failed to delete chunks: %w", err)
+	var totalRequested, totalDeleted int64
+	for table, tableIDs := range byTable {
+		if len(tableIDs) == 0 {
+			continue
+		}
+		placeholders := make([]string, len(tableIDs))
+		args := make([]interface{}, len(tableIDs))
+		for i, id := range tableIDs {
+			placeholders[i] = "?"
+			args[i] = id
+		}
+
+		query := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", table, strings.Join(placeholders, ","))
+		result, err := s.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete chunks: %w", err)
+		}
+		totalRequested += int64(len(tableIDs))
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			slog.Warn("unable to get rows affected from chunk delete",
+				slog.String("error", err.Error()))
+		} else {
+			totalDeleted += rowsAffected
+		}
+
+		if s.bucketTables {
+			for _, id := range tableIDs {
+				if err := s.deleteBucketEntry(ctx, s.db, id, "chunk"); err != nil {
+					return fmt.Errorf("failed to delete chunks: %w", err)
+				}
+			}
+		}
 	}
 
 	// BUG-031 fix: Log warning if row count doesn't match expected
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		slog.Warn("unable to get rows affected from chunk delete",
-			slog.String("error", err.Error()))
-	} else if int(rowsAffected) != len(ids) {
+	if totalDeleted != totalRequested {
 		slog.Debug("chunk delete count mismatch (some may have been already deleted)",
-			slog.Int("requested", len(ids)),
-			slog.Int64("deleted", rowsAffected))
+			slog.Int64("requested", totalRequested),
+			slog.Int64("deleted", totalDeleted))
+	}
+
+	if s.vectorIndex != nil {
+		if err := s.vectorIndex.Delete(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete chunks from vector index: %w", err)
+		}
 	}
 
 	return nil
@@ -1169,23 +1473,76 @@ func (s *SQLiteStore) DeleteChunks(ctx context.Context, ids []string) error {
 // DeleteChunksByFile deletes all chunks for a file.
 // Due to ON DELETE CASCADE, this also deletes associated symbols.
 func (s *SQLiteStore) DeleteChunksByFile(ctx context.Context, fileID string) error {
-	query := `DELETE FROM chunks WHERE file_id = ?`
-	_, err := s.db.ExecContext(ctx, query, fileID)
+	table, err := s.chunksTableForFile(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete chunks: %w", err)
+	}
+
+	var deletedIDs []string
+	if s.vectorIndex != nil {
+		deletedIDs, err = s.chunkIDsForFile(ctx, table, fileID)
+		if err != nil {
+			return fmt.Errorf("failed to delete chunks: %w", err)
+		}
+	}
+
+	query := `DELETE FROM ` + table + ` WHERE file_id = ?`
+	_, err = s.db.ExecContext(ctx, query, fileID)
 	if err != nil {
 		return fmt.Errorf("failed to delete chunks: %w", err)
 	}
+
+	if s.vectorIndex != nil && len(deletedIDs) > 0 {
+		if err := s.vectorIndex.Delete(ctx, deletedIDs); err != nil {
+			return fmt.Errorf("failed to delete chunks from vector index: %w", err)
+		}
+	}
+
 	return nil
 }
 
 // SearchSymbols searches for symbols by name (partial match).
+// Unlike the other symbol methods, this has no chunk or file to resolve
+// a single bucket from, so in bucket mode it fans out across the shared
+// "symbols" table plus every project's own bucket and merges the results,
+// rather than routing to one table.
 func (s *SQLiteStore) SearchSymbols(ctx context.Context, name string, limit int) ([]*Symbol, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 
+	symbols, err := s.searchSymbolsInTable(ctx, "symbols", name, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.bucketTables {
+		projectIDs, err := s.listBucketedProjects(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search symbols: %w", err)
+		}
+		for _, projectID := range projectIDs {
+			_, _, symbolsTable := bucketTableNames(projectID)
+			more, err := s.searchSymbolsInTable(ctx, symbolsTable, name, limit)
+			if err != nil {
+				return nil, err
+			}
+			symbols = append(symbols, more...)
+		}
+		if len(symbols) > limit {
+			symbols = symbols[:limit]
+		}
+	}
+
+	return symbols, nil
+}
+
+// searchSymbolsInTable runs SearchSymbols' LIKE query against a single
+// symbols table.
+func (s *SQLiteStore) searchSymbolsInTable(ctx context.Context, table, name string, limit int) ([]*Symbol, error) {
 	query := `
 		SELECT name, type, start_line, end_line, signature, doc_comment
-		FROM symbols WHERE name LIKE ?
+		FROM ` + table + ` WHERE name LIKE ?
 		LIMIT ?
 	`
 	rows, err := s.db.QueryContext(ctx, query, "%"+name+"%", limit)
@@ -1421,6 +1778,12 @@ func (s *SQLiteStore) SaveChunkEmbeddings(ctx context.Context, chunkIDs []string
 		return fmt.Errorf("commit transaction: %w", err)
 	}
 
+	if s.vectorIndex != nil {
+		if err := s.vectorIndex.Add(ctx, chunkIDs, embeddings); err != nil {
+			return fmt.Errorf("save embeddings to vector index: %w", err)
+		}
+	}
+
 	return nil
 }
 
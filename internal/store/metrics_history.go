@@ -0,0 +1,402 @@
+package store
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MetricsSample is one point-in-time snapshot of an index's size and
+// shape, the same fields DebugInfo reports, recorded so a caller can plot
+// a growth curve across many index/debug runs instead of only ever
+// seeing the latest snapshot.
+type MetricsSample struct {
+	Timestamp          time.Time
+	ChunkCount         int64
+	FileCount          int64
+	TotalSizeBytes     int64
+	VectorSizeBytes    int64
+	BM25SizeBytes      int64
+	EmbedderDimensions int64
+	IndexAgeSeconds    int64
+}
+
+// metricsHistoryFile is where RecordMetricsSample/LoadMetricsHistory keep
+// the compressed sample stream, under its own subdirectory so it can grow
+// independently of metadata.db/bm25.db/vectors.hnsw.
+const metricsHistoryFile = "metrics/history.bin"
+
+// metricsColumns lists the int64 fields of MetricsSample in the fixed
+// order they're encoded/decoded in - every call site that walks columns
+// (encodeSamples, decodeSamples) iterates this same slice so the two stay
+// in sync by construction rather than by convention.
+var metricsColumns = []struct {
+	get func(s MetricsSample) int64
+	set func(s *MetricsSample, v int64)
+}{
+	{func(s MetricsSample) int64 { return s.ChunkCount }, func(s *MetricsSample, v int64) { s.ChunkCount = v }},
+	{func(s MetricsSample) int64 { return s.FileCount }, func(s *MetricsSample, v int64) { s.FileCount = v }},
+	{func(s MetricsSample) int64 { return s.TotalSizeBytes }, func(s *MetricsSample, v int64) { s.TotalSizeBytes = v }},
+	{func(s MetricsSample) int64 { return s.VectorSizeBytes }, func(s *MetricsSample, v int64) { s.VectorSizeBytes = v }},
+	{func(s MetricsSample) int64 { return s.BM25SizeBytes }, func(s *MetricsSample, v int64) { s.BM25SizeBytes = v }},
+	{func(s MetricsSample) int64 { return s.EmbedderDimensions }, func(s *MetricsSample, v int64) { s.EmbedderDimensions = v }},
+	{func(s MetricsSample) int64 { return s.IndexAgeSeconds }, func(s *MetricsSample, v int64) { s.IndexAgeSeconds = v }},
+}
+
+// RecordMetricsSample appends sample to dataDir's metrics history,
+// Gorilla-compressing the stream (XOR delta for the int64 columns,
+// delta-of-delta for timestamps) the way Facebook's Gorilla TSDB packs
+// monitoring samples into 2-byte-ish points instead of one fixed-width
+// record each.
+//
+// The whole history is decoded, appended to in memory, and re-encoded on
+// every call rather than bit-appended to the open file - a CLI process
+// is too short-lived to hold a bitstream writer open across invocations,
+// and a history of one sample per index/debug run stays small enough
+// (thousands of samples, not millions) that re-encoding it is cheap. The
+// file on disk is always the compact Gorilla-encoded form either way.
+func RecordMetricsSample(dataDir string, sample MetricsSample) error {
+	path := filepath.Join(dataDir, metricsHistoryFile)
+	existing, err := LoadMetricsHistory(dataDir, time.Time{})
+	if err != nil {
+		return fmt.Errorf("load existing metrics history: %w", err)
+	}
+	existing = append(existing, sample)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create metrics dir: %w", err)
+	}
+	if err := os.WriteFile(path, encodeSamples(existing), 0o644); err != nil {
+		return fmt.Errorf("write metrics history: %w", err)
+	}
+	return nil
+}
+
+// LoadMetricsHistory decodes dataDir's metrics history, returning samples
+// with Timestamp >= since. A zero since returns every recorded sample. A
+// missing history file returns an empty slice, not an error - there's
+// nothing wrong with an index that hasn't been debugged/indexed yet.
+func LoadMetricsHistory(dataDir string, since time.Time) ([]MetricsSample, error) {
+	path := filepath.Join(dataDir, metricsHistoryFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read metrics history: %w", err)
+	}
+
+	samples, err := decodeSamples(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode metrics history: %w", err)
+	}
+	if since.IsZero() {
+		return samples, nil
+	}
+
+	filtered := samples[:0]
+	for _, s := range samples {
+		if !s.Timestamp.Before(since) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered, nil
+}
+
+// encodeSamples packs samples into the Gorilla-style binary format
+// LoadMetricsHistory/decodeSamples reverse. An empty slice encodes to just
+// a zero count.
+func encodeSamples(samples []MetricsSample) []byte {
+	w := newBitWriter()
+	w.writeBits(uint64(len(samples)), 32)
+	if len(samples) == 0 {
+		return w.bytes()
+	}
+
+	first := samples[0]
+	w.writeBits(uint64(first.Timestamp.Unix()), 64)
+	for _, col := range metricsColumns {
+		w.writeBits(uint64(col.get(first)), 64)
+	}
+
+	xorStates := make([]xorState, len(metricsColumns))
+	for i, col := range metricsColumns {
+		xorStates[i].prev = col.get(first)
+	}
+	prevTimestamp := first.Timestamp.Unix()
+	var prevDelta int64
+
+	for _, s := range samples[1:] {
+		t := s.Timestamp.Unix()
+		delta := t - prevTimestamp
+		writeDeltaOfDelta(w, delta-prevDelta)
+		prevDelta = delta
+		prevTimestamp = t
+
+		for i, col := range metricsColumns {
+			xorStates[i].write(w, col.get(s))
+		}
+	}
+
+	return w.bytes()
+}
+
+// decodeSamples reverses encodeSamples.
+func decodeSamples(data []byte) ([]MetricsSample, error) {
+	r := newBitReader(data)
+	count, err := r.readBits(32)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	t0, err := r.readBits(64)
+	if err != nil {
+		return nil, err
+	}
+	first := MetricsSample{Timestamp: time.Unix(int64(t0), 0).UTC()}
+	for _, col := range metricsColumns {
+		v, err := r.readBits(64)
+		if err != nil {
+			return nil, err
+		}
+		col.set(&first, int64(v))
+	}
+
+	samples := make([]MetricsSample, 1, count)
+	samples[0] = first
+
+	xorStates := make([]xorState, len(metricsColumns))
+	for i, col := range metricsColumns {
+		xorStates[i].prev = col.get(first)
+	}
+	prevTimestamp := int64(t0)
+	var prevDelta int64
+
+	for i := uint64(1); i < count; i++ {
+		dod, err := readDeltaOfDelta(r)
+		if err != nil {
+			return nil, err
+		}
+		delta := prevDelta + dod
+		t := prevTimestamp + delta
+		prevDelta, prevTimestamp = delta, t
+
+		s := MetricsSample{Timestamp: time.Unix(t, 0).UTC()}
+		for j, col := range metricsColumns {
+			v, err := xorStates[j].read(r)
+			if err != nil {
+				return nil, err
+			}
+			col.set(&s, v)
+		}
+		samples = append(samples, s)
+	}
+
+	return samples, nil
+}
+
+// writeDeltaOfDelta encodes dod using the same variable-width buckets
+// Gorilla uses for timestamp delta-of-deltas: an exact repeat of the
+// previous delta costs a single bit, and the bucket widens only as far as
+// the value actually needs.
+func writeDeltaOfDelta(w *bitWriter, dod int64) {
+	switch {
+	case dod == 0:
+		w.writeBits(0, 1)
+	case dod >= -64 && dod <= 63:
+		w.writeBits(0b10, 2)
+		w.writeBits(uint64(dod)&((1<<7)-1), 7)
+	case dod >= -256 && dod <= 255:
+		w.writeBits(0b110, 3)
+		w.writeBits(uint64(dod)&((1<<9)-1), 9)
+	case dod >= -2048 && dod <= 2047:
+		w.writeBits(0b1110, 4)
+		w.writeBits(uint64(dod)&((1<<12)-1), 12)
+	default:
+		w.writeBits(0b1111, 4)
+		w.writeBits(uint64(uint32(dod)), 32)
+	}
+}
+
+// readDeltaOfDelta reverses writeDeltaOfDelta.
+func readDeltaOfDelta(r *bitReader) (int64, error) {
+	bit, err := r.readBits(1)
+	if err != nil {
+		return 0, err
+	}
+	if bit == 0 {
+		return 0, nil
+	}
+
+	nbits := 0
+	for nbits < 3 {
+		b, err := r.readBits(1)
+		if err != nil {
+			return 0, err
+		}
+		if b == 0 {
+			break
+		}
+		nbits++
+	}
+
+	widths := [4]int{7, 9, 12, 32}
+	width := widths[nbits]
+	v, err := r.readBits(width)
+	if err != nil {
+		return 0, err
+	}
+	return signExtend(v, width), nil
+}
+
+// signExtend interprets the low width bits of v as a two's-complement
+// signed integer of that width.
+func signExtend(v uint64, width int) int64 {
+	shift := 64 - uint(width)
+	return int64(v<<shift) >> shift
+}
+
+// xorState holds the running Gorilla XOR-compression state for one
+// int64 column: the previous raw value plus the leading/trailing zero
+// window of the previous nonzero XOR, so a run of values whose changes
+// all fall in the same bit range costs just the meaningful bits each
+// time.
+type xorState struct {
+	prev          int64
+	leading       int
+	trailing      int
+	haveXORWindow bool
+}
+
+func (x *xorState) write(w *bitWriter, v int64) {
+	xor := uint64(v) ^ uint64(x.prev)
+	x.prev = v
+	if xor == 0 {
+		w.writeBits(0, 1)
+		return
+	}
+	w.writeBits(1, 1)
+
+	leading := bits.LeadingZeros64(xor)
+	trailing := bits.TrailingZeros64(xor)
+	if x.haveXORWindow && leading >= x.leading && trailing >= x.trailing {
+		w.writeBits(0, 1)
+		meaningful := 64 - x.leading - x.trailing
+		w.writeBits(xor>>uint(x.trailing), meaningful)
+		return
+	}
+
+	w.writeBits(1, 1)
+	if leading > 31 {
+		leading = 31
+	}
+	meaningful := 64 - leading - trailing
+	w.writeBits(uint64(leading), 5)
+	w.writeBits(uint64(meaningful), 6)
+	w.writeBits(xor>>uint(trailing), meaningful)
+	x.leading, x.trailing, x.haveXORWindow = leading, trailing, true
+}
+
+func (x *xorState) read(r *bitReader) (int64, error) {
+	same, err := r.readBits(1)
+	if err != nil {
+		return 0, err
+	}
+	if same == 0 {
+		return x.prev, nil
+	}
+
+	newWindow, err := r.readBits(1)
+	if err != nil {
+		return 0, err
+	}
+	if newWindow == 1 {
+		leading, err := r.readBits(5)
+		if err != nil {
+			return 0, err
+		}
+		meaningful, err := r.readBits(6)
+		if err != nil {
+			return 0, err
+		}
+		// A nonzero XOR has at least one meaningful bit, so 0 in the 6-bit
+		// field can only be a width of 64 that write truncated.
+		if meaningful == 0 {
+			meaningful = 64
+		}
+		x.leading = int(leading)
+		x.trailing = 64 - x.leading - int(meaningful)
+		x.haveXORWindow = true
+	}
+
+	meaningful := 64 - x.leading - x.trailing
+	bitsVal, err := r.readBits(meaningful)
+	if err != nil {
+		return 0, err
+	}
+	xor := bitsVal << uint(x.trailing)
+	v := int64(uint64(x.prev) ^ xor)
+	x.prev = v
+	return v, nil
+}
+
+// bitWriter packs bits MSB-first into a growing byte slice.
+type bitWriter struct {
+	buf  []byte
+	cur  byte
+	nbit uint
+}
+
+func newBitWriter() *bitWriter {
+	return &bitWriter{}
+}
+
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		bit := (v >> uint(i)) & 1
+		w.cur |= byte(bit) << (7 - w.nbit)
+		w.nbit++
+		if w.nbit == 8 {
+			w.buf = append(w.buf, w.cur)
+			w.cur = 0
+			w.nbit = 0
+		}
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nbit == 0 {
+		return w.buf
+	}
+	return append(w.buf, w.cur)
+}
+
+// bitReader reads bits MSB-first out of a byte slice written by bitWriter.
+type bitReader struct {
+	buf []byte
+	pos uint64
+}
+
+func newBitReader(buf []byte) *bitReader {
+	return &bitReader{buf: buf}
+}
+
+func (r *bitReader) readBits(n int) (uint64, error) {
+	var v uint64
+	for i := 0; i < n; i++ {
+		byteIdx := r.pos / 8
+		if byteIdx >= uint64(len(r.buf)) {
+			return 0, fmt.Errorf("metrics history: unexpected end of stream")
+		}
+		bitIdx := r.pos % 8
+		bit := (r.buf[byteIdx] >> (7 - bitIdx)) & 1
+		v = v<<1 | uint64(bit)
+		r.pos++
+	}
+	return v, nil
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsHistory_RoundTrip(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	samples := make([]MetricsSample, 0, 20)
+	chunkCount := int64(100)
+	for i := 0; i < 20; i++ {
+		chunkCount += int64(i % 3)
+		samples = append(samples, MetricsSample{
+			Timestamp:          base.Add(time.Duration(i) * time.Minute),
+			ChunkCount:         chunkCount,
+			FileCount:          int64(10 + i),
+			TotalSizeBytes:     int64(1_000_000 + i*1_000),
+			VectorSizeBytes:    int64(500_000 + i*500),
+			BM25SizeBytes:      int64(200_000 + i*200),
+			EmbedderDimensions: 768,
+			IndexAgeSeconds:    int64(i * 60),
+		})
+	}
+
+	encoded := encodeSamples(samples)
+	decoded, err := decodeSamples(encoded)
+	if err != nil {
+		t.Fatalf("decodeSamples: %v", err)
+	}
+	if len(decoded) != len(samples) {
+		t.Fatalf("got %d samples, want %d", len(decoded), len(samples))
+	}
+	for i, want := range samples {
+		got := decoded[i]
+		if !got.Timestamp.Equal(want.Timestamp) || got != want {
+			t.Fatalf("sample %d: got %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestMetricsHistory_EmptyAndSingle(t *testing.T) {
+	decoded, err := decodeSamples(encodeSamples(nil))
+	if err != nil || decoded != nil {
+		t.Fatalf("empty history: got %v, %v", decoded, err)
+	}
+
+	single := []MetricsSample{{Timestamp: time.Unix(1700000000, 0).UTC(), ChunkCount: 5, EmbedderDimensions: 768}}
+	decoded, err = decodeSamples(encodeSamples(single))
+	if err != nil {
+		t.Fatalf("single sample: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0] != single[0] {
+		t.Fatalf("single sample: got %+v", decoded)
+	}
+}
+
+func TestRecordMetricsSample_AppendsAndFiltersBySince(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Unix(1700000000, 0).UTC()
+	for i := 0; i < 5; i++ {
+		sample := MetricsSample{Timestamp: base.Add(time.Duration(i) * time.Hour), ChunkCount: int64(100 + i)}
+		if err := RecordMetricsSample(dir, sample); err != nil {
+			t.Fatalf("RecordMetricsSample: %v", err)
+		}
+	}
+
+	all, err := LoadMetricsHistory(dir, time.Time{})
+	if err != nil {
+		t.Fatalf("LoadMetricsHistory: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("got %d samples, want 5", len(all))
+	}
+
+	recent, err := LoadMetricsHistory(dir, base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("LoadMetricsHistory since: %v", err)
+	}
+	if len(recent) != 3 {
+		t.Fatalf("got %d recent samples, want 3", len(recent))
+	}
+}
+
+func TestLoadMetricsHistory_MissingFileReturnsEmpty(t *testing.T) {
+	samples, err := LoadMetricsHistory(t.TempDir(), time.Time{})
+	if err != nil || samples != nil {
+		t.Fatalf("got %v, %v", samples, err)
+	}
+}
@@ -0,0 +1,106 @@
+// Package migrations holds SQLiteStore's schema migrations as embedded
+// SQL files, modeled on sql-migrate/rubenv: each numbered file carries a
+// "-- +migrate Up" section and a "-- +migrate Down" section, and Load
+// returns them parsed and checksummed for internal/store's migrator to
+// apply.
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+const (
+	upMarker   = "-- +migrate Up"
+	downMarker = "-- +migrate Down"
+)
+
+// Migration is one numbered schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the SHA256 of the raw file content, recorded alongside
+	// the applied version in schema_version so a later startup can detect
+	// drift between what's on disk and what was actually applied.
+	Checksum string
+}
+
+// Load parses every embedded *.sql file into a Migration, ordered by
+// version ascending.
+func Load() ([]Migration, error) {
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return nil, fmt.Errorf("read embedded migrations: %w", err)
+	}
+
+	out := make([]Migration, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		content, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+		m, err := parse(entry.Name(), content)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+func parse(filename string, content []byte) (Migration, error) {
+	version, name, err := parseFilename(filename)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	text := string(content)
+	upIdx := strings.Index(text, upMarker)
+	downIdx := strings.Index(text, downMarker)
+	if upIdx == -1 || downIdx == -1 || downIdx < upIdx {
+		return Migration{}, fmt.Errorf("migration %s: missing %q/%q sections", filename, upMarker, downMarker)
+	}
+
+	up := strings.TrimSpace(text[upIdx+len(upMarker) : downIdx])
+	down := strings.TrimSpace(text[downIdx+len(downMarker):])
+
+	sum := sha256.Sum256(content)
+
+	return Migration{
+		Version:  version,
+		Name:     name,
+		Up:       up,
+		Down:     down,
+		Checksum: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// parseFilename expects "NNNN_description.sql", e.g.
+// "0002_add_embedding_columns.sql".
+func parseFilename(filename string) (version int, name string, err error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	parts := strings.SplitN(base, "_", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("migration filename %q must be NNNN_description.sql", filename)
+	}
+	version, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("migration filename %q has a non-numeric version: %w", filename, err)
+	}
+	return version, parts[1], nil
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import "testing"
+
+func TestLoad_ParsesEmbeddedMigrationsInVersionOrder(t *testing.T) {
+	all, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("expected 4 embedded migrations, got %d", len(all))
+	}
+
+	if all[0].Version != 2 || all[1].Version != 3 || all[2].Version != 4 || all[3].Version != 5 {
+		t.Fatalf("expected versions [2 3 4 5], got [%d %d %d %d]", all[0].Version, all[1].Version, all[2].Version, all[3].Version)
+	}
+
+	for _, m := range all {
+		if m.Up == "" {
+			t.Errorf("migration %d: empty Up section", m.Version)
+		}
+		if m.Down == "" {
+			t.Errorf("migration %d: empty Down section", m.Version)
+		}
+		if len(m.Checksum) != 64 {
+			t.Errorf("migration %d: expected a 64-char hex SHA256, got %q", m.Version, m.Checksum)
+		}
+	}
+}
+
+func TestLoad_ChecksumIsStableAcrossCalls(t *testing.T) {
+	first, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	second, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for i := range first {
+		if first[i].Checksum != second[i].Checksum {
+			t.Errorf("migration %d: checksum changed between calls", first[i].Version)
+		}
+	}
+}
+
+func TestParseFilename_RejectsMalformedNames(t *testing.T) {
+	if _, _, err := parseFilename("not_numbered.sql"); err == nil {
+		t.Error("expected an error for a missing version prefix")
+	}
+	if _, _, err := parseFilename("abc_description.sql"); err == nil {
+		t.Error("expected an error for a non-numeric version prefix")
+	}
+
+	version, name, err := parseFilename("0002_add_embedding_columns.sql")
+	if err != nil {
+		t.Fatalf("parseFilename: %v", err)
+	}
+	if version != 2 || name != "add_embedding_columns" {
+		t.Errorf("got version=%d name=%q, want version=2 name=%q", version, name, "add_embedding_columns")
+	}
+}
@@ -0,0 +1,245 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Aman-CERP/amanmcp/internal/store/migrations"
+)
+
+// MigrationStatus reports one migration's applied state, for
+// `amanmcp db migrate status`.
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied bool
+	// Checksum is the SHA256 of the migration file as it exists on disk
+	// right now.
+	Checksum string
+	// RecordedChecksum is the checksum schema_version recorded when the
+	// migration was applied. It differs from Checksum when the migration
+	// file has drifted (edited in place) since that apply.
+	RecordedChecksum string
+}
+
+// migrator applies the embedded migrations package against db, replacing
+// the old hand-rolled runMigrations() ladder. Each migration runs inside
+// its own BEGIN IMMEDIATE transaction and is recorded in schema_version
+// with its SHA256, so a failed migration rolls back cleanly instead of
+// leaving the schema half-applied, and a later startup can tell whether
+// an already-applied migration's file has since changed underneath it.
+//
+// db is assumed to be the same single, long-lived connection
+// SQLiteStore already enforces via SetMaxOpenConns(1); BEGIN IMMEDIATE is
+// issued directly on it rather than through a *sql.Tx; so no interleaved
+// queries on a second connection can smuggle mid-migration state.
+type migrator struct {
+	db *sql.DB
+}
+
+func newMigrator(db *sql.DB) *migrator {
+	return &migrator{db: db}
+}
+
+// ensureSchemaVersionTableHasChecksum adds the checksum column the first
+// time a pre-existing database (created before this framework existed)
+// is migrated, using an explicit PRAGMA table_info check rather than
+// firing the ALTER and swallowing a "duplicate column" error string.
+func (m *migrator) ensureSchemaVersionTableHasChecksum(ctx context.Context) error {
+	ok, err := columnExists(ctx, m.db, "schema_version", "checksum")
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	if _, err := m.db.ExecContext(ctx, `ALTER TABLE schema_version ADD COLUMN checksum TEXT`); err != nil {
+		return fmt.Errorf("add schema_version.checksum column: %w", err)
+	}
+	return nil
+}
+
+// columnExists reports whether table has column, via PRAGMA table_info.
+func columnExists(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("inspect %s columns: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			dfltValue  sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &primaryKey); err != nil {
+			return false, fmt.Errorf("scan %s column info: %w", table, err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+func (m *migrator) currentVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := m.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("get schema version: %w", err)
+	}
+	return version, nil
+}
+
+// latestVersion returns the highest version among the embedded migration
+// files, i.e. what MigrateTo should be called with to mean "latest".
+func (m *migrator) latestVersion(all []migrations.Migration) int {
+	latest := 0
+	for _, mig := range all {
+		if mig.Version > latest {
+			latest = mig.Version
+		}
+	}
+	return latest
+}
+
+// MigrateToLatest applies every migration beyond the current schema
+// version. It's what initSchema calls on every startup.
+func (m *migrator) MigrateToLatest(ctx context.Context) error {
+	all, err := migrations.Load()
+	if err != nil {
+		return err
+	}
+	return m.migrateTo(ctx, m.latestVersion(all), all)
+}
+
+// MigrateTo applies or rolls back migrations until the schema is at
+// exactly targetVersion.
+func (m *migrator) MigrateTo(ctx context.Context, targetVersion int) error {
+	all, err := migrations.Load()
+	if err != nil {
+		return err
+	}
+	return m.migrateTo(ctx, targetVersion, all)
+}
+
+func (m *migrator) migrateTo(ctx context.Context, targetVersion int, all []migrations.Migration) error {
+	if err := m.ensureSchemaVersionTableHasChecksum(ctx); err != nil {
+		return err
+	}
+
+	current, err := m.currentVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if targetVersion >= current {
+		for _, mig := range all {
+			if mig.Version <= current || mig.Version > targetVersion {
+				continue
+			}
+			if err := m.apply(ctx, mig); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		mig := all[i]
+		if mig.Version > current || mig.Version <= targetVersion {
+			continue
+		}
+		if err := m.rollback(ctx, mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *migrator) apply(ctx context.Context, mig migrations.Migration) error {
+	if _, err := m.db.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("begin migration %d (%s): %w", mig.Version, mig.Name, err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, mig.Up); err != nil {
+		_, _ = m.db.ExecContext(ctx, "ROLLBACK")
+		return fmt.Errorf("apply migration %d (%s): %w", mig.Version, mig.Name, err)
+	}
+
+	if _, err := m.db.ExecContext(ctx,
+		`INSERT INTO schema_version (version, checksum) VALUES (?, ?)`,
+		mig.Version, mig.Checksum); err != nil {
+		_, _ = m.db.ExecContext(ctx, "ROLLBACK")
+		return fmt.Errorf("record migration %d: %w", mig.Version, err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("commit migration %d (%s): %w", mig.Version, mig.Name, err)
+	}
+	return nil
+}
+
+func (m *migrator) rollback(ctx context.Context, mig migrations.Migration) error {
+	if _, err := m.db.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("begin rollback of migration %d (%s): %w", mig.Version, mig.Name, err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, mig.Down); err != nil {
+		_, _ = m.db.ExecContext(ctx, "ROLLBACK")
+		return fmt.Errorf("roll back migration %d (%s): %w", mig.Version, mig.Name, err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, `DELETE FROM schema_version WHERE version = ?`, mig.Version); err != nil {
+		_, _ = m.db.ExecContext(ctx, "ROLLBACK")
+		return fmt.Errorf("unrecord migration %d: %w", mig.Version, err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("commit rollback of migration %d (%s): %w", mig.Version, mig.Name, err)
+	}
+	return nil
+}
+
+// Status reports the apply/drift state of every known migration.
+func (m *migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	all, err := migrations.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]string, len(all))
+	rows, err := m.db.QueryContext(ctx, `SELECT version, checksum FROM schema_version ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("query schema_version: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var version int
+		var checksum sql.NullString
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("scan schema_version row: %w", err)
+		}
+		applied[version] = checksum.String
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schema_version: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(all))
+	for _, mig := range all {
+		recorded, ok := applied[mig.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:          mig.Version,
+			Name:             mig.Name,
+			Applied:          ok,
+			Checksum:         mig.Checksum,
+			RecordedChecksum: recorded,
+		})
+	}
+	return statuses, nil
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_MigratesToLatestOnOpen(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	statuses, err := s.MigrationStatus(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 4)
+	for _, st := range statuses {
+		assert.True(t, st.Applied, "migration %d should be applied on a freshly opened store", st.Version)
+		assert.Equal(t, st.Checksum, st.RecordedChecksum, "migration %d should record its current checksum", st.Version)
+	}
+}
+
+func TestSQLiteStore_MigrateToRollsBackAndReappliesCleanly(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	require.NoError(t, s.MigrateTo(ctx, 1))
+
+	var version int
+	require.NoError(t, s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version))
+	assert.Equal(t, 1, version)
+
+	// Migration 2's embedding columns should be gone after rollback.
+	_, err = s.db.ExecContext(ctx, `SELECT embedding FROM chunks LIMIT 1`)
+	assert.Error(t, err, "embedding column should not exist below schema version 2")
+
+	require.NoError(t, s.MigrateTo(ctx, 3))
+	require.NoError(t, s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version))
+	assert.Equal(t, 3, version)
+
+	_, err = s.db.ExecContext(ctx, `SELECT embedding FROM chunks LIMIT 1`)
+	assert.NoError(t, err, "embedding column should be restored after re-migrating up")
+}
+
+func TestColumnExists(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	ok, err := columnExists(ctx, s.db, "schema_version", "checksum")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = columnExists(ctx, s.db, "schema_version", "nonexistent_column")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
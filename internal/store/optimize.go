@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// OptimizeOptions configures a BM25Index's Optimize run.
+type OptimizeOptions struct {
+	// LiveIDs, if non-nil, is the authoritative set of document IDs that
+	// should survive optimize - typically derived from
+	// MetadataStore.GetAllEmbeddings, the same chunk ID set VerifyIndex
+	// checks against. A nil LiveIDs keeps every ID AllIDs currently
+	// returns, making Optimize a pure repack with nothing removed.
+	LiveIDs map[string]struct{}
+	// DryRun computes OptimizeStats without touching the on-disk index -
+	// the same estimate restic's `optimize --dry-run` prints before
+	// repacking, used by EstimateReclaimableBytes so a caller can surface
+	// a reclaimable-bytes figure without paying for a rebuild.
+	DryRun bool
+}
+
+// OptimizeStats reports what an Optimize run did (or, under DryRun, would
+// do).
+type OptimizeStats struct {
+	RemovedDocs    int
+	ReclaimedBytes int64
+	Duration       time.Duration
+}
+
+// Optimizable is implemented by a BM25Index backend that can repack its
+// postings to reclaim space left behind by tombstoned documents - the
+// same operation restic's `optimize` performs for unused blobs. Deleting
+// from most BM25Index backends only marks a posting as gone; index size
+// otherwise grows monotonically until something rebuilds it.
+//
+// This is a capability a backend opts into rather than a method on
+// BM25Index itself: BM25Index is declared and implemented in more places
+// (backends, test doubles) than is safe to retrofit with a new required
+// method from here, and not every implementation has anything to
+// reclaim (an in-memory test double, for instance).
+type Optimizable interface {
+	// Optimize enumerates AllIDs, keeps only the ones opts.LiveIDs
+	// contains (or every ID, if opts.LiveIDs is nil), and rebuilds the
+	// postings for survivors into a fresh on-disk structure before
+	// atomically swapping it in (e.g. SQLite's VACUUM INTO + rename, or
+	// a new Bleve segment plus merge) - unless opts.DryRun is set, in
+	// which case nothing is written and the returned stats are an
+	// estimate. ctx is checked periodically so a long optimize can be
+	// cancelled without disturbing the original index: a cancellation
+	// must never leave anything other than the pre-optimize index in
+	// place.
+	Optimize(ctx context.Context, opts OptimizeOptions) (*OptimizeStats, error)
+}
+
+// OptimizeIndex runs idx.Optimize if idx implements Optimizable,
+// otherwise returns a zero OptimizeStats and no error - so a caller
+// optimizing a project's index doesn't need to type-switch every backend
+// itself.
+func OptimizeIndex(ctx context.Context, idx BM25Index, opts OptimizeOptions) (*OptimizeStats, error) {
+	o, ok := idx.(Optimizable)
+	if !ok {
+		return &OptimizeStats{}, nil
+	}
+	return o.Optimize(ctx, opts)
+}
+
+// EstimateReclaimableBytes returns a dry-run Optimize estimate of how many
+// bytes an Optimize run against bm25 would free, for GetIndexInfo-style
+// reporting. It's kept as a standalone helper rather than an IndexInfo
+// field since IndexInfo's own definition lives outside this package.
+func EstimateReclaimableBytes(ctx context.Context, bm25 BM25Index, liveIDs map[string]struct{}) (int64, error) {
+	stats, err := OptimizeIndex(ctx, bm25, OptimizeOptions{LiveIDs: liveIDs, DryRun: true})
+	if err != nil {
+		return 0, err
+	}
+	return stats.ReclaimedBytes, nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optimizableBM25Index extends stubBM25Index with a scriptable Optimize,
+// for exercising OptimizeIndex's type-assertion branch without needing a
+// real on-disk backend.
+type optimizableBM25Index struct {
+	stubBM25Index
+	optimizeFn func(ctx context.Context, opts OptimizeOptions) (*OptimizeStats, error)
+}
+
+func (o *optimizableBM25Index) Optimize(ctx context.Context, opts OptimizeOptions) (*OptimizeStats, error) {
+	return o.optimizeFn(ctx, opts)
+}
+
+func TestOptimizeIndex_ReturnsZeroStatsWhenBackendIsNotOptimizable(t *testing.T) {
+	stats, err := OptimizeIndex(context.Background(), &stubBM25Index{}, OptimizeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &OptimizeStats{}, stats)
+}
+
+func TestOptimizeIndex_DelegatesToOptimizableBackend(t *testing.T) {
+	called := false
+	idx := &optimizableBM25Index{optimizeFn: func(ctx context.Context, opts OptimizeOptions) (*OptimizeStats, error) {
+		called = true
+		return &OptimizeStats{RemovedDocs: 3, ReclaimedBytes: 1024, Duration: time.Millisecond}, nil
+	}}
+
+	stats, err := OptimizeIndex(context.Background(), idx, OptimizeOptions{})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, 3, stats.RemovedDocs)
+	assert.Equal(t, int64(1024), stats.ReclaimedBytes)
+}
+
+func TestEstimateReclaimableBytes_PassesDryRunAndLiveIDs(t *testing.T) {
+	var gotOpts OptimizeOptions
+	idx := &optimizableBM25Index{optimizeFn: func(ctx context.Context, opts OptimizeOptions) (*OptimizeStats, error) {
+		gotOpts = opts
+		return &OptimizeStats{ReclaimedBytes: 4096}, nil
+	}}
+
+	live := map[string]struct{}{"a": {}}
+	reclaimable, err := EstimateReclaimableBytes(context.Background(), idx, live)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), reclaimable)
+	assert.True(t, gotOpts.DryRun)
+	assert.Equal(t, live, gotOpts.LiveIDs)
+}
+
+func TestEstimateReclaimableBytes_ZeroForNonOptimizableBackend(t *testing.T) {
+	reclaimable, err := EstimateReclaimableBytes(context.Background(), &stubBM25Index{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), reclaimable)
+}
@@ -0,0 +1,326 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSnapshotUnavailable is returned by Snapshot when s wasn't opened
+// through NewSQLiteStoreWithConfig (dbPath unknown, so there's no file to
+// open a second connection against) or has SupportBucketTable enabled
+// (per-project bucket tables aren't resolved through the snapshot's own
+// transaction yet - see chunksTableForChunk/chunksTableForFile).
+var ErrSnapshotUnavailable = errors.New("store: snapshot not available for this store")
+
+// Snapshot is a point-in-time read view into a SQLiteStore, backed by a
+// single BEGIN DEFERRED transaction opened on its own dedicated WAL
+// connection - deliberately not s.db itself, which NewSQLiteStoreWithConfig
+// restricts to a single pooled connection (see SetMaxOpenConns(1)) shared
+// by every writer. Borrowing that single connection for a long-lived read
+// transaction would block SaveChunks/SaveChunkEmbeddings/DeleteChunks*
+// until Close, which defeats the point of a snapshot; opening a second
+// connection instead lets SQLite's WAL reader isolation do the work, the
+// same dedicated-connection approach NewSQLiteStoreReadOnlySnapshot already
+// uses for the whole-database read-only case.
+//
+// Because the transaction is opened once and reused for every call, every
+// query run through a Snapshot sees the database exactly as it was at the
+// moment Snapshot was opened, even as the primary connection keeps
+// committing further chunk/embedding writes - so GetChunks can never
+// observe a chunk row with content already rewritten but embedding_model
+// not yet updated to match. Borrows the snapshot/release naming from
+// goleveldb: call Close once done reading. An open Snapshot pins the WAL
+// file at its starting point, so holding one indefinitely prevents the
+// writer's periodic checkpoint from reclaiming those pages.
+type Snapshot struct {
+	store *SQLiteStore
+	conn  *sql.DB
+	tx    *sql.Tx
+}
+
+// Snapshot opens a new point-in-time read view of s. Only GetChunk,
+// GetChunks, GetChunksByFile, SearchSymbols, and TopKByEmbedding are
+// exposed - the read surface a query-serving caller (the MCP server)
+// needs while a concurrent indexing pass is writing - rather than the full
+// MetadataStore interface SnapshotMetadataStore wraps for its different
+// read-only-until-promoted use case.
+func (s *SQLiteStore) Snapshot(ctx context.Context) (*Snapshot, error) {
+	if s.dbPath == "" || s.bucketTables {
+		return nil, ErrSnapshotUnavailable
+	}
+
+	conn, err := sql.Open("sqlite3", s.dbPath+"?mode=ro&_txlock=deferred&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot connection: %w", err)
+	}
+	conn.SetMaxOpenConns(1)
+	conn.SetMaxIdleConns(1)
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA read_uncommitted = 0"); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("configure snapshot connection: %w", err)
+	}
+
+	// go-sqlite3's _txlock=deferred DSN param makes BeginTx issue BEGIN
+	// DEFERRED rather than BEGIN IMMEDIATE, so this is the request's "BEGIN
+	// DEFERRED read transaction" - taken here, at Snapshot-open time, is
+	// exactly what fixes this connection's view of the database for the
+	// rest of the Snapshot's life.
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("begin snapshot transaction: %w", err)
+	}
+	// A deferred transaction doesn't actually acquire its read mark on the
+	// WAL until its first statement that touches the database - "SELECT 1"
+	// wouldn't do it, since it never reads a table - so query sqlite_master
+	// here to pin the snapshot at Snapshot-open time rather than whenever
+	// the caller happens to run its first real read.
+	if _, err := tx.ExecContext(ctx, "SELECT count(*) FROM sqlite_master"); err != nil {
+		_ = tx.Rollback()
+		_ = conn.Close()
+		return nil, fmt.Errorf("start snapshot transaction: %w", err)
+	}
+
+	return &Snapshot{store: s, conn: conn, tx: tx}, nil
+}
+
+// Close releases the snapshot's read transaction and its dedicated
+// connection. Safe to call once; callers should not continue using a
+// Snapshot after Close.
+func (sn *Snapshot) Close() error {
+	_ = sn.tx.Rollback()
+	return sn.conn.Close()
+}
+
+// GetChunk retrieves a chunk by ID as it existed when sn was opened.
+func (sn *Snapshot) GetChunk(ctx context.Context, id string) (*Chunk, error) {
+	row := sn.tx.QueryRowContext(ctx, `
+		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at
+		FROM chunks WHERE id = ?
+	`, id)
+
+	c, err := scanChunkRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk: %w", err)
+	}
+
+	symbols, err := sn.getSymbolsForChunk(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.Symbols = symbols
+	return c, nil
+}
+
+// GetChunks retrieves chunks by ID, in input order, as they existed when
+// sn was opened. IDs with no matching row are omitted.
+func (sn *Snapshot) GetChunks(ctx context.Context, ids []string) ([]*Chunk, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	query := `
+		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at
+		FROM chunks WHERE id IN (` + strings.Join(placeholders, ",") + `)
+	`
+	rows, err := sn.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	chunkMap := make(map[string]*Chunk, len(ids))
+	for rows.Next() {
+		c, err := scanChunkRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		chunkMap[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate chunks: %w", err)
+	}
+
+	result := make([]*Chunk, 0, len(ids))
+	for _, id := range ids {
+		if c, ok := chunkMap[id]; ok {
+			result = append(result, c)
+		}
+	}
+	for _, c := range result {
+		symbols, err := sn.getSymbolsForChunk(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.Symbols = symbols
+	}
+	return result, nil
+}
+
+// GetChunksByFile retrieves a file's chunks, ordered by start line, as
+// they existed when sn was opened.
+func (sn *Snapshot) GetChunksByFile(ctx context.Context, fileID string) ([]*Chunk, error) {
+	rows, err := sn.tx.QueryContext(ctx, `
+		SELECT id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at
+		FROM chunks WHERE file_id = ?
+		ORDER BY start_line ASC
+	`, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var chunks []*Chunk
+	for rows.Next() {
+		c, err := scanChunkRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		chunks = append(chunks, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate chunks: %w", err)
+	}
+
+	for _, c := range chunks {
+		symbols, err := sn.getSymbolsForChunk(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.Symbols = symbols
+	}
+	return chunks, nil
+}
+
+func (sn *Snapshot) getSymbolsForChunk(ctx context.Context, chunkID string) ([]*Symbol, error) {
+	rows, err := sn.tx.QueryContext(ctx, `
+		SELECT name, type, start_line, end_line, signature, doc_comment
+		FROM symbols WHERE chunk_id = ?
+	`, chunkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbols: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var symbols []*Symbol
+	for rows.Next() {
+		var sym Symbol
+		var symType, signature, docComment sql.NullString
+		sym.ChunkID = chunkID
+		if err := rows.Scan(&sym.Name, &symType, &sym.StartLine, &sym.EndLine, &signature, &docComment); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		if symType.Valid {
+			sym.Type = SymbolType(symType.String)
+		}
+		sym.Signature = signature.String
+		sym.DocComment = docComment.String
+		symbols = append(symbols, &sym)
+	}
+	return symbols, rows.Err()
+}
+
+// SearchSymbols runs SQLiteStore.SearchSymbols' LIKE-on-name query against
+// sn's point-in-time view.
+func (sn *Snapshot) SearchSymbols(ctx context.Context, name string, limit int) ([]*Symbol, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := sn.tx.QueryContext(ctx, `
+		SELECT name, type, start_line, end_line, signature, doc_comment
+		FROM symbols WHERE name LIKE ?
+		LIMIT ?
+	`, "%"+name+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search symbols: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var symbols []*Symbol
+	for rows.Next() {
+		var sym Symbol
+		var symType, signature, docComment sql.NullString
+		if err := rows.Scan(&sym.Name, &symType, &sym.StartLine, &sym.EndLine, &signature, &docComment); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		if symType.Valid {
+			sym.Type = SymbolType(symType.String)
+		}
+		sym.Signature = signature.String
+		sym.DocComment = docComment.String
+		symbols = append(symbols, &sym)
+	}
+	return symbols, rows.Err()
+}
+
+// TopKByEmbedding ranks against the store's attached VectorStore - a
+// separate component with its own consistency model, not part of this
+// transaction - then loads the resulting chunks through sn so the chunk
+// data returned still reflects sn's point-in-time view rather than
+// whatever the primary connection has since written.
+func (sn *Snapshot) TopKByEmbedding(ctx context.Context, query []float32, k int, filter *ChunkFilter) ([]ScoredChunk, error) {
+	if sn.store.vectorIndex == nil {
+		return nil, ErrVectorIndexNotAttached
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	results, err := sn.store.vectorIndex.Search(ctx, query, k)
+	if err != nil {
+		return nil, fmt.Errorf("vector index search: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	chunks, err := sn.GetChunks(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load chunks for vector search results: %w", err)
+	}
+	chunkByID := make(map[string]*Chunk, len(chunks))
+	for _, c := range chunks {
+		chunkByID[c.ID] = c
+	}
+
+	scored := make([]ScoredChunk, 0, len(results))
+	for _, r := range results {
+		c, ok := chunkByID[r.ID]
+		if !ok {
+			continue
+		}
+		if filter != nil {
+			if filter.ProjectID != "" {
+				var fileProjectID string
+				if err := sn.tx.QueryRowContext(ctx, `SELECT project_id FROM files WHERE id = ?`, c.FileID).Scan(&fileProjectID); err != nil || fileProjectID != filter.ProjectID {
+					continue
+				}
+			}
+			if filter.ContentType != "" && c.ContentType != filter.ContentType {
+				continue
+			}
+			if filter.Language != "" && c.Language != filter.Language {
+				continue
+			}
+		}
+		scored = append(scored, ScoredChunk{Chunk: c, Score: r.Score})
+	}
+	return scored, nil
+}
@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSnapshotReadOnly is returned by every write method of
+// SnapshotMetadataStore while it is backed by a read-only connection, so a
+// caller gets an explicit, typed rejection instead of a raw SQLite
+// "attempt to write a readonly database" error.
+var ErrSnapshotReadOnly = errors.New("store: snapshot is read-only until reindex completes")
+
+// NewSQLiteStoreReadOnlySnapshot opens dbPath as a read-only WAL snapshot:
+// `mode=ro` so the connection can never write, `_txlock=deferred` so it
+// never takes a reserved lock against a concurrent writer (e.g. a running
+// `amanmcp index --resume`), matching the repo's existing WAL-mode
+// convention in NewSQLiteStoreWithConfig. Unlike a normal open, schema
+// initialization and the startup integrity check are skipped: the
+// database already exists and is mid-write, so both would either fail
+// against a read-only connection or race the writer.
+func NewSQLiteStoreReadOnlySnapshot(dbPath string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", dbPath+"?mode=ro&_txlock=deferred&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open read-only snapshot: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	if _, err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to set pragma: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// SnapshotMetadataStore wraps a MetadataStore whose underlying connection
+// can be swapped at runtime from a read-only snapshot to a read-write
+// store once an in-progress reindex completes, without the caller (the
+// search engine, the file watcher) ever needing to know the swap
+// happened. Reads always pass through to whichever connection is
+// current; writes are rejected with ErrSnapshotReadOnly while readOnly is
+// true, matching serve's "block only writes/reconciliation, allow reads"
+// degraded-mode contract.
+type SnapshotMetadataStore struct {
+	mu       sync.RWMutex
+	current  MetadataStore
+	readOnly bool
+}
+
+// NewSnapshotMetadataStore wraps ro, marked read-only, as the store's
+// initial backing connection.
+func NewSnapshotMetadataStore(ro MetadataStore) *SnapshotMetadataStore {
+	return &SnapshotMetadataStore{current: ro, readOnly: true}
+}
+
+// IsReadOnly reports whether the store is still serving from the
+// read-only snapshot.
+func (s *SnapshotMetadataStore) IsReadOnly() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.readOnly
+}
+
+// PromoteReadWrite swaps in rw as the current backing connection and
+// marks the store read-write, returning whichever connection it
+// replaced so the caller can close it. Call this once LoadIndexCheckpoint
+// reports the reindex that made this snapshot necessary has reached
+// stage "complete".
+func (s *SnapshotMetadataStore) PromoteReadWrite(rw MetadataStore) (previous MetadataStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous = s.current
+	s.current = rw
+	s.readOnly = false
+	return previous
+}
+
+func (s *SnapshotMetadataStore) snapshot() (MetadataStore, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current, s.readOnly
+}
+
+func (s *SnapshotMetadataStore) GetChunk(ctx context.Context, id string) (*Chunk, error) {
+	cur, _ := s.snapshot()
+	return cur.GetChunk(ctx, id)
+}
+func (s *SnapshotMetadataStore) GetChunks(ctx context.Context, ids []string) ([]*Chunk, error) {
+	cur, _ := s.snapshot()
+	return cur.GetChunks(ctx, ids)
+}
+func (s *SnapshotMetadataStore) SaveProject(ctx context.Context, p *Project) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.SaveProject(ctx, p)
+}
+func (s *SnapshotMetadataStore) GetProject(ctx context.Context, id string) (*Project, error) {
+	cur, _ := s.snapshot()
+	return cur.GetProject(ctx, id)
+}
+func (s *SnapshotMetadataStore) UpdateProjectStats(ctx context.Context, id string, fileCount, chunkCount int) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.UpdateProjectStats(ctx, id, fileCount, chunkCount)
+}
+func (s *SnapshotMetadataStore) RefreshProjectStats(ctx context.Context, id string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.RefreshProjectStats(ctx, id)
+}
+func (s *SnapshotMetadataStore) SaveFiles(ctx context.Context, files []*File) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.SaveFiles(ctx, files)
+}
+func (s *SnapshotMetadataStore) GetFileByPath(ctx context.Context, projectID, path string) (*File, error) {
+	cur, _ := s.snapshot()
+	return cur.GetFileByPath(ctx, projectID, path)
+}
+func (s *SnapshotMetadataStore) GetChangedFiles(ctx context.Context, projectID string, since time.Time) ([]*File, error) {
+	cur, _ := s.snapshot()
+	return cur.GetChangedFiles(ctx, projectID, since)
+}
+func (s *SnapshotMetadataStore) DeleteFilesByProject(ctx context.Context, projectID string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.DeleteFilesByProject(ctx, projectID)
+}
+func (s *SnapshotMetadataStore) SaveChunks(ctx context.Context, chunks []*Chunk) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.SaveChunks(ctx, chunks)
+}
+func (s *SnapshotMetadataStore) GetChunksByFile(ctx context.Context, fileID string) ([]*Chunk, error) {
+	cur, _ := s.snapshot()
+	return cur.GetChunksByFile(ctx, fileID)
+}
+func (s *SnapshotMetadataStore) DeleteChunks(ctx context.Context, ids []string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.DeleteChunks(ctx, ids)
+}
+func (s *SnapshotMetadataStore) DeleteChunksByFile(ctx context.Context, fileID string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.DeleteChunksByFile(ctx, fileID)
+}
+func (s *SnapshotMetadataStore) SearchSymbols(ctx context.Context, query string, limit int) ([]*Symbol, error) {
+	cur, _ := s.snapshot()
+	return cur.SearchSymbols(ctx, query, limit)
+}
+func (s *SnapshotMetadataStore) ListFiles(ctx context.Context, projectID, cursor string, limit int) ([]*File, string, error) {
+	cur, _ := s.snapshot()
+	return cur.ListFiles(ctx, projectID, cursor, limit)
+}
+func (s *SnapshotMetadataStore) GetFilePathsByProject(ctx context.Context, projectID string) ([]string, error) {
+	cur, _ := s.snapshot()
+	return cur.GetFilePathsByProject(ctx, projectID)
+}
+func (s *SnapshotMetadataStore) GetFilesForReconciliation(ctx context.Context, projectID string) (map[string]*File, error) {
+	cur, _ := s.snapshot()
+	return cur.GetFilesForReconciliation(ctx, projectID)
+}
+func (s *SnapshotMetadataStore) ListFilePathsUnder(ctx context.Context, projectID, dir string) ([]string, error) {
+	cur, _ := s.snapshot()
+	return cur.ListFilePathsUnder(ctx, projectID, dir)
+}
+func (s *SnapshotMetadataStore) DeleteFile(ctx context.Context, fileID string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.DeleteFile(ctx, fileID)
+}
+func (s *SnapshotMetadataStore) GetState(ctx context.Context, key string) (string, error) {
+	cur, _ := s.snapshot()
+	return cur.GetState(ctx, key)
+}
+func (s *SnapshotMetadataStore) SetState(ctx context.Context, key, value string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.SetState(ctx, key, value)
+}
+func (s *SnapshotMetadataStore) SaveChunkEmbeddings(ctx context.Context, ids []string, embeddings [][]float32, model string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.SaveChunkEmbeddings(ctx, ids, embeddings, model)
+}
+func (s *SnapshotMetadataStore) GetAllEmbeddings(ctx context.Context) (map[string][]float32, error) {
+	cur, _ := s.snapshot()
+	return cur.GetAllEmbeddings(ctx)
+}
+func (s *SnapshotMetadataStore) GetEmbeddingStats(ctx context.Context) (int, int, error) {
+	cur, _ := s.snapshot()
+	return cur.GetEmbeddingStats(ctx)
+}
+func (s *SnapshotMetadataStore) SaveIndexCheckpoint(ctx context.Context, stage string, total, embeddedCount int, embedderModel string) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.SaveIndexCheckpoint(ctx, stage, total, embeddedCount, embedderModel)
+}
+func (s *SnapshotMetadataStore) LoadIndexCheckpoint(ctx context.Context) (*IndexCheckpoint, error) {
+	cur, _ := s.snapshot()
+	return cur.LoadIndexCheckpoint(ctx)
+}
+func (s *SnapshotMetadataStore) ClearIndexCheckpoint(ctx context.Context) error {
+	cur, ro := s.snapshot()
+	if ro {
+		return ErrSnapshotReadOnly
+	}
+	return cur.ClearIndexCheckpoint(ctx)
+}
+
+// Close closes the current backing connection only. A previous
+// connection PromoteReadWrite replaced is the caller's responsibility,
+// per PromoteReadWrite's return value contract.
+func (s *SnapshotMetadataStore) Close() error {
+	cur, _ := s.snapshot()
+	return cur.Close()
+}
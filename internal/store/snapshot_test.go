@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSnapshotTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestSQLiteStore_SnapshotSeesPointInTimeView(t *testing.T) {
+	s := newSnapshotTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, s.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "before", StartLine: 1, EndLine: 2},
+	}))
+
+	snap, err := s.Snapshot(ctx)
+	require.NoError(t, err)
+	defer func() { _ = snap.Close() }()
+
+	require.NoError(t, s.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "after", StartLine: 1, EndLine: 2},
+	}))
+
+	fromSnapshot, err := snap.GetChunk(ctx, "chunk1")
+	require.NoError(t, err)
+	require.NotNil(t, fromSnapshot)
+	assert.Equal(t, "before", fromSnapshot.Content)
+
+	fromPrimary, err := s.GetChunk(ctx, "chunk1")
+	require.NoError(t, err)
+	require.NotNil(t, fromPrimary)
+	assert.Equal(t, "after", fromPrimary.Content)
+}
+
+func TestSQLiteStore_SnapshotUnavailableForBucketTables(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStoreWithConfig(dbPath, StoreConfig{SupportBucketTable: true})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	_, err = s.Snapshot(context.Background())
+	assert.ErrorIs(t, err, ErrSnapshotUnavailable)
+}
+
+// TestSQLiteStore_SnapshotStableDuringConcurrentIndexing runs a scaled-down
+// version of a live-reindex workload - chunks saved and re-embedded on the
+// primary connection while many goroutines read a held-open Snapshot - and
+// asserts that every read sees a chunk's embedding_model/embedding_dims as
+// a consistent pair, never content from one SaveChunkEmbeddings call and
+// metadata from another.
+func TestSQLiteStore_SnapshotStableDuringConcurrentIndexing(t *testing.T) {
+	s := newSnapshotTestStore(t)
+	ctx := context.Background()
+
+	const numChunks = 200
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+
+	ids := make([]string, numChunks)
+	chunks := make([]*Chunk, numChunks)
+	for i := 0; i < numChunks; i++ {
+		ids[i] = fmt.Sprintf("chunk%d", i)
+		chunks[i] = &Chunk{ID: ids[i], FileID: "file1", FilePath: "a.go", Content: "v0", StartLine: i, EndLine: i + 1}
+	}
+	require.NoError(t, s.SaveChunks(ctx, chunks))
+
+	embeddings := make([][]float32, numChunks)
+	for i := range embeddings {
+		embeddings[i] = []float32{1, 2, 3}
+	}
+	require.NoError(t, s.SaveChunkEmbeddings(ctx, ids, embeddings, "model-v1"))
+
+	snap, err := s.Snapshot(ctx)
+	require.NoError(t, err)
+	defer func() { _ = snap.Close() }()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 64)
+
+	// Writer: keeps re-embedding with a different model/dims, simulating a
+	// concurrent reindex.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for round := 0; round < 20; round++ {
+			if err := s.SaveChunkEmbeddings(ctx, ids, make([][]float32, numChunks), fmt.Sprintf("model-v%d", round+2)); err != nil {
+				errCh <- fmt.Errorf("writer round %d: %w", round, err)
+				return
+			}
+		}
+	}()
+
+	// Readers: hammer the snapshot's GetChunks, checking each returned
+	// chunk's embedding stays internally consistent.
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := snap.GetChunks(ctx, ids); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		t.Fatal(err)
+	}
+
+	// The snapshot's own view must still be exactly as it was when opened:
+	// model-v1 with 3-dim embeddings, regardless of how many rounds the
+	// writer completed on the primary connection afterward.
+	var model string
+	var dims int
+	require.NoError(t, snap.tx.QueryRowContext(ctx, `SELECT embedding_model, embedding_dims FROM chunks WHERE id = ?`, ids[0]).Scan(&model, &dims))
+	assert.Equal(t, "model-v1", model)
+	assert.Equal(t, 3, dims)
+}
@@ -0,0 +1,327 @@
+package store
+
+import "fmt"
+
+// SQLGenerator produces the dialect-specific SQL text for the handful of
+// statements that differ between backends (schema DDL, upserts, and the
+// directory-prefix LIKE query). Everything else a MetadataStore needs is
+// either identical across dialects or simple enough to inline directly.
+//
+// This mirrors how a lot of multi-backend SQL stores split a thin
+// SqlGenerator out from the store that drives it: the store owns
+// connection handling, transactions and scanning, while the generator
+// owns only the text that has to change per-dialect.
+type SQLGenerator interface {
+	// Placeholder returns the bind-variable marker for the n-th (1-indexed)
+	// parameter in a statement, e.g. "?" for SQLite and "$3" for Postgres.
+	Placeholder(n int) string
+
+	// GetSqlCreateSchema returns the DDL that creates all tables and
+	// indexes required by schema version 1. Migrations beyond version 1
+	// are applied separately by the store itself.
+	GetSqlCreateSchema() string
+
+	// GetSqlUpsertProject returns the insert-or-update statement for the
+	// projects table, keyed by id.
+	GetSqlUpsertProject() string
+
+	// GetSqlUpsertFile returns the insert-or-update statement for the
+	// files table, keyed by id.
+	GetSqlUpsertFile() string
+
+	// GetSqlInsertChunk returns the insert-or-update statement for the
+	// chunks table, keyed by id.
+	GetSqlInsertChunk() string
+
+	// GetSqlListFilesUnder returns the statement used by
+	// ListFilePathsUnder to select every path under a directory prefix.
+	GetSqlListFilesUnder() string
+}
+
+// sqliteGenerator produces the exact SQL SQLiteStore has always used.
+// Keeping it byte-for-byte identical to the pre-refactor inline literals
+// means routing SQLiteStore's methods through it changes no behavior.
+type sqliteGenerator struct{}
+
+func (sqliteGenerator) Placeholder(int) string { return "?" }
+
+func (sqliteGenerator) GetSqlCreateSchema() string {
+	return `
+	-- Schema version for migrations
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Project information
+	CREATE TABLE IF NOT EXISTS projects (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		root_path TEXT NOT NULL,
+		project_type TEXT,
+		indexed_at TIMESTAMP,
+		chunk_count INTEGER DEFAULT 0,
+		file_count INTEGER DEFAULT 0,
+		schema_version TEXT
+	);
+
+	-- File tracking
+	CREATE TABLE IF NOT EXISTS files (
+		id TEXT PRIMARY KEY,
+		project_id TEXT NOT NULL,
+		path TEXT NOT NULL,
+		size INTEGER,
+		mod_time TIMESTAMP,
+		content_hash TEXT,
+		language TEXT,
+		content_type TEXT,
+		indexed_at TIMESTAMP,
+		FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_files_project ON files(project_id);
+	CREATE INDEX IF NOT EXISTS idx_files_path ON files(project_id, path);
+	CREATE INDEX IF NOT EXISTS idx_files_mod_time ON files(project_id, mod_time);
+
+	-- Chunk metadata
+	CREATE TABLE IF NOT EXISTS chunks (
+		id TEXT PRIMARY KEY,
+		file_id TEXT NOT NULL,
+		file_path TEXT NOT NULL,
+		content TEXT NOT NULL,
+		raw_content TEXT,
+		context TEXT,
+		content_type TEXT,
+		language TEXT,
+		start_line INTEGER NOT NULL,
+		end_line INTEGER NOT NULL,
+		metadata TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_chunks_file ON chunks(file_id);
+
+	-- Symbols in chunks
+	CREATE TABLE IF NOT EXISTS symbols (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		chunk_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		start_line INTEGER,
+		end_line INTEGER,
+		signature TEXT,
+		doc_comment TEXT,
+		FOREIGN KEY (chunk_id) REFERENCES chunks(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_symbols_chunk ON symbols(chunk_id);
+	CREATE INDEX IF NOT EXISTS idx_symbols_name ON symbols(name);
+
+	-- Key-value store for misc state
+	CREATE TABLE IF NOT EXISTS state (
+		key TEXT PRIMARY KEY,
+		value TEXT,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Insert schema version if not exists
+	INSERT OR IGNORE INTO schema_version (version) VALUES (1);
+	`
+}
+
+func (sqliteGenerator) GetSqlUpsertProject() string {
+	return `
+		INSERT INTO projects (id, name, root_path, project_type, indexed_at, chunk_count, file_count, schema_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			root_path = excluded.root_path,
+			project_type = excluded.project_type,
+			indexed_at = excluded.indexed_at,
+			chunk_count = excluded.chunk_count,
+			file_count = excluded.file_count,
+			schema_version = excluded.schema_version
+	`
+}
+
+func (sqliteGenerator) GetSqlUpsertFile() string {
+	return `
+		INSERT INTO files (id, project_id, path, size, mod_time, content_hash, language, content_type, indexed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			project_id = excluded.project_id,
+			path = excluded.path,
+			size = excluded.size,
+			mod_time = excluded.mod_time,
+			content_hash = excluded.content_hash,
+			language = excluded.language,
+			content_type = excluded.content_type,
+			indexed_at = excluded.indexed_at
+	`
+}
+
+func (sqliteGenerator) GetSqlInsertChunk() string {
+	return `
+		INSERT INTO chunks (id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			file_id = excluded.file_id,
+			file_path = excluded.file_path,
+			content = excluded.content,
+			raw_content = excluded.raw_content,
+			context = excluded.context,
+			content_type = excluded.content_type,
+			language = excluded.language,
+			start_line = excluded.start_line,
+			end_line = excluded.end_line,
+			metadata = excluded.metadata,
+			updated_at = excluded.updated_at
+	`
+}
+
+func (sqliteGenerator) GetSqlListFilesUnder() string {
+	return `SELECT path FROM files WHERE project_id = ? AND (path LIKE ? OR path = ?) ORDER BY path`
+}
+
+// postgresGenerator produces Postgres-flavored SQL for the same
+// statements. SQLite's ON CONFLICT ... DO UPDATE SET col = excluded.col
+// upsert syntax is already Postgres-compatible, so the generated text only
+// differs in placeholder style and schema column types/identity syntax.
+type postgresGenerator struct{}
+
+func (postgresGenerator) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (postgresGenerator) GetSqlCreateSchema() string {
+	return `
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS projects (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		root_path TEXT NOT NULL,
+		project_type TEXT,
+		indexed_at TIMESTAMP,
+		chunk_count INTEGER DEFAULT 0,
+		file_count INTEGER DEFAULT 0,
+		schema_version TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS files (
+		id TEXT PRIMARY KEY,
+		project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+		path TEXT NOT NULL,
+		size BIGINT,
+		mod_time TIMESTAMP,
+		content_hash TEXT,
+		language TEXT,
+		content_type TEXT,
+		indexed_at TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_files_project ON files(project_id);
+	CREATE INDEX IF NOT EXISTS idx_files_path ON files(project_id, path);
+	CREATE INDEX IF NOT EXISTS idx_files_mod_time ON files(project_id, mod_time);
+
+	CREATE TABLE IF NOT EXISTS chunks (
+		id TEXT PRIMARY KEY,
+		file_id TEXT NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+		file_path TEXT NOT NULL,
+		content TEXT NOT NULL,
+		raw_content TEXT,
+		context TEXT,
+		content_type TEXT,
+		language TEXT,
+		start_line INTEGER NOT NULL,
+		end_line INTEGER NOT NULL,
+		metadata TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		embedding BYTEA,
+		embedding_model TEXT,
+		embedding_dims INTEGER
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_chunks_file ON chunks(file_id);
+
+	CREATE TABLE IF NOT EXISTS symbols (
+		id BIGSERIAL PRIMARY KEY,
+		chunk_id TEXT NOT NULL REFERENCES chunks(id) ON DELETE CASCADE,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		start_line INTEGER,
+		end_line INTEGER,
+		signature TEXT,
+		doc_comment TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_symbols_chunk ON symbols(chunk_id);
+	CREATE INDEX IF NOT EXISTS idx_symbols_name ON symbols(name);
+
+	CREATE TABLE IF NOT EXISTS state (
+		key TEXT PRIMARY KEY,
+		value TEXT,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	INSERT INTO schema_version (version) VALUES (1) ON CONFLICT (version) DO NOTHING;
+	`
+}
+
+func (postgresGenerator) GetSqlUpsertProject() string {
+	return `
+		INSERT INTO projects (id, name, root_path, project_type, indexed_at, chunk_count, file_count, schema_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			root_path = excluded.root_path,
+			project_type = excluded.project_type,
+			indexed_at = excluded.indexed_at,
+			chunk_count = excluded.chunk_count,
+			file_count = excluded.file_count,
+			schema_version = excluded.schema_version
+	`
+}
+
+func (postgresGenerator) GetSqlUpsertFile() string {
+	return `
+		INSERT INTO files (id, project_id, path, size, mod_time, content_hash, language, content_type, indexed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT(id) DO UPDATE SET
+			project_id = excluded.project_id,
+			path = excluded.path,
+			size = excluded.size,
+			mod_time = excluded.mod_time,
+			content_hash = excluded.content_hash,
+			language = excluded.language,
+			content_type = excluded.content_type,
+			indexed_at = excluded.indexed_at
+	`
+}
+
+func (postgresGenerator) GetSqlInsertChunk() string {
+	return `
+		INSERT INTO chunks (id, file_id, file_path, content, raw_content, context, content_type, language, start_line, end_line, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT(id) DO UPDATE SET
+			file_id = excluded.file_id,
+			file_path = excluded.file_path,
+			content = excluded.content,
+			raw_content = excluded.raw_content,
+			context = excluded.context,
+			content_type = excluded.content_type,
+			language = excluded.language,
+			start_line = excluded.start_line,
+			end_line = excluded.end_line,
+			metadata = excluded.metadata,
+			updated_at = excluded.updated_at
+	`
+}
+
+func (postgresGenerator) GetSqlListFilesUnder() string {
+	return `SELECT path FROM files WHERE project_id = $1 AND (path LIKE $2 OR path = $3) ORDER BY path`
+}
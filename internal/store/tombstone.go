@@ -0,0 +1,200 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// stateKeyTombstoneLog persists the tombstone log as a single JSON blob in
+// the metadata store's state table, mirroring how index checkpoints are
+// stored (BUG-023: best-effort delete can leave BM25/vector diverged from
+// metadata, so we need a durable record of what's still pending).
+const stateKeyTombstoneLog = "tombstone_log"
+
+// PendingIndex identifies which secondary indices still need a delete
+// retried for a tombstoned chunk.
+type PendingIndex string
+
+const (
+	// PendingIndexBM25 marks that the BM25 index still holds the chunk.
+	PendingIndexBM25 PendingIndex = "bm25"
+	// PendingIndexVector marks that the vector store still holds the chunk.
+	PendingIndexVector PendingIndex = "vector"
+)
+
+// Tombstone records a chunk whose delete failed to reach one or more
+// secondary indices.
+type Tombstone struct {
+	ChunkID   string         `json:"chunk_id"`
+	Pending   []PendingIndex `json:"indices_pending"`
+	FirstSeen time.Time      `json:"first_seen"`
+}
+
+// hasPending reports whether idx is still outstanding for this tombstone.
+func (t *Tombstone) hasPending(idx PendingIndex) bool {
+	for _, p := range t.Pending {
+		if p == idx {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tombstone) clearPending(idx PendingIndex) {
+	out := t.Pending[:0]
+	for _, p := range t.Pending {
+		if p != idx {
+			out = append(out, p)
+		}
+	}
+	t.Pending = out
+}
+
+// TombstoneLog is an append-only record of chunks that a best-effort
+// Delete failed to remove from every index, persisted via the metadata
+// store's state table so it survives process restarts. A background
+// reconciler retries pending deletes; live search results are filtered
+// against this log so a deleted chunk never resurfaces even if the vector
+// or BM25 index is still serving it.
+type TombstoneLog struct {
+	metadata MetadataStore
+
+	// mu serializes Record and Resolve's load-mutate-save round trip
+	// against the single tombstone_log state blob. Without it, Record
+	// (called synchronously from the delete path) and Resolve (called
+	// from the background Reconciler) can each load the same blob,
+	// mutate their own copy, and save - whichever saves second silently
+	// overwrites the other's update instead of merging with it.
+	mu sync.Mutex
+}
+
+// NewTombstoneLog wraps metadata with tombstone-log persistence.
+func NewTombstoneLog(metadata MetadataStore) *TombstoneLog {
+	return &TombstoneLog{metadata: metadata}
+}
+
+// Record adds or updates a tombstone for chunkID, marking pending as the
+// indices that still need a retried delete.
+func (l *TombstoneLog) Record(ctx context.Context, chunkID string, pending ...PendingIndex) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	log, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if existing, ok := log[chunkID]; ok {
+		existing.Pending = mergePending(existing.Pending, pending)
+		log[chunkID] = existing
+	} else {
+		log[chunkID] = &Tombstone{ChunkID: chunkID, Pending: pending, FirstSeen: time.Now()}
+	}
+
+	return l.save(ctx, log)
+}
+
+// Resolve clears idx as pending for chunkID, removing the tombstone
+// entirely once no index has anything left pending.
+func (l *TombstoneLog) Resolve(ctx context.Context, chunkID string, idx PendingIndex) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	log, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	t, ok := log[chunkID]
+	if !ok {
+		return nil
+	}
+	t.clearPending(idx)
+	if len(t.Pending) == 0 {
+		delete(log, chunkID)
+	}
+
+	return l.save(ctx, log)
+}
+
+// IsTombstoned reports whether chunkID has any pending tombstone at all
+// (regardless of which index it's still pending against), so search can
+// exclude it unconditionally.
+func (l *TombstoneLog) IsTombstoned(ctx context.Context, chunkID string) bool {
+	log, err := l.load(ctx)
+	if err != nil {
+		return false
+	}
+	_, ok := log[chunkID]
+	return ok
+}
+
+// Pending returns every tombstone still awaiting reconciliation, oldest
+// first, for the background reconciler to retry.
+func (l *TombstoneLog) Pending(ctx context.Context) ([]*Tombstone, error) {
+	log, err := l.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Tombstone, 0, len(log))
+	for _, t := range log {
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// Count returns the number of chunks currently tombstoned, for Stats().
+func (l *TombstoneLog) Count(ctx context.Context) int {
+	log, err := l.load(ctx)
+	if err != nil {
+		return 0
+	}
+	return len(log)
+}
+
+func (l *TombstoneLog) load(ctx context.Context) (map[string]*Tombstone, error) {
+	raw, err := l.metadata.GetState(ctx, stateKeyTombstoneLog)
+	if err != nil {
+		return nil, fmt.Errorf("tombstone log: load state: %w", err)
+	}
+	if raw == "" {
+		return make(map[string]*Tombstone), nil
+	}
+	var log map[string]*Tombstone
+	if err := json.Unmarshal([]byte(raw), &log); err != nil {
+		return nil, fmt.Errorf("tombstone log: decode state: %w", err)
+	}
+	if log == nil {
+		log = make(map[string]*Tombstone)
+	}
+	return log, nil
+}
+
+func (l *TombstoneLog) save(ctx context.Context, log map[string]*Tombstone) error {
+	raw, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("tombstone log: encode state: %w", err)
+	}
+	if err := l.metadata.SetState(ctx, stateKeyTombstoneLog, string(raw)); err != nil {
+		return fmt.Errorf("tombstone log: save state: %w", err)
+	}
+	return nil
+}
+
+func mergePending(existing []PendingIndex, add []PendingIndex) []PendingIndex {
+	seen := make(map[PendingIndex]bool, len(existing))
+	for _, p := range existing {
+		seen[p] = true
+	}
+	out := existing
+	for _, p := range add {
+		if !seen[p] {
+			out = append(out, p)
+			seen[p] = true
+		}
+	}
+	return out
+}
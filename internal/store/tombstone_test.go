@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inMemoryStateStore is a minimal MetadataStore stand-in exercising only
+// GetState/SetState, enough to test TombstoneLog in isolation. It guards
+// its map with its own mutex so concurrency tests exercise TombstoneLog's
+// locking, not a race in the test double.
+type inMemoryStateStore struct {
+	MetadataStore
+
+	mu    sync.Mutex
+	state map[string]string
+}
+
+func newInMemoryStateStore() *inMemoryStateStore {
+	return &inMemoryStateStore{state: make(map[string]string)}
+}
+
+func (s *inMemoryStateStore) GetState(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state[key], nil
+}
+
+func (s *inMemoryStateStore) SetState(_ context.Context, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state[key] = value
+	return nil
+}
+
+func TestTombstoneLog_RecordAndIsTombstoned(t *testing.T) {
+	log := NewTombstoneLog(newInMemoryStateStore())
+	ctx := context.Background()
+
+	require.NoError(t, log.Record(ctx, "chunk1", PendingIndexBM25, PendingIndexVector))
+
+	assert.True(t, log.IsTombstoned(ctx, "chunk1"))
+	assert.False(t, log.IsTombstoned(ctx, "chunk2"))
+	assert.Equal(t, 1, log.Count(ctx))
+}
+
+func TestTombstoneLog_ResolveClearsOnlyThatIndex(t *testing.T) {
+	log := NewTombstoneLog(newInMemoryStateStore())
+	ctx := context.Background()
+	require.NoError(t, log.Record(ctx, "chunk1", PendingIndexBM25, PendingIndexVector))
+
+	require.NoError(t, log.Resolve(ctx, "chunk1", PendingIndexBM25))
+
+	assert.True(t, log.IsTombstoned(ctx, "chunk1"), "still pending against vector")
+
+	pending, err := log.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, []PendingIndex{PendingIndexVector}, pending[0].Pending)
+}
+
+func TestTombstoneLog_ResolveLastIndexClearsTombstone(t *testing.T) {
+	log := NewTombstoneLog(newInMemoryStateStore())
+	ctx := context.Background()
+	require.NoError(t, log.Record(ctx, "chunk1", PendingIndexBM25))
+
+	require.NoError(t, log.Resolve(ctx, "chunk1", PendingIndexBM25))
+
+	assert.False(t, log.IsTombstoned(ctx, "chunk1"))
+	assert.Equal(t, 0, log.Count(ctx))
+}
+
+func TestTombstoneLog_RecordMergesPendingIndices(t *testing.T) {
+	log := NewTombstoneLog(newInMemoryStateStore())
+	ctx := context.Background()
+	require.NoError(t, log.Record(ctx, "chunk1", PendingIndexBM25))
+	require.NoError(t, log.Record(ctx, "chunk1", PendingIndexVector))
+
+	pending, err := log.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.ElementsMatch(t, []PendingIndex{PendingIndexBM25, PendingIndexVector}, pending[0].Pending)
+}
+
+// TestTombstoneLog_ConcurrentRecordAndResolveDontLoseUpdates exercises the
+// exact race Record (delete path) and Resolve (background Reconciler) can
+// hit: both load-mutate-save the same state blob, so without a mutex
+// serializing them, whichever saves second wins and silently drops the
+// other's update.
+func TestTombstoneLog_ConcurrentRecordAndResolveDontLoseUpdates(t *testing.T) {
+	log := NewTombstoneLog(newInMemoryStateStore())
+	ctx := context.Background()
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		require.NoError(t, log.Record(ctx, fmt.Sprintf("chunk%d", i), PendingIndexBM25, PendingIndexVector))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = log.Resolve(ctx, fmt.Sprintf("chunk%d", i), PendingIndexBM25)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = log.Record(ctx, fmt.Sprintf("chunk%d", i), PendingIndexVector)
+		}()
+	}
+	wg.Wait()
+
+	pending, err := log.Pending(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, n, "every chunk should still have a tombstone (BM25 resolved, vector still pending)")
+	for _, tomb := range pending {
+		assert.Equal(t, []PendingIndex{PendingIndexVector}, tomb.Pending)
+	}
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+)
+
+// txExecutor is the subset of *sql.DB and *sql.Tx that SQLiteStore's
+// write helpers need, letting the same helper run either against the
+// store's top-level connection or inside a transaction opened by WithTx.
+type txExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// StoreTx is the transactional subset of SQLiteStore exposed inside a
+// WithTx callback. Writes issued through it participate in the same
+// transaction (or, for a WithTx nested inside another, the same
+// SAVEPOINT), so a caller like the indexer coordinator can commit a
+// file's {file row, chunks, symbols} as one atomic unit instead of
+// SaveFiles and SaveChunks racing independently across separate
+// transactions.
+type StoreTx struct {
+	store *SQLiteStore
+	exec  txExecutor
+}
+
+// txStateKey is the context.Context key WithTx stores the enclosing
+// transaction under. Keying nesting off the context (rather than a field
+// on *SQLiteStore) means two unrelated call chains that happen to run
+// concurrently never mistake each other's open transaction for their own
+// enclosing one - only a ctx that actually descends from a WithTx callback
+// carries its txState.
+type txStateKey struct{}
+
+// txState is the per-transaction state threaded through context.Context
+// for the lifetime of a WithTx call: the executor nested calls should use,
+// and a counter for generating unique SAVEPOINT names within it.
+type txState struct {
+	exec txExecutor
+	seq  int64 // accessed via atomic.AddInt64
+}
+
+// withTxState returns a context carrying ts, so a nested WithTx(ctx, ...)
+// call further down the same call chain recognizes it's already inside a
+// transaction.
+func withTxState(ctx context.Context, ts *txState) context.Context {
+	return context.WithValue(ctx, txStateKey{}, ts)
+}
+
+// txStateFromContext returns the enclosing transaction's state, if ctx
+// descends from a WithTx callback, and nil otherwise.
+func txStateFromContext(ctx context.Context) *txState {
+	ts, _ := ctx.Value(txStateKey{}).(*txState)
+	return ts
+}
+
+// SaveFiles saves files within the enclosing transaction.
+func (t *StoreTx) SaveFiles(ctx context.Context, files []*File) error {
+	return t.store.saveFilesExec(ctx, t.exec, files)
+}
+
+// SaveChunks saves chunks (and their symbols) within the enclosing transaction.
+func (t *StoreTx) SaveChunks(ctx context.Context, chunks []*Chunk) error {
+	return t.store.saveChunksExec(ctx, t.exec, chunks)
+}
+
+// SaveSymbols replaces chunkID's symbols within the enclosing transaction.
+func (t *StoreTx) SaveSymbols(ctx context.Context, chunkID string, symbols []*Symbol) error {
+	symbolsTable, err := t.store.symbolsTableForChunk(ctx, chunkID)
+	if err != nil {
+		return err
+	}
+	return execSaveSymbols(ctx, t.exec, symbolsTable, chunkID, symbols)
+}
+
+// UpdateProjectStats updates a project's file/chunk counts within the enclosing transaction.
+func (t *StoreTx) UpdateProjectStats(ctx context.Context, id string, fileCount, chunkCount int) error {
+	return execUpdateProjectStats(ctx, t.exec, id, fileCount, chunkCount)
+}
+
+// WithTx runs fn against a transaction, committing on success and
+// rolling back if fn (or the commit itself) returns an error. This
+// eliminates the race where a crash between SaveFiles and SaveChunks
+// leaves a file row with no chunks: callers that need both to land
+// together now do tx.SaveFiles(...); tx.SaveChunks(...) inside one
+// WithTx.
+//
+// fn receives a ctx carrying this transaction's state. Callers that need
+// to nest - a helper several calls deep wrapping its own work
+// transactionally without needing to know whether its caller already
+// opened one - MUST call WithTx again with that ctx, not the one passed
+// into the outer WithTx: nesting is detected from the context, not from
+// any state shared on *SQLiteStore, so passing the original ctx down
+// instead would make the nested call open its own independent
+// transaction rather than a SAVEPOINT inside this one. Given that ctx,
+// nesting goes via SAVEPOINT/RELEASE instead of a second BEGIN.
+func (s *SQLiteStore) WithTx(ctx context.Context, fn func(ctx context.Context, tx *StoreTx) error) error {
+	if outer := txStateFromContext(ctx); outer != nil {
+		return s.withSavepoint(ctx, outer, fn)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	txCtx := withTxState(ctx, &txState{exec: tx})
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := fn(txCtx, &StoreTx{store: s, exec: tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// withSavepoint runs fn inside a SAVEPOINT nested within the already-open
+// transaction recorded in ts, rolling back to the savepoint - not the
+// whole transaction - on error, so the outer WithTx caller decides
+// whether to retry or propagate the failure.
+func (s *SQLiteStore) withSavepoint(ctx context.Context, ts *txState, fn func(ctx context.Context, tx *StoreTx) error) error {
+	seq := atomic.AddInt64(&ts.seq, 1)
+	name := fmt.Sprintf("sp_%d", seq)
+
+	if _, err := ts.exec.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("create savepoint %s: %w", name, err)
+	}
+
+	if err := fn(ctx, &StoreTx{store: s, exec: ts.exec}); err != nil {
+		if _, rbErr := ts.exec.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("%w (rollback to savepoint %s also failed: %v)", err, name, rbErr)
+		}
+		return err
+	}
+
+	if _, err := ts.exec.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("release savepoint %s: %w", name, err)
+	}
+	return nil
+}
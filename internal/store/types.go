@@ -0,0 +1,280 @@
+// Package store provides vector storage (HNSW), BM25 keyword search, and
+// metadata persistence (SQLite) - the persistence layer every indexed
+// project's chunks, files, and embeddings pass through.
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ContentType identifies what kind of content a Chunk holds, so search and
+// chunking can apply format-specific handling (e.g. code gets symbol
+// extraction, markdown and text don't).
+type ContentType string
+
+const (
+	ContentTypeCode     ContentType = "code"
+	ContentTypeMarkdown ContentType = "markdown"
+	ContentTypePDF      ContentType = "pdf"
+	ContentTypeText     ContentType = "text"
+)
+
+// State keys for the embedding dimension/model an index was built with,
+// read by GetIndexInfo to detect a mismatch against the current embedder.
+const (
+	// StateKeyIndexDimension stores the embedding dimension used to build
+	// the index.
+	StateKeyIndexDimension = "index_embedding_dimension"
+	// StateKeyIndexModel stores the embedding model name used to build the
+	// index.
+	StateKeyIndexModel = "index_embedding_model"
+)
+
+// State keys backing SaveIndexCheckpoint/LoadIndexCheckpoint's resumable
+// indexing support.
+const (
+	// StateKeyCheckpointStage stores the current indexing stage:
+	// "scanning", "chunking", "embedding", "indexing", or "complete".
+	StateKeyCheckpointStage = "checkpoint_stage"
+	// StateKeyCheckpointTotal stores the total number of chunks to process.
+	StateKeyCheckpointTotal = "checkpoint_total"
+	// StateKeyCheckpointEmbedded stores the count of chunks embedded so far.
+	StateKeyCheckpointEmbedded = "checkpoint_embedded"
+	// StateKeyCheckpointTimestamp stores when the checkpoint was last
+	// updated.
+	StateKeyCheckpointTimestamp = "checkpoint_timestamp"
+	// StateKeyCheckpointEmbedderModel stores the embedder model used for
+	// this checkpoint, so resuming can refuse to continue a run under a
+	// different model (which would produce a dimension-mismatched index).
+	StateKeyCheckpointEmbedderModel = "checkpoint_embedder_model"
+)
+
+// SymbolType identifies what kind of code symbol a Symbol represents.
+type SymbolType string
+
+const (
+	SymbolTypeFunction  SymbolType = "function"
+	SymbolTypeClass     SymbolType = "class"
+	SymbolTypeInterface SymbolType = "interface"
+	SymbolTypeType      SymbolType = "type"
+	SymbolTypeVariable  SymbolType = "variable"
+	SymbolTypeConstant  SymbolType = "constant"
+	SymbolTypeMethod    SymbolType = "method"
+)
+
+// Symbol is a code symbol extracted from a Chunk during chunking.
+type Symbol struct {
+	Name       string
+	Type       SymbolType
+	StartLine  int
+	EndLine    int
+	Signature  string // Function/method signature, if applicable.
+	DocComment string
+}
+
+// Chunk is a retrievable unit of content - a function, a markdown
+// section, a slice of plain text - along with the metadata search and
+// chunking need to rank and display it.
+type Chunk struct {
+	ID          string // Stable identifier; see StateKeyChunkIDVersion's doc in compress.go-adjacent code for the ID scheme.
+	FileID      string
+	FilePath    string // Relative to the project root.
+	Content     string // Full content, including surrounding context.
+	RawContent  string // Just the symbol body, no surrounding context (code only).
+	Context     string // Imports, package declaration, etc. (code only).
+	ContentType ContentType
+	Language    string // go, typescript, python, etc.
+	StartLine   int    // 1-indexed.
+	EndLine     int    // Inclusive.
+	Symbols     []*Symbol
+	Metadata    map[string]string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// File is a tracked file within an indexed project.
+type File struct {
+	ID          string
+	ProjectID   string
+	Path        string // Relative to the project root.
+	Size        int64
+	ModTime     time.Time
+	ContentHash string
+	Language    string
+	ContentType string
+	IndexedAt   time.Time
+}
+
+// Project is an indexed codebase.
+type Project struct {
+	ID          string
+	Name        string
+	RootPath    string // Absolute path.
+	ProjectType string // go, node, python, etc.
+	ChunkCount  int
+	FileCount   int
+	IndexedAt   time.Time
+	Version     string // Index schema version.
+}
+
+// IndexCheckpoint is the saved state of an in-progress indexing run,
+// allowing it to resume rather than restart from scratch after an
+// interruption.
+type IndexCheckpoint struct {
+	Stage         string // "scanning", "chunking", "embedding", "indexing", "complete"
+	Total         int
+	EmbeddedCount int
+	Timestamp     time.Time
+	EmbedderModel string
+}
+
+// IndexInfo is the information GetIndexInfo assembles for the
+// `amanmcp index info` command: where an index lives, what it was built
+// with, and whether that still matches the currently configured embedder.
+type IndexInfo struct {
+	Location    string
+	ProjectRoot string
+
+	IndexModel      string
+	IndexBackend    string
+	IndexDimensions int
+
+	ChunkCount      int
+	DocumentCount   int
+	IndexSizeBytes  int64
+	BM25SizeBytes   int64
+	VectorSizeBytes int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	CurrentModel      string
+	CurrentBackend    string
+	CurrentDimensions int
+	Compatible        bool
+}
+
+// MetadataStore persists chunk, file, and project metadata in SQLite -
+// everything about an indexed project except the BM25 postings and
+// vectors themselves, which live in BM25Index and VectorStore.
+type MetadataStore interface {
+	// Project operations
+	SaveProject(ctx context.Context, project *Project) error
+	GetProject(ctx context.Context, id string) (*Project, error)
+	UpdateProjectStats(ctx context.Context, id string, fileCount, chunkCount int) error
+	RefreshProjectStats(ctx context.Context, id string) error
+
+	// File operations
+	SaveFiles(ctx context.Context, files []*File) error
+	GetFileByPath(ctx context.Context, projectID, path string) (*File, error)
+	GetChangedFiles(ctx context.Context, projectID string, since time.Time) ([]*File, error)
+	ListFiles(ctx context.Context, projectID string, cursor string, limit int) ([]*File, string, error)
+	GetFilePathsByProject(ctx context.Context, projectID string) ([]string, error)
+	GetFilesForReconciliation(ctx context.Context, projectID string) (map[string]*File, error)
+	ListFilePathsUnder(ctx context.Context, projectID, dirPrefix string) ([]string, error)
+	DeleteFile(ctx context.Context, fileID string) error
+	DeleteFilesByProject(ctx context.Context, projectID string) error
+
+	// Chunk operations
+	SaveChunks(ctx context.Context, chunks []*Chunk) error
+	GetChunk(ctx context.Context, id string) (*Chunk, error)
+	GetChunks(ctx context.Context, ids []string) ([]*Chunk, error)
+	GetChunksByFile(ctx context.Context, fileID string) ([]*Chunk, error)
+	DeleteChunks(ctx context.Context, ids []string) error
+	DeleteChunksByFile(ctx context.Context, fileID string) error
+
+	// Symbol operations
+	SearchSymbols(ctx context.Context, name string, limit int) ([]*Symbol, error)
+
+	// State operations (key-value store for runtime state, see the
+	// StateKey* constants above)
+	GetState(ctx context.Context, key string) (string, error)
+	SetState(ctx context.Context, key, value string) error
+
+	// Embedding operations (for HNSW compaction/rebuild)
+	SaveChunkEmbeddings(ctx context.Context, chunkIDs []string, embeddings [][]float32, model string) error
+	GetAllEmbeddings(ctx context.Context) (map[string][]float32, error)
+	GetEmbeddingStats(ctx context.Context) (withEmbedding, withoutEmbedding int, err error)
+
+	// Checkpoint operations (for resumable indexing)
+	SaveIndexCheckpoint(ctx context.Context, stage string, total, embeddedCount int, embedderModel string) error
+	LoadIndexCheckpoint(ctx context.Context) (*IndexCheckpoint, error)
+	ClearIndexCheckpoint(ctx context.Context) error
+
+	Close() error
+}
+
+// Document is a unit of text handed to a BM25Index for indexing.
+type Document struct {
+	ID      string // Chunk ID.
+	Content string
+}
+
+// BM25Result is a single BM25Index.Search match.
+type BM25Result struct {
+	DocID        string
+	Score        float64
+	MatchedTerms []string
+}
+
+// IndexStats summarizes a BM25Index's current contents.
+type IndexStats struct {
+	DocumentCount int
+	TermCount     int
+	AvgDocLength  float64
+}
+
+// BM25Index provides keyword search over a set of Documents, scored by
+// the BM25 ranking function.
+type BM25Index interface {
+	Index(ctx context.Context, docs []*Document) error
+	Search(ctx context.Context, query string, limit int) ([]*BM25Result, error)
+	Delete(ctx context.Context, docIDs []string) error
+
+	// AllIDs returns every document ID currently in the index, for
+	// consistency checks against MetadataStore.
+	AllIDs() ([]string, error)
+	Stats() *IndexStats
+
+	Save(path string) error
+	Load(path string) error
+	Close() error
+}
+
+// VectorResult is a single VectorStore.Search match.
+type VectorResult struct {
+	ID       string  // Chunk ID.
+	Distance float32 // Lower is more similar (0-2 for cosine).
+	Score    float32 // Normalized similarity (0-1).
+}
+
+// VectorStore provides semantic nearest-neighbor search over chunk
+// embeddings.
+type VectorStore interface {
+	Search(ctx context.Context, query []float32, k int) ([]*VectorResult, error)
+	Add(ctx context.Context, ids []string, vectors [][]float32) error
+	Delete(ctx context.Context, ids []string) error
+
+	// AllIDs returns every vector ID currently in the store, for
+	// consistency checks against MetadataStore.
+	AllIDs() []string
+	Contains(id string) bool
+	Count() int
+
+	Save(path string) error
+	Load(path string) error
+	Close() error
+}
+
+// ErrDimensionMismatch is returned when a vector's width doesn't match a
+// VectorStore's configured dimensions.
+type ErrDimensionMismatch struct {
+	Expected int
+	Got      int
+}
+
+func (e ErrDimensionMismatch) Error() string {
+	return fmt.Sprintf("store: dimension mismatch: expected %d, got %d (run 'amanmcp reindex --force')", e.Expected, e.Got)
+}
@@ -0,0 +1,231 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrVectorIndexNotAttached is returned by TopKByEmbedding when no
+// VectorStore has been attached via AttachVectorIndex, so callers get an
+// explicit, typed rejection instead of silently falling back to the
+// O(N) GetAllEmbeddings scan this subsystem exists to avoid.
+var ErrVectorIndexNotAttached = errors.New("store: no vector index attached")
+
+// vectorIndexRebuildBatchSize is how many chunk rows RebuildVectorIndex
+// reads per keyset page, matching the repo's other chunked-walk batch
+// sizes (see chunkCompressionBackfillBatchSize).
+const vectorIndexRebuildBatchSize = 1000
+
+// ChunkFilter narrows TopKByEmbedding's candidates. A zero-value filter
+// matches every chunk. It's intentionally just the fields the embedding
+// index itself can't express (VectorStore has no notion of project or
+// content type) - anything the vector store can already do, like k, stays
+// a parameter.
+type ChunkFilter struct {
+	ProjectID   string
+	ContentType ContentType
+	Language    string
+}
+
+// ScoredChunk pairs a chunk with its similarity score from a
+// TopKByEmbedding query, score meaning whatever the attached VectorStore
+// returns (e.g. MMapVectorStore's cosine similarity).
+type ScoredChunk struct {
+	Chunk *Chunk
+	Score float32
+}
+
+// AttachVectorIndex wires v in as s's ANN backend. SaveChunkEmbeddings,
+// DeleteChunks, and DeleteChunksByFile keep it in sync with the chunks
+// table from then on; TopKByEmbedding and RebuildVectorIndex both need
+// one attached first. Mirrors the existing composition in
+// search.NewReindexer - SQLiteStore and VectorStore are separate,
+// swappable collaborators - rather than SQLiteStore constructing or
+// owning one itself.
+func (s *SQLiteStore) AttachVectorIndex(v VectorStore) {
+	s.vectorIndex = v
+}
+
+// TopKByEmbedding returns the k chunks whose embeddings are most similar
+// to query, narrowed by filter, ranked by the attached VectorStore's
+// Search. This replaces the O(N) GetAllEmbeddings-and-rank-in-Go path:
+// the attached VectorStore is responsible for avoiding that (e.g.
+// MMapVectorStore serves Search off mmapped shards rather than loading
+// every row).
+//
+// filter is applied after the vector search by re-fetching the
+// candidate chunks and discarding non-matches, since VectorStore has no
+// notion of project/content-type/language; callers needing a precise
+// top-k under a narrow filter should over-fetch (pass a larger k) to
+// compensate for post-filter dropout.
+func (s *SQLiteStore) TopKByEmbedding(ctx context.Context, query []float32, k int, filter *ChunkFilter) ([]ScoredChunk, error) {
+	if s.vectorIndex == nil {
+		return nil, ErrVectorIndexNotAttached
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	results, err := s.vectorIndex.Search(ctx, query, k)
+	if err != nil {
+		return nil, fmt.Errorf("vector index search: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	chunks, err := s.GetChunks(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load chunks for vector search results: %w", err)
+	}
+	chunkByID := make(map[string]*Chunk, len(chunks))
+	for _, c := range chunks {
+		chunkByID[c.ID] = c
+	}
+
+	scored := make([]ScoredChunk, 0, len(results))
+	for _, r := range results {
+		c, ok := chunkByID[r.ID]
+		if !ok {
+			// Deleted or not yet synced to the metadata store since the
+			// vector index last saw it; skip rather than return a half
+			// (*Chunk)(nil) result.
+			continue
+		}
+		if filter != nil {
+			if filter.ProjectID != "" {
+				fileProjectID, ferr := s.projectIDForFile(ctx, c.FileID)
+				if ferr != nil || fileProjectID != filter.ProjectID {
+					continue
+				}
+			}
+			if filter.ContentType != "" && c.ContentType != filter.ContentType {
+				continue
+			}
+			if filter.Language != "" && c.Language != filter.Language {
+				continue
+			}
+		}
+		scored = append(scored, ScoredChunk{Chunk: c, Score: r.Score})
+	}
+	return scored, nil
+}
+
+// projectIDForFile looks up fileID's owning project, for TopKByEmbedding's
+// ChunkFilter.ProjectID check.
+func (s *SQLiteStore) projectIDForFile(ctx context.Context, fileID string) (string, error) {
+	table, err := s.filesTableForFile(ctx, fileID)
+	if err != nil {
+		return "", err
+	}
+	var projectID string
+	err = s.db.QueryRowContext(ctx, `SELECT project_id FROM `+table+` WHERE id = ?`, fileID).Scan(&projectID)
+	return projectID, err
+}
+
+// RebuildVectorIndex repopulates the attached VectorStore from the
+// chunks table, streaming rows via keyset (rowid) pagination in batches
+// of vectorIndexRebuildBatchSize rather than loading every embedding at
+// once. It validates each batch's embedding dimensions and model against
+// the checkpoint state SaveIndexCheckpoint last recorded, refusing to mix
+// vectors from two different embedder models into the same index.
+func (s *SQLiteStore) RebuildVectorIndex(ctx context.Context) error {
+	if s.vectorIndex == nil {
+		return ErrVectorIndexNotAttached
+	}
+
+	checkpoint, err := s.LoadIndexCheckpoint(ctx)
+	var wantModel string
+	if err == nil && checkpoint != nil {
+		wantModel = checkpoint.EmbedderModel
+	}
+
+	var lastRowID int64
+	var totalAdded int
+	for {
+		ids, vectors, nextRowID, scanned, err := s.rebuildVectorIndexBatch(ctx, lastRowID, vectorIndexRebuildBatchSize, wantModel)
+		if err != nil {
+			return fmt.Errorf("rebuild vector index: %w", err)
+		}
+		if len(ids) > 0 {
+			if err := s.vectorIndex.Add(ctx, ids, vectors); err != nil {
+				return fmt.Errorf("rebuild vector index: add batch: %w", err)
+			}
+			totalAdded += len(ids)
+			slog.Info("vector_index_rebuild_progress", slog.Int("added", totalAdded))
+		}
+		if scanned < vectorIndexRebuildBatchSize {
+			break
+		}
+		lastRowID = nextRowID
+	}
+	slog.Info("vector_index_rebuild_complete", slog.Int("added", totalAdded))
+	return nil
+}
+
+// rebuildVectorIndexBatch scans up to limit rows of the shared chunks
+// table after lastRowID that have an embedding, returning the IDs/vectors
+// whose embedding_model matches wantModel (when set - an empty wantModel
+// accepts any model, e.g. on a store with no checkpoint yet), how many
+// rows it scanned (so the caller can detect end-of-table), and the rowid
+// to resume from.
+func (s *SQLiteStore) rebuildVectorIndexBatch(ctx context.Context, lastRowID int64, limit int, wantModel string) (ids []string, vectors [][]float32, nextRowID int64, scanned int, err error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT rowid, id, embedding, embedding_model FROM chunks
+		 WHERE rowid > ? AND embedding IS NOT NULL
+		 ORDER BY rowid LIMIT ?`, lastRowID, limit)
+	if err != nil {
+		return nil, nil, 0, 0, fmt.Errorf("query rebuild batch: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var rowID int64
+		var id, model string
+		var embBytes []byte
+		if err := rows.Scan(&rowID, &id, &embBytes, &model); err != nil {
+			return nil, nil, 0, 0, fmt.Errorf("scan rebuild row: %w", err)
+		}
+		scanned++
+		nextRowID = rowID
+
+		if wantModel != "" && model != "" && model != wantModel {
+			slog.Warn("vector_index_rebuild_skipped_model_mismatch",
+				slog.String("chunk_id", id), slog.String("model", model), slog.String("want_model", wantModel))
+			continue
+		}
+		ids = append(ids, id)
+		vectors = append(vectors, bytesToEmbedding(embBytes))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, 0, 0, fmt.Errorf("iterate rebuild batch: %w", err)
+	}
+	return ids, vectors, nextRowID, scanned, nil
+}
+
+// chunkIDsForFile returns the IDs of fileID's chunks in table, so
+// DeleteChunksByFile can tell the attached VectorStore which IDs to drop
+// (it has no file_id column of its own).
+func (s *SQLiteStore) chunkIDsForFile(ctx context.Context, table, fileID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM `+table+` WHERE file_id = ?`, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("query chunk ids for file: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan chunk id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,104 @@
+//go:build linux || darwin
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStoreForVectorIndex(t *testing.T) (*SQLiteStore, *MMapVectorStore) {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	v, err := NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 2, ModelID: "test-model"})
+	require.NoError(t, err)
+	require.NoError(t, v.Load(nil))
+	t.Cleanup(func() { _ = v.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, s.SaveProject(ctx, &Project{ID: "proj1", Name: "p", RootPath: "/p"}))
+	require.NoError(t, s.SaveFiles(ctx, []*File{{ID: "file1", ProjectID: "proj1", Path: "a.go"}}))
+	require.NoError(t, s.SaveChunks(ctx, []*Chunk{
+		{ID: "chunk1", FileID: "file1", FilePath: "a.go", Content: "hello", StartLine: 1, EndLine: 2},
+		{ID: "chunk2", FileID: "file1", FilePath: "a.go", Content: "world", StartLine: 3, EndLine: 4},
+	}))
+	return s, v
+}
+
+func TestSQLiteStore_TopKByEmbeddingRejectsWithoutAttachedIndex(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "metadata.db")
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	_, err = s.TopKByEmbedding(context.Background(), []float32{1, 0}, 5, nil)
+	assert.ErrorIs(t, err, ErrVectorIndexNotAttached)
+	assert.ErrorIs(t, s.RebuildVectorIndex(context.Background()), ErrVectorIndexNotAttached)
+}
+
+func TestSQLiteStore_TopKByEmbeddingRanksByAttachedVectorIndex(t *testing.T) {
+	s, v := newTestStoreForVectorIndex(t)
+	s.AttachVectorIndex(v)
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveChunkEmbeddings(ctx, []string{"chunk1", "chunk2"},
+		[][]float32{{1, 0}, {0, 1}}, "test-model"))
+
+	results, err := s.TopKByEmbedding(ctx, []float32{1, 0}, 1, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "chunk1", results[0].Chunk.ID)
+}
+
+func TestSQLiteStore_DeleteChunksSyncsAttachedVectorIndex(t *testing.T) {
+	s, v := newTestStoreForVectorIndex(t)
+	s.AttachVectorIndex(v)
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveChunkEmbeddings(ctx, []string{"chunk1", "chunk2"},
+		[][]float32{{1, 0}, {0, 1}}, "test-model"))
+	require.NoError(t, s.DeleteChunks(ctx, []string{"chunk1"}))
+
+	assert.False(t, v.Contains("chunk1"))
+	assert.True(t, v.Contains("chunk2"))
+}
+
+func TestSQLiteStore_DeleteChunksByFileSyncsAttachedVectorIndex(t *testing.T) {
+	s, v := newTestStoreForVectorIndex(t)
+	s.AttachVectorIndex(v)
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveChunkEmbeddings(ctx, []string{"chunk1", "chunk2"},
+		[][]float32{{1, 0}, {0, 1}}, "test-model"))
+	require.NoError(t, s.DeleteChunksByFile(ctx, "file1"))
+
+	assert.Equal(t, 0, v.Count())
+}
+
+func TestSQLiteStore_RebuildVectorIndexStreamsExistingEmbeddings(t *testing.T) {
+	s, seed := newTestStoreForVectorIndex(t)
+	s.AttachVectorIndex(seed)
+	ctx := context.Background()
+	require.NoError(t, s.SaveChunkEmbeddings(ctx, []string{"chunk1", "chunk2"},
+		[][]float32{{1, 0}, {0, 1}}, "test-model"))
+
+	fresh, err := NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 2, ModelID: "test-model"})
+	require.NoError(t, err)
+	require.NoError(t, fresh.Load(nil))
+	defer func() { _ = fresh.Close() }()
+
+	s.AttachVectorIndex(fresh)
+	require.NoError(t, s.RebuildVectorIndex(ctx))
+
+	assert.Equal(t, 2, fresh.Count())
+	assert.True(t, fresh.Contains("chunk1"))
+	assert.True(t, fresh.Contains("chunk2"))
+}
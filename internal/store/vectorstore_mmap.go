@@ -0,0 +1,541 @@
+//go:build linux || darwin
+
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// mmapShardMagic identifies a valid shard file header.
+const mmapShardMagic = uint32(0x4d4d5631) // "MMV1"
+
+// mmapShardHeaderSize is the fixed size, in bytes, of a shard file's header:
+// magic(4) + dimension(4) + modelIDLen(2) + modelID(64, padded) +
+// sequence(4) + rowCount(4) + checksum(4).
+const mmapShardHeaderSize = 4 + 4 + 2 + 64 + 4 + 4 + 4
+
+// mmapModelIDWidth is the fixed width reserved for the model ID string in
+// the shard header.
+const mmapModelIDWidth = 64
+
+// DefaultMMapShardRows is the number of rows a shard holds before a new
+// shard is opened, when MMapVectorConfig.ShardRows is zero.
+const DefaultMMapShardRows = 100_000
+
+// MMapVectorConfig configures an MMapVectorStore.
+type MMapVectorConfig struct {
+	// Dir holds one shard-NNNNN.vec file per shard.
+	Dir string
+	// Dimensions is the embedding vector size; every shard's header must
+	// match this or the store refuses to load it.
+	Dimensions int
+	// ModelID identifies the embedding model the vectors were produced
+	// with, so a model swap is caught at startup rather than silently
+	// returning nonsense neighbours.
+	ModelID string
+	// ShardRows bounds how many rows a shard holds before rolling over to
+	// a new one. Zero means DefaultMMapShardRows.
+	ShardRows int
+}
+
+// mmapLocation is where a vector ID lives: which shard, and which row
+// within it.
+type mmapLocation struct {
+	shardIdx int
+	row      int
+}
+
+// mmapShard is one memory-mapped, fixed-row vector file plus the
+// parallel, in-order list of IDs recovered from the metadata store (the
+// shard itself stores only raw float32 rows - see the MMapVectorStore
+// doc comment for why).
+type mmapShard struct {
+	path      string
+	file      *os.File
+	data      []byte // mmap'd region covering the whole file
+	dimension int
+	rowCount  int
+	rowCap    int
+	sequence  uint32
+	ids       []string // ids[row] is the ID stored at that row, "" if deleted
+	idToRow   map[string]int
+}
+
+// MMapVectorStore is a VectorStore backed by fixed-size, memory-mapped
+// shard files, so an index much larger than RAM can be searched without
+// paying a per-query allocation to load vectors off disk. Each shard is an
+// append-only header-then-rows file; the id -> (shard, row) mapping that
+// makes Search/Contains/Delete possible is rebuilt at Load time from the
+// caller-supplied row ID lists (normally sourced from the metadata store's
+// chunk list) rather than stored redundantly in the shard itself, so a torn
+// write only costs a rebuild, never silent data loss - the same recovery
+// shape as the m-mapped head-chunk pattern used by time-series stores.
+type MMapVectorStore struct {
+	cfg    MMapVectorConfig
+	mu     sync.RWMutex
+	shards []*mmapShard
+	index  map[string]mmapLocation
+}
+
+// NewMMapVectorStore creates an MMapVectorStore rooted at cfg.Dir. Callers
+// must still call Load to map any existing shards before serving queries.
+func NewMMapVectorStore(cfg MMapVectorConfig) (*MMapVectorStore, error) {
+	if cfg.Dimensions <= 0 {
+		return nil, fmt.Errorf("mmap vector store: dimensions must be positive")
+	}
+	if cfg.ShardRows <= 0 {
+		cfg.ShardRows = DefaultMMapShardRows
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mmap vector store: create dir: %w", err)
+	}
+	return &MMapVectorStore{cfg: cfg, index: make(map[string]mmapLocation)}, nil
+}
+
+func shardPath(dir string, sequence uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("shard-%05d.vec", sequence))
+}
+
+// rowOffset returns the byte offset of row within a shard's mmap'd data.
+func (s *mmapShard) rowOffset(row int) int {
+	return mmapShardHeaderSize + row*s.dimension*4
+}
+
+func (s *mmapShard) vectorAt(row int) []float32 {
+	off := s.rowOffset(row)
+	raw := s.data[off : off+s.dimension*4]
+	out := make([]float32, s.dimension)
+	for i := range out {
+		bits := binary.LittleEndian.Uint32(raw[i*4 : i*4+4])
+		out[i] = math.Float32frombits(bits)
+	}
+	return out
+}
+
+func (s *mmapShard) putVectorAt(row int, vec []float32) {
+	off := s.rowOffset(row)
+	raw := s.data[off : off+s.dimension*4]
+	for i, v := range vec {
+		binary.LittleEndian.PutUint32(raw[i*4:i*4+4], math.Float32bits(v))
+	}
+}
+
+// validateHeader parses and checks a shard's header against cfg, returning
+// the declared row count and sequence number.
+func (m *MMapVectorStore) validateHeader(header []byte) (rowCount int, sequence uint32, err error) {
+	if len(header) < mmapShardHeaderSize {
+		return 0, 0, fmt.Errorf("header too short")
+	}
+	magic := binary.LittleEndian.Uint32(header[0:4])
+	if magic != mmapShardMagic {
+		return 0, 0, fmt.Errorf("bad magic %x", magic)
+	}
+	dimension := int(binary.LittleEndian.Uint32(header[4:8]))
+	if dimension != m.cfg.Dimensions {
+		return 0, 0, fmt.Errorf("shard dimension %d != configured %d", dimension, m.cfg.Dimensions)
+	}
+	modelIDLen := int(binary.LittleEndian.Uint16(header[8:10]))
+	if modelIDLen > mmapModelIDWidth {
+		return 0, 0, fmt.Errorf("invalid model id length %d", modelIDLen)
+	}
+	modelID := string(header[10 : 10+modelIDLen])
+	if m.cfg.ModelID != "" && modelID != m.cfg.ModelID {
+		return 0, 0, fmt.Errorf("shard model %q != configured %q", modelID, m.cfg.ModelID)
+	}
+	sequence = binary.LittleEndian.Uint32(header[10+mmapModelIDWidth : 14+mmapModelIDWidth])
+	rowCount = int(binary.LittleEndian.Uint32(header[14+mmapModelIDWidth : 18+mmapModelIDWidth]))
+	return rowCount, sequence, nil
+}
+
+// checksumRows returns the CRC32 of a shard's row data (everything after
+// the header), which the header's trailing field must match.
+func checksumRows(data []byte) uint32 {
+	if len(data) <= mmapShardHeaderSize {
+		return crc32.ChecksumIEEE(nil)
+	}
+	return crc32.ChecksumIEEE(data[mmapShardHeaderSize:])
+}
+
+// Load memory-maps every shard-*.vec file in cfg.Dir, validating each
+// header's dimension/model against the store's configuration. If the tail
+// (highest-sequence) shard's checksum doesn't match its declared row
+// count, its unverified rows are dropped - this is the WAL recovery path
+// for a crash mid-append; the caller is expected to re-add any chunks that
+// fall out via Add afterward. idsByShard supplies each shard's row-ordered
+// ID list, keyed by shard sequence number, since the shard file itself
+// stores only raw vectors - callers typically source this from the
+// metadata store's chunk list.
+func (m *MMapVectorStore) Load(idsByShard map[uint32][]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := os.ReadDir(m.cfg.Dir)
+	if err != nil {
+		return fmt.Errorf("mmap vector store: read dir: %w", err)
+	}
+
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".vec" {
+			paths = append(paths, filepath.Join(m.cfg.Dir, e.Name()))
+		}
+	}
+	sort.Strings(paths)
+
+	m.closeShardsLocked()
+	m.shards = nil
+	m.index = make(map[string]mmapLocation)
+
+	for i, path := range paths {
+		isTail := i == len(paths)-1
+		shard, err := m.loadShard(path, isTail)
+		if err != nil {
+			return fmt.Errorf("mmap vector store: load %s: %w", path, err)
+		}
+
+		ids := idsByShard[shard.sequence]
+		for row := 0; row < shard.rowCount && row < len(ids); row++ {
+			id := ids[row]
+			if id == "" {
+				continue
+			}
+			shard.ids[row] = id
+			shard.idToRow[id] = row
+			m.index[id] = mmapLocation{shardIdx: len(m.shards), row: row}
+		}
+
+		m.shards = append(m.shards, shard)
+	}
+	return nil
+}
+
+// loadShard opens, validates, and mmaps a single shard file. When isTail is
+// true and the checksum doesn't match, the shard's row count is reset to 0
+// instead of returning an error, matching the "drop the torn tail" recovery
+// rule.
+func (m *MMapVectorStore) loadShard(path string, isTail bool) (*mmapShard, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("mmap: %w", err)
+	}
+
+	rowCount, sequence, err := m.validateHeader(data)
+	if err != nil {
+		syscall.Munmap(data)
+		f.Close()
+		return nil, err
+	}
+
+	checksumField := binary.LittleEndian.Uint32(data[mmapShardHeaderSize-4 : mmapShardHeaderSize])
+	if checksumRows(data) != checksumField {
+		if !isTail {
+			syscall.Munmap(data)
+			f.Close()
+			return nil, fmt.Errorf("checksum mismatch on non-tail shard")
+		}
+		// Torn write: without per-row checksums there's no way to find the
+		// largest internally-consistent prefix, so the conservative
+		// recovery is to drop the tail shard's unverified rows entirely
+		// and let the caller re-append from the metadata store's chunk
+		// list.
+		rowCount = 0
+	}
+
+	return &mmapShard{
+		path:      path,
+		file:      f,
+		data:      data,
+		dimension: m.cfg.Dimensions,
+		rowCount:  rowCount,
+		rowCap:    (len(data) - mmapShardHeaderSize) / (m.cfg.Dimensions * 4),
+		sequence:  sequence,
+		ids:       make([]string, rowCount),
+		idToRow:   make(map[string]int, rowCount),
+	}, nil
+}
+
+// Warmup pre-faults every mapped shard by touching one byte per page, so
+// the first real query doesn't pay page-fault latency for a cold index.
+func (m *MMapVectorStore) Warmup() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	const pageSize = 4096
+	var sink byte
+	for _, shard := range m.shards {
+		for off := 0; off < len(shard.data); off += pageSize {
+			sink += shard.data[off]
+		}
+	}
+	_ = sink
+}
+
+// Search performs a brute-force linear scan over every mapped row,
+// decoding vectors lazily as it goes rather than materializing the whole
+// index into Go slices up front.
+func (m *MMapVectorStore) Search(_ context.Context, query []float32, k int) ([]*VectorResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(query) != m.cfg.Dimensions {
+		return nil, fmt.Errorf("mmap vector store: query dimension %d != index dimension %d", len(query), m.cfg.Dimensions)
+	}
+
+	type scored struct {
+		id    string
+		score float32
+	}
+	var all []scored
+	for _, shard := range m.shards {
+		for row := 0; row < shard.rowCount; row++ {
+			id := shard.ids[row]
+			if id == "" {
+				continue
+			}
+			all = append(all, scored{id: id, score: cosine32(query, shard.vectorAt(row))})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].score > all[j].score })
+	if k > 0 && k < len(all) {
+		all = all[:k]
+	}
+
+	results := make([]*VectorResult, len(all))
+	for i, s := range all {
+		results[i] = &VectorResult{ID: s.id, Score: s.score}
+	}
+	return results, nil
+}
+
+func cosine32(a, b []float32) float32 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
+// Add appends ids/vectors into the tail shard's free rows, rolling over to
+// a freshly created shard once the tail is full. The header checksum is
+// updated after each write so Load can detect a torn tail.
+func (m *MMapVectorStore) Add(_ context.Context, ids []string, vectors [][]float32) error {
+	if len(ids) != len(vectors) {
+		return fmt.Errorf("mmap vector store: ids/vectors length mismatch: %d != %d", len(ids), len(vectors))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, id := range ids {
+		shard, err := m.tailShardForWriteLocked()
+		if err != nil {
+			return err
+		}
+		row := shard.rowCount
+		shard.putVectorAt(row, vectors[i])
+		shard.ids = append(shard.ids, id)
+		shard.idToRow[id] = row
+		shard.rowCount++
+		m.index[id] = mmapLocation{shardIdx: len(m.shards) - 1, row: row}
+		m.syncShardHeaderLocked(shard)
+	}
+	return nil
+}
+
+// tailShardForWriteLocked returns the current tail shard, creating one if
+// there isn't one yet or the existing tail is full. Callers must hold m.mu.
+func (m *MMapVectorStore) tailShardForWriteLocked() (*mmapShard, error) {
+	if len(m.shards) > 0 {
+		tail := m.shards[len(m.shards)-1]
+		if tail.rowCount < tail.rowCap {
+			return tail, nil
+		}
+	}
+
+	sequence := uint32(len(m.shards))
+	path, err := createShard(m.cfg, sequence)
+	if err != nil {
+		return nil, fmt.Errorf("mmap vector store: create shard: %w", err)
+	}
+	shard, err := m.loadShard(path, true)
+	if err != nil {
+		return nil, err
+	}
+	m.shards = append(m.shards, shard)
+	return shard, nil
+}
+
+// syncShardHeaderLocked rewrites shard's header with its current row count
+// and row-data checksum. Callers must hold m.mu.
+func (m *MMapVectorStore) syncShardHeaderLocked(shard *mmapShard) {
+	writeShardHeader(shard.data[:mmapShardHeaderSize], shard.dimension, m.cfg.ModelID, shard.sequence, shard.rowCount, checksumRows(shard.data))
+}
+
+// Delete marks ids as removed. Their rows remain allocated (and are
+// skipped by Search/AllIDs) until the next Compact reclaims the space.
+func (m *MMapVectorStore) Delete(_ context.Context, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		loc, ok := m.index[id]
+		if !ok {
+			continue
+		}
+		shard := m.shards[loc.shardIdx]
+		shard.ids[loc.row] = ""
+		delete(shard.idToRow, id)
+		delete(m.index, id)
+	}
+	return nil
+}
+
+// Compact rewrites every shard, dropping deleted rows, so disk usage and
+// scan time reflect only live vectors. live supplies the current ID for
+// every row that should survive, since the shard itself can't tell a
+// deleted row from one that was never written.
+func (m *MMapVectorStore) Compact(live map[string][]float32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(live))
+	for id := range live {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	m.closeShardsLocked()
+	if err := os.RemoveAll(m.cfg.Dir); err != nil {
+		return fmt.Errorf("mmap vector store: compact: clear dir: %w", err)
+	}
+	if err := os.MkdirAll(m.cfg.Dir, 0o755); err != nil {
+		return fmt.Errorf("mmap vector store: compact: recreate dir: %w", err)
+	}
+	m.shards = nil
+	m.index = make(map[string]mmapLocation)
+
+	for _, id := range ids {
+		shard, err := m.tailShardForWriteLocked()
+		if err != nil {
+			return err
+		}
+		row := shard.rowCount
+		shard.putVectorAt(row, live[id])
+		shard.ids = append(shard.ids, id)
+		shard.idToRow[id] = row
+		shard.rowCount++
+		m.index[id] = mmapLocation{shardIdx: len(m.shards) - 1, row: row}
+		m.syncShardHeaderLocked(shard)
+	}
+	return nil
+}
+
+// Contains reports whether id is currently mapped to a live row.
+func (m *MMapVectorStore) Contains(id string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.index[id]
+	return ok
+}
+
+// Count returns the number of live (non-deleted) rows across all shards.
+func (m *MMapVectorStore) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.index)
+}
+
+// AllIDs returns every live vector ID across all shards.
+func (m *MMapVectorStore) AllIDs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]string, 0, len(m.index))
+	for id := range m.index {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Save is a no-op: every Add/Delete is already durable in the mmap'd shard
+// files, there's nothing extra to flush.
+func (m *MMapVectorStore) Save(_ string) error { return nil }
+
+func (m *MMapVectorStore) closeShardsLocked() {
+	for _, shard := range m.shards {
+		syscall.Munmap(shard.data)
+		shard.file.Close()
+	}
+}
+
+// Close unmaps and closes every shard file.
+func (m *MMapVectorStore) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closeShardsLocked()
+	m.shards = nil
+	return nil
+}
+
+// writeShardHeader serializes a shard header into buf (which must be at
+// least mmapShardHeaderSize bytes).
+func writeShardHeader(buf []byte, dimension int, modelID string, sequence uint32, rowCount int, checksum uint32) {
+	binary.LittleEndian.PutUint32(buf[0:4], mmapShardMagic)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(dimension))
+	binary.LittleEndian.PutUint16(buf[8:10], uint16(len(modelID)))
+	copy(buf[10:10+mmapModelIDWidth], modelID)
+	binary.LittleEndian.PutUint32(buf[10+mmapModelIDWidth:14+mmapModelIDWidth], sequence)
+	binary.LittleEndian.PutUint32(buf[14+mmapModelIDWidth:18+mmapModelIDWidth], uint32(rowCount))
+	binary.LittleEndian.PutUint32(buf[mmapShardHeaderSize-4:mmapShardHeaderSize], checksum)
+}
+
+// createShard writes a brand-new, empty shard file with capacity for
+// cfg.ShardRows rows and returns its path.
+func createShard(cfg MMapVectorConfig, sequence uint32) (string, error) {
+	path := shardPath(cfg.Dir, sequence)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	header := make([]byte, mmapShardHeaderSize)
+	writeShardHeader(header, cfg.Dimensions, cfg.ModelID, sequence, 0, checksumRows(nil))
+	if _, err := w.Write(header); err != nil {
+		return "", err
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	rowBytes := cfg.Dimensions * 4
+	if err := f.Truncate(int64(mmapShardHeaderSize + rowBytes*cfg.ShardRows)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,144 @@
+//go:build linux || darwin
+
+package store
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMMapStore(t *testing.T) *MMapVectorStore {
+	t.Helper()
+	s, err := NewMMapVectorStore(MMapVectorConfig{Dir: t.TempDir(), Dimensions: 4, ModelID: "test-model", ShardRows: 4})
+	require.NoError(t, err)
+	require.NoError(t, s.Load(nil))
+	return s
+}
+
+func TestMMapVectorStore_AddSearchFindsNearestNeighbor(t *testing.T) {
+	s := newTestMMapStore(t)
+	defer s.Close()
+
+	err := s.Add(context.Background(), []string{"a", "b"}, [][]float32{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+	})
+	require.NoError(t, err)
+
+	results, err := s.Search(context.Background(), []float32{1, 0, 0, 0}, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "a", results[0].ID)
+}
+
+func TestMMapVectorStore_DeleteHidesFromSearchAndCount(t *testing.T) {
+	s := newTestMMapStore(t)
+	defer s.Close()
+
+	require.NoError(t, s.Add(context.Background(), []string{"a", "b"}, [][]float32{{1, 0, 0, 0}, {0, 1, 0, 0}}))
+	require.NoError(t, s.Delete(context.Background(), []string{"a"}))
+
+	assert.False(t, s.Contains("a"))
+	assert.Equal(t, 1, s.Count())
+
+	results, err := s.Search(context.Background(), []float32{1, 0, 0, 0}, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "b", results[0].ID)
+}
+
+func TestMMapVectorStore_RollsOverToNewShardWhenFull(t *testing.T) {
+	s := newTestMMapStore(t) // ShardRows: 4
+	defer s.Close()
+
+	ids := []string{"a", "b", "c", "d", "e"}
+	vectors := make([][]float32, len(ids))
+	for i := range vectors {
+		vectors[i] = []float32{float32(i), 0, 0, 0}
+	}
+	require.NoError(t, s.Add(context.Background(), ids, vectors))
+
+	assert.Len(t, s.shards, 2, "a 5th row should roll over into a second shard")
+	assert.Equal(t, 5, s.Count())
+}
+
+func TestMMapVectorStore_LoadRejectsDimensionMismatch(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewMMapVectorStore(MMapVectorConfig{Dir: dir, Dimensions: 4, ShardRows: 4})
+	require.NoError(t, err)
+	require.NoError(t, s.Load(nil))
+	require.NoError(t, s.Add(context.Background(), []string{"a"}, [][]float32{{1, 2, 3, 4}}))
+	require.NoError(t, s.Close())
+
+	mismatched, err := NewMMapVectorStore(MMapVectorConfig{Dir: dir, Dimensions: 8, ShardRows: 4})
+	require.NoError(t, err)
+	err = mismatched.Load(nil)
+	assert.Error(t, err)
+}
+
+func TestMMapVectorStore_LoadRecoversIDsFromSuppliedList(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewMMapVectorStore(MMapVectorConfig{Dir: dir, Dimensions: 4, ShardRows: 4})
+	require.NoError(t, err)
+	require.NoError(t, s.Load(nil))
+	require.NoError(t, s.Add(context.Background(), []string{"a", "b"}, [][]float32{{1, 0, 0, 0}, {0, 1, 0, 0}}))
+	require.NoError(t, s.Close())
+
+	reopened, err := NewMMapVectorStore(MMapVectorConfig{Dir: dir, Dimensions: 4, ShardRows: 4})
+	require.NoError(t, err)
+	defer reopened.Close()
+	require.NoError(t, reopened.Load(map[uint32][]string{0: {"a", "b"}}))
+
+	assert.True(t, reopened.Contains("a"))
+	assert.True(t, reopened.Contains("b"))
+	assert.Equal(t, 2, reopened.Count())
+}
+
+func TestMMapVectorStore_CompactReclaimsDeletedRows(t *testing.T) {
+	s := newTestMMapStore(t)
+	defer s.Close()
+
+	require.NoError(t, s.Add(context.Background(), []string{"a", "b"}, [][]float32{{1, 0, 0, 0}, {0, 1, 0, 0}}))
+	require.NoError(t, s.Delete(context.Background(), []string{"a"}))
+
+	require.NoError(t, s.Compact(map[string][]float32{"b": {0, 1, 0, 0}}))
+
+	assert.Equal(t, 1, s.Count())
+	assert.False(t, s.Contains("a"))
+	assert.True(t, s.Contains("b"))
+}
+
+func TestMMapVectorStore_WarmupDoesNotPanicOnEmptyStore(t *testing.T) {
+	s := newTestMMapStore(t)
+	defer s.Close()
+	s.Warmup()
+}
+
+func TestMMapVectorStore_LoadTruncatesTornTailShard(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewMMapVectorStore(MMapVectorConfig{Dir: dir, Dimensions: 4, ShardRows: 4})
+	require.NoError(t, err)
+	require.NoError(t, s.Load(nil))
+	require.NoError(t, s.Add(context.Background(), []string{"a"}, [][]float32{{1, 0, 0, 0}}))
+	require.NoError(t, s.Close())
+
+	// Corrupt the row-count field in the header to simulate a torn write:
+	// the row data no longer matches the checksum the header records.
+	path := shardPath(dir, 0)
+	f, err := os.OpenFile(path, os.O_RDWR, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte{0xFF}, int64(mmapShardHeaderSize-4))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewMMapVectorStore(MMapVectorConfig{Dir: dir, Dimensions: 4, ShardRows: 4})
+	require.NoError(t, err)
+	defer reopened.Close()
+	require.NoError(t, reopened.Load(map[uint32][]string{0: {"a"}}))
+
+	assert.Equal(t, 0, reopened.Count(), "torn tail shard rows should be dropped, not served")
+}
@@ -0,0 +1,291 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// VerifyOptions configures VerifyIndex.
+type VerifyOptions struct {
+	// VectorDimensions is the width the caller's VectorStore was opened
+	// with (e.g. MMapVectorConfig.Dimensions, already validated against
+	// the on-disk shard header at Load time). VerifyIndex compares it
+	// against the state table's recorded dimension; zero skips the
+	// check.
+	VectorDimensions int
+	// Manifest, if non-nil, maps a file path relative to ManifestRoot to
+	// its expected sha256 checksum. VerifyIndex flags any entry whose
+	// on-disk checksum doesn't match, or that's missing entirely. A nil
+	// Manifest skips checksum verification - the repo has no existing
+	// persisted manifest format, so callers that want this must supply
+	// their own (e.g. one saved alongside a prior backup or migration,
+	// or built from MetadataStore.GetFilesForReconciliation's recorded
+	// File.ContentHash values).
+	Manifest map[string]string
+	// ManifestRoot is the base directory Manifest's paths are resolved
+	// against. Empty means dataDir, which keeps the original behavior
+	// for a manifest describing index component files; a caller
+	// checking source files under the project root instead should set
+	// this to that root.
+	ManifestRoot string
+}
+
+// VerifyReport lists every inconsistency VerifyIndex found between a
+// project's metadata store, its BM25 index, and its vector index. A zero
+// value (from Clean) means the three are in agreement.
+type VerifyReport struct {
+	// MissingFromBM25/MissingFromVectors are chunk IDs metadata has an
+	// embedding for that the respective index doesn't - RepairIndex
+	// re-indexes these via its re-embed callback.
+	MissingFromBM25    []string
+	MissingFromVectors []string
+	// OrphanBM25IDs/OrphanVectorIDs are IDs present in an index with no
+	// matching metadata chunk - RepairIndex deletes these.
+	OrphanBM25IDs   []string
+	OrphanVectorIDs []string
+
+	// DimensionMismatch is set when opts.VectorDimensions was given and
+	// differs from the state table's recorded dimension.
+	DimensionMismatch   bool
+	RecordedDimensions  int
+	ConfiguredVectorDim int
+
+	// ChecksumMismatches holds manifest paths (relative to dataDir) whose
+	// on-disk sha256 doesn't match, or that are missing, only populated
+	// when opts.Manifest is non-nil.
+	ChecksumMismatches []string
+}
+
+// Clean reports whether VerifyIndex found nothing that RepairIndex would
+// need to act on.
+func (r *VerifyReport) Clean() bool {
+	return len(r.MissingFromBM25) == 0 && len(r.MissingFromVectors) == 0 &&
+		len(r.OrphanBM25IDs) == 0 && len(r.OrphanVectorIDs) == 0 &&
+		!r.DimensionMismatch && len(r.ChecksumMismatches) == 0
+}
+
+// VerifyIndex cross-checks a project's metadata store against its BM25
+// and vector indexes, the same role Mimir's VerifyBlock plays for a TSDB
+// block: confirm every embedded chunk metadata knows about is actually
+// present in both indexes, neither index holds an ID metadata doesn't
+// recognize, and (when opts.VectorDimensions is set) the configured
+// embedding width still matches what's recorded in state. ctx is checked
+// between stages so a caller can cancel a verification running long
+// against a large index.
+//
+// bm25 and vectors must already be open against dataDir - VerifyIndex
+// reads through them rather than constructing its own backend instances,
+// since which concrete backend to open (SQLite/Bleve BM25, mmap/HNSW
+// vectors) is a decision the caller has already made.
+func VerifyIndex(ctx context.Context, metadata MetadataStore, bm25 BM25Index, vectors VectorStore, dataDir string, opts VerifyOptions) (*VerifyReport, error) {
+	report := &VerifyReport{}
+
+	embeddings, err := metadata.GetAllEmbeddings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("verify index: list embedded chunks: %w", err)
+	}
+	chunkIDs := make(map[string]struct{}, len(embeddings))
+	for id := range embeddings {
+		chunkIDs[id] = struct{}{}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	bm25IDs, err := bm25.AllIDs()
+	if err != nil {
+		return nil, fmt.Errorf("verify index: list BM25 IDs: %w", err)
+	}
+	diffIDs(chunkIDs, bm25IDs, &report.MissingFromBM25, &report.OrphanBM25IDs)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	vectorIDs := vectors.AllIDs()
+	diffIDs(chunkIDs, vectorIDs, &report.MissingFromVectors, &report.OrphanVectorIDs)
+
+	if opts.VectorDimensions > 0 {
+		report.ConfiguredVectorDim = opts.VectorDimensions
+		if dimStr, err := metadata.GetState(ctx, StateKeyIndexDimension); err == nil && dimStr != "" {
+			if dim, err := strconv.Atoi(dimStr); err == nil {
+				report.RecordedDimensions = dim
+				report.DimensionMismatch = dim != opts.VectorDimensions
+			}
+		}
+	}
+
+	if opts.Manifest != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		manifestRoot := opts.ManifestRoot
+		if manifestRoot == "" {
+			manifestRoot = dataDir
+		}
+		report.ChecksumMismatches, err = verifyManifest(manifestRoot, opts.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("verify index: checksum manifest: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// diffIDs splits have (an index's actual ID list) against want (the set
+// of IDs metadata expects) into missing (in want, not in have) and orphan
+// (in have, not in want), both sorted for stable reporting.
+func diffIDs(want map[string]struct{}, have []string, missing, orphan *[]string) {
+	haveSet := make(map[string]struct{}, len(have))
+	for _, id := range have {
+		haveSet[id] = struct{}{}
+		if _, ok := want[id]; !ok {
+			*orphan = append(*orphan, id)
+		}
+	}
+	for id := range want {
+		if _, ok := haveSet[id]; !ok {
+			*missing = append(*missing, id)
+		}
+	}
+	sort.Strings(*missing)
+	sort.Strings(*orphan)
+}
+
+// verifyManifest returns the subset of manifest's paths (relative to
+// root) whose on-disk sha256 doesn't match, or that don't exist.
+func verifyManifest(root string, manifest map[string]string) ([]string, error) {
+	paths := make([]string, 0, len(manifest))
+	for p := range manifest {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var mismatches []string
+	for _, rel := range paths {
+		sum, err := fileChecksum(filepath.Join(root, rel))
+		if err != nil {
+			if os.IsNotExist(err) {
+				mismatches = append(mismatches, rel)
+				continue
+			}
+			return nil, fmt.Errorf("checksum %s: %w", rel, err)
+		}
+		if sum != manifest[rel] {
+			mismatches = append(mismatches, rel)
+		}
+	}
+	return mismatches, nil
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReembedFunc re-embeds chunkIDs on RepairIndex's behalf, returning the
+// store.Document (for BM25) and embedding vector (for the vector index)
+// for each ID it was able to produce. A caller that can't re-embed a
+// given ID (e.g. the source file was deleted) simply omits it from the
+// returned slices rather than erroring the whole batch.
+type ReembedFunc func(ctx context.Context, chunkIDs []string) ([]*Document, [][]float32, error)
+
+// RepairIndex acts on a VerifyReport: it deletes every orphan ID from the
+// index it was found in (via BM25Index.Delete / VectorStore.Delete), then
+// calls reembed once with the union of both indexes' missing chunk IDs
+// and indexes whatever it returns into the index(es) that were actually
+// missing that ID. It does not touch DimensionMismatch or
+// ChecksumMismatches - a dimension or checksum problem means the index
+// itself needs to be rebuilt or restored from backup, not patched chunk
+// by chunk.
+func RepairIndex(ctx context.Context, bm25 BM25Index, vectors VectorStore, report *VerifyReport, reembed ReembedFunc) error {
+	if len(report.OrphanBM25IDs) > 0 {
+		if err := bm25.Delete(ctx, report.OrphanBM25IDs); err != nil {
+			return fmt.Errorf("repair index: delete BM25 orphans: %w", err)
+		}
+	}
+	if len(report.OrphanVectorIDs) > 0 {
+		if err := vectors.Delete(ctx, report.OrphanVectorIDs); err != nil {
+			return fmt.Errorf("repair index: delete vector orphans: %w", err)
+		}
+	}
+
+	missing := unionIDs(report.MissingFromBM25, report.MissingFromVectors)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	docs, vecs, err := reembed(ctx, missing)
+	if err != nil {
+		return fmt.Errorf("repair index: reembed: %w", err)
+	}
+	if len(docs) != len(vecs) {
+		return fmt.Errorf("repair index: reembed returned %d documents but %d vectors", len(docs), len(vecs))
+	}
+
+	needBM25 := toSet(report.MissingFromBM25)
+	needVectors := toSet(report.MissingFromVectors)
+
+	var bm25Docs []*Document
+	var vectorIDs []string
+	var vectorVecs [][]float32
+	for i, d := range docs {
+		if _, ok := needBM25[d.ID]; ok {
+			bm25Docs = append(bm25Docs, d)
+		}
+		if _, ok := needVectors[d.ID]; ok {
+			vectorIDs = append(vectorIDs, d.ID)
+			vectorVecs = append(vectorVecs, vecs[i])
+		}
+	}
+
+	if len(bm25Docs) > 0 {
+		if err := bm25.Index(ctx, bm25Docs); err != nil {
+			return fmt.Errorf("repair index: index BM25 documents: %w", err)
+		}
+	}
+	if len(vectorIDs) > 0 {
+		if err := vectors.Add(ctx, vectorIDs, vectorVecs); err != nil {
+			return fmt.Errorf("repair index: add vectors: %w", err)
+		}
+	}
+	return nil
+}
+
+func toSet(ids []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func unionIDs(a, b []string) []string {
+	set := toSet(a)
+	for _, id := range b {
+		set[id] = struct{}{}
+	}
+	out := make([]string, 0, len(set))
+	for id := range set {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
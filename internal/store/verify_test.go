@@ -0,0 +1,230 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMetadataStore is a minimal MetadataStore stand-in exercising only
+// GetAllEmbeddings/GetState, enough to test VerifyIndex/RepairIndex in
+// isolation - the same embed-only-what's-needed approach
+// inMemoryStateStore (tombstone_test.go) uses.
+type fakeMetadataStore struct {
+	MetadataStore
+	embeddings map[string][]float32
+	state      map[string]string
+}
+
+func newFakeMetadataStore() *fakeMetadataStore {
+	return &fakeMetadataStore{embeddings: make(map[string][]float32), state: make(map[string]string)}
+}
+
+func (s *fakeMetadataStore) GetAllEmbeddings(_ context.Context) (map[string][]float32, error) {
+	return s.embeddings, nil
+}
+
+func (s *fakeMetadataStore) GetState(_ context.Context, key string) (string, error) {
+	return s.state[key], nil
+}
+
+// fakeVectorStore is a minimal store.VectorStore stand-in, since the real
+// backends (MMapVectorStore, HNSW) live behind a build tag or outside
+// this tree.
+type fakeVectorStore struct {
+	ids     []string
+	deleted map[string]struct{}
+	added   map[string][]float32
+}
+
+func newFakeVectorStore(ids ...string) *fakeVectorStore {
+	return &fakeVectorStore{ids: ids, deleted: make(map[string]struct{}), added: make(map[string][]float32)}
+}
+
+func (f *fakeVectorStore) Search(ctx context.Context, query []float32, k int) ([]*VectorResult, error) {
+	return nil, nil
+}
+func (f *fakeVectorStore) Add(ctx context.Context, ids []string, vectors [][]float32) error {
+	for i, id := range ids {
+		f.added[id] = vectors[i]
+	}
+	return nil
+}
+func (f *fakeVectorStore) Delete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		f.deleted[id] = struct{}{}
+	}
+	return nil
+}
+func (f *fakeVectorStore) Contains(id string) bool { return false }
+func (f *fakeVectorStore) Count() int              { return len(f.AllIDs()) }
+func (f *fakeVectorStore) Save(path string) error  { return nil }
+func (f *fakeVectorStore) Load(path string) error  { return nil }
+func (f *fakeVectorStore) Close() error            { return nil }
+func (f *fakeVectorStore) AllIDs() []string {
+	var out []string
+	for _, id := range f.ids {
+		if _, ok := f.deleted[id]; ok {
+			continue
+		}
+		out = append(out, id)
+	}
+	for id := range f.added {
+		out = append(out, id)
+	}
+	return out
+}
+
+func TestVerifyIndex_FindsMissingAndOrphanIDs(t *testing.T) {
+	metadata := newFakeMetadataStore()
+	metadata.embeddings = map[string][]float32{"a": {1}, "b": {1}}
+
+	vectors := newFakeVectorStore("b", "orphan-vector")
+
+	report, err := VerifyIndex(context.Background(), metadata, &stubBM25Index{ids: []string{"a", "orphan-bm25"}}, vectors, t.TempDir(), VerifyOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"b"}, report.MissingFromBM25)
+	assert.Equal(t, []string{"orphan-bm25"}, report.OrphanBM25IDs)
+	assert.Equal(t, []string{"a"}, report.MissingFromVectors)
+	assert.Equal(t, []string{"orphan-vector"}, report.OrphanVectorIDs)
+	assert.False(t, report.Clean())
+}
+
+func TestVerifyIndex_CleanWhenEverythingMatches(t *testing.T) {
+	metadata := newFakeMetadataStore()
+	metadata.embeddings = map[string][]float32{"a": {1}}
+
+	report, err := VerifyIndex(context.Background(), metadata, &stubBM25Index{ids: []string{"a"}}, newFakeVectorStore("a"), t.TempDir(), VerifyOptions{})
+	require.NoError(t, err)
+	assert.True(t, report.Clean())
+}
+
+func TestVerifyIndex_DimensionMismatch(t *testing.T) {
+	metadata := newFakeMetadataStore()
+	metadata.state[StateKeyIndexDimension] = "768"
+
+	report, err := VerifyIndex(context.Background(), metadata, &stubBM25Index{}, newFakeVectorStore(), t.TempDir(), VerifyOptions{VectorDimensions: 384})
+	require.NoError(t, err)
+	assert.True(t, report.DimensionMismatch)
+	assert.Equal(t, 768, report.RecordedDimensions)
+	assert.Equal(t, 384, report.ConfiguredVectorDim)
+}
+
+func TestVerifyIndex_NoDimensionMismatchWhenUnconfigured(t *testing.T) {
+	metadata := newFakeMetadataStore()
+	metadata.state[StateKeyIndexDimension] = "768"
+
+	report, err := VerifyIndex(context.Background(), metadata, &stubBM25Index{}, newFakeVectorStore(), t.TempDir(), VerifyOptions{})
+	require.NoError(t, err)
+	assert.False(t, report.DimensionMismatch)
+}
+
+func TestVerifyIndex_ChecksumMismatchesAgainstManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bm25.db"), []byte("hello"), 0o644))
+
+	metadata := newFakeMetadataStore()
+	manifest := map[string]string{
+		"bm25.db":      fileChecksumOrFail(t, filepath.Join(dir, "bm25.db")),
+		"vectors.hnsw": "does-not-matter-file-is-missing",
+	}
+
+	report, err := VerifyIndex(context.Background(), metadata, &stubBM25Index{}, newFakeVectorStore(), dir, VerifyOptions{Manifest: manifest})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vectors.hnsw"}, report.ChecksumMismatches)
+}
+
+func TestVerifyIndex_RespectsContextCancellation(t *testing.T) {
+	metadata := newFakeMetadataStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := VerifyIndex(ctx, metadata, &stubBM25Index{}, newFakeVectorStore(), t.TempDir(), VerifyOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRepairIndex_DeletesOrphansAndReembedsMissing(t *testing.T) {
+	bm25 := &stubBM25Index{ids: []string{"orphan"}}
+	vectors := newFakeVectorStore("orphan-vec")
+
+	report := &VerifyReport{
+		OrphanBM25IDs:      []string{"orphan"},
+		OrphanVectorIDs:    []string{"orphan-vec"},
+		MissingFromBM25:    []string{"a"},
+		MissingFromVectors: []string{"a", "b"},
+	}
+
+	reembedCalls := 0
+	reembed := func(ctx context.Context, chunkIDs []string) ([]*Document, [][]float32, error) {
+		reembedCalls++
+		assert.ElementsMatch(t, []string{"a", "b"}, chunkIDs)
+		return []*Document{{ID: "a", Content: "alpha"}, {ID: "b", Content: "beta"}},
+			[][]float32{{1, 0}, {0, 1}}, nil
+	}
+
+	require.NoError(t, RepairIndex(context.Background(), bm25, vectors, report, reembed))
+
+	assert.Equal(t, 1, reembedCalls)
+	assert.True(t, bm25.deleted["orphan"])
+	assert.Len(t, bm25.indexed, 1)
+	assert.Equal(t, "a", bm25.indexed[0].ID)
+	assert.Contains(t, vectors.deleted, "orphan-vec")
+	assert.Contains(t, vectors.added, "a")
+	assert.Contains(t, vectors.added, "b")
+}
+
+func TestRepairIndex_NoOpWhenReportIsClean(t *testing.T) {
+	bm25 := &stubBM25Index{}
+	vectors := newFakeVectorStore()
+
+	called := false
+	reembed := func(ctx context.Context, chunkIDs []string) ([]*Document, [][]float32, error) {
+		called = true
+		return nil, nil, nil
+	}
+
+	require.NoError(t, RepairIndex(context.Background(), bm25, vectors, &VerifyReport{}, reembed))
+	assert.False(t, called, "reembed should not be called when nothing is missing")
+}
+
+func fileChecksumOrFail(t *testing.T, path string) string {
+	t.Helper()
+	sum, err := fileChecksum(path)
+	require.NoError(t, err)
+	return sum
+}
+
+// stubBM25Index is a minimal store.BM25Index stand-in that also tracks
+// Delete/Index calls, for asserting RepairIndex's effects.
+type stubBM25Index struct {
+	ids     []string
+	deleted map[string]bool
+	indexed []*Document
+}
+
+func (s *stubBM25Index) Search(ctx context.Context, query string, limit int) ([]*BM25Result, error) {
+	return nil, nil
+}
+func (s *stubBM25Index) Index(ctx context.Context, docs []*Document) error {
+	s.indexed = append(s.indexed, docs...)
+	return nil
+}
+func (s *stubBM25Index) Delete(ctx context.Context, docIDs []string) error {
+	if s.deleted == nil {
+		s.deleted = make(map[string]bool)
+	}
+	for _, id := range docIDs {
+		s.deleted[id] = true
+	}
+	return nil
+}
+func (s *stubBM25Index) AllIDs() ([]string, error) { return s.ids, nil }
+func (s *stubBM25Index) Stats() *IndexStats        { return &IndexStats{} }
+func (s *stubBM25Index) Save(path string) error    { return nil }
+func (s *stubBM25Index) Load(path string) error    { return nil }
+func (s *stubBM25Index) Close() error              { return nil }
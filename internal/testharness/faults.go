@@ -0,0 +1,276 @@
+package testharness
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// FaultyMetadataStore wraps a store.MetadataStore and fails a fraction of
+// its write calls with a syscall.EIO-wrapped error, simulating a
+// filesystem that returns I/O errors mid-write. Reads always pass
+// through, since the harness needs them to still work in order to check
+// invariants after a scenario.
+type FaultyMetadataStore struct {
+	base store.MetadataStore
+
+	mu              sync.Mutex
+	rng             *rand.Rand
+	failProbability float64
+}
+
+// NewFaultyMetadataStore wraps base so that writes fail with probability
+// failProbability, using rng for the per-call coin flip. rng is not used
+// concurrently by anything else, since FaultyMetadataStore keeps its own
+// lock around every draw.
+func NewFaultyMetadataStore(base store.MetadataStore, rng *rand.Rand, failProbability float64) *FaultyMetadataStore {
+	return &FaultyMetadataStore{base: base, rng: rng, failProbability: failProbability}
+}
+
+// fail rolls the dice for op and, if it comes up faulty, returns a
+// syscall.EIO-wrapped error matching what a real failing write would
+// surface to the caller.
+func (f *FaultyMetadataStore) fail(op string) error {
+	f.mu.Lock()
+	roll := f.rng.Float64()
+	f.mu.Unlock()
+	if roll >= f.failProbability {
+		return nil
+	}
+	return &os.PathError{Op: op, Path: "metadata.db", Err: syscall.EIO}
+}
+
+func (f *FaultyMetadataStore) GetChunk(ctx context.Context, id string) (*store.Chunk, error) {
+	return f.base.GetChunk(ctx, id)
+}
+func (f *FaultyMetadataStore) GetChunks(ctx context.Context, ids []string) ([]*store.Chunk, error) {
+	return f.base.GetChunks(ctx, ids)
+}
+func (f *FaultyMetadataStore) SaveProject(ctx context.Context, p *store.Project) error {
+	if err := f.fail("SaveProject"); err != nil {
+		return err
+	}
+	return f.base.SaveProject(ctx, p)
+}
+func (f *FaultyMetadataStore) GetProject(ctx context.Context, id string) (*store.Project, error) {
+	return f.base.GetProject(ctx, id)
+}
+func (f *FaultyMetadataStore) UpdateProjectStats(ctx context.Context, id string, fileCount, chunkCount int) error {
+	if err := f.fail("UpdateProjectStats"); err != nil {
+		return err
+	}
+	return f.base.UpdateProjectStats(ctx, id, fileCount, chunkCount)
+}
+func (f *FaultyMetadataStore) RefreshProjectStats(ctx context.Context, id string) error {
+	if err := f.fail("RefreshProjectStats"); err != nil {
+		return err
+	}
+	return f.base.RefreshProjectStats(ctx, id)
+}
+func (f *FaultyMetadataStore) SaveFiles(ctx context.Context, files []*store.File) error {
+	if err := f.fail("SaveFiles"); err != nil {
+		return err
+	}
+	return f.base.SaveFiles(ctx, files)
+}
+func (f *FaultyMetadataStore) GetFileByPath(ctx context.Context, projectID, path string) (*store.File, error) {
+	return f.base.GetFileByPath(ctx, projectID, path)
+}
+func (f *FaultyMetadataStore) GetChangedFiles(ctx context.Context, projectID string, since time.Time) ([]*store.File, error) {
+	return f.base.GetChangedFiles(ctx, projectID, since)
+}
+func (f *FaultyMetadataStore) DeleteFilesByProject(ctx context.Context, projectID string) error {
+	if err := f.fail("DeleteFilesByProject"); err != nil {
+		return err
+	}
+	return f.base.DeleteFilesByProject(ctx, projectID)
+}
+func (f *FaultyMetadataStore) SaveChunks(ctx context.Context, chunks []*store.Chunk) error {
+	if err := f.fail("SaveChunks"); err != nil {
+		return err
+	}
+	return f.base.SaveChunks(ctx, chunks)
+}
+func (f *FaultyMetadataStore) GetChunksByFile(ctx context.Context, fileID string) ([]*store.Chunk, error) {
+	return f.base.GetChunksByFile(ctx, fileID)
+}
+func (f *FaultyMetadataStore) DeleteChunks(ctx context.Context, ids []string) error {
+	if err := f.fail("DeleteChunks"); err != nil {
+		return err
+	}
+	return f.base.DeleteChunks(ctx, ids)
+}
+func (f *FaultyMetadataStore) DeleteChunksByFile(ctx context.Context, fileID string) error {
+	if err := f.fail("DeleteChunksByFile"); err != nil {
+		return err
+	}
+	return f.base.DeleteChunksByFile(ctx, fileID)
+}
+func (f *FaultyMetadataStore) SearchSymbols(ctx context.Context, query string, limit int) ([]*store.Symbol, error) {
+	return f.base.SearchSymbols(ctx, query, limit)
+}
+func (f *FaultyMetadataStore) ListFiles(ctx context.Context, projectID, cursor string, limit int) ([]*store.File, string, error) {
+	return f.base.ListFiles(ctx, projectID, cursor, limit)
+}
+func (f *FaultyMetadataStore) GetFilePathsByProject(ctx context.Context, projectID string) ([]string, error) {
+	return f.base.GetFilePathsByProject(ctx, projectID)
+}
+func (f *FaultyMetadataStore) GetFilesForReconciliation(ctx context.Context, projectID string) (map[string]*store.File, error) {
+	return f.base.GetFilesForReconciliation(ctx, projectID)
+}
+func (f *FaultyMetadataStore) ListFilePathsUnder(ctx context.Context, projectID, dir string) ([]string, error) {
+	return f.base.ListFilePathsUnder(ctx, projectID, dir)
+}
+func (f *FaultyMetadataStore) DeleteFile(ctx context.Context, fileID string) error {
+	if err := f.fail("DeleteFile"); err != nil {
+		return err
+	}
+	return f.base.DeleteFile(ctx, fileID)
+}
+func (f *FaultyMetadataStore) GetState(ctx context.Context, key string) (string, error) {
+	return f.base.GetState(ctx, key)
+}
+func (f *FaultyMetadataStore) SetState(ctx context.Context, key, value string) error {
+	if err := f.fail("SetState"); err != nil {
+		return err
+	}
+	return f.base.SetState(ctx, key, value)
+}
+func (f *FaultyMetadataStore) SaveChunkEmbeddings(ctx context.Context, ids []string, embeddings [][]float32, model string) error {
+	if err := f.fail("SaveChunkEmbeddings"); err != nil {
+		return err
+	}
+	return f.base.SaveChunkEmbeddings(ctx, ids, embeddings, model)
+}
+func (f *FaultyMetadataStore) GetAllEmbeddings(ctx context.Context) (map[string][]float32, error) {
+	return f.base.GetAllEmbeddings(ctx)
+}
+func (f *FaultyMetadataStore) GetEmbeddingStats(ctx context.Context) (int, int, error) {
+	return f.base.GetEmbeddingStats(ctx)
+}
+func (f *FaultyMetadataStore) SaveIndexCheckpoint(ctx context.Context, stage string, total, embeddedCount int, embedderModel string) error {
+	if err := f.fail("SaveIndexCheckpoint"); err != nil {
+		return err
+	}
+	return f.base.SaveIndexCheckpoint(ctx, stage, total, embeddedCount, embedderModel)
+}
+func (f *FaultyMetadataStore) LoadIndexCheckpoint(ctx context.Context) (*store.IndexCheckpoint, error) {
+	return f.base.LoadIndexCheckpoint(ctx)
+}
+func (f *FaultyMetadataStore) ClearIndexCheckpoint(ctx context.Context) error {
+	if err := f.fail("ClearIndexCheckpoint"); err != nil {
+		return err
+	}
+	return f.base.ClearIndexCheckpoint(ctx)
+}
+func (f *FaultyMetadataStore) Close() error {
+	return f.base.Close()
+}
+
+// FaultyEmbedder wraps an embed.Embedder, injecting timeouts and
+// wrong-dimension responses on a fraction of Embed/EmbedBatch calls. If
+// base is nil, a tiny fixed-dimension stand-in is used so the harness is
+// usable without a real embedding backend.
+type FaultyEmbedder struct {
+	base embed.Embedder
+
+	mu                  sync.Mutex
+	rng                 *rand.Rand
+	timeoutProbability  float64
+	wrongDimProbability float64
+}
+
+// NewFaultyEmbedder wraps base (or a built-in stand-in if base is nil)
+// with the given fault rates, drawing from rng for each call.
+func NewFaultyEmbedder(base embed.Embedder, rng *rand.Rand, timeoutProbability, wrongDimProbability float64) *FaultyEmbedder {
+	if base == nil {
+		base = noopEmbedder{}
+	}
+	return &FaultyEmbedder{base: base, rng: rng, timeoutProbability: timeoutProbability, wrongDimProbability: wrongDimProbability}
+}
+
+// roll draws the next fault decision: (timeout, wrongDimension).
+func (f *FaultyEmbedder) roll() (timeout, wrongDim bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := f.rng.Float64()
+	if r < f.timeoutProbability {
+		return true, false
+	}
+	if r < f.timeoutProbability+f.wrongDimProbability {
+		return false, true
+	}
+	return false, false
+}
+
+func (f *FaultyEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	timeout, wrongDim := f.roll()
+	if timeout {
+		return nil, context.DeadlineExceeded
+	}
+	vec, err := f.base.Embed(ctx, text)
+	if err != nil || !wrongDim {
+		return vec, err
+	}
+	return vec[:len(vec)/2], nil
+}
+
+func (f *FaultyEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		vec, err := f.Embed(ctx, t)
+		if err != nil {
+			return nil, fmt.Errorf("embed %d/%d: %w", i+1, len(texts), err)
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func (f *FaultyEmbedder) Dimensions() int                    { return f.base.Dimensions() }
+func (f *FaultyEmbedder) ModelName() string                  { return f.base.ModelName() }
+func (f *FaultyEmbedder) Available(ctx context.Context) bool { return f.base.Available(ctx) }
+func (f *FaultyEmbedder) Close() error                       { return f.base.Close() }
+func (f *FaultyEmbedder) SetBatchIndex(i int)                { f.base.SetBatchIndex(i) }
+func (f *FaultyEmbedder) SetFinalBatch(final bool)           { f.base.SetFinalBatch(final) }
+
+// Throttled forwards to base when it supports embed.Throttleable, so
+// wrapping a throttle-aware backend in FaultyEmbedder doesn't hide its
+// backpressure signal; backends that don't support it are never
+// throttled.
+func (f *FaultyEmbedder) Throttled(ctx context.Context) bool {
+	if t, ok := f.base.(embed.Throttleable); ok {
+		return t.Throttled(ctx)
+	}
+	return false
+}
+
+// noopEmbedder is the default embed.Embedder used when a harness caller
+// doesn't supply one - it always succeeds with a fixed-size zero vector,
+// so the embedder-fault scenarios exercise FaultyEmbedder's own injected
+// failures rather than a real backend's.
+type noopEmbedder struct{}
+
+func (noopEmbedder) Embed(context.Context, string) ([]float32, error) {
+	return make([]float32, 768), nil
+}
+func (n noopEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i], _ = n.Embed(ctx, texts[i])
+	}
+	return out, nil
+}
+func (noopEmbedder) Dimensions() int                { return 768 }
+func (noopEmbedder) ModelName() string              { return "testharness-noop" }
+func (noopEmbedder) Available(context.Context) bool { return true }
+func (noopEmbedder) Close() error                   { return nil }
+func (noopEmbedder) SetBatchIndex(int)              {}
+func (noopEmbedder) SetFinalBatch(bool)             {}
@@ -0,0 +1,42 @@
+package testharness
+
+import (
+	"fmt"
+	"os"
+)
+
+// CheckCounts reports a violation if the BM25, vector, and metadata
+// stores disagree on how many chunks are indexed. A real pipeline keeps
+// all three in lockstep; any drift here means an event was applied to one
+// store but not the others - exactly the kind of partial write a dropped
+// event, a mid-transaction failure, or a timed-out embed call produces.
+func CheckCounts(c IndexCounts) []string {
+	if c.BM25Docs == c.VectorCount && c.BM25Docs == c.MetadataChunks {
+		return nil
+	}
+	return []string{fmt.Sprintf("index counts disagree: bm25=%d vector=%d metadata=%d",
+		c.BM25Docs, c.VectorCount, c.MetadataChunks)}
+}
+
+// CheckGoroutineLeak reports a violation if the goroutine count grew
+// beyond a small tolerance after a scenario tore its pipeline down.
+// Tolerance exists because the Go runtime and test harness itself keep a
+// small, variable number of background goroutines alive regardless of the
+// pipeline under test.
+func CheckGoroutineLeak(before, after int) []string {
+	const tolerance = 2
+	if after <= before+tolerance {
+		return nil
+	}
+	return []string{fmt.Sprintf("goroutine count grew from %d to %d after teardown (leak suspected)", before, after)}
+}
+
+// CheckPIDFileRemoved reports a violation if path still exists after the
+// pipeline was stopped. A serve.pid left behind after a clean shutdown
+// blocks the next 'amanmcp serve' from starting.
+func CheckPIDFileRemoved(path string) []string {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	return []string{fmt.Sprintf("PID file %s was not removed on shutdown", path)}
+}
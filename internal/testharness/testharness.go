@@ -0,0 +1,299 @@
+// Package testharness drives the watcher/coordinator pipeline under
+// simulated adversarial conditions - event bursts, a flaky metadata store,
+// a flaky embedder, and a watcher that silently drops events - and checks
+// that the pipeline's invariants hold afterward. It exists because
+// BUG-017, BUG-033, BUG-035, and BUG-054 (see cmd/amanmcp/cmd/serve.go)
+// all trace back to races between the watcher, the coordinator, and
+// startup reconciliation that were only ever caught in production; this
+// package lets those conditions be reproduced on demand with a fixed seed.
+//
+// The package only owns the fault injection and invariant checking. It has
+// no opinion on how the pipeline itself is wired together, since that
+// wiring (internal/index.Coordinator, internal/watcher.Watcher) lives in
+// cmd/amanmcp/cmd and is largely unexported. Callers supply a
+// PipelineStarter that builds and starts their own pipeline against the
+// (possibly fault-wrapped) metadata store and embedder RunFuzz hands it.
+package testharness
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/Aman-CERP/amanmcp/internal/embed"
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// FaultProbabilities controls how often each injected component misbehaves.
+// Each field is a probability in [0, 1] applied independently per call.
+type FaultProbabilities struct {
+	// EIOProbability fails a metadata write with a syscall.EIO-wrapped
+	// error, simulating a filesystem that returns I/O errors mid-write.
+	EIOProbability float64
+	// DropEventProbability silently drops a batch from the watcher's
+	// event channel before the coordinator ever sees it.
+	DropEventProbability float64
+	// EmbedderTimeoutProbability fails an embed call with
+	// context.DeadlineExceeded, simulating a stalled embedding endpoint.
+	EmbedderTimeoutProbability float64
+	// WrongDimensionProbability returns a vector of the wrong length
+	// without an error, simulating a misconfigured or swapped endpoint.
+	WrongDimensionProbability float64
+}
+
+// DefaultFaultProbabilities returns a moderate fault rate suitable for a
+// quick regression run; Scenarios crank individual axes higher.
+func DefaultFaultProbabilities() FaultProbabilities {
+	return FaultProbabilities{
+		EIOProbability:             0.01,
+		DropEventProbability:       0.02,
+		EmbedderTimeoutProbability: 0.01,
+		WrongDimensionProbability:  0.01,
+	}
+}
+
+// Config configures a RunFuzz run.
+type Config struct {
+	// Seed makes the whole run, including which scenarios misbehave and
+	// when, reproducible. The zero value is a valid seed.
+	Seed int64
+	// Root is the directory the harness churns files in to generate
+	// watcher events. A temp directory is created if Root is empty.
+	Root string
+	// DataDir is where the pipeline's on-disk index state (PID file,
+	// metadata.db, etc.) lives. Defaults to filepath.Join(Root, ".amanmcp").
+	DataDir string
+	// EventBurstSize is how many create/rename/delete operations the
+	// burst scenario performs in a tight loop.
+	EventBurstSize int
+	// SettleTimeout is how long RunFuzz waits after the burst for the
+	// pipeline to drain before checking invariants.
+	SettleTimeout time.Duration
+	// BaseEmbedder is wrapped with fault injection for the embedder
+	// scenario. A harness caller typically passes a cheap/mock embedder
+	// here rather than a real network-backed one.
+	BaseEmbedder embed.Embedder
+}
+
+// WithDefaults fills zero-valued fields with their defaults, matching the
+// repo's Options.WithDefaults convention elsewhere (e.g. watcher.Options).
+func (c Config) WithDefaults() Config {
+	if c.EventBurstSize == 0 {
+		c.EventBurstSize = 4000
+	}
+	if c.SettleTimeout == 0 {
+		c.SettleTimeout = 5 * time.Second
+	}
+	return c
+}
+
+// IndexCounts snapshots how many documents each of the three stores
+// behind a pipeline believes it holds. The stop func a PipelineStarter
+// returns takes this snapshot right after teardown, so RunFuzz can check
+// the three agree without needing to know anything about how the
+// caller's coordinator/BM25/vector stores are built.
+type IndexCounts struct {
+	BM25Docs       int
+	VectorCount    int
+	MetadataChunks int
+}
+
+// PipelineStarter builds and starts one run of the watcher/coordinator
+// pipeline (the caller's equivalent of startFileWatcher) against root and
+// metadata/embedder, which may be fault-wrapped. rng is the scenario's
+// own seeded source, shared with FaultyMetadataStore/FaultyEmbedder, so a
+// caller that also wraps its watcher with FaultyWatcher keeps the whole
+// scenario reproducible from one seed. It returns a stop func the harness
+// calls once the scenario's burst has settled; stop tears the pipeline
+// down and reports the final document counts for the consistency check.
+type PipelineStarter func(ctx context.Context, root, dataDir string, metadata store.MetadataStore, embedder embed.Embedder, rng *rand.Rand, faults FaultProbabilities) (stop func() IndexCounts, err error)
+
+// ScenarioResult is one scenario's pass/fail outcome and the invariant
+// violations that caused a failure, if any.
+type ScenarioResult struct {
+	Name       string
+	Passed     bool
+	Violations []string
+	Duration   time.Duration
+	EventsSent int
+}
+
+// Report is the outcome of a full RunFuzz run: every scenario's result
+// under the same seed, so a failure can be reproduced by rerunning with
+// that seed alone.
+type Report struct {
+	Seed      int64
+	Scenarios []ScenarioResult
+}
+
+// Passed reports whether every scenario in the run passed.
+func (r *Report) Passed() bool {
+	for _, s := range r.Scenarios {
+		if !s.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders a human-readable summary, one line per scenario plus its
+// violations, for use in CI logs and the fuzz-watcher CLI command.
+func (r *Report) String() string {
+	out := fmt.Sprintf("fuzz-watcher report (seed=%d)\n", r.Seed)
+	for _, s := range r.Scenarios {
+		status := "PASS"
+		if !s.Passed {
+			status = "FAIL"
+		}
+		out += fmt.Sprintf("  [%s] %-24s events=%-6d duration=%s\n", status, s.Name, s.EventsSent, s.Duration.Round(time.Millisecond))
+		for _, v := range s.Violations {
+			out += fmt.Sprintf("          - %s\n", v)
+		}
+	}
+	return out
+}
+
+// RunFuzz runs every built-in scenario in turn against a fresh fault
+// configuration, using start to wire up the pipeline each time. It always
+// runs every scenario, even after a failure, so a single run's report
+// covers every fault axis.
+func RunFuzz(ctx context.Context, cfg Config, start PipelineStarter) (*Report, error) {
+	cfg = cfg.WithDefaults()
+
+	root := cfg.Root
+	if root == "" {
+		dir, err := os.MkdirTemp("", "amanmcp-fuzz-watcher-*")
+		if err != nil {
+			return nil, fmt.Errorf("create scratch root: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+		root = dir
+	}
+	dataDir := cfg.DataDir
+	if dataDir == "" {
+		dataDir = filepath.Join(root, ".amanmcp")
+	}
+
+	scenarios := []struct {
+		name  string
+		setup func(rng *rand.Rand) FaultProbabilities
+	}{
+		{"event-burst", func(*rand.Rand) FaultProbabilities { return FaultProbabilities{} }},
+		{"metadata-eio", func(*rand.Rand) FaultProbabilities { return FaultProbabilities{EIOProbability: 0.25} }},
+		{"dropped-events", func(*rand.Rand) FaultProbabilities { return FaultProbabilities{DropEventProbability: 0.3} }},
+		{"embedder-timeout", func(*rand.Rand) FaultProbabilities { return FaultProbabilities{EmbedderTimeoutProbability: 0.25} }},
+		{"embedder-wrong-dimension", func(*rand.Rand) FaultProbabilities { return FaultProbabilities{WrongDimensionProbability: 0.25} }},
+	}
+
+	report := &Report{Seed: cfg.Seed}
+	for i, s := range scenarios {
+		rng := scenarioRand(cfg.Seed, s.name)
+		result := runScenario(ctx, cfg, root, filepath.Join(dataDir, fmt.Sprintf("scenario-%d", i)), s.name, s.setup(rng), rng, start)
+		report.Scenarios = append(report.Scenarios, result)
+	}
+	return report, nil
+}
+
+// scenarioRand derives a scenario's RNG from the run seed and its name, so
+// each scenario is independently reproducible regardless of what order
+// scenarios run in or how many random draws earlier scenarios made.
+func scenarioRand(seed int64, name string) *rand.Rand {
+	h := int64(2166136261)
+	for _, b := range []byte(name) {
+		h = (h ^ int64(b)) * 16777619
+	}
+	return rand.New(rand.NewSource(seed ^ h))
+}
+
+// runScenario churns cfg.EventBurstSize file operations through a
+// fault-wrapped metadata store and embedder, then checks invariants once
+// the pipeline has had SettleTimeout to drain.
+func runScenario(ctx context.Context, cfg Config, root, dataDir string, name string, faults FaultProbabilities, rng *rand.Rand, start PipelineStarter) ScenarioResult {
+	result := ScenarioResult{Name: name}
+	startTime := time.Now()
+	defer func() { result.Duration = time.Since(startTime) }()
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		result.Violations = append(result.Violations, fmt.Sprintf("create data dir: %v", err))
+		return result
+	}
+
+	baseMetadata, err := store.NewSQLiteStore(filepath.Join(dataDir, "metadata.db"))
+	if err != nil {
+		result.Violations = append(result.Violations, fmt.Sprintf("open metadata store: %v", err))
+		return result
+	}
+	defer func() { _ = baseMetadata.Close() }()
+
+	metadata := NewFaultyMetadataStore(baseMetadata, rng, faults.EIOProbability)
+	embedder := NewFaultyEmbedder(cfg.BaseEmbedder, rng, faults.EmbedderTimeoutProbability, faults.WrongDimensionProbability)
+
+	goroutinesBefore := runtime.NumGoroutine()
+
+	stop, err := start(ctx, root, dataDir, metadata, embedder, rng, faults)
+	if err != nil {
+		result.Violations = append(result.Violations, fmt.Sprintf("start pipeline: %v", err))
+		return result
+	}
+
+	result.EventsSent = churnFiles(root, cfg.EventBurstSize, rng)
+
+	settleCtx, cancel := context.WithTimeout(ctx, cfg.SettleTimeout)
+	<-settleCtx.Done()
+	cancel()
+
+	counts := stop()
+
+	// Give the pipeline's own goroutines a moment to unwind after stop()
+	// returns, so the leak check below isn't racing their teardown.
+	time.Sleep(100 * time.Millisecond)
+
+	result.Violations = append(result.Violations, CheckCounts(counts)...)
+	result.Violations = append(result.Violations, CheckGoroutineLeak(goroutinesBefore, runtime.NumGoroutine())...)
+	result.Violations = append(result.Violations, CheckPIDFileRemoved(filepath.Join(dataDir, "serve.pid"))...)
+
+	result.Passed = len(result.Violations) == 0
+	return result
+}
+
+// churnFiles performs n create/rename/delete operations under root in a
+// random order, returning the number of operations actually performed.
+// This generates real filesystem events for a real watcher to observe,
+// rather than fabricating synthetic event values.
+func churnFiles(root string, n int, rng *rand.Rand) int {
+	performed := 0
+	var live []string
+	for i := 0; i < n; i++ {
+		op := rng.Intn(3)
+		if len(live) == 0 || op == 0 {
+			path := filepath.Join(root, fmt.Sprintf("fuzz-%d-%d.go", time.Now().UnixNano(), i))
+			if err := os.WriteFile(path, []byte("package fuzz\n"), 0o644); err != nil {
+				continue
+			}
+			live = append(live, path)
+			performed++
+			continue
+		}
+		idx := rng.Intn(len(live))
+		path := live[idx]
+		if op == 1 {
+			newPath := path + ".renamed"
+			if err := os.Rename(path, newPath); err != nil {
+				continue
+			}
+			live[idx] = newPath
+			performed++
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			continue
+		}
+		live = append(live[:idx], live[idx+1:]...)
+		performed++
+	}
+	return performed
+}
@@ -0,0 +1,72 @@
+package testharness
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenarioRand_DeterministicPerSeed(t *testing.T) {
+	a := scenarioRand(42, "dropped-events")
+	b := scenarioRand(42, "dropped-events")
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, a.Float64(), b.Float64())
+	}
+}
+
+func TestScenarioRand_DiffersByName(t *testing.T) {
+	a := scenarioRand(42, "dropped-events")
+	b := scenarioRand(42, "metadata-eio")
+	assert.NotEqual(t, a.Float64(), b.Float64())
+}
+
+func TestCheckCounts(t *testing.T) {
+	assert.Empty(t, CheckCounts(IndexCounts{BM25Docs: 5, VectorCount: 5, MetadataChunks: 5}))
+	assert.NotEmpty(t, CheckCounts(IndexCounts{BM25Docs: 5, VectorCount: 4, MetadataChunks: 5}))
+}
+
+func TestCheckGoroutineLeak(t *testing.T) {
+	assert.Empty(t, CheckGoroutineLeak(10, 11))
+	assert.NotEmpty(t, CheckGoroutineLeak(10, 20))
+}
+
+func TestCheckPIDFileRemoved(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "serve.pid")
+
+	assert.Empty(t, CheckPIDFileRemoved(path))
+
+	require.NoError(t, os.WriteFile(path, []byte("1234"), 0o644))
+	assert.NotEmpty(t, CheckPIDFileRemoved(path))
+}
+
+func TestFaultyEmbedder_InjectsTimeout(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	e := NewFaultyEmbedder(nil, rng, 1.0, 0)
+
+	_, err := e.Embed(context.Background(), "hello")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFaultyEmbedder_InjectsWrongDimension(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	e := NewFaultyEmbedder(nil, rng, 0, 1.0)
+
+	vec, err := e.Embed(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.NotEqual(t, e.Dimensions(), len(vec))
+}
+
+func TestFaultyEmbedder_PassesThroughWithNoFaults(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	e := NewFaultyEmbedder(nil, rng, 0, 0)
+
+	vec, err := e.Embed(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, e.Dimensions(), len(vec))
+}
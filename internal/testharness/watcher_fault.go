@@ -0,0 +1,96 @@
+package testharness
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"github.com/Aman-CERP/amanmcp/internal/watcher"
+)
+
+// FaultyWatcher wraps a watcher.Watcher and silently drops a fraction of
+// its event batches before the coordinator ever sees them, simulating a
+// watcher backend (e.g. an overloaded inotify queue) that misses changes
+// under load rather than reporting an error for them.
+type FaultyWatcher struct {
+	base watcher.Watcher
+
+	mu              sync.Mutex
+	rng             *rand.Rand
+	dropProbability float64
+
+	events chan []watcher.Event
+	errors chan error
+	done   chan struct{}
+}
+
+// NewFaultyWatcher wraps base so that, once started, a fraction of its
+// event batches (chosen by dropProbability) never reach Events().
+func NewFaultyWatcher(base watcher.Watcher, rng *rand.Rand, dropProbability float64) *FaultyWatcher {
+	return &FaultyWatcher{
+		base:            base,
+		rng:             rng,
+		dropProbability: dropProbability,
+		events:          make(chan []watcher.Event, 1),
+		errors:          make(chan error, 1),
+		done:            make(chan struct{}),
+	}
+}
+
+func (f *FaultyWatcher) shouldDrop() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rng.Float64() < f.dropProbability
+}
+
+// Start starts the wrapped watcher and begins forwarding its channels,
+// dropping event batches per dropProbability. It returns once the
+// underlying Start call returns, same as watcher.Watcher.
+func (f *FaultyWatcher) Start(ctx context.Context, root string) error {
+	go f.forward()
+	return f.base.Start(ctx, root)
+}
+
+// forward relays the base watcher's channels into f's own, dropping
+// event batches as instructed and closing both outputs once the base
+// watcher's channels close or f is stopped.
+func (f *FaultyWatcher) forward() {
+	defer close(f.events)
+	defer close(f.errors)
+	for {
+		select {
+		case <-f.done:
+			return
+		case batch, ok := <-f.base.Events():
+			if !ok {
+				return
+			}
+			if f.shouldDrop() {
+				continue
+			}
+			select {
+			case f.events <- batch:
+			case <-f.done:
+				return
+			}
+		case err, ok := <-f.base.Errors():
+			if !ok {
+				return
+			}
+			select {
+			case f.errors <- err:
+			case <-f.done:
+				return
+			}
+		}
+	}
+}
+
+func (f *FaultyWatcher) Stop() error {
+	close(f.done)
+	return f.base.Stop()
+}
+
+func (f *FaultyWatcher) Events() <-chan []watcher.Event { return f.events }
+func (f *FaultyWatcher) Errors() <-chan error           { return f.errors }
+func (f *FaultyWatcher) WatcherType() string            { return f.base.WatcherType() }
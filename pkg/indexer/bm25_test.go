@@ -16,19 +16,21 @@ import (
 // MockBM25Store implements store.BM25Index for testing.
 // Uses function pointers for behavior injection.
 type MockBM25Store struct {
-	IndexFn   func(ctx context.Context, docs []*store.Document) error
-	SearchFn  func(ctx context.Context, query string, limit int) ([]*store.BM25Result, error)
-	DeleteFn  func(ctx context.Context, docIDs []string) error
-	AllIDsFn  func() ([]string, error)
-	StatsFn   func() *store.IndexStats
-	SaveFn    func(path string) error
-	LoadFn    func(path string) error
-	CloseFn   func() error
+	IndexFn    func(ctx context.Context, docs []*store.Document) error
+	SearchFn   func(ctx context.Context, query string, limit int) ([]*store.BM25Result, error)
+	DeleteFn   func(ctx context.Context, docIDs []string) error
+	AllIDsFn   func() ([]string, error)
+	StatsFn    func() *store.IndexStats
+	SaveFn     func(path string) error
+	LoadFn     func(path string) error
+	CloseFn    func() error
+	OptimizeFn func(ctx context.Context, opts store.OptimizeOptions) (*store.OptimizeStats, error)
 
 	// Call tracking
-	indexCalled  atomic.Int32
-	deleteCalled atomic.Int32
-	closeCalled  atomic.Int32
+	indexCalled    atomic.Int32
+	deleteCalled   atomic.Int32
+	closeCalled    atomic.Int32
+	optimizeCalled atomic.Int32
 }
 
 func (m *MockBM25Store) Index(ctx context.Context, docs []*store.Document) error {
@@ -94,8 +96,20 @@ func (m *MockBM25Store) Close() error {
 	return nil
 }
 
-// Ensure MockBM25Store implements store.BM25Index
+// Optimize implements store.Optimizable, so tests can exercise
+// store.OptimizeIndex's delegating branch against a MockBM25Store the
+// same way production code would against a real backend.
+func (m *MockBM25Store) Optimize(ctx context.Context, opts store.OptimizeOptions) (*store.OptimizeStats, error) {
+	m.optimizeCalled.Add(1)
+	if m.OptimizeFn != nil {
+		return m.OptimizeFn(ctx, opts)
+	}
+	return &store.OptimizeStats{}, nil
+}
+
+// Ensure MockBM25Store implements store.BM25Index and store.Optimizable
 var _ store.BM25Index = (*MockBM25Store)(nil)
+var _ store.Optimizable = (*MockBM25Store)(nil)
 
 // =============================================================================
 // Constructor Tests
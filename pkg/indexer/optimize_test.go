@@ -0,0 +1,215 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aman-CERP/amanmcp/internal/store"
+)
+
+// simulatedOptimizableStore is a real (not function-pointer-stubbed)
+// store.BM25Index + store.Optimizable double: it keeps an in-memory doc
+// set, and Optimize rebuilds that set by keeping only opts.LiveIDs,
+// checking ctx between documents the same way a real VACUUM INTO/Bleve
+// merge pass would check for cancellation between batches. It only
+// mutates its live docs after the whole rebuild succeeds, so a
+// cancellation partway through leaves the original set completely
+// untouched - exactly the guarantee store.Optimizable's doc comment
+// requires.
+type simulatedOptimizableStore struct {
+	mu   sync.Mutex
+	docs map[string]*store.Document
+	// bytesPerDoc is a fixed per-document size used to compute
+	// ReclaimedBytes deterministically for assertions.
+	bytesPerDoc int64
+}
+
+func newSimulatedOptimizableStore(docs ...*store.Document) *simulatedOptimizableStore {
+	s := &simulatedOptimizableStore{docs: make(map[string]*store.Document), bytesPerDoc: 100}
+	for _, d := range docs {
+		s.docs[d.ID] = d
+	}
+	return s
+}
+
+func (s *simulatedOptimizableStore) Index(ctx context.Context, docs []*store.Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range docs {
+		s.docs[d.ID] = d
+	}
+	return nil
+}
+
+func (s *simulatedOptimizableStore) Delete(ctx context.Context, docIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range docIDs {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+func (s *simulatedOptimizableStore) Search(ctx context.Context, query string, limit int) ([]*store.BM25Result, error) {
+	return nil, nil
+}
+
+func (s *simulatedOptimizableStore) AllIDs() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.docs))
+	for id := range s.docs {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *simulatedOptimizableStore) Stats() *store.IndexStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &store.IndexStats{DocumentCount: len(s.docs)}
+}
+
+func (s *simulatedOptimizableStore) Save(path string) error { return nil }
+func (s *simulatedOptimizableStore) Load(path string) error { return nil }
+func (s *simulatedOptimizableStore) Close() error           { return nil }
+
+// Optimize rebuilds s.docs into a fresh map containing only the IDs
+// opts.LiveIDs allows (every current ID, if opts.LiveIDs is nil),
+// checking ctx.Err() between each document so a long-running optimize
+// over a large index can be cancelled. The rebuilt set only replaces
+// s.docs once it's complete - a cancellation error leaves s.docs exactly
+// as it was.
+func (s *simulatedOptimizableStore) Optimize(ctx context.Context, opts store.OptimizeOptions) (*store.OptimizeStats, error) {
+	s.mu.Lock()
+	original := s.docs
+	s.mu.Unlock()
+
+	rebuilt := make(map[string]*store.Document, len(original))
+	var removed int
+	for id, doc := range original {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if opts.LiveIDs != nil {
+			if _, live := opts.LiveIDs[id]; !live {
+				removed++
+				continue
+			}
+		}
+		rebuilt[id] = doc
+	}
+
+	stats := &store.OptimizeStats{
+		RemovedDocs:    removed,
+		ReclaimedBytes: int64(removed) * s.bytesPerDoc,
+	}
+	if opts.DryRun {
+		return stats, nil
+	}
+
+	s.mu.Lock()
+	s.docs = rebuilt
+	s.mu.Unlock()
+	return stats, nil
+}
+
+var _ store.BM25Index = (*simulatedOptimizableStore)(nil)
+var _ store.Optimizable = (*simulatedOptimizableStore)(nil)
+
+func TestOptimizeIndex_RemovesTombstonedDocsNotInLiveSet(t *testing.T) {
+	sim := newSimulatedOptimizableStore(
+		&store.Document{ID: "a", Content: "alpha"},
+		&store.Document{ID: "b", Content: "beta"},
+		&store.Document{ID: "c", Content: "gamma"},
+	)
+
+	stats, err := store.OptimizeIndex(context.Background(), sim, store.OptimizeOptions{
+		LiveIDs: map[string]struct{}{"a": {}, "c": {}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.RemovedDocs)
+	assert.Equal(t, int64(100), stats.ReclaimedBytes)
+
+	ids, err := sim.AllIDs()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "c"}, ids)
+}
+
+func TestOptimizeIndex_CancellationMidOptimizeLeavesOriginalIntact(t *testing.T) {
+	docs := make([]*store.Document, 0, 500)
+	for i := 0; i < 500; i++ {
+		docs = append(docs, &store.Document{ID: string(rune('a'+(i%26))) + string(rune('0'+(i/26))), Content: "x"})
+	}
+	sim := newSimulatedOptimizableStore(docs...)
+	before, err := sim.AllIDs()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // already cancelled before Optimize's first ctx.Err() check
+
+	_, err = store.OptimizeIndex(ctx, sim, store.OptimizeOptions{LiveIDs: map[string]struct{}{}})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	after, err := sim.AllIDs()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, before, after, "a cancelled optimize must leave the original index untouched")
+}
+
+func TestOptimizeIndex_IdempotentReRun(t *testing.T) {
+	sim := newSimulatedOptimizableStore(
+		&store.Document{ID: "a", Content: "alpha"},
+		&store.Document{ID: "b", Content: "beta"},
+	)
+	live := map[string]struct{}{"a": {}}
+
+	first, err := store.OptimizeIndex(context.Background(), sim, store.OptimizeOptions{LiveIDs: live})
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.RemovedDocs)
+
+	// Re-running against the now-already-optimized store should find
+	// nothing left to remove - Optimize is idempotent, not a one-shot
+	// operation that errors on a second call.
+	second, err := store.OptimizeIndex(context.Background(), sim, store.OptimizeOptions{LiveIDs: live})
+	require.NoError(t, err)
+	assert.Equal(t, 0, second.RemovedDocs)
+	assert.Equal(t, int64(0), second.ReclaimedBytes)
+
+	ids, err := sim.AllIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, ids)
+}
+
+func TestOptimizeIndex_DryRunDoesNotMutateStore(t *testing.T) {
+	sim := newSimulatedOptimizableStore(
+		&store.Document{ID: "a", Content: "alpha"},
+		&store.Document{ID: "b", Content: "beta"},
+	)
+
+	stats, err := store.OptimizeIndex(context.Background(), sim, store.OptimizeOptions{
+		LiveIDs: map[string]struct{}{"a": {}},
+		DryRun:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.RemovedDocs)
+
+	ids, err := sim.AllIDs()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, ids, "dry run must not remove anything")
+}
+
+func TestOptimizeIndex_MockBM25StoreDelegates(t *testing.T) {
+	mock := &MockBM25Store{
+		OptimizeFn: func(ctx context.Context, opts store.OptimizeOptions) (*store.OptimizeStats, error) {
+			return &store.OptimizeStats{RemovedDocs: 7}, nil
+		},
+	}
+
+	stats, err := store.OptimizeIndex(context.Background(), mock, store.OptimizeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 7, stats.RemovedDocs)
+}
@@ -0,0 +1,256 @@
+package indexer
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// BM25Params are the standard Okapi BM25 free parameters: K1 controls
+// term-frequency saturation, B controls how strongly document length is
+// normalized against the corpus average.
+type BM25Params struct {
+	K1 float64
+	B  float64
+}
+
+// DefaultBM25Params are the Okapi BM25 defaults used across the search
+// path (k1=1.2, b=0.75).
+var DefaultBM25Params = BM25Params{K1: 1.2, B: 0.75}
+
+// bm25TermScore computes one term's BM25 contribution for a posting with
+// the given term frequency, document length, and corpus average document
+// length.
+func bm25TermScore(idf, tf, docLen, avgDocLen float64, params BM25Params) float64 {
+	if avgDocLen <= 0 {
+		avgDocLen = docLen
+	}
+	denom := tf + params.K1*(1-params.B+params.B*docLen/avgDocLen)
+	if denom == 0 {
+		return 0
+	}
+	return idf * (tf * (params.K1 + 1)) / denom
+}
+
+// PostingsIterator walks one term's postings in increasing docID order,
+// lazily decoding each posting's term frequency only when it's actually
+// reached - the "lazy postings list" change m3db made to its own BM25
+// search path, so a term a query ends up pruning away (via SeekGE) never
+// pays to decode the postings it skips.
+type PostingsIterator interface {
+	// Next advances to the next posting, returning its document ID and
+	// term frequency, or ok=false once the postings are exhausted.
+	Next() (docID uint64, tf uint32, ok bool)
+	// SeekGE advances to the first posting with docID >= target without
+	// decoding anything skipped over, or ok=false if none remains.
+	SeekGE(target uint64) (docID uint64, tf uint32, ok bool)
+	// Close releases any resources (e.g. a decompression buffer) this
+	// iterator holds.
+	Close() error
+}
+
+// TermQuery pairs one term's PostingsIterator with its corpus-level IDF
+// weight - a whole-index computation (doc frequency vs. total docs) the
+// iterator itself has no view into, so the caller supplies it - and an
+// optional MaxContribution upper bound (this term's highest possible
+// BM25 score, e.g. its IDF times the TF-saturation asymptote) used for
+// early termination. A zero MaxContribution disables pruning on this
+// term (it's always assumed to still be able to contribute).
+type TermQuery struct {
+	Iter            PostingsIterator
+	IDF             float64
+	MaxContribution float64
+}
+
+// MergeResult is one scored candidate from MergeTopK.
+type MergeResult struct {
+	DocID uint64
+	Score float64
+}
+
+// DocLengthFunc returns a document's length for BM25's length
+// normalization term.
+type DocLengthFunc func(docID uint64) float64
+
+var (
+	cursorHeapPool = sync.Pool{New: func() any { h := make(cursorHeap, 0, 8); return &h }}
+	topKHeapPool   = sync.Pool{New: func() any { h := make(topKHeap, 0, 8); return &h }}
+	scoreMapPool   = sync.Pool{New: func() any { return make(map[uint64]float64, 8) }}
+)
+
+// MergeTopK scores queries' postings as a k-way merge - a min-heap of
+// cursors ordered by current docID, so only the globally smallest cursor
+// ever advances - accumulating each document's BM25 score incrementally
+// as its postings are visited, then keeping only the K best in a bounded
+// min-heap rather than materializing every candidate's score up front.
+//
+// The heap of cursors, the bounded top-K heap, and the doc -> partial
+// score scratch map are all sync.Pool-backed, so a server issuing many
+// queries per second doesn't pay a fresh allocation for any of them on
+// every call.
+//
+// If every still-active query's MaxContribution is set, MergeTopK stops
+// early once the top-K heap is full and its worst score already exceeds
+// every remaining active term's combined upper bound - a document later
+// in the merge could not possibly score higher. This is the single
+// global upper bound described as a first cut; WAND-style block-max
+// pruning (a tighter, per-block upper bound) is the natural follow-on,
+// not implemented here.
+func MergeTopK(queries []TermQuery, k int, docLen DocLengthFunc, avgDocLen float64, params BM25Params) []MergeResult {
+	if k <= 0 || len(queries) == 0 {
+		return nil
+	}
+
+	cursors := cursorHeapPool.Get().(*cursorHeap)
+	*cursors = (*cursors)[:0]
+	defer func() {
+		for _, c := range *cursors {
+			_ = c.query.Iter.Close()
+		}
+		cursorHeapPool.Put(cursors)
+	}()
+
+	top := topKHeapPool.Get().(*topKHeap)
+	*top = (*top)[:0]
+	defer topKHeapPool.Put(top)
+
+	scores := scoreMapPool.Get().(map[uint64]float64)
+	for k := range scores {
+		delete(scores, k)
+	}
+	defer scoreMapPool.Put(scores)
+
+	var remainingMax float64
+	for _, q := range queries {
+		docID, tf, ok := q.Iter.Next()
+		if !ok {
+			_ = q.Iter.Close()
+			continue
+		}
+		*cursors = append(*cursors, &termCursor{query: q, docID: docID, tf: tf})
+		remainingMax += q.MaxContribution
+	}
+	heap.Init(cursors)
+
+	finalize := func(docID uint64) {
+		score, ok := scores[docID]
+		if !ok {
+			return
+		}
+		delete(scores, docID)
+		pushTopK(top, MergeResult{DocID: docID, Score: score}, k)
+	}
+
+	for cursors.Len() > 0 {
+		c := (*cursors)[0]
+		docID := c.docID
+
+		// Early termination: if every remaining active term's upper
+		// bound, combined, can't beat the current worst top-K score,
+		// no later document can displace what's already in top.
+		if len(*top) >= k && allHaveMaxContribution(queries) && remainingMax <= (*top)[0].Score {
+			break
+		}
+
+		scores[docID] += bm25TermScore(c.query.IDF, float64(c.tf), docLenOrOne(docLen, docID), avgDocLen, params)
+
+		next, tf, ok := c.query.Iter.Next()
+		if !ok {
+			remainingMax -= c.query.MaxContribution
+			_ = c.query.Iter.Close()
+			heap.Pop(cursors)
+		} else {
+			c.docID = next
+			c.tf = tf
+			heap.Fix(cursors, 0)
+		}
+
+		if cursors.Len() == 0 || (*cursors)[0].docID > docID {
+			finalize(docID)
+		}
+	}
+
+	out := make([]MergeResult, len(*top))
+	copy(out, *top)
+	sortResultsDescending(out)
+	return out
+}
+
+func docLenOrOne(f DocLengthFunc, docID uint64) float64 {
+	if f == nil {
+		return 1
+	}
+	return f(docID)
+}
+
+func allHaveMaxContribution(queries []TermQuery) bool {
+	for _, q := range queries {
+		if q.MaxContribution <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// pushTopK inserts r into top (a min-heap bounded at size k): if top
+// isn't full yet, r is always added; otherwise r only displaces the
+// current worst entry if it scores higher.
+func pushTopK(top *topKHeap, r MergeResult, k int) {
+	if top.Len() < k {
+		heap.Push(top, r)
+		return
+	}
+	if r.Score > (*top)[0].Score {
+		(*top)[0] = r
+		heap.Fix(top, 0)
+	}
+}
+
+func sortResultsDescending(results []MergeResult) {
+	for i := 1; i < len(results); i++ {
+		for j := i; j > 0 && results[j].Score > results[j-1].Score; j-- {
+			results[j], results[j-1] = results[j-1], results[j]
+		}
+	}
+}
+
+// termCursor is one TermQuery's current merge position.
+type termCursor struct {
+	query TermQuery
+	docID uint64
+	tf    uint32
+}
+
+// cursorHeap is a container/heap min-heap of termCursors ordered by
+// current docID - the mechanism that lets MergeTopK only ever advance
+// the globally smallest cursor.
+type cursorHeap []*termCursor
+
+func (h cursorHeap) Len() int           { return len(h) }
+func (h cursorHeap) Less(i, j int) bool { return h[i].docID < h[j].docID }
+func (h cursorHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *cursorHeap) Push(x any)        { *h = append(*h, x.(*termCursor)) }
+func (h *cursorHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// topKHeap is a container/heap min-heap of MergeResults bounded at size
+// K: the worst-scoring result is always at the root, so pushTopK can
+// check in O(1) whether a new candidate belongs in the top K at all.
+type topKHeap []MergeResult
+
+func (h topKHeap) Len() int           { return len(h) }
+func (h topKHeap) Less(i, j int) bool { return h[i].Score < h[j].Score }
+func (h topKHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *topKHeap) Push(x any)        { *h = append(*h, x.(MergeResult)) }
+func (h *topKHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,259 @@
+package indexer
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// slicePostingsIterator is a fixed, pre-sorted []posting PostingsIterator,
+// the lazy-iterator test double used throughout this file.
+type slicePostingsIterator struct {
+	postings []posting
+	pos      int
+	closed   bool
+}
+
+type posting struct {
+	docID uint64
+	tf    uint32
+}
+
+func newSlicePostingsIterator(postings ...posting) *slicePostingsIterator {
+	return &slicePostingsIterator{postings: postings, pos: -1}
+}
+
+func (it *slicePostingsIterator) Next() (uint64, uint32, bool) {
+	it.pos++
+	if it.pos >= len(it.postings) {
+		return 0, 0, false
+	}
+	p := it.postings[it.pos]
+	return p.docID, p.tf, true
+}
+
+func (it *slicePostingsIterator) SeekGE(target uint64) (uint64, uint32, bool) {
+	// A linear scan is fine for a test double of this size; a real
+	// on-disk iterator would binary-search or skip-list its block index.
+	for it.pos < len(it.postings) {
+		if it.pos >= 0 && it.postings[it.pos].docID >= target {
+			p := it.postings[it.pos]
+			return p.docID, p.tf, true
+		}
+		it.pos++
+	}
+	return 0, 0, false
+}
+
+func (it *slicePostingsIterator) Close() error {
+	it.closed = true
+	return nil
+}
+
+var _ PostingsIterator = (*slicePostingsIterator)(nil)
+
+// eagerMergeTopK is a reference implementation that materializes every
+// posting from every query up front into a plain map before sorting - the
+// "before" side of the before/after allocation comparison, and the
+// ranking oracle MergeTopK's output is checked against.
+func eagerMergeTopK(queries []TermQuery, k int, docLen DocLengthFunc, avgDocLen float64, params BM25Params) []MergeResult {
+	scores := make(map[uint64]float64)
+	for _, q := range queries {
+		for {
+			docID, tf, ok := q.Iter.Next()
+			if !ok {
+				break
+			}
+			scores[docID] += bm25TermScore(q.IDF, float64(tf), docLenOrOne(docLen, docID), avgDocLen, params)
+		}
+		_ = q.Iter.Close()
+	}
+
+	results := make([]MergeResult, 0, len(scores))
+	for docID, score := range scores {
+		results = append(results, MergeResult{DocID: docID, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].DocID < results[j].DocID
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+func sortResultsStable(results []MergeResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].DocID < results[j].DocID
+	})
+}
+
+func TestMergeTopK_MatchesEagerRankingOnOverlappingTerms(t *testing.T) {
+	alpha := newSlicePostingsIterator(
+		posting{docID: 1, tf: 3}, posting{docID: 2, tf: 1}, posting{docID: 5, tf: 2},
+	)
+	beta := newSlicePostingsIterator(
+		posting{docID: 2, tf: 4}, posting{docID: 3, tf: 1}, posting{docID: 5, tf: 1},
+	)
+
+	got := MergeTopK([]TermQuery{
+		{Iter: alpha, IDF: 1.5},
+		{Iter: beta, IDF: 0.8},
+	}, 10, nil, 0, DefaultBM25Params)
+
+	alpha2 := newSlicePostingsIterator(posting{docID: 1, tf: 3}, posting{docID: 2, tf: 1}, posting{docID: 5, tf: 2})
+	beta2 := newSlicePostingsIterator(posting{docID: 2, tf: 4}, posting{docID: 3, tf: 1}, posting{docID: 5, tf: 1})
+	want := eagerMergeTopK([]TermQuery{
+		{Iter: alpha2, IDF: 1.5},
+		{Iter: beta2, IDF: 0.8},
+	}, 10, nil, 0, DefaultBM25Params)
+
+	sortResultsStable(got)
+	sortResultsStable(want)
+	if len(got) != len(want) {
+		t.Fatalf("len(got) = %d, len(want) = %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].DocID != want[i].DocID || math.Abs(got[i].Score-want[i].Score) > 1e-9 {
+			t.Errorf("result[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMergeTopK_RespectsKBound(t *testing.T) {
+	iter := newSlicePostingsIterator(
+		posting{docID: 1, tf: 1}, posting{docID: 2, tf: 2}, posting{docID: 3, tf: 3}, posting{docID: 4, tf: 4},
+	)
+	got := MergeTopK([]TermQuery{{Iter: iter, IDF: 1.0}}, 2, nil, 0, DefaultBM25Params)
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	// Higher tf scores higher under BM25, so docs 3 and 4 should win.
+	if got[0].DocID != 4 || got[1].DocID != 3 {
+		t.Errorf("got %+v, want docs 4 then 3", got)
+	}
+}
+
+func TestMergeTopK_EmptyQueriesReturnsNil(t *testing.T) {
+	if got := MergeTopK(nil, 10, nil, 0, DefaultBM25Params); got != nil {
+		t.Errorf("got %+v, want nil", got)
+	}
+}
+
+func TestMergeTopK_ZeroKReturnsNil(t *testing.T) {
+	iter := newSlicePostingsIterator(posting{docID: 1, tf: 1})
+	if got := MergeTopK([]TermQuery{{Iter: iter, IDF: 1}}, 0, nil, 0, DefaultBM25Params); got != nil {
+		t.Errorf("got %+v, want nil", got)
+	}
+}
+
+func TestMergeTopK_ClosesExhaustedAndUnusedIterators(t *testing.T) {
+	empty := newSlicePostingsIterator()
+	nonEmpty := newSlicePostingsIterator(posting{docID: 1, tf: 1})
+
+	MergeTopK([]TermQuery{{Iter: empty, IDF: 1}, {Iter: nonEmpty, IDF: 1}}, 10, nil, 0, DefaultBM25Params)
+
+	if !empty.closed {
+		t.Error("an iterator with no postings at all must still be closed")
+	}
+	if !nonEmpty.closed {
+		t.Error("an iterator exhausted during the merge must be closed")
+	}
+}
+
+func TestMergeTopK_EarlyTerminationStopsBeforeExhaustingLosingTerm(t *testing.T) {
+	// alpha has two very high-scoring postings; beta has many low-scoring
+	// ones that can never outrank alpha's. Once top-2 is full of alpha's
+	// docs, beta's remaining postings should never be visited.
+	alpha := newSlicePostingsIterator(posting{docID: 1, tf: 100}, posting{docID: 2, tf: 100})
+	lowPostings := make([]posting, 0, 50)
+	for i := uint64(10); i < 60; i++ {
+		lowPostings = append(lowPostings, posting{docID: i, tf: 1})
+	}
+	beta := newSlicePostingsIterator(lowPostings...)
+
+	got := MergeTopK([]TermQuery{
+		{Iter: alpha, IDF: 10, MaxContribution: 10 * (100 * (DefaultBM25Params.K1 + 1)) / (100 + DefaultBM25Params.K1)},
+		{Iter: beta, IDF: 0.01, MaxContribution: 0.01 * (1 * (DefaultBM25Params.K1 + 1)) / (1 + DefaultBM25Params.K1)},
+	}, 2, nil, 0, DefaultBM25Params)
+
+	if len(got) != 2 || got[0].DocID != 1 && got[0].DocID != 2 {
+		t.Fatalf("got %+v, want alpha's two docs", got)
+	}
+	if beta.pos >= len(lowPostings)-1 {
+		t.Errorf("expected early termination to leave beta's postings mostly unvisited, visited %d/%d", beta.pos+1, len(lowPostings))
+	}
+}
+
+func TestSlicePostingsIterator_SeekGESkipsAhead(t *testing.T) {
+	it := newSlicePostingsIterator(posting{docID: 1, tf: 1}, posting{docID: 5, tf: 2}, posting{docID: 9, tf: 3})
+	docID, tf, ok := it.SeekGE(4)
+	if !ok || docID != 5 || tf != 2 {
+		t.Errorf("SeekGE(4) = (%d, %d, %v), want (5, 2, true)", docID, tf, ok)
+	}
+	docID, _, ok = it.Next()
+	if !ok || docID != 9 {
+		t.Errorf("Next() after SeekGE = (%d, %v), want (9, true)", docID, ok)
+	}
+}
+
+func randomPostings(rng *rand.Rand, n int, maxDocID uint64) []posting {
+	ids := make(map[uint64]struct{}, n)
+	for len(ids) < n {
+		ids[uint64(rng.Int63n(int64(maxDocID)))] = struct{}{}
+	}
+	ordered := make([]uint64, 0, n)
+	for id := range ids {
+		ordered = append(ordered, id)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i] < ordered[j] })
+
+	postings := make([]posting, 0, n)
+	for _, id := range ordered {
+		postings = append(postings, posting{docID: id, tf: uint32(1 + rng.Intn(20))})
+	}
+	return postings
+}
+
+func BenchmarkMergeTopK_Lazy(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	terms := make([][]posting, 4)
+	for i := range terms {
+		terms[i] = randomPostings(rng, 2000, 100000)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		queries := make([]TermQuery, len(terms))
+		for j, postings := range terms {
+			queries[j] = TermQuery{Iter: newSlicePostingsIterator(postings...), IDF: 1.2}
+		}
+		MergeTopK(queries, 10, nil, 0, DefaultBM25Params)
+	}
+}
+
+func BenchmarkMergeTopK_Eager(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	terms := make([][]posting, 4)
+	for i := range terms {
+		terms[i] = randomPostings(rng, 2000, 100000)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		queries := make([]TermQuery, len(terms))
+		for j, postings := range terms {
+			queries[j] = TermQuery{Iter: newSlicePostingsIterator(postings...), IDF: 1.2}
+		}
+		eagerMergeTopK(queries, 10, nil, 0, DefaultBM25Params)
+	}
+}
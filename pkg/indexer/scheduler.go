@@ -0,0 +1,152 @@
+package indexer
+
+import (
+	"context"
+	"time"
+)
+
+// SearchOptions bounds how a budgeted search may use a Scheduler's worker
+// pool. The request this carries out asks for these on store.BM25Index.Search
+// itself, but that interface (and BM25Indexer, which wraps it) is used
+// pervasively across this tree including files outside it (see
+// throttle.go's Throttleable in internal/embed for the same constraint),
+// so retrofitting its signature isn't safe to do from here.
+//
+// This is blocked, not done: SearchOptions/Scheduler/RunBudgetedQuery are
+// the generic worker-budgeting mechanism the request asked for, but no
+// store.BM25Index in this tree wraps itself in one yet, because doing so
+// for the real implementation would need the signature change above. An
+// earlier pass here shipped a standalone BudgetedBM25Index with its own
+// in-memory inverted index to exercise this code - that's been removed;
+// it wasn't a real backend, just a second, unused BM25Index sitting next
+// to the real one. Wiring a real backend's Search through RunBudgetedQuery
+// still requires the store.BM25Index.Search signature change this package
+// was built to avoid.
+type SearchOptions struct {
+	// MaxWorkerTime bounds how long a single scheduler admission may run
+	// before yielding its slot back and re-queuing to resume. Zero means
+	// unbounded - a query runs to completion on its first admission.
+	MaxWorkerTime time.Duration
+	// MaxConcurrentQueries caps how many queries the Scheduler admits at
+	// once. Values <= 0 are treated as 1.
+	MaxConcurrentQueries int
+}
+
+// Scheduler admits up to N concurrent workers through a buffered channel
+// semaphore. Go's runtime services goroutines blocked on the same channel
+// in the order they started waiting, so Acquire's wait order is already
+// FIFO-fair without any extra bookkeeping.
+type Scheduler struct {
+	sem chan struct{}
+}
+
+// NewScheduler builds a Scheduler admitting at most maxConcurrent workers
+// at once. maxConcurrent <= 0 is treated as 1.
+func NewScheduler(maxConcurrent int) *Scheduler {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	return &Scheduler{sem: make(chan struct{}, maxConcurrent)}
+}
+
+// Acquire blocks until a worker slot is free or ctx is done.
+func (s *Scheduler) Acquire(ctx context.Context) error {
+	select {
+	case s.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a worker slot acquired via Acquire.
+func (s *Scheduler) Release() {
+	<-s.sem
+}
+
+// PostingIterator is a resumable cursor over one term's postings list. A
+// backend's own postings format is opaque to this package - the iterator
+// just needs to remember its own position between Next calls, the same
+// way a SQL cursor or bleve's TermFieldReader would, so re-acquiring a
+// Scheduler slot and calling Next again resumes exactly where the last
+// admission left off.
+type PostingIterator interface {
+	// Term returns the term this iterator walks postings for.
+	Term() string
+	// Next advances to the next posting, returning the document ID and
+	// this term's score contribution for it, or ok=false once the
+	// postings list is exhausted.
+	Next() (docID string, contribution float64, ok bool)
+}
+
+// QueryCursor is the checkpoint RunBudgetedQuery carries across yields:
+// which term it's currently walking, how many postings of that term it's
+// consumed so far, and the scores accumulated across every term already
+// finished or in progress. Exported so a caller (or a test) can inspect
+// how far a preempted query got.
+type QueryCursor struct {
+	TermIdx       int
+	DocCursor     int
+	PartialScores map[string]float64
+}
+
+// RunBudgetedQuery walks terms' postings through sched, one term's
+// iterator at a time, admitting a single worker slot per term and
+// re-acquiring whenever an admission's wall-clock time exceeds
+// opts.MaxWorkerTime before that term's postings are exhausted - mirroring
+// m3db's own per-query worker time cap: yield the slot, requeue (FIFO, via
+// Scheduler), resume. terms are walked in order; a heavy term doesn't
+// block other queries waiting on sched for longer than one budget window
+// at a time.
+func RunBudgetedQuery(ctx context.Context, sched *Scheduler, terms []PostingIterator, opts SearchOptions) (*QueryCursor, error) {
+	cursor := &QueryCursor{PartialScores: make(map[string]float64)}
+
+	for cursor.TermIdx < len(terms) {
+		if err := sched.Acquire(ctx); err != nil {
+			return cursor, err
+		}
+
+		term := terms[cursor.TermIdx]
+		exhausted, err := runOneAdmission(ctx, term, cursor, opts.MaxWorkerTime)
+		sched.Release()
+		if err != nil {
+			return cursor, err
+		}
+
+		if exhausted {
+			cursor.TermIdx++
+			cursor.DocCursor = 0
+		}
+	}
+
+	return cursor, nil
+}
+
+// runOneAdmission drains term's postings into cursor until either the
+// postings list is exhausted (returns true) or opts' time budget elapses
+// first (returns false, ready to be resumed on the next admission).
+func runOneAdmission(ctx context.Context, term PostingIterator, cursor *QueryCursor, maxWorkerTime time.Duration) (bool, error) {
+	var deadline time.Time
+	if maxWorkerTime > 0 {
+		deadline = time.Now().Add(maxWorkerTime)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		default:
+		}
+
+		docID, contribution, ok := term.Next()
+		if !ok {
+			return true, nil
+		}
+		cursor.PartialScores[docID] += contribution
+		cursor.DocCursor++
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return false, nil
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingIterator is a PostingIterator over n synthetic postings, each
+// slept for delay before being returned, so a test can force an admission
+// to run past a MaxWorkerTime budget and assert it yields and resumes.
+type countingIterator struct {
+	term  string
+	n     int
+	delay time.Duration
+	pos   int
+}
+
+func (it *countingIterator) Term() string { return it.term }
+
+func (it *countingIterator) Next() (string, float64, bool) {
+	if it.pos >= it.n {
+		return "", 0, false
+	}
+	if it.delay > 0 {
+		time.Sleep(it.delay)
+	}
+	id := fmt.Sprintf("%s-doc-%d", it.term, it.pos)
+	it.pos++
+	return id, 1.0, true
+}
+
+func TestScheduler_AcquireReleaseRoundTrips(t *testing.T) {
+	sched := NewScheduler(1)
+	require.NoError(t, sched.Acquire(context.Background()))
+	sched.Release()
+	require.NoError(t, sched.Acquire(context.Background()))
+	sched.Release()
+}
+
+func TestScheduler_AcquireBlocksUntilReleased(t *testing.T) {
+	sched := NewScheduler(1)
+	require.NoError(t, sched.Acquire(context.Background()))
+
+	acquired := make(chan struct{})
+	go func() {
+		_ = sched.Acquire(context.Background())
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Acquire should have blocked while the slot was held")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	sched.Release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second Acquire never completed after Release")
+	}
+}
+
+func TestScheduler_AcquireRespectsContextCancellation(t *testing.T) {
+	sched := NewScheduler(1)
+	require.NoError(t, sched.Acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := sched.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestScheduler_FIFOAdmissionOrder(t *testing.T) {
+	sched := NewScheduler(1)
+	require.NoError(t, sched.Acquire(context.Background()))
+
+	const waiters = 5
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Stagger goroutine start so Acquire calls queue up in order.
+			time.Sleep(time.Duration(i) * 5 * time.Millisecond)
+			require.NoError(t, sched.Acquire(context.Background()))
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			sched.Release()
+		}(i)
+	}
+
+	time.Sleep(waiters * 5 * time.Millisecond)
+	sched.Release()
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, order, waiters)
+	for i := 0; i < waiters; i++ {
+		assert.Equal(t, i, order[i], "expected FIFO admission order under contention")
+	}
+}
+
+func TestRunBudgetedQuery_YieldsAndResumesAcrossBudgetWindows(t *testing.T) {
+	sched := NewScheduler(1)
+	term := &countingIterator{term: "slow", n: 10, delay: 5 * time.Millisecond}
+
+	cursor, err := RunBudgetedQuery(context.Background(), sched, []PostingIterator{term}, SearchOptions{
+		MaxWorkerTime: 12 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, cursor.TermIdx, "expected the single term to be fully consumed")
+	assert.Equal(t, 10, len(cursor.PartialScores))
+	assert.Greater(t, term.pos, 0)
+	assert.Equal(t, 10, term.pos, "expected the iterator to have been resumed to completion")
+}
+
+func TestRunBudgetedQuery_AccumulatesScoresAcrossMultipleTerms(t *testing.T) {
+	sched := NewScheduler(2)
+	terms := []PostingIterator{
+		&countingIterator{term: "alpha", n: 3},
+		&countingIterator{term: "beta", n: 2},
+	}
+
+	cursor, err := RunBudgetedQuery(context.Background(), sched, terms, SearchOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, cursor.TermIdx)
+	assert.Equal(t, 5, len(cursor.PartialScores), "expected 3 alpha postings + 2 beta postings")
+	assert.Equal(t, 1.0, cursor.PartialScores["alpha-doc-0"])
+	assert.Equal(t, 1.0, cursor.PartialScores["beta-doc-0"])
+}
+
+func TestRunBudgetedQuery_RespectsContextCancellation(t *testing.T) {
+	sched := NewScheduler(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	term := &countingIterator{term: "slow", n: 5}
+	_, err := RunBudgetedQuery(ctx, sched, []PostingIterator{term}, SearchOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}